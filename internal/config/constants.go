@@ -28,6 +28,7 @@ const (
 	WorkersOpenAI     = 20 // API calls, OpenAI handles high concurrency
 	WorkersOpenAITTS  = 25 // API calls, TTS is fast
 	WorkersDeepSeek   = 25 // API calls, generous limits
+	WorkersGrok       = 20 // API calls, xAI's default rate limits
 	WorkersEdgeTTS    = 30 // Free API, very generous rate limits
 	WorkersFishAudio  = 5  // Fish Audio starter tier (5 concurrent requests)
 )
@@ -58,14 +59,47 @@ const (
 	ChunkSizeArgos    = 50 // Local processing, moderate batch
 	ChunkSizeOpenAI   = 50 // API token limits
 	ChunkSizeDeepSeek = 20 // Smaller batches = more parallelism (200 subs → 10 batches → 10 workers active)
+	ChunkSizeGrok     = 20 // Same trade-off as DeepSeek
+
+	ChunkSizeLibreTranslate = 25 // One "q" array per request
 )
 
+// LibreTranslateConcurrency is how many requests run at once against a
+// LibreTranslate server (self-hosted instances translate on a few CPU cores)
+const LibreTranslateConcurrency = 4
+
 // Retry settings
 const (
 	DefaultMaxRetries     = 3
 	DefaultRetryDelayBase = time.Second
 )
 
+// TTS segment settings (shared by every provider through tts.BaseTTS)
+const (
+	TTSSegmentAttempts       = 3               // Tries per segment before it's given up on
+	TTSRetryDelay            = 2 * time.Second // Multiplied by the attempt number
+	TTSMaxFailedSegmentRatio = 0.1             // Above this share of failed segments the run fails
+)
+
+// Native rate fitting: over-long segments are re-synthesized faster by the
+// provider itself before ffmpeg atempo is used as a last resort
+const (
+	TTSRateAttempts = 2                     // Re-syntheses per segment; a rate can undershoot
+	TTSRateHeadroom = 1.03                  // Aim slightly faster than measured so it fits
+	TTSFitTolerance = 50 * time.Millisecond // Overshoot ignored, as in AdjustAudioDuration
+)
+
+// Voice-clone references: the cleanest stretch of a speaker's speech is cut
+// from the source video as the sample a cloning TTS provider imitates
+const (
+	VoiceReferenceMin     = 5 * time.Second
+	VoiceReferenceMax     = 15 * time.Second
+	VoiceReferenceIdeal   = 10 * time.Second
+	VoiceReferenceMaxGap  = time.Second            // Longest pause joined into one window
+	VoiceReferenceGuard   = 500 * time.Millisecond // Another speaker this close counts as overlap
+	VoiceReferenceContext = 3 * time.Second        // Around a window, where the noise floor is measured
+)
+
 // HTTP client settings
 const (
 	HTTPTimeout             = 2 * time.Minute
@@ -102,7 +136,8 @@ const (
 // API endpoints
 const (
 	OpenAIAPIEndpoint     = "https://api.openai.com/v1"
-	DeepSeekAPIEndpoint   = "https://api.deepseek.com/v1/chat/completions"
+	DeepSeekAPIBaseURL    = "https://api.deepseek.com/v1"
+	GrokAPIBaseURL        = "https://api.x.ai/v1"
 	OpenAIChatEndpoint    = "https://api.openai.com/v1/chat/completions"
 	OpenAIWhisperEndpoint = "https://api.openai.com/v1/audio/transcriptions"
 	OpenAITTSEndpoint     = "https://api.openai.com/v1/audio/speech"
@@ -112,18 +147,20 @@ const (
 const (
 	OpenAITranslationModel = "gpt-4o-mini"
 	DeepSeekModel          = "deepseek-chat"
+	GrokModel              = "grok-4-1-fast-non-reasoning" // Cheapest: $0.20/$0.50 per million tokens
 	OpenAITTSModelTTS1     = "tts-1"
 	OpenAITTSModelTTS1HD   = "tts-1-hd"
 )
 
-// Translation delimiter
-const TranslationDelimiter = "|||SUBTITLE|||"
+// Follow-up requests for segments an LLM reply dropped or mangled
+const TranslationRepairRounds = 2
 
 // Temperature settings for LLM calls
 const (
 	TranslationTemperature = 0.3
 	TranslationMaxTokens   = 4096
 	DeepSeekMaxTokens      = 8192
+	GrokMaxTokens          = 8192
 )
 
 // Default TTS voices
@@ -152,6 +189,12 @@ const (
 	ExecTimeoutWhisper = 30 * time.Minute  // Transcription (can be long)
 )
 
+// Persistent Python workers (Argos, CosyVoice local)
+const (
+	PythonWorkerStartTimeout     = 3 * time.Minute  // Imports and model loading
+	PythonWorkerHealthCheckAfter = 10 * time.Minute // Ping idle workers before reuse
+)
+
 // DynamicWorkerCount returns the optimal worker count based on task type and CPU cores.
 // This allows scaling workers based on system resources rather than fixed values.
 func DynamicWorkerCount(taskType string) int {
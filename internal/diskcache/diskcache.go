@@ -0,0 +1,224 @@
+// Package diskcache keeps entries as files in one directory, evicting the
+// least recently used once the directory grows past a size limit. It is the
+// storage behind the transcription and speech caches.
+package diskcache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"video-translator/internal/logger"
+)
+
+// Cache is a directory of entries named key+ext. An index of entry sizes
+// and last use is read from the directory once, so writes don't rescan it.
+// The lock only guards the index and renames: entries are copied in and
+// out without holding it, and a reader racing an eviction just misses.
+type Cache struct {
+	name     string // For logs ("Speech cache")
+	dir      string
+	ext      string // Entry file extension (".wav")
+	maxBytes int64
+
+	mu    sync.Mutex
+	index map[string]*entry // By key; nil until loaded from dir
+	total int64             // Sum of index sizes
+	clock int64             // Last use stamp handed out
+}
+
+// entry is one cached file in the index
+type entry struct {
+	size int64
+	used int64 // Use stamp; higher is more recent
+}
+
+// New creates a cache of ext files in dir limited to maxBytes (0 = unlimited)
+func New(name, dir, ext string, maxBytes int64) *Cache {
+	return &Cache{
+		name:     name,
+		dir:      dir,
+		ext:      ext,
+		maxBytes: maxBytes,
+	}
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key+c.ext)
+}
+
+// Get calls read with the path of key's entry and marks it used when read
+// succeeds. It reports false when the entry is missing or read fails.
+func (c *Cache) Get(key string, read func(path string) error) bool {
+	c.mu.Lock()
+	c.load()
+	_, ok := c.index[key]
+	c.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	path := c.entryPath(key)
+	if err := read(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.forget(key) // Removed behind our back (e.g. cleared elsewhere)
+		}
+		return false
+	}
+
+	c.mu.Lock()
+	if e, ok := c.index[key]; ok {
+		c.clock++
+		e.used = c.clock
+	}
+	c.mu.Unlock()
+
+	// The modification time carries LRU order over to the next load
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return true
+}
+
+// Put stores the entry write creates at the path it is given, then evicts
+// old entries if over the size limit. The entry is written to a temporary
+// file and renamed, so readers never see a partial one.
+func (c *Cache) Put(key string, write func(path string) error) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", strings.ToLower(c.name), err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s entry: %w", strings.ToLower(c.name), err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if err := write(tmp.Name()); err != nil {
+		return fmt.Errorf("failed to write %s entry: %w", strings.ToLower(c.name), err)
+	}
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to write %s entry: %w", strings.ToLower(c.name), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	if err := os.Rename(tmp.Name(), c.entryPath(key)); err != nil {
+		return fmt.Errorf("failed to write %s entry: %w", strings.ToLower(c.name), err)
+	}
+	if old, ok := c.index[key]; ok {
+		c.total -= old.size
+	}
+	c.clock++
+	c.index[key] = &entry{size: info.Size(), used: c.clock}
+	c.total += info.Size()
+
+	c.evict()
+	return nil
+}
+
+// Size returns the total size in bytes and number of entries
+func (c *Cache) Size() (int64, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	return c.total, len(c.index)
+}
+
+// Clear removes every entry
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	for key, e := range c.index {
+		if err := os.Remove(c.entryPath(key)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear %s: %w", strings.ToLower(c.name), err)
+		}
+		c.total -= e.size
+		delete(c.index, key)
+	}
+	logger.LogInfo("%s: cleared %s", c.name, c.dir)
+	return nil
+}
+
+// forget drops key from the index
+func (c *Cache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.index[key]; ok {
+		c.total -= e.size
+		delete(c.index, key)
+	}
+}
+
+// load reads the index from the directory the first time it is needed,
+// ordering use by modification time; caller must hold c.mu
+func (c *Cache) load() {
+	if c.index != nil {
+		return
+	}
+	c.index = make(map[string]*entry)
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	var infos []os.FileInfo
+	for _, de := range dirEntries {
+		if de.IsDir() || filepath.Ext(de.Name()) != c.ext {
+			continue
+		}
+		if info, err := de.Info(); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	for _, info := range infos {
+		c.clock++
+		c.index[strings.TrimSuffix(info.Name(), c.ext)] = &entry{size: info.Size(), used: c.clock}
+		c.total += info.Size()
+	}
+}
+
+// evict removes least recently used entries until the cache fits in
+// maxBytes; caller must hold c.mu
+func (c *Cache) evict() {
+	if c.maxBytes <= 0 || c.total <= c.maxBytes {
+		return
+	}
+
+	keys := make([]string, 0, len(c.index))
+	for key := range c.index {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.index[keys[i]].used < c.index[keys[j]].used
+	})
+
+	for _, key := range keys {
+		if c.total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(c.entryPath(key)); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		c.total -= c.index[key].size
+		delete(c.index, key)
+		logger.LogDebug("%s: evicted %s", c.name, key+c.ext)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"video-translator/internal/config"
@@ -93,10 +95,12 @@ func DoWithRetryContext(ctx context.Context, client *http.Client, req *http.Requ
 			resp.Body.Close() // Close the response before retry
 			lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
 
+			// The server knows best when it will take requests again
+			wait := max(delay, ParseRetryAfter(resp.Header))
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(delay):
+			case <-time.After(wait):
 			}
 			delay = time.Duration(float64(delay) * cfg.BackoffFactor)
 			continue
@@ -109,6 +113,31 @@ func DoWithRetryContext(ctx context.Context, client *http.Client, req *http.Requ
 	return nil, fmt.Errorf("failed after %d retries: %w", cfg.MaxAttempts, lastErr)
 }
 
+// ParseRetryAfter returns how long a throttled response asks clients to
+// wait: Retry-After in seconds or as an HTTP date, or OpenAI's
+// retry-after-ms. Zero when the response doesn't say.
+func ParseRetryAfter(h http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(strings.TrimSpace(h.Get("Retry-After-Ms")), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := strings.TrimSpace(h.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
 // RetryFunc is a helper for retrying any function with exponential backoff.
 type RetryFunc[T any] func() (T, error)
 
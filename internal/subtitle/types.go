@@ -12,6 +12,7 @@ type Subtitle struct {
 	StartTime time.Duration
 	EndTime   time.Duration
 	Text      string
+	Emotion   string // Delivery for expressive TTS ("" = neutral)
 }
 
 // Duration returns the duration of this subtitle.
@@ -79,6 +80,7 @@ func (l List) WithUpdatedTexts(texts []string) List {
 			StartTime: sub.StartTime,
 			EndTime:   sub.EndTime,
 			Text:      texts[i],
+			Emotion:   sub.Emotion,
 		}
 	}
 	return result
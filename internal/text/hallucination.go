@@ -0,0 +1,128 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// HallucinationPhrases lists phrases Whisper is known to invent during music,
+// silence or end credits, keyed by ISO 639-1 language code. Several are
+// also ordinary dialogue, so a match alone only makes a segment suspect.
+// Entries are compared after NormalizeForMatch, so keep them lowercase and
+// without punctuation. The "*" list applies to every language.
+var HallucinationPhrases = map[string][]string{
+	"*": {
+		"amaraorg",
+		"subtitles by the amaraorg community",
+	},
+	"en": {
+		"thanks for watching",
+		"thank you for watching",
+		"thanks for watching and see you next time",
+		"please subscribe",
+		"please subscribe to my channel",
+		"like and subscribe",
+		"dont forget to like and subscribe",
+		"subtitles by",
+		"subtitled by",
+		"translated by",
+		"transcription by",
+		"see you in the next video",
+		"see you next time",
+		"bye bye",
+		"you",
+	},
+	"ru": {
+		"спасибо за просмотр",
+		"продолжение следует",
+		"подписывайтесь на канал",
+		"ставьте лайки и подписывайтесь",
+		"редактор субтитров",
+		"корректор",
+		"субтитры сделал",
+		"субтитры создавал",
+		"субтитры подогнал",
+		"до новых встреч",
+		"всем пока",
+	},
+	"de": {
+		"vielen dank fürs zuschauen",
+		"danke fürs zuschauen",
+		"untertitel im auftrag des zdf",
+		"untertitel der amaraorg community",
+		"bis zum nächsten mal",
+	},
+	"fr": {
+		"merci davoir regardé",
+		"merci davoir regardé cette vidéo",
+		"sous-titres réalisés par la communauté damaraorg",
+		"sous-titrage st 501",
+		"abonnez-vous",
+		"à bientôt",
+	},
+	"es": {
+		"gracias por ver",
+		"gracias por ver el video",
+		"subtítulos realizados por la comunidad de amaraorg",
+		"suscríbete",
+		"hasta la próxima",
+	},
+	"it": {
+		"grazie per la visione",
+		"sottotitoli creati dalla comunità amaraorg",
+		"iscriviti al canale",
+	},
+	"pt": {
+		"obrigado por assistir",
+		"legendas pela comunidade amaraorg",
+		"inscreva-se no canal",
+	},
+	"ja": {
+		"ご視聴ありがとうございました",
+		"チャンネル登録お願いします",
+	},
+	"zh": {
+		"谢谢观看",
+		"请不吝点赞 订阅 转发 打赏支持明镜与点点栏目",
+		"字幕由amaraorg社区提供",
+	},
+	"ko": {
+		"시청해주셔서 감사합니다",
+		"구독과 좋아요 부탁드립니다",
+	},
+}
+
+// NormalizeForMatch lowercases s, drops punctuation and symbols, and collapses
+// whitespace so that "Thanks for watching!!" matches "thanks for watching".
+// Hyphens are kept because several phrase list entries rely on them.
+func NormalizeForMatch(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// MatchHallucinationPhrase reports whether s is a known hallucination for lang
+// and returns the matching phrase. Only whole-segment matches count, so a real
+// sentence that merely contains "thanks for watching" is left alone.
+func MatchHallucinationPhrase(s, lang string) (string, bool) {
+	normalized := NormalizeForMatch(s)
+	if normalized == "" {
+		return "", false
+	}
+	for _, key := range []string{"*", lang} {
+		for _, phrase := range HallucinationPhrases[key] {
+			if normalized == phrase {
+				return phrase, true
+			}
+		}
+	}
+	return "", false
+}
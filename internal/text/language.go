@@ -1,95 +1,61 @@
 package text
 
-// LanguageNames maps ISO 639-1 language codes to human-readable names.
-var LanguageNames = map[string]string{
-	"ru": "Russian",
-	"en": "English",
-	"de": "German",
-	"fr": "French",
-	"es": "Spanish",
-	"it": "Italian",
-	"pt": "Portuguese",
-	"zh": "Chinese",
-	"ja": "Japanese",
-	"ko": "Korean",
-	"ar": "Arabic",
-	"hi": "Hindi",
-	"nl": "Dutch",
-	"pl": "Polish",
-	"tr": "Turkish",
-	"vi": "Vietnamese",
-}
-
-// SupportedSourceLanguages returns a map of language codes to names
-// that can be used as source languages for transcription and translation.
-var SupportedSourceLanguages = map[string]string{
-	"ru": "Russian",
-	"en": "English",
-	"de": "German",
-	"fr": "French",
-	"es": "Spanish",
-	"it": "Italian",
-	"pt": "Portuguese",
-	"nl": "Dutch",
-	"pl": "Polish",
-	"ja": "Japanese",
-	"zh": "Chinese",
-	"ko": "Korean",
-	"ar": "Arabic",
-	"hi": "Hindi",
-}
-
-// SupportedTargetLanguages returns a map of language codes to names
-// that can be used as target languages for translation and TTS.
-var SupportedTargetLanguages = map[string]string{
-	"en": "English",
-	"de": "German",
-	"fr": "French",
-	"es": "Spanish",
-	"it": "Italian",
-	"pt": "Portuguese",
-	"nl": "Dutch",
-	"pl": "Polish",
-	"ja": "Japanese",
-	"zh": "Chinese",
-	"ru": "Russian",
-}
+import "strings"
 
-// GetLanguageName returns the human-readable name for a language code.
+// GetLanguageName returns the English name for a language code.
 // If the code is not found, it returns the code itself.
 func GetLanguageName(code string) string {
-	if name, ok := LanguageNames[code]; ok {
-		return name
+	if l, ok := LookupLanguage(code); ok {
+		return l.Name
 	}
 	return code
 }
 
-// IsValidSourceLanguage checks if a language code is a valid source language.
+// IsValidSourceLanguage checks if a language code can be transcribed by
+// at least one provider.
 func IsValidSourceLanguage(code string) bool {
-	_, ok := SupportedSourceLanguages[code]
-	return ok
+	return len(ProvidersFor(StageTranscription, code)) > 0
 }
 
-// IsValidTargetLanguage checks if a language code is a valid target language.
+// IsValidTargetLanguage checks if a language code can be both translated
+// into and spoken by at least one provider.
 func IsValidTargetLanguage(code string) bool {
-	_, ok := SupportedTargetLanguages[code]
-	return ok
+	return len(ProvidersFor(StageTranslation, code)) > 0 && len(ProvidersFor(StageTTS, code)) > 0
 }
 
 // GetSourceLanguageCodes returns all valid source language codes.
 func GetSourceLanguageCodes() []string {
-	codes := make([]string, 0, len(SupportedSourceLanguages))
-	for code := range SupportedSourceLanguages {
-		codes = append(codes, code)
+	var codes []string
+	for _, l := range Languages() {
+		if IsValidSourceLanguage(l.Code) {
+			codes = append(codes, l.Code)
+		}
 	}
 	return codes
 }
 
 // GetTargetLanguageCodes returns all valid target language codes.
 func GetTargetLanguageCodes() []string {
-	codes := make([]string, 0, len(SupportedTargetLanguages))
-	for code := range SupportedTargetLanguages {
-		codes = append(codes, code)
+	var codes []string
+	for _, l := range Languages() {
+		if IsValidTargetLanguage(l.Code) {
+			codes = append(codes, l.Code)
+		}
 	}
 	return codes
 }
+
+// LanguageCodeFromName normalizes a language reported by a provider to a
+// registry code. Accepts codes ("ru"), provider codes ("jw"), English
+// names ("Russian", "russian") and region-tagged codes ("en-US").
+// Returns "" if unknown.
+func LanguageCodeFromName(name string) string {
+	if l, ok := LookupLanguage(name); ok {
+		return l.Code
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 2 {
+		return name // Valid-looking code we just don't have a name for
+	}
+	return ""
+}
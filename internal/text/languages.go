@@ -0,0 +1,321 @@
+package text
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Pipeline stages a provider can support a language at
+const (
+	StageTranscription = "transcription"
+	StageTranslation   = "translation"
+	StageTTS           = "tts"
+)
+
+// Language is one entry of the language registry
+type Language struct {
+	Code       string // BCP-47 tag used throughout the app ("en", "zh", "jv")
+	Name       string // English name
+	NativeName string
+	Script     string // ISO 15924 ("Latn", "Cyrl", "Hans")
+	RTL        bool   // Written right to left
+
+	// Codes of provider code sets that differ from Code ("whisper": "jw")
+	codes map[string]string
+	// Other names providers report the language by ("castilian")
+	aliases []string
+}
+
+// Direction returns "rtl" or "ltr"
+func (l Language) Direction() string {
+	if l.RTL {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// registry holds every language Whisper can transcribe, which is every
+// language the pipeline can start from
+var registry = []Language{
+	{Code: "af", Name: "Afrikaans", NativeName: "Afrikaans", Script: "Latn"},
+	{Code: "am", Name: "Amharic", NativeName: "አማርኛ", Script: "Ethi"},
+	{Code: "ar", Name: "Arabic", NativeName: "العربية", Script: "Arab", RTL: true},
+	{Code: "as", Name: "Assamese", NativeName: "অসমীয়া", Script: "Beng"},
+	{Code: "az", Name: "Azerbaijani", NativeName: "Azərbaycanca", Script: "Latn"},
+	{Code: "ba", Name: "Bashkir", NativeName: "Башҡортса", Script: "Cyrl"},
+	{Code: "be", Name: "Belarusian", NativeName: "Беларуская", Script: "Cyrl"},
+	{Code: "bg", Name: "Bulgarian", NativeName: "Български", Script: "Cyrl"},
+	{Code: "bn", Name: "Bengali", NativeName: "বাংলা", Script: "Beng", aliases: []string{"bangla"}},
+	{Code: "bo", Name: "Tibetan", NativeName: "བོད་ཡིག", Script: "Tibt"},
+	{Code: "br", Name: "Breton", NativeName: "Brezhoneg", Script: "Latn"},
+	{Code: "bs", Name: "Bosnian", NativeName: "Bosanski", Script: "Latn"},
+	{Code: "ca", Name: "Catalan", NativeName: "Català", Script: "Latn", aliases: []string{"valencian"}},
+	{Code: "cs", Name: "Czech", NativeName: "Čeština", Script: "Latn"},
+	{Code: "cy", Name: "Welsh", NativeName: "Cymraeg", Script: "Latn"},
+	{Code: "da", Name: "Danish", NativeName: "Dansk", Script: "Latn"},
+	{Code: "de", Name: "German", NativeName: "Deutsch", Script: "Latn"},
+	{Code: "el", Name: "Greek", NativeName: "Ελληνικά", Script: "Grek"},
+	{Code: "en", Name: "English", NativeName: "English", Script: "Latn"},
+	{Code: "es", Name: "Spanish", NativeName: "Español", Script: "Latn", aliases: []string{"castilian"}},
+	{Code: "et", Name: "Estonian", NativeName: "Eesti", Script: "Latn"},
+	{Code: "eu", Name: "Basque", NativeName: "Euskara", Script: "Latn"},
+	{Code: "fa", Name: "Persian", NativeName: "فارسی", Script: "Arab", RTL: true, aliases: []string{"farsi"}},
+	{Code: "fi", Name: "Finnish", NativeName: "Suomi", Script: "Latn"},
+	{Code: "fo", Name: "Faroese", NativeName: "Føroyskt", Script: "Latn"},
+	{Code: "fr", Name: "French", NativeName: "Français", Script: "Latn"},
+	{Code: "gl", Name: "Galician", NativeName: "Galego", Script: "Latn"},
+	{Code: "gu", Name: "Gujarati", NativeName: "ગુજરાતી", Script: "Gujr"},
+	{Code: "ha", Name: "Hausa", NativeName: "Hausa", Script: "Latn"},
+	{Code: "haw", Name: "Hawaiian", NativeName: "ʻŌlelo Hawaiʻi", Script: "Latn"},
+	{Code: "he", Name: "Hebrew", NativeName: "עברית", Script: "Hebr", RTL: true},
+	{Code: "hi", Name: "Hindi", NativeName: "हिन्दी", Script: "Deva"},
+	{Code: "hr", Name: "Croatian", NativeName: "Hrvatski", Script: "Latn"},
+	{Code: "ht", Name: "Haitian Creole", NativeName: "Kreyòl ayisyen", Script: "Latn", aliases: []string{"haitian"}},
+	{Code: "hu", Name: "Hungarian", NativeName: "Magyar", Script: "Latn"},
+	{Code: "hy", Name: "Armenian", NativeName: "Հայերեն", Script: "Armn"},
+	{Code: "id", Name: "Indonesian", NativeName: "Bahasa Indonesia", Script: "Latn"},
+	{Code: "is", Name: "Icelandic", NativeName: "Íslenska", Script: "Latn"},
+	{Code: "it", Name: "Italian", NativeName: "Italiano", Script: "Latn"},
+	{Code: "ja", Name: "Japanese", NativeName: "日本語", Script: "Jpan"},
+	{Code: "jv", Name: "Javanese", NativeName: "Basa Jawa", Script: "Latn", codes: map[string]string{"whisper": "jw"}},
+	{Code: "ka", Name: "Georgian", NativeName: "ქართული", Script: "Geor"},
+	{Code: "kk", Name: "Kazakh", NativeName: "Қазақша", Script: "Cyrl"},
+	{Code: "km", Name: "Khmer", NativeName: "ខ្មែរ", Script: "Khmr"},
+	{Code: "kn", Name: "Kannada", NativeName: "ಕನ್ನಡ", Script: "Knda"},
+	{Code: "ko", Name: "Korean", NativeName: "한국어", Script: "Kore"},
+	{Code: "la", Name: "Latin", NativeName: "Latina", Script: "Latn"},
+	{Code: "lb", Name: "Luxembourgish", NativeName: "Lëtzebuergesch", Script: "Latn", aliases: []string{"letzeburgesch"}},
+	{Code: "ln", Name: "Lingala", NativeName: "Lingála", Script: "Latn"},
+	{Code: "lo", Name: "Lao", NativeName: "ລາວ", Script: "Laoo"},
+	{Code: "lt", Name: "Lithuanian", NativeName: "Lietuvių", Script: "Latn"},
+	{Code: "lv", Name: "Latvian", NativeName: "Latviešu", Script: "Latn"},
+	{Code: "mg", Name: "Malagasy", NativeName: "Malagasy", Script: "Latn"},
+	{Code: "mi", Name: "Maori", NativeName: "Te reo Māori", Script: "Latn"},
+	{Code: "mk", Name: "Macedonian", NativeName: "Македонски", Script: "Cyrl"},
+	{Code: "ml", Name: "Malayalam", NativeName: "മലയാളം", Script: "Mlym"},
+	{Code: "mn", Name: "Mongolian", NativeName: "Монгол", Script: "Cyrl"},
+	{Code: "mr", Name: "Marathi", NativeName: "मराठी", Script: "Deva"},
+	{Code: "ms", Name: "Malay", NativeName: "Bahasa Melayu", Script: "Latn"},
+	{Code: "mt", Name: "Maltese", NativeName: "Malti", Script: "Latn"},
+	{Code: "my", Name: "Burmese", NativeName: "မြန်မာ", Script: "Mymr", aliases: []string{"myanmar"}},
+	{Code: "ne", Name: "Nepali", NativeName: "नेपाली", Script: "Deva"},
+	{Code: "nl", Name: "Dutch", NativeName: "Nederlands", Script: "Latn", aliases: []string{"flemish"}},
+	{Code: "nn", Name: "Norwegian Nynorsk", NativeName: "Nynorsk", Script: "Latn", aliases: []string{"nynorsk"}},
+	{Code: "no", Name: "Norwegian", NativeName: "Norsk", Script: "Latn", codes: map[string]string{"argos": "nb"}, aliases: []string{"norwegian bokmål", "bokmål"}},
+	{Code: "oc", Name: "Occitan", NativeName: "Occitan", Script: "Latn"},
+	{Code: "pa", Name: "Punjabi", NativeName: "ਪੰਜਾਬੀ", Script: "Guru", aliases: []string{"panjabi"}},
+	{Code: "pl", Name: "Polish", NativeName: "Polski", Script: "Latn"},
+	{Code: "ps", Name: "Pashto", NativeName: "پښتو", Script: "Arab", RTL: true, aliases: []string{"pushto"}},
+	{Code: "pt", Name: "Portuguese", NativeName: "Português", Script: "Latn"},
+	{Code: "ro", Name: "Romanian", NativeName: "Română", Script: "Latn", aliases: []string{"moldavian", "moldovan"}},
+	{Code: "ru", Name: "Russian", NativeName: "Русский", Script: "Cyrl"},
+	{Code: "sa", Name: "Sanskrit", NativeName: "संस्कृतम्", Script: "Deva"},
+	{Code: "sd", Name: "Sindhi", NativeName: "سنڌي", Script: "Arab", RTL: true},
+	{Code: "si", Name: "Sinhala", NativeName: "සිංහල", Script: "Sinh", aliases: []string{"sinhalese"}},
+	{Code: "sk", Name: "Slovak", NativeName: "Slovenčina", Script: "Latn"},
+	{Code: "sl", Name: "Slovenian", NativeName: "Slovenščina", Script: "Latn"},
+	{Code: "sn", Name: "Shona", NativeName: "ChiShona", Script: "Latn"},
+	{Code: "so", Name: "Somali", NativeName: "Soomaali", Script: "Latn"},
+	{Code: "sq", Name: "Albanian", NativeName: "Shqip", Script: "Latn"},
+	{Code: "sr", Name: "Serbian", NativeName: "Српски", Script: "Cyrl"},
+	{Code: "su", Name: "Sundanese", NativeName: "Basa Sunda", Script: "Latn"},
+	{Code: "sv", Name: "Swedish", NativeName: "Svenska", Script: "Latn"},
+	{Code: "sw", Name: "Swahili", NativeName: "Kiswahili", Script: "Latn"},
+	{Code: "ta", Name: "Tamil", NativeName: "தமிழ்", Script: "Taml"},
+	{Code: "te", Name: "Telugu", NativeName: "తెలుగు", Script: "Telu"},
+	{Code: "tg", Name: "Tajik", NativeName: "Тоҷикӣ", Script: "Cyrl"},
+	{Code: "th", Name: "Thai", NativeName: "ไทย", Script: "Thai"},
+	{Code: "tk", Name: "Turkmen", NativeName: "Türkmençe", Script: "Latn"},
+	{Code: "tl", Name: "Tagalog", NativeName: "Tagalog", Script: "Latn", aliases: []string{"filipino"}},
+	{Code: "tr", Name: "Turkish", NativeName: "Türkçe", Script: "Latn"},
+	{Code: "tt", Name: "Tatar", NativeName: "Татарча", Script: "Cyrl"},
+	{Code: "uk", Name: "Ukrainian", NativeName: "Українська", Script: "Cyrl"},
+	{Code: "ur", Name: "Urdu", NativeName: "اردو", Script: "Arab", RTL: true},
+	{Code: "uz", Name: "Uzbek", NativeName: "Oʻzbekcha", Script: "Latn"},
+	{Code: "vi", Name: "Vietnamese", NativeName: "Tiếng Việt", Script: "Latn"},
+	{Code: "yi", Name: "Yiddish", NativeName: "ייִדיש", Script: "Hebr", RTL: true},
+	{Code: "yo", Name: "Yoruba", NativeName: "Yorùbá", Script: "Latn"},
+	{Code: "yue", Name: "Cantonese", NativeName: "粵語", Script: "Hant"},
+	{Code: "zh", Name: "Chinese", NativeName: "中文", Script: "Hans", codes: map[string]string{"libretranslate": "zh-Hans"}, aliases: []string{"mandarin"}},
+}
+
+// Code sets: providers that name languages differently share a set
+var providerCodeSets = map[string]map[string]string{
+	StageTranscription: {
+		"whisper-cpp": "whisper", "faster-whisper": "whisper", "whisperkit": "whisper",
+		"openai": "whisper", "groq": "whisper",
+	},
+	StageTranslation: {
+		"argos":          "argos",
+		"libretranslate": "libretranslate",
+	},
+}
+
+// argosLanguages are the languages with Argos Translate packages
+// (LibreTranslate serves the same models)
+var argosLanguages = []string{
+	"ar", "az", "bg", "bn", "ca", "cs", "da", "de", "el", "en", "es", "et", "eu",
+	"fa", "fi", "fr", "gl", "he", "hi", "hu", "id", "it", "ja", "ko", "lt", "lv",
+	"ms", "nl", "no", "pl", "pt", "ro", "ru", "sk", "sl", "sq", "sr", "sv", "th",
+	"tl", "tr", "uk", "ur", "vi", "zh",
+}
+
+// allLanguages marks a provider that handles every registered language
+var allLanguages = []string{"*"}
+
+// providerLanguages lists what each provider supports at each stage.
+// Providers with voice catalogs register theirs with SetProviderLanguages.
+var (
+	providerLanguagesMu sync.RWMutex
+	providerLanguages   = map[string]map[string][]string{
+		StageTranscription: {
+			"whisper-cpp": allLanguages, "faster-whisper": allLanguages, "whisperkit": allLanguages,
+			"openai": allLanguages, "groq": allLanguages,
+		},
+		StageTranslation: {
+			"openai": allLanguages, "deepseek": allLanguages, "grok": allLanguages,
+			"ollama": allLanguages, "lmstudio": allLanguages, "llama-cpp": allLanguages, "llm": allLanguages,
+			"argos":          argosLanguages,
+			"libretranslate": argosLanguages, // Servers may load fewer; checked when a job starts
+		},
+		StageTTS: {
+			"openai":     allLanguages, // Same languages as Whisper
+			"fish-audio": {"ar", "de", "en", "es", "fr", "it", "ja", "ko", "nl", "pl", "pt", "ru", "zh"},
+			"cosyvoice":  {"en", "ja", "ko", "yue", "zh"},
+		},
+	}
+)
+
+// Languages returns the registry sorted by English name
+func Languages() []Language {
+	langs := make([]Language, len(registry))
+	copy(langs, registry)
+	sort.Slice(langs, func(i, j int) bool { return langs[i].Name < langs[j].Name })
+	return langs
+}
+
+// LookupLanguage finds a language by code, by BCP-47 tag with region or
+// script ("en-US", "zh-Hans", "pt_BR"), by a provider's code ("jw", "nb")
+// or by name ("German", "deutsch", "castilian")
+func LookupLanguage(s string) (Language, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Language{}, false
+	}
+	primary := s
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		primary = s[:i]
+	}
+	for _, l := range registry {
+		if l.Code == s || l.Code == primary {
+			return l, true
+		}
+	}
+	for _, l := range registry {
+		for _, code := range l.codes {
+			if strings.ToLower(code) == s || code == primary {
+				return l, true
+			}
+		}
+	}
+	for _, l := range registry {
+		if strings.ToLower(l.Name) == s || strings.ToLower(l.NativeName) == s {
+			return l, true
+		}
+		for _, alias := range l.aliases {
+			if alias == s {
+				return l, true
+			}
+		}
+	}
+	return Language{}, false
+}
+
+// ProviderCode returns the code a provider uses for a language at a stage
+// (Whisper's "jw" for Javanese, Argos' "nb" for Norwegian); code itself
+// when the provider uses the registry's codes
+func ProviderCode(stage, provider, code string) string {
+	l, ok := LookupLanguage(code)
+	if !ok {
+		return code
+	}
+	if set := providerCodeSets[stage][provider]; set != "" {
+		if c, ok := l.codes[set]; ok {
+			return c
+		}
+	}
+	return l.Code
+}
+
+// SupportsLanguage reports whether provider handles code at stage.
+// Providers the registry doesn't know are not restricted.
+func SupportsLanguage(stage, provider, code string) bool {
+	l, ok := LookupLanguage(code)
+	if !ok {
+		return false
+	}
+	providerLanguagesMu.RLock()
+	defer providerLanguagesMu.RUnlock()
+	codes, known := providerLanguages[stage][provider]
+	if !known {
+		return true
+	}
+	for _, c := range codes {
+		if c == "*" || c == l.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvidersFor returns the known providers that handle code at stage
+func ProvidersFor(stage, code string) []string {
+	providerLanguagesMu.RLock()
+	providers := make([]string, 0, len(providerLanguages[stage]))
+	for provider := range providerLanguages[stage] {
+		providers = append(providers, provider)
+	}
+	providerLanguagesMu.RUnlock()
+
+	var result []string
+	for _, provider := range providers {
+		if SupportsLanguage(stage, provider, code) {
+			result = append(result, provider)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// LanguagesFor returns the languages provider handles at stage, sorted by
+// English name
+func LanguagesFor(stage, provider string) []Language {
+	var result []Language
+	for _, l := range Languages() {
+		if SupportsLanguage(stage, provider, l.Code) {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// SetProviderLanguages records the languages a provider supports at a
+// stage, for providers whose coverage follows their voice catalog. Codes
+// may carry a region ("en-US", "en_GB").
+func SetProviderLanguages(stage, provider string, codes []string) {
+	seen := make(map[string]bool)
+	var langs []string
+	for _, code := range codes {
+		if l, ok := LookupLanguage(code); ok && !seen[l.Code] {
+			seen[l.Code] = true
+			langs = append(langs, l.Code)
+		}
+	}
+	providerLanguagesMu.Lock()
+	defer providerLanguagesMu.Unlock()
+	if providerLanguages[stage] == nil {
+		providerLanguages[stage] = make(map[string][]string)
+	}
+	providerLanguages[stage][provider] = langs
+}
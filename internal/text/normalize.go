@@ -0,0 +1,784 @@
+package text
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// speechWord is a noun read after a number, with its plural forms
+// (singular, plural; Russian: 1, 2-4, 5+)
+type speechWord struct {
+	forms    []string
+	feminine bool // Russian "одна минута"
+}
+
+// speechRules spell out, for one language, what voices read badly:
+// numbers, dates, money, percentages, units, abbreviations and URLs
+type speechRules struct {
+	cardinal  func(n int64, feminine bool) string
+	year      func(n int64) string        // 4-digit years 1100-2099 after a yearWords word; nil = cardinal
+	yearWords map[string]bool             // Lowercase words that make the next number a year ("in", months)
+	ordinal   func(n int64) string        // "1st", "2nd" (nil = not written that way)
+	fraction  func(num, den int64) string // "1/2" up to tenths (nil = left as written)
+	// Years read by the word after them (Russian "году"); see ruYear
+	yearBefore func(n int64, next string) (spoken string, isYear bool)
+	date       func(day, month, year int64) string
+	plural     func(n int64, fraction bool) int // Index into speechWord.forms
+	decimal    string                           // Word for the decimal separator
+	fracDigits bool                             // Read decimals digit by digit
+	comma      bool                             // Decimal comma, "." groups thousands
+	spaceGroup bool                             // Plain spaces group thousands ("80 000")
+	dayFirst   bool                             // 15/03/2024 rather than 03/15/2024
+	dateSep    string                           // Separator of numeric dates
+	millionsOf string                           // Joins whole millions to money ("de")
+	and        string                           // Joins money to its subunit
+	apocope    func(string) string              // Shortens numbers before nouns
+	minus      string                           // Read for "-5"
+	plus       string                           // Read for a phone number's "+"
+
+	percent       speechWord
+	currencies    map[string]currency
+	units         map[string]speechWord
+	abbreviations map[string]string
+	dot, slash    string // Read inside URLs
+	at            string
+
+	numberRe, percentRe, moneyBeforeRe, moneyAfterRe *regexp.Regexp
+	unitRe, abbrevRe, localDateRe                    *regexp.Regexp
+}
+
+// currency is a symbol's name and the name of its hundredths (nil = none)
+type currency struct {
+	speechWord
+	cents *speechWord
+}
+
+func forms(feminine bool, f ...string) speechWord {
+	return speechWord{forms: f, feminine: feminine}
+}
+
+const (
+	currencySymbol = `([$€£¥₽]|USD|EUR|GBP|JPY|RUB)`
+	moneyScale     = `(k|K|M|B|mn|bn|million|billion|thousand)`
+)
+
+var (
+	isoDateRe  = regexp.MustCompile(`(\d{4})-(\d{1,2})-(\d{1,2})`)
+	ordinalRe  = regexp.MustCompile(`(\d+)(st|nd|rd|th)`)
+	fractionRe = regexp.MustCompile(`(\d{1,2})/(\d{1,2})`)
+	minusRe    = regexp.MustCompile(`(^|[\s(])[-−](\d)`)
+	phoneRe    = regexp.MustCompile(`(?:\+(\d{1,3})[ -]?)?(?:\((\d{2,4})\) ?|(\d{3})-)?(\d{3})-(\d{4})`)
+	emailRe    = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	urlRe      = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|org|net|io|dev|ai|app|co|edu|gov|info|tv|me|uk|de|fr|es|ru|eu)(?:/[^\s]*[^\s.,;:!?)])?/?`)
+)
+
+// yearJoiners continue a list of years: "in 2005 and 1900"
+var yearJoiners = map[string]bool{",": true, "-": true, "–": true, "and": true, "or": true, "to": true,
+	"und": true, "oder": true, "bis": true}
+
+var moneyScales = map[string]float64{
+	"k": 1e3, "K": 1e3, "thousand": 1e3,
+	"M": 1e6, "mn": 1e6, "million": 1e6,
+	"B": 1e9, "bn": 1e9, "billion": 1e9,
+}
+
+var currencyCodes = map[string]string{"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "RUB": "₽"}
+
+func pluralOneOther(n int64, fraction bool) int {
+	if n == 1 && !fraction {
+		return 0
+	}
+	return 1
+}
+
+func pluralFrench(n int64, _ bool) int {
+	if n < 2 {
+		return 0
+	}
+	return 1
+}
+
+func pluralRussian(n int64, fraction bool) int {
+	if fraction {
+		return 1
+	}
+	return ruPluralIndex(n)
+}
+
+var speechRulesByLang = map[string]*speechRules{
+	"en": {
+		cardinal: func(n int64, _ bool) string { return enCardinal(n) },
+		year:     enYear,
+		yearWords: yearWordSet(enMonths, "in", "since", "from", "until", "till", "before", "after", "during",
+			"year", "early", "late", "mid", "circa", "spring", "summer", "fall", "autumn", "winter"),
+		ordinal:  enOrdinal,
+		fraction: enFraction,
+		date: func(d, m, y int64) string {
+			return enMonths[m-1] + " " + enOrdinal(d) + ", " + enYear(y)
+		},
+		plural:     pluralOneOther,
+		decimal:    "point",
+		fracDigits: true,
+		dateSep:    "/",
+		and:        "and",
+		minus:      "minus",
+		plus:       "plus",
+		percent:    forms(false, "percent", "percent"),
+		currencies: map[string]currency{
+			"$": {forms(false, "dollar", "dollars"), &speechWord{forms: []string{"cent", "cents"}}},
+			"€": {forms(false, "euro", "euros"), &speechWord{forms: []string{"cent", "cents"}}},
+			"£": {forms(false, "pound", "pounds"), &speechWord{forms: []string{"penny", "pence"}}},
+			"¥": {forms(false, "yen", "yen"), nil},
+			"₽": {forms(false, "ruble", "rubles"), &speechWord{forms: []string{"kopeck", "kopecks"}}},
+		},
+		units: map[string]speechWord{
+			"km": forms(false, "kilometer", "kilometers"), "m": forms(false, "meter", "meters"),
+			"cm": forms(false, "centimeter", "centimeters"), "mm": forms(false, "millimeter", "millimeters"),
+			"kg": forms(false, "kilogram", "kilograms"), "g": forms(false, "gram", "grams"),
+			"mg": forms(false, "milligram", "milligrams"), "l": forms(false, "liter", "liters"),
+			"L": forms(false, "liter", "liters"), "ml": forms(false, "milliliter", "milliliters"),
+			"km/h": forms(false, "kilometer per hour", "kilometers per hour"),
+			"mph":  forms(false, "mile per hour", "miles per hour"),
+			"°C":   forms(false, "degree Celsius", "degrees Celsius"), "°F": forms(false, "degree Fahrenheit", "degrees Fahrenheit"),
+			"GB": forms(false, "gigabyte", "gigabytes"), "MB": forms(false, "megabyte", "megabytes"),
+			"TB": forms(false, "terabyte", "terabytes"), "kWh": forms(false, "kilowatt-hour", "kilowatt-hours"),
+			"kW": forms(false, "kilowatt", "kilowatts"), "h": forms(false, "hour", "hours"),
+			"min": forms(false, "minute", "minutes"),
+		},
+		abbreviations: map[string]string{
+			"Dr.": "Doctor", "Mr.": "Mister", "Mrs.": "Missus", "Ms.": "Miz", "Prof.": "Professor",
+			"Jr.": "Junior", "Sr.": "Senior", "e.g.": "for example", "i.e.": "that is", "etc.": "et cetera",
+			"vs.": "versus", "approx.": "approximately",
+		},
+		dot: "dot", slash: "slash", at: "at",
+	},
+	"de": {
+		cardinal: func(n int64, _ bool) string { return deCardinal(n) },
+		year:     deYear,
+		yearWords: yearWordSet(deMonths, "im", "jahr", "jahre", "jahres", "seit", "bis", "von", "ab", "anno",
+			"zwischen", "frühjahr", "sommer", "herbst", "winter"),
+		fraction: deFraction,
+		date: func(d, m, y int64) string {
+			return deOrdinalDative(d) + " " + deMonths[m-1] + " " + deYear(y)
+		},
+		plural:   pluralOneOther,
+		decimal:  "Komma",
+		comma:    true,
+		dayFirst: true,
+		dateSep:  ".",
+		and:      "und",
+		minus:    "minus",
+		plus:     "plus",
+		percent:  forms(false, "Prozent", "Prozent"),
+		currencies: map[string]currency{
+			"$": {forms(false, "Dollar", "Dollar"), &speechWord{forms: []string{"Cent", "Cent"}}},
+			"€": {forms(false, "Euro", "Euro"), &speechWord{forms: []string{"Cent", "Cent"}}},
+			"£": {forms(false, "Pfund", "Pfund"), &speechWord{forms: []string{"Penny", "Pence"}}},
+			"¥": {forms(false, "Yen", "Yen"), nil},
+			"₽": {forms(false, "Rubel", "Rubel"), &speechWord{forms: []string{"Kopeke", "Kopeken"}}},
+		},
+		units: map[string]speechWord{
+			"km": forms(false, "Kilometer", "Kilometer"), "m": forms(false, "Meter", "Meter"),
+			"cm": forms(false, "Zentimeter", "Zentimeter"), "mm": forms(false, "Millimeter", "Millimeter"),
+			"kg": forms(false, "Kilogramm", "Kilogramm"), "g": forms(false, "Gramm", "Gramm"),
+			"mg": forms(false, "Milligramm", "Milligramm"), "l": forms(false, "Liter", "Liter"),
+			"L": forms(false, "Liter", "Liter"), "ml": forms(false, "Milliliter", "Milliliter"),
+			"km/h": forms(false, "Kilometer pro Stunde", "Kilometer pro Stunde"),
+			"mph":  forms(false, "Meile pro Stunde", "Meilen pro Stunde"),
+			"°C":   forms(false, "Grad Celsius", "Grad Celsius"), "°F": forms(false, "Grad Fahrenheit", "Grad Fahrenheit"),
+			"GB": forms(false, "Gigabyte", "Gigabyte"), "MB": forms(false, "Megabyte", "Megabyte"),
+			"TB": forms(false, "Terabyte", "Terabyte"), "kWh": forms(false, "Kilowattstunde", "Kilowattstunden"),
+			"kW": forms(false, "Kilowatt", "Kilowatt"), "h": forms(false, "Stunde", "Stunden"),
+			"min": forms(false, "Minute", "Minuten"),
+		},
+		abbreviations: map[string]string{
+			"Dr.": "Doktor", "Prof.": "Professor", "z.B.": "zum Beispiel", "z. B.": "zum Beispiel",
+			"bzw.": "beziehungsweise", "usw.": "und so weiter", "d.h.": "das heißt", "d. h.": "das heißt",
+			"ca.": "circa", "Nr.": "Nummer", "evtl.": "eventuell", "inkl.": "inklusive",
+		},
+		dot: "Punkt", slash: "Schrägstrich", at: "at",
+	},
+	"es": {
+		cardinal: func(n int64, _ bool) string { return esCardinal(n) },
+		fraction: esFraction,
+		date: func(d, m, y int64) string {
+			day := esCardinal(d)
+			if d == 1 {
+				day = "primero"
+			}
+			return day + " de " + esMonths[m-1] + " de " + esCardinal(y)
+		},
+		plural:     pluralOneOther,
+		decimal:    "coma",
+		comma:      true,
+		dayFirst:   true,
+		dateSep:    "/",
+		millionsOf: "de",
+		and:        "con",
+		apocope:    esApocope,
+		minus:      "menos",
+		plus:       "más",
+		percent:    forms(false, "por ciento", "por ciento"),
+		currencies: map[string]currency{
+			"$": {forms(false, "dólar", "dólares"), &speechWord{forms: []string{"centavo", "centavos"}}},
+			"€": {forms(false, "euro", "euros"), &speechWord{forms: []string{"céntimo", "céntimos"}}},
+			"£": {forms(false, "libra", "libras"), &speechWord{forms: []string{"penique", "peniques"}}},
+			"¥": {forms(false, "yen", "yenes"), nil},
+			"₽": {forms(false, "rublo", "rublos"), &speechWord{forms: []string{"kopek", "kopeks"}}},
+		},
+		units: map[string]speechWord{
+			"km": forms(false, "kilómetro", "kilómetros"), "m": forms(false, "metro", "metros"),
+			"cm": forms(false, "centímetro", "centímetros"), "mm": forms(false, "milímetro", "milímetros"),
+			"kg": forms(false, "kilogramo", "kilogramos"), "g": forms(false, "gramo", "gramos"),
+			"mg": forms(false, "miligramo", "miligramos"), "l": forms(false, "litro", "litros"),
+			"L": forms(false, "litro", "litros"), "ml": forms(false, "mililitro", "mililitros"),
+			"km/h": forms(false, "kilómetro por hora", "kilómetros por hora"),
+			"mph":  forms(false, "milla por hora", "millas por hora"),
+			"°C":   forms(false, "grado Celsius", "grados Celsius"), "°F": forms(false, "grado Fahrenheit", "grados Fahrenheit"),
+			"GB": forms(false, "gigabyte", "gigabytes"), "MB": forms(false, "megabyte", "megabytes"),
+			"TB": forms(false, "terabyte", "terabytes"), "kWh": forms(false, "kilovatio hora", "kilovatios hora"),
+			"kW": forms(false, "kilovatio", "kilovatios"), "h": forms(false, "hora", "horas"),
+			"min": forms(false, "minuto", "minutos"),
+		},
+		abbreviations: map[string]string{
+			"Dr.": "doctor", "Dra.": "doctora", "Sr.": "señor", "Sra.": "señora", "Srta.": "señorita",
+			"p. ej.": "por ejemplo", "etc.": "etcétera", "aprox.": "aproximadamente", "núm.": "número",
+		},
+		dot: "punto", slash: "barra", at: "arroba",
+	},
+	"fr": {
+		cardinal: func(n int64, _ bool) string { return frCardinal(n) },
+		fraction: frFraction,
+		date: func(d, m, y int64) string {
+			day := frCardinal(d)
+			if d == 1 {
+				day = "premier"
+			}
+			return day + " " + frMonths[m-1] + " " + frCardinal(y)
+		},
+		plural:     pluralFrench,
+		decimal:    "virgule",
+		comma:      true,
+		dayFirst:   true,
+		dateSep:    "/",
+		spaceGroup: true,
+		millionsOf: "de",
+		and:        "et",
+		minus:      "moins",
+		plus:       "plus",
+		percent:    forms(false, "pour cent", "pour cent"),
+		currencies: map[string]currency{
+			"$": {forms(false, "dollar", "dollars"), &speechWord{forms: []string{"cent", "cents"}}},
+			"€": {forms(false, "euro", "euros"), &speechWord{forms: []string{"centime", "centimes"}}},
+			"£": {forms(false, "livre", "livres"), &speechWord{forms: []string{"penny", "pence"}}},
+			"¥": {forms(false, "yen", "yens"), nil},
+			"₽": {forms(false, "rouble", "roubles"), &speechWord{forms: []string{"kopeck", "kopecks"}}},
+		},
+		units: map[string]speechWord{
+			"km": forms(false, "kilomètre", "kilomètres"), "m": forms(false, "mètre", "mètres"),
+			"cm": forms(false, "centimètre", "centimètres"), "mm": forms(false, "millimètre", "millimètres"),
+			"kg": forms(false, "kilogramme", "kilogrammes"), "g": forms(false, "gramme", "grammes"),
+			"mg": forms(false, "milligramme", "milligrammes"), "l": forms(false, "litre", "litres"),
+			"L": forms(false, "litre", "litres"), "ml": forms(false, "millilitre", "millilitres"),
+			"km/h": forms(false, "kilomètre heure", "kilomètres heure"),
+			"mph":  forms(false, "mile par heure", "miles par heure"),
+			"°C":   forms(false, "degré Celsius", "degrés Celsius"), "°F": forms(false, "degré Fahrenheit", "degrés Fahrenheit"),
+			"Go": forms(false, "gigaoctet", "gigaoctets"), "Mo": forms(false, "mégaoctet", "mégaoctets"),
+			"To": forms(false, "téraoctet", "téraoctets"), "GB": forms(false, "gigaoctet", "gigaoctets"),
+			"MB": forms(false, "mégaoctet", "mégaoctets"), "kWh": forms(false, "kilowattheure", "kilowattheures"),
+			"kW": forms(false, "kilowatt", "kilowatts"), "h": forms(false, "heure", "heures"),
+			"min": forms(false, "minute", "minutes"),
+		},
+		abbreviations: map[string]string{
+			"M.": "Monsieur", "Mme": "Madame", "Mlle": "Mademoiselle", "Dr": "docteur",
+			"etc.": "et cetera", "p. ex.": "par exemple", "env.": "environ", "n°": "numéro",
+		},
+		dot: "point", slash: "slash", at: "arobase",
+	},
+	"ru": {
+		cardinal:   ruCardinal,
+		yearBefore: ruYear,
+		date: func(d, m, y int64) string {
+			return ruOrdinal(d, "ое") + " " + ruMonthsGenitive[m-1] + " " + ruOrdinal(y, "ого") + " года"
+		},
+		plural:     pluralRussian,
+		decimal:    "запятая",
+		comma:      true,
+		dayFirst:   true,
+		dateSep:    ".",
+		spaceGroup: true,
+		minus:      "минус",
+		plus:       "плюс",
+		percent:    forms(false, "процент", "процента", "процентов"),
+		currencies: map[string]currency{
+			"$": {forms(false, "доллар", "доллара", "долларов"), &speechWord{forms: []string{"цент", "цента", "центов"}}},
+			"€": {forms(false, "евро", "евро", "евро"), &speechWord{forms: []string{"цент", "цента", "центов"}}},
+			"£": {forms(false, "фунт", "фунта", "фунтов"), &speechWord{forms: []string{"пенс", "пенса", "пенсов"}}},
+			"¥": {forms(true, "иена", "иены", "иен"), nil},
+			"₽": {forms(false, "рубль", "рубля", "рублей"), &speechWord{forms: []string{"копейка", "копейки", "копеек"}, feminine: true}},
+		},
+		units: map[string]speechWord{
+			"км": forms(false, "километр", "километра", "километров"), "м": forms(false, "метр", "метра", "метров"),
+			"см": forms(false, "сантиметр", "сантиметра", "сантиметров"), "мм": forms(false, "миллиметр", "миллиметра", "миллиметров"),
+			"кг": forms(false, "килограмм", "килограмма", "килограммов"), "г": forms(false, "грамм", "грамма", "граммов"),
+			"мг": forms(false, "миллиграмм", "миллиграмма", "миллиграммов"), "л": forms(false, "литр", "литра", "литров"),
+			"мл":   forms(false, "миллилитр", "миллилитра", "миллилитров"),
+			"км/ч": forms(false, "километр в час", "километра в час", "километров в час"),
+			"°C":   forms(false, "градус Цельсия", "градуса Цельсия", "градусов Цельсия"),
+			"°F":   forms(false, "градус Фаренгейта", "градуса Фаренгейта", "градусов Фаренгейта"),
+			"ГБ":   forms(false, "гигабайт", "гигабайта", "гигабайт"), "МБ": forms(false, "мегабайт", "мегабайта", "мегабайт"),
+			"ТБ":    forms(false, "терабайт", "терабайта", "терабайт"),
+			"кВт·ч": forms(false, "киловатт-час", "киловатт-часа", "киловатт-часов"),
+			"кВт":   forms(false, "киловатт", "киловатта", "киловатт"), "ч": forms(false, "час", "часа", "часов"),
+			"мин": forms(true, "минута", "минуты", "минут"),
+		},
+		abbreviations: map[string]string{
+			"т.е.": "то есть", "т. е.": "то есть", "т.д.": "так далее", "т. д.": "так далее",
+			"т.п.": "тому подобное", "т. п.": "тому подобное", "напр.": "например", "др.": "другие",
+		},
+		dot: "точка", slash: "слэш", at: "собака",
+	},
+}
+
+var (
+	enMonths = []string{"January", "February", "March", "April", "May", "June", "July",
+		"August", "September", "October", "November", "December"}
+	deMonths = []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli",
+		"August", "September", "Oktober", "November", "Dezember"}
+	esMonths = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+		"agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	frMonths = []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+		"août", "septembre", "octobre", "novembre", "décembre"}
+	ruMonthsGenitive = []string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля",
+		"августа", "сентября", "октября", "ноября", "декабря"}
+)
+
+func init() {
+	for _, r := range speechRulesByLang {
+		number := `\d+(?:[.,\x{00a0}\x{202f}]\d+)*`
+		if r.spaceGroup {
+			number = `\d+(?:[.,\x{00a0}\x{202f}]\d+| \d{3})*`
+		}
+		r.numberRe = regexp.MustCompile(number)
+		r.percentRe = regexp.MustCompile(`(` + number + `)\s?%`)
+		r.moneyBeforeRe = regexp.MustCompile(currencySymbol + `\s?(` + number + `)(?:\s?` + moneyScale + `)?`)
+		r.moneyAfterRe = regexp.MustCompile(`(` + number + `)(?:\s?` + moneyScale + `)?\s?` + currencySymbol)
+		r.unitRe = regexp.MustCompile(`(` + number + `)\s?(` + alternation(keys(r.units)) + `)`)
+		r.abbrevRe = regexp.MustCompile(alternation(keys(r.abbreviations)))
+		sep := regexp.QuoteMeta(r.dateSep)
+		r.localDateRe = regexp.MustCompile(`(\d{1,2})` + sep + `(\d{1,2})` + sep + `(\d{4})`)
+	}
+}
+
+// yearWordSet builds a yearWords set from the month names and ws
+func yearWordSet(months []string, ws ...string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range append(ws, months...) {
+		set[strings.ToLower(w)] = true
+	}
+	return set
+}
+
+func keys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// alternation matches any of words, longest first so "km/h" wins over "km"
+func alternation(words []string) string {
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) > len(words[j])
+		}
+		return words[i] < words[j]
+	})
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// HasSpeechRules reports whether NormalizeForSpeech rewrites lang
+func HasSpeechRules(lang string) bool {
+	_, ok := speechRulesByLang[primaryTag(lang)]
+	return ok
+}
+
+func primaryTag(lang string) string {
+	if l, ok := LookupLanguage(lang); ok {
+		return l.Code
+	}
+	return strings.ToLower(lang)
+}
+
+// NormalizeForSpeech rewrites s the way a voice should read it in lang:
+// URLs and e-mail addresses, abbreviations ("Dr."), dates, phone numbers,
+// signs, money ("$5M"), percentages, units ("5 km"), ordinals, fractions
+// and years are spelled out, then the remaining numbers. A number is only
+// read as a year after a word that sets up a date ("in 1985", "March
+// 1985"); forms a language's rules can't inflect are left as digits.
+// Languages without rules are returned unchanged.
+func NormalizeForSpeech(s, lang string) string {
+	r, ok := speechRulesByLang[primaryTag(lang)]
+	if !ok || s == "" {
+		return s
+	}
+	s = replaceStandalone(s, emailRe, func(g []string) (string, bool) { return r.spellAddress(g[0]), true })
+	s = replaceStandalone(s, urlRe, func(g []string) (string, bool) { return r.spellAddress(g[0]), true })
+	s = replaceStandalone(s, r.abbrevRe, func(g []string) (string, bool) { return r.abbreviations[g[0]], true })
+	s = replaceStandalone(s, isoDateRe, func(g []string) (string, bool) { return r.spellDate(g[3], g[2], g[1]) })
+	s = replaceStandalone(s, r.localDateRe, func(g []string) (string, bool) {
+		if r.dayFirst {
+			return r.spellDate(g[1], g[2], g[3])
+		}
+		return r.spellDate(g[2], g[1], g[3])
+	})
+	s = replaceStandalone(s, phoneRe, func(g []string) (string, bool) { return r.spellPhone(g[1:]), true })
+	s = minusRe.ReplaceAllString(s, "${1}"+r.minus+" ${2}")
+	s = replaceStandalone(s, r.moneyBeforeRe, func(g []string) (string, bool) { return r.spellMoney(g[2], g[3], g[1]) })
+	s = replaceStandalone(s, r.moneyAfterRe, func(g []string) (string, bool) { return r.spellMoney(g[1], g[2], g[3]) })
+	s = replaceStandalone(s, r.percentRe, func(g []string) (string, bool) { return r.spellCounted(g[1], r.percent) })
+	s = replaceStandaloneAt(s, r.unitRe, func(loc []int) (string, bool) {
+		if _, isYear := r.yearByNextWord(s, loc[2], loc[3]); isYear {
+			return "", false // "1999 г." is a year, not grams
+		}
+		return r.spellCounted(s[loc[2]:loc[3]], r.units[s[loc[4]:loc[5]]])
+	})
+	if r.ordinal != nil {
+		s = replaceStandalone(s, ordinalRe, func(g []string) (string, bool) {
+			n, err := strconv.ParseInt(g[1], 10, 64)
+			if err != nil || n > 1e12 {
+				return "", false
+			}
+			return r.ordinal(n), true
+		})
+	}
+	if r.fraction != nil {
+		s = replaceStandalone(s, fractionRe, func(g []string) (string, bool) {
+			num, _ := strconv.ParseInt(g[1], 10, 64)
+			den, _ := strconv.ParseInt(g[2], 10, 64)
+			if num < 1 || num >= den || den > 10 {
+				return "", false // "24/7", "9/11"
+			}
+			return r.fraction(num, den), true
+		})
+	}
+	lastYear := -1 // End of the last number read as a year
+	return replaceStandaloneAt(s, r.numberRe, func(loc []int) (string, bool) {
+		tok := s[loc[0]:loc[1]]
+		if spoken, isYear := r.yearByNextWord(s, loc[0], loc[1]); isYear {
+			return spoken, spoken != ""
+		}
+		if n, ok := yearNumber(tok); ok && r.year != nil && r.yearContext(s, loc[0], lastYear) {
+			lastYear = loc[1]
+			return r.year(n), true
+		}
+		return r.spellNumber(tok, false)
+	})
+}
+
+// yearNumber parses a 4-digit number that can be read as a year
+func yearNumber(tok string) (int64, bool) {
+	n, err := strconv.ParseInt(tok, 10, 64)
+	return n, err == nil && len(tok) == 4 && n >= 1100 && n <= 2099
+}
+
+// yearByNextWord reads the number s[start:end] with r.yearBefore; isYear
+// with spoken "" means a year to leave as digits
+func (r *speechRules) yearByNextWord(s string, start, end int) (spoken string, isYear bool) {
+	if r.yearBefore == nil {
+		return "", false
+	}
+	n, ok := yearNumber(s[start:end])
+	if !ok {
+		return "", false
+	}
+	return r.yearBefore(n, nextWord(s[end:]))
+}
+
+// yearContext reports whether the number at start follows a word that
+// sets up a year, or continues a list of years ending at lastYear
+func (r *speechRules) yearContext(s string, start, lastYear int) bool {
+	if lastYear >= 0 && yearJoiners[strings.TrimSpace(s[lastYear:start])] {
+		return true
+	}
+	before := strings.TrimRightFunc(s[:start], unicode.IsSpace)
+	if len(before) == len(s[:start]) {
+		return false // Glued to punctuation: "(1985)", "#1985"
+	}
+	i := strings.LastIndexFunc(before, func(c rune) bool { return !unicode.IsLetter(c) })
+	return r.yearWords[strings.ToLower(before[i+1:])]
+}
+
+// nextWord returns the word after s's leading spaces with a period that
+// ends it ("году", "г.")
+func nextWord(s string) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	i := strings.IndexFunc(s, func(c rune) bool { return !unicode.IsLetter(c) })
+	if i < 0 {
+		return s
+	}
+	if s[i] == '.' {
+		i++
+	}
+	return s[:i]
+}
+
+// replaceStandalone replaces matches of re that aren't glued to letters or
+// digits (so "Web3" and "MP3" stay) nor part of a longer number ("1.2.3",
+// "10:30", "1/2/3"). fn returns false to leave a match as it is.
+func replaceStandalone(s string, re *regexp.Regexp, fn func(groups []string) (string, bool)) string {
+	return replaceStandaloneAt(s, re, func(loc []int) (string, bool) {
+		groups := make([]string, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] >= 0 {
+				groups[i] = s[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		return fn(groups)
+	})
+}
+
+// replaceStandaloneAt is replaceStandalone for callers that need to look
+// around the match: fn gets its submatch indexes into s
+func replaceStandaloneAt(s string, re *regexp.Regexp, fn func(loc []int) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		if !standalone(s, loc[0], loc[1]) {
+			continue
+		}
+		repl, ok := fn(loc)
+		if !ok {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(repl)
+		last = loc[1]
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func standalone(s string, start, end int) bool {
+	if start > 0 {
+		before, size := utf8.DecodeLastRuneInString(s[:start])
+		if isWordRune(before) {
+			return false
+		}
+		if strings.ContainsRune(".,:/", before) && start-size > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(s[:start-size]); unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	if end < len(s) {
+		after, size := utf8.DecodeRuneInString(s[end:])
+		if isWordRune(after) {
+			return false
+		}
+		if strings.ContainsRune(".,:/", after) && end+size < len(s) {
+			if r, _ := utf8.DecodeRuneInString(s[end+size:]); unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// parseNumber splits a written number into its whole part and decimal
+// digits, reading separators the language's way. ok is false for things
+// that only look like numbers ("1.2.3", "12,34,5").
+func (r *speechRules) parseNumber(tok string) (whole int64, frac string, ok bool) {
+	decimal, group := ".", ","
+	if r.comma {
+		decimal, group = ",", "."
+	}
+	intPart := tok
+	if strings.Count(tok, decimal) > 1 {
+		return 0, "", false
+	}
+	if i := strings.LastIndex(tok, decimal); i >= 0 {
+		intPart, frac = tok[:i], tok[i+1:]
+	} else if i := strings.LastIndex(tok, group); i >= 0 && strings.Count(tok, group) == 1 && len(tok)-i-1 != 3 {
+		// "3.5" in German text: a decimal point, not thousands
+		intPart, frac = tok[:i], tok[i+1:]
+	}
+
+	// Every group after the first must have three digits
+	groups := strings.FieldsFunc(intPart, func(c rune) bool { return !unicode.IsDigit(c) })
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 {
+			return 0, "", false
+		}
+	}
+	digits := strings.Join(groups, "")
+	if digits == "" || len(digits) > 15 || strings.ContainsFunc(frac, func(c rune) bool { return !unicode.IsDigit(c) }) {
+		return 0, "", false
+	}
+	whole, err := strconv.ParseInt(digits, 10, 64)
+	return whole, frac, err == nil
+}
+
+// spellNumber reads a written number; feminine applies to Russian
+func (r *speechRules) spellNumber(tok string, feminine bool) (string, bool) {
+	whole, frac, ok := r.parseNumber(tok)
+	if !ok {
+		return "", false
+	}
+	return r.spellParsed(whole, frac, feminine), true
+}
+
+func (r *speechRules) spellParsed(whole int64, frac string, feminine bool) string {
+	if frac == "" {
+		return r.cardinal(whole, feminine)
+	}
+	s := r.cardinal(whole, false) + " " + r.decimal
+	if r.fracDigits {
+		for _, d := range frac {
+			s += " " + r.cardinal(int64(d-'0'), false)
+		}
+		return s
+	}
+	// Leading zeros one by one, then the rest as a number ("coma cero cinco")
+	trimmed := strings.TrimLeft(frac, "0")
+	for range len(frac) - len(trimmed) {
+		s += " " + r.cardinal(0, false)
+	}
+	if trimmed != "" {
+		n, _ := strconv.ParseInt(trimmed, 10, 64)
+		s += " " + r.cardinal(n, false)
+	}
+	return s
+}
+
+// spellCounted reads a number and the word it counts ("5 km")
+func (r *speechRules) spellCounted(tok string, w speechWord) (string, bool) {
+	whole, frac, ok := r.parseNumber(tok)
+	if !ok {
+		return "", false
+	}
+	number := r.spellParsed(whole, frac, w.feminine)
+	if r.apocope != nil && frac == "" {
+		number = r.apocope(number) // "un kilogramo"
+	}
+	return number + " " + r.form(w, whole, frac != ""), true
+}
+
+func (r *speechRules) form(w speechWord, n int64, fraction bool) string {
+	return w.forms[min(r.plural(n, fraction), len(w.forms)-1)]
+}
+
+// spellMoney reads an amount with an optional scale ("$5M") in a currency
+// given by symbol or ISO code
+func (r *speechRules) spellMoney(tok, scale, symbol string) (string, bool) {
+	if code, ok := currencyCodes[symbol]; ok {
+		symbol = code
+	}
+	cur, ok := r.currencies[symbol]
+	if !ok {
+		return "", false
+	}
+	whole, frac, ok := r.parseNumber(tok)
+	if !ok {
+		return "", false
+	}
+
+	if factor, ok := moneyScales[scale]; ok {
+		value, _ := strconv.ParseFloat(strconv.FormatInt(whole, 10)+"."+frac+"0", 64)
+		whole, frac = int64(math.Round(value*factor)), ""
+	}
+	amount := r.cardinal(whole, cur.feminine)
+	if r.millionsOf != "" && whole >= 1e6 && whole%1e6 == 0 {
+		amount += " " + r.millionsOf
+	}
+	if r.apocope != nil {
+		amount = r.apocope(amount) // "veintiún dólares"
+	}
+
+	if frac == "" || strings.Trim(frac, "0") == "" {
+		return amount + " " + r.form(cur.speechWord, whole, false), true
+	}
+	if cur.cents == nil || len(frac) > 2 {
+		return r.spellParsed(whole, frac, false) + " " + r.form(cur.speechWord, whole, true), true
+	}
+	if len(frac) == 1 {
+		frac += "0"
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+	centWords := r.cardinal(cents, cur.cents.feminine)
+	if r.apocope != nil {
+		centWords = r.apocope(centWords)
+	}
+	s := centWords + " " + r.form(*cur.cents, cents, false)
+	if whole == 0 {
+		return s, true
+	}
+	parts := []string{amount, r.form(cur.speechWord, whole, false)}
+	if r.and != "" {
+		parts = append(parts, r.and)
+	}
+	return strings.Join(append(parts, s), " "), true
+}
+
+// spellDate reads a numeric date; ok is false when it isn't a valid one
+func (r *speechRules) spellDate(day, month, year string) (string, bool) {
+	d, err1 := strconv.ParseInt(day, 10, 64)
+	m, err2 := strconv.ParseInt(month, 10, 64)
+	y, err3 := strconv.ParseInt(year, 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || m < 1 || m > 12 || d < 1 || d > 31 || r.date == nil {
+		return "", false
+	}
+	return r.date(d, m, y), true
+}
+
+// spellPhone reads a phone number's groups (country code, area code in
+// parentheses or not, then the rest) digit by digit, pausing between them
+func (r *speechRules) spellPhone(groups []string) string {
+	var parts []string
+	for i, g := range groups {
+		if g == "" {
+			continue
+		}
+		digits := make([]string, 0, len(g))
+		for _, d := range g {
+			digits = append(digits, r.cardinal(int64(d-'0'), false))
+		}
+		part := strings.Join(digits, " ")
+		if i == 0 {
+			part = r.plus + " " + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// spellAddress reads a URL or e-mail address: "example.com/docs" becomes
+// "example dot com slash docs"
+func (r *speechRules) spellAddress(addr string) string {
+	lower := strings.ToLower(addr)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		if strings.HasPrefix(lower, prefix) {
+			addr, lower = addr[len(prefix):], lower[len(prefix):]
+		}
+	}
+	addr = strings.TrimSuffix(addr, "/")
+	replacer := strings.NewReplacer(".", " "+r.dot+" ", "/", " "+r.slash+" ", "@", " "+r.at+" ")
+	return strings.Join(strings.Fields(replacer.Replace(addr)), " ")
+}
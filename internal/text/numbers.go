@@ -0,0 +1,545 @@
+package text
+
+import "strings"
+
+// Number words for the languages NormalizeForSpeech has rules for. Each
+// cardinal function spells out n >= 0; callers keep n below 10^15.
+
+type numberScale struct {
+	value int64
+	name  string
+}
+
+// spellScaled spells n with the largest scale that fits: head, scale name
+// and the rest ("two million five")
+func spellScaled(n int64, scales []numberScale, spell func(int64) string) string {
+	for _, sc := range scales {
+		if n >= sc.value {
+			s := spell(n/sc.value) + " " + sc.name
+			if rest := n % sc.value; rest > 0 {
+				s += " " + spell(rest)
+			}
+			return s
+		}
+	}
+	return spell(n)
+}
+
+// English
+
+var enOnes = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+var enTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+var enScales = []numberScale{{1e12, "trillion"}, {1e9, "billion"}, {1e6, "million"}, {1e3, "thousand"}}
+
+func enCardinal(n int64) string {
+	switch {
+	case n < 20:
+		return enOnes[n]
+	case n < 100:
+		s := enTens[n/10]
+		if n%10 > 0 {
+			s += "-" + enOnes[n%10]
+		}
+		return s
+	case n < 1000:
+		s := enOnes[n/100] + " hundred"
+		if n%100 > 0 {
+			s += " " + enCardinal(n%100)
+		}
+		return s
+	}
+	return spellScaled(n, enScales, enCardinal)
+}
+
+// enOrdinal turns the last word of the cardinal into an ordinal
+func enOrdinal(n int64) string {
+	s := enCardinal(n)
+	i := strings.LastIndexAny(s, " -") + 1
+	head, last := s[:i], s[i:]
+	switch last {
+	case "one":
+		last = "first"
+	case "two":
+		last = "second"
+	case "three":
+		last = "third"
+	case "five":
+		last = "fifth"
+	case "eight":
+		last = "eighth"
+	case "nine":
+		last = "ninth"
+	case "twelve":
+		last = "twelfth"
+	default:
+		if strings.HasSuffix(last, "y") {
+			last = strings.TrimSuffix(last, "y") + "ieth"
+		} else {
+			last += "th"
+		}
+	}
+	return head + last
+}
+
+// enYear reads years in pairs: "nineteen eighty-four", "twenty twenty-four",
+// but "two thousand five"
+func enYear(n int64) string {
+	hi, lo := n/100, n%100
+	switch {
+	case n >= 2000 && n < 2010:
+		return enCardinal(n)
+	case lo == 0:
+		return enCardinal(hi) + " hundred"
+	case lo < 10:
+		return enCardinal(hi) + " oh " + enCardinal(lo)
+	}
+	return enCardinal(hi) + " " + enCardinal(lo)
+}
+
+// enFraction reads a simple fraction: "one half", "three quarters",
+// "two thirds"
+func enFraction(num, den int64) string {
+	var word string
+	switch den {
+	case 2:
+		word = "half"
+	case 4:
+		word = "quarter"
+	default:
+		word = enOrdinal(den)
+	}
+	if num > 1 {
+		word += "s"
+	}
+	return enCardinal(num) + " " + word
+}
+
+// German: compounds below a million are written as one word
+
+var deOnes = []string{"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun",
+	"zehn", "elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn"}
+var deTens = []string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig", "sechzig", "siebzig", "achtzig", "neunzig"}
+
+// deUnder1000 spells 1..999; final is false inside compounds ("ein" not
+// "eins": "eintausend")
+func deUnder1000(n int64, final bool) string {
+	var s string
+	if h := n / 100; h > 0 {
+		s = deCompoundUnit(h) + "hundert"
+	}
+	switch r := n % 100; {
+	case r == 0:
+	case r == 1 && final:
+		s += "eins"
+	case r < 20:
+		s += deCompoundUnit(r)
+	default:
+		if u := r % 10; u > 0 {
+			s += deCompoundUnit(u) + "und"
+		}
+		s += deTens[r/10]
+	}
+	return s
+}
+
+func deCompoundUnit(n int64) string {
+	if n == 1 {
+		return "ein"
+	}
+	return deOnes[n]
+}
+
+func deCardinal(n int64) string {
+	if n == 0 {
+		return deOnes[0]
+	}
+	for _, sc := range []struct {
+		value        int64
+		one, several string
+	}{{1e12, "Billion", "Billionen"}, {1e9, "Milliarde", "Milliarden"}, {1e6, "Million", "Millionen"}} {
+		if n >= sc.value {
+			head := n / sc.value
+			s := "eine " + sc.one
+			if head > 1 {
+				s = deCardinal(head) + " " + sc.several
+			}
+			if rest := n % sc.value; rest > 0 {
+				s += " " + deCardinal(rest)
+			}
+			return s
+		}
+	}
+	var s string
+	if th := n / 1000; th > 0 {
+		s = deUnder1000(th, false) + "tausend"
+	}
+	if r := n % 1000; r > 0 {
+		s += deUnder1000(r, true)
+	}
+	return s
+}
+
+// deYear reads 1100-1999 in hundreds ("neunzehnhundertvierundachtzig")
+func deYear(n int64) string {
+	if n >= 1100 && n < 2000 {
+		s := deUnder1000(n/100, false) + "hundert"
+		if r := n % 100; r > 0 {
+			s += deUnder1000(r, true)
+		}
+		return s
+	}
+	return deCardinal(n)
+}
+
+// deOrdinalDative is the ordinal as read in dates ("am fünfzehnten März")
+func deOrdinalDative(n int64) string {
+	switch n {
+	case 1:
+		return "ersten"
+	case 3:
+		return "dritten"
+	case 7:
+		return "siebten"
+	case 8:
+		return "achten"
+	}
+	if n < 20 {
+		return deCardinal(n) + "ten"
+	}
+	return deCardinal(n) + "sten"
+}
+
+var deFractions = map[int64]string{2: "halb", 3: "Drittel", 4: "Viertel", 5: "Fünftel",
+	6: "Sechstel", 7: "Siebtel", 8: "Achtel", 9: "Neuntel", 10: "Zehntel"}
+
+// deFraction reads a simple fraction: "ein halb", "drei Viertel"
+func deFraction(num, den int64) string {
+	if num == 1 {
+		return "ein " + deFractions[den]
+	}
+	return deCardinal(num) + " " + deFractions[den]
+}
+
+// Spanish
+
+var esOnes = []string{"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+	"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete", "dieciocho", "diecinueve",
+	"veinte", "veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve"}
+var esTens = []string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+var esHundreds = []string{"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos",
+	"seiscientos", "setecientos", "ochocientos", "novecientos"}
+
+func esUnder1000(n int64) string {
+	if n == 100 {
+		return "cien"
+	}
+	var parts []string
+	if h := n / 100; h > 0 {
+		parts = append(parts, esHundreds[h])
+	}
+	switch r := n % 100; {
+	case r == 0:
+	case r < 30:
+		parts = append(parts, esOnes[r])
+	default:
+		s := esTens[r/10]
+		if u := r % 10; u > 0 {
+			s += " y " + esOnes[u]
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func esCardinal(n int64) string {
+	if n == 0 {
+		return esOnes[0]
+	}
+	if n >= 1e6 {
+		head := n / 1e6
+		s := "un millón"
+		if head > 1 {
+			s = esApocope(esCardinal(head)) + " millones"
+		}
+		if rest := n % 1e6; rest > 0 {
+			s += " " + esCardinal(rest)
+		}
+		return s
+	}
+	var parts []string
+	if th := n / 1000; th == 1 {
+		parts = append(parts, "mil")
+	} else if th > 1 {
+		parts = append(parts, esApocope(esUnder1000(th))+" mil")
+	}
+	if r := n % 1000; r > 0 {
+		parts = append(parts, esUnder1000(r))
+	}
+	return strings.Join(parts, " ")
+}
+
+var esFractions = map[int64]string{2: "medio", 3: "tercio", 4: "cuarto", 5: "quinto",
+	6: "sexto", 7: "séptimo", 8: "octavo", 9: "noveno", 10: "décimo"}
+
+// esFraction reads a simple fraction: "un medio", "tres cuartos"
+func esFraction(num, den int64) string {
+	if num == 1 {
+		return "un " + esFractions[den]
+	}
+	return esCardinal(num) + " " + esFractions[den] + "s"
+}
+
+// esApocope shortens a trailing "uno" before a noun ("veintiún mil",
+// "un dólar")
+func esApocope(s string) string {
+	switch {
+	case strings.HasSuffix(s, "veintiuno"):
+		return strings.TrimSuffix(s, "veintiuno") + "veintiún"
+	case strings.HasSuffix(s, "uno"):
+		return strings.TrimSuffix(s, "uno") + "un"
+	}
+	return s
+}
+
+// French (traditional spelling; voices read both the same)
+
+var frOnes = []string{"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf",
+	"dix", "onze", "douze", "treize", "quatorze", "quinze", "seize"}
+var frTens = []string{"", "", "vingt", "trente", "quarante", "cinquante", "soixante"}
+
+func frUnder100(n int64) string {
+	switch {
+	case n < 17:
+		return frOnes[n]
+	case n < 20:
+		return "dix-" + frOnes[n-10]
+	case n < 70:
+		s := frTens[n/10]
+		switch u := n % 10; u {
+		case 0:
+		case 1:
+			s += " et un"
+		default:
+			s += "-" + frOnes[u]
+		}
+		return s
+	case n == 71:
+		return "soixante et onze"
+	case n < 80:
+		return "soixante-" + frUnder100(n-60)
+	case n == 80:
+		return "quatre-vingts"
+	}
+	return "quatre-vingt-" + frUnder100(n-80)
+}
+
+// frUnder1000 spells 1..999; final is false before "mille", where
+// "cents" and "quatre-vingts" lose their s
+func frUnder1000(n int64, final bool) string {
+	h, r := n/100, n%100
+	var s string
+	switch {
+	case h == 1:
+		s = "cent"
+	case h > 1:
+		s = frOnes[h] + " cent"
+		if r == 0 && final {
+			s += "s"
+		}
+	}
+	if r > 0 {
+		under := frUnder100(r)
+		if r == 80 && !final {
+			under = "quatre-vingt"
+		}
+		if s != "" {
+			s += " "
+		}
+		s += under
+	}
+	return s
+}
+
+func frCardinal(n int64) string {
+	if n == 0 {
+		return frOnes[0]
+	}
+	for _, sc := range []struct {
+		value int64
+		name  string
+	}{{1e12, "billion"}, {1e9, "milliard"}, {1e6, "million"}} {
+		if n >= sc.value {
+			head := n / sc.value
+			s := "un " + sc.name
+			if head > 1 {
+				s = frCardinal(head) + " " + sc.name + "s"
+			}
+			if rest := n % sc.value; rest > 0 {
+				s += " " + frCardinal(rest)
+			}
+			return s
+		}
+	}
+	var parts []string
+	if th := n / 1000; th == 1 {
+		parts = append(parts, "mille")
+	} else if th > 1 {
+		parts = append(parts, frUnder1000(th, false)+" mille")
+	}
+	if r := n % 1000; r > 0 {
+		parts = append(parts, frUnder1000(r, true))
+	}
+	return strings.Join(parts, " ")
+}
+
+var frFractions = map[int64]string{2: "demi", 3: "tiers", 4: "quart", 5: "cinquième",
+	6: "sixième", 7: "septième", 8: "huitième", 9: "neuvième", 10: "dixième"}
+
+// frFraction reads a simple fraction: "un demi", "trois quarts" ("tiers"
+// is the same in the plural)
+func frFraction(num, den int64) string {
+	if num == 1 {
+		return "un " + frFractions[den]
+	}
+	word := frFractions[den]
+	if !strings.HasSuffix(word, "s") {
+		word += "s"
+	}
+	return frCardinal(num) + " " + word
+}
+
+// Russian: numbers agree in gender with what they count, and nouns after
+// them take one of three plural forms
+
+var ruOnes = []string{"ноль", "один", "два", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять",
+	"десять", "одиннадцать", "двенадцать", "тринадцать", "четырнадцать", "пятнадцать", "шестнадцать", "семнадцать", "восемнадцать", "девятнадцать"}
+var ruTens = []string{"", "", "двадцать", "тридцать", "сорок", "пятьдесят", "шестьдесят", "семьдесят", "восемьдесят", "девяносто"}
+var ruHundreds = []string{"", "сто", "двести", "триста", "четыреста", "пятьсот", "шестьсот", "семьсот", "восемьсот", "девятьсот"}
+
+// ruPluralIndex picks the noun form after n: 0 for 1 (21, 31...), 1 for
+// 2-4 (22-24...), 2 for the rest
+func ruPluralIndex(n int64) int {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	}
+	return 2
+}
+
+func ruUnder1000(n int64, feminine bool) string {
+	var parts []string
+	if h := n / 100; h > 0 {
+		parts = append(parts, ruHundreds[h])
+	}
+	r := n % 100
+	if r >= 20 {
+		parts = append(parts, ruTens[r/10])
+		r %= 10
+	}
+	switch {
+	case r == 0:
+	case feminine && r == 1:
+		parts = append(parts, "одна")
+	case feminine && r == 2:
+		parts = append(parts, "две")
+	default:
+		parts = append(parts, ruOnes[r])
+	}
+	return strings.Join(parts, " ")
+}
+
+func ruCardinal(n int64, feminine bool) string {
+	if n == 0 {
+		return ruOnes[0]
+	}
+	var parts []string
+	for _, sc := range []struct {
+		value    int64
+		forms    [3]string
+		feminine bool
+	}{
+		{1e12, [3]string{"триллион", "триллиона", "триллионов"}, false},
+		{1e9, [3]string{"миллиард", "миллиарда", "миллиардов"}, false},
+		{1e6, [3]string{"миллион", "миллиона", "миллионов"}, false},
+		{1e3, [3]string{"тысяча", "тысячи", "тысяч"}, true},
+	} {
+		if head := n / sc.value; head > 0 {
+			parts = append(parts, ruUnder1000(head, sc.feminine), sc.forms[ruPluralIndex(head)])
+			n %= sc.value
+		}
+	}
+	if n > 0 {
+		parts = append(parts, ruUnder1000(n, feminine))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Ordinals for dates: the day in the neuter nominative ("пятнадцатое"),
+// the year in the masculine genitive ("двадцать четвёртого года")
+var ruOrdinalStems = map[int64]string{
+	1: "перв", 2: "втор", 3: "трет", 4: "четвёрт", 5: "пят", 6: "шест", 7: "седьм", 8: "восьм", 9: "девят",
+	10: "десят", 11: "одиннадцат", 12: "двенадцат", 13: "тринадцат", 14: "четырнадцат", 15: "пятнадцат",
+	16: "шестнадцат", 17: "семнадцат", 18: "восемнадцат", 19: "девятнадцат",
+	20: "двадцат", 30: "тридцат", 40: "сороков", 50: "пятидесят", 60: "шестидесят", 70: "семидесят",
+	80: "восьмидесят", 90: "девяност",
+	100: "сот", 200: "двухсот", 300: "трёхсот", 400: "четырёхсот", 500: "пятисот", 600: "шестисот",
+	700: "семисот", 800: "восьмисот", 900: "девятисот", 1000: "тысячн", 2000: "двухтысячн",
+}
+
+// ruOrdinal spells n with its last part as an ordinal with ending
+// (neuter "ое", genitive "ого", prepositional "ом", instrumental "ым";
+// "третий" has its own). Years are read "тысяча девятьсот...", without
+// "одна".
+func ruOrdinal(n int64, ending string) string {
+	var last int64
+	switch {
+	case n%1000 == 0:
+		last = n % 10000
+		if last != 1000 && last != 2000 {
+			return ruCardinal(n, false) // Rare enough to read as a number
+		}
+	case n%100 == 0:
+		last = n % 1000
+	case n%100 < 20:
+		last = n % 100
+	case n%10 == 0:
+		last = n % 100
+	default:
+		last = n % 10
+	}
+	word := ruOrdinalStems[last]
+	if last == 3 {
+		word += map[string]string{"ое": "ье", "ого": "ьего", "ом": "ьем", "ым": "ьим"}[ending]
+	} else {
+		word += ending
+	}
+	if head := n - last; head > 0 {
+		return strings.TrimPrefix(ruCardinal(head, false), "одна ") + " " + word
+	}
+	return word
+}
+
+// ruYear reads a year before a form of "год" as the ordinal it agrees
+// with: "в 1985 году" is "в тысяча девятьсот восемьдесят пятом году".
+// isYear with spoken "" means a form whose ending this doesn't know
+// ("1985 г." can be any case), to be left as digits. next may end in a
+// period; "г" without one is grams.
+func ruYear(n int64, next string) (spoken string, isYear bool) {
+	if next == "г." || next == "гг." {
+		return "", true
+	}
+	switch strings.TrimSuffix(next, ".") {
+	case "году":
+		return ruOrdinal(n, "ом"), true
+	case "года":
+		return ruOrdinal(n, "ого"), true
+	case "годом":
+		return ruOrdinal(n, "ым"), true
+	case "год", "годы", "годах", "годов":
+		return "", true
+	}
+	return "", false
+}
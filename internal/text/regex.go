@@ -10,4 +10,12 @@ var (
 
 	// WhisperProgressRegex matches progress output like PROGRESS:45.5
 	WhisperProgressRegex = regexp.MustCompile(`PROGRESS:(\d+\.?\d*)`)
+
+	// WhisperDetectedLangRegex matches whisper-cpp's language detection line,
+	// e.g. "auto-detected language: ru (p = 0.976562)"
+	WhisperDetectedLangRegex = regexp.MustCompile(`auto-detected language: (\w+) \(p = (\d+\.?\d*)\)`)
+
+	// WhisperScoresRegex matches per-segment decoder scores like
+	// SCORES:12 -0.4312 0.0871 (index, avg_logprob, no_speech_prob)
+	WhisperScoresRegex = regexp.MustCompile(`SCORES:(\d+) (-?\d+\.?\d*) (-?\d+\.?\d*)`)
 )
@@ -0,0 +1,79 @@
+package text
+
+import "strings"
+
+// maxPromptChars keeps vocabulary prompts well under Whisper's 224-token
+// prompt window; anything longer is silently truncated by the decoder.
+const maxPromptChars = 800
+
+// BuildVocabularyPrompt turns a list of terms into a Whisper initial prompt.
+// Whisper copies spelling and style from the prompt, so a comma-separated
+// sentence of names works better than a bare list. Returns "" for no terms.
+func BuildVocabularyPrompt(terms []string) string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	length := 0
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		if length+len(term)+2 > maxPromptChars {
+			break
+		}
+		seen[key] = true
+		cleaned = append(cleaned, term)
+		length += len(term) + 2
+	}
+
+	if len(cleaned) == 0 {
+		return ""
+	}
+	return "Glossary: " + strings.Join(cleaned, ", ") + "."
+}
+
+// ParseVocabulary splits user input (one term per line or comma-separated)
+// into a clean list of terms.
+func ParseVocabulary(s string) []string {
+	var terms []string
+	for _, line := range strings.Split(s, "\n") {
+		for _, term := range strings.Split(line, ",") {
+			if term = strings.TrimSpace(term); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// Levenshtein returns the edit distance between a and b, counted in runes.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
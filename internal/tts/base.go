@@ -2,46 +2,59 @@ package tts
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"video-translator/internal/config"
+	"video-translator/internal/logger"
 	"video-translator/internal/media"
 	"video-translator/internal/subtitle"
 	"video-translator/internal/worker"
 )
 
-// BaseTTS provides shared functionality for TTS services.
-// Embed this in concrete TTS implementations to inherit common behavior.
+// BaseTTS is the synthesis engine shared by TTS services. A service embeds
+// it and supplies only its Synthesizer; BaseTTS owns concurrency, retries,
+// the segment cache, partial failures and assembly into timed audio, so
+// fixes there apply to every provider.
 type BaseTTS struct {
-	Voice    string
-	FFmpeg   *media.FFmpegService
-	TempDir  string
-	Workers  int
+	Name    string // Provider name for logs ("Edge TTS")
+	FFmpeg  *media.FFmpegService
+	TempDir string
+	Workers int
+	Cache   *SegmentCache // Previously synthesized segments (nil = off)
+
+	RetryDelay time.Duration // Wait before a segment's second try; grows with each attempt
+
+	synth Synthesizer
 }
 
-// NewBaseTTS creates a new BaseTTS with defaults.
-func NewBaseTTS(voice string, workers int) *BaseTTS {
-	tempDir := filepath.Join(os.TempDir(), "video-translator-tts")
+// NewBaseTTS creates the engine for synth, keeping segments under
+// os.TempDir()/tempDirName while a run is in progress.
+func NewBaseTTS(name, tempDirName string, workers int, synth Synthesizer) *BaseTTS {
+	tempDir := filepath.Join(os.TempDir(), tempDirName)
 	os.MkdirAll(tempDir, 0755)
 
 	return &BaseTTS{
-		Voice:   voice,
+		Name:    name,
 		FFmpeg:  media.NewFFmpegService(),
 		TempDir: tempDir,
 		Workers: workers,
-	}
-}
+		synth:   synth,
 
-// SetVoice sets the voice for synthesis.
-func (b *BaseTTS) SetVoice(voice string) {
-	b.Voice = voice
+		RetryDelay: config.TTSRetryDelay,
+	}
 }
 
-// GetVoice returns the current voice.
-func (b *BaseTTS) GetVoice() string {
-	return b.Voice
+// SetCache sets where synthesized segments are reused from (nil disables
+// the cache). Set it before the service is shared: runs read it without
+// locking.
+func (b *BaseTTS) SetCache(c *SegmentCache) {
+	b.Cache = c
 }
 
 // EstimateCost returns 0 by default (free services).
@@ -54,97 +67,236 @@ func (b *BaseTTS) Cleanup() error {
 	return os.RemoveAll(b.TempDir)
 }
 
-// SynthesizeFunc is the function signature for synthesizing a single text.
-type SynthesizeFunc func(text, outputPath string) error
+// SynthesizeSubtitles generates timed audio for subtitles.
+func (b *BaseTTS) SynthesizeSubtitles(subs subtitle.List, outputPath string) error {
+	return b.SynthesizeWithCallback(subs, outputPath, nil)
+}
+
+// SynthesizeWithCallback is SynthesizeWithOptions without any job's
+// options.
+func (b *BaseTTS) SynthesizeWithCallback(subs subtitle.List, outputPath string, onProgress ProgressCallback) error {
+	return b.SynthesizeWithOptions(subs, outputPath, RunOptions{}, onProgress)
+}
 
-// SynthesizeWithCallbackGeneric provides a generic implementation of parallel TTS synthesis.
-// It takes a synthesize function and handles the worker pool, progress tracking, and audio assembly.
-func (b *BaseTTS) SynthesizeWithCallbackGeneric(
-	subs subtitle.List,
-	outputPath string,
-	onProgress ProgressCallback,
-	synthesize SynthesizeFunc,
-) error {
+// SynthesizeWithOptions speaks every non-empty subtitle in parallel and
+// assembles the segments into audio timed like subs. Each segment is tried
+// config.TTSSegmentAttempts times; segments that still fail are left
+// silent, unless more than config.TTSMaxFailedSegmentRatio of them fail or
+// the provider reports a Permanent error, which fails the run. Segments
+// longer than their window are re-synthesized at a faster native rate when
+// the provider is a RateSynthesizer; the assembler's atempo only handles
+// what still doesn't fit.
+func (b *BaseTTS) SynthesizeWithOptions(subs subtitle.List, outputPath string, opts RunOptions, onProgress ProgressCallback) error {
 	if len(subs) == 0 {
 		return fmt.Errorf("no subtitles provided")
 	}
+	if err := b.synth.CheckInstalled(); err != nil {
+		return err
+	}
 
-	// Create unique temp directory for this job
-	segmentDir := filepath.Join(b.TempDir, fmt.Sprintf("segments_%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(segmentDir, 0755); err != nil {
+	segmentDir, err := b.CreateSegmentDir()
+	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(segmentDir)
 
-	// Identify subtitles that need TTS
-	var jobs []Job
+	var segments []Segment
 	for i, sub := range subs {
-		if !sub.IsEmpty() && strings.TrimSpace(sub.Text) != "" {
-			jobs = append(jobs, Job{Index: i, Sub: sub})
+		if !sub.IsEmpty() {
+			segments = append(segments, Segment{
+				Index:    i,
+				Text:     sub.Text,
+				Emotion:  sub.Emotion,
+				Duration: sub.Duration(),
+				Voice:    opts.Voice,
+				Usage:    opts.Usage,
+			})
 		}
 	}
 
-	// Process TTS jobs in parallel
-	speechPaths := make(map[int]string)
-	total := len(subs)
-
-	if len(jobs) > 0 {
-		processFunc := func(job worker.Job[Job]) (string, error) {
-			ttsJob := job.Data
-			speechPath := filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", ttsJob.Index))
-			err := synthesize(ttsJob.Sub.Text, speechPath)
-			if err != nil {
-				return "", err
-			}
-
-			// Adjust duration if needed
-			targetDuration := (ttsJob.Sub.EndTime - ttsJob.Sub.StartTime).Seconds()
-			if targetDuration > 0.2 {
-				adjustedPath := filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", ttsJob.Index))
-				if adjErr := b.FFmpeg.AdjustAudioDuration(speechPath, adjustedPath, targetDuration); adjErr == nil {
-					return adjustedPath, nil
-				}
-			}
-
-			return speechPath, nil
+	run := &segmentRun{
+		base:      b,
+		dir:       segmentDir,
+		cacheKey:  b.synth.CacheKey(opts.Voice),
+		maxFailed: int(float64(len(segments)) * config.TTSMaxFailedSegmentRatio),
+	}
+	if rs, ok := b.synth.(RateSynthesizer); ok && opts.RateMax > 1 {
+		run.minRate = opts.RateMin
+		run.maxRate = min(opts.RateMax, rs.MaxRate())
+	}
+	logger.LogInfo("%s: synthesizing %d segments with %d workers", b.Name, len(segments), b.Workers)
+
+	var progress worker.ProgressFunc
+	if onProgress != nil {
+		progress = func(completed, total int) {
+			onProgress(completed, total)
 		}
+	}
+	paths, _ := worker.ProcessWithErrors(segments, max(b.Workers, 1), run.process, progress)
+	if err := run.abortErr(); err != nil {
+		return err
+	}
 
-		// Create worker pool
-		workerJobs := make([]worker.Job[Job], len(jobs))
-		for i, job := range jobs {
-			workerJobs[i] = worker.Job[Job]{Index: i, Data: job}
+	// Failed segments become silence for their window so the rest stays in sync
+	voiced := subs.Clone()
+	speechPaths := make(map[int]string)
+	for i, seg := range segments {
+		if paths[i] == "" {
+			voiced[seg.Index].Text = ""
+			continue
 		}
+		speechPaths[seg.Index] = paths[i]
+	}
+	if n := run.failed.Load(); n > 0 {
+		logger.LogError("%s: %d/%d segments failed and were left silent", b.Name, n, len(segments))
+	}
+	if n := run.cached.Load(); n > 0 {
+		logger.LogInfo("%s: reused %d/%d cached segments", b.Name, n, len(segments))
+	}
+	if n := run.faster.Load(); n > 0 {
+		logger.LogInfo("%s: %d/%d segments re-synthesized faster to fit", b.Name, n, len(segments))
+	}
+
+	assembler := media.NewAudioAssembler(b.FFmpeg, segmentDir)
+	if err := assembler.AssembleFromSpeechPathsParallel(voiced, speechPaths, outputPath); err != nil {
+		return fmt.Errorf("failed to assemble audio: %w", err)
+	}
+	return nil
+}
+
+// segmentRun tracks one SynthesizeWithOptions call across its workers
+type segmentRun struct {
+	base      *BaseTTS
+	dir       string
+	cacheKey  string
+	maxFailed int
+	minRate   float64 // Smallest speed-up worth re-synthesizing for
+	maxRate   float64 // Fastest native rate for fitting (<= 1 = off)
+
+	failed atomic.Int32
+	cached atomic.Int32
+	faster atomic.Int32 // Segments re-synthesized at a faster rate
+
+	mu  sync.Mutex
+	err error // Set once the run is given up on; later segments are skipped
+}
 
-		// Progress tracking
-		progressCount := 0
-		progressCallback := func(completed, _ int) {
-			progressCount = completed
-			if onProgress != nil {
-				onProgress(progressCount, total)
-			}
+// process synthesizes one segment, returning "" if it failed
+func (r *segmentRun) process(job worker.Job[Segment]) (string, error) {
+	if err := r.abortErr(); err != nil {
+		return "", err
+	}
+
+	seg := job.Data
+	path := SpeechPath(r.dir, seg.Index)
+	cached, err := r.synthesize(seg, path)
+	if err == nil {
+		if cached {
+			r.cached.Add(1)
 		}
+		r.fit(seg, path)
+		return path, nil
+	}
 
-		// Run worker pool
-		results, err := worker.Process(jobs, b.Workers, processFunc, progressCallback)
-		if err != nil {
-			return err
+	failed := int(r.failed.Add(1))
+	switch {
+	case IsPermanent(err):
+		r.abort(err)
+	case failed > r.maxFailed:
+		r.abort(fmt.Errorf("TTS synthesis failed: %d segments failed, last: %w", failed, err))
+	default:
+		logger.LogError("%s: %v", r.base.Name, err)
+	}
+	return "", err
+}
+
+// synthesize reuses a cached segment or asks the provider, retrying
+// errors that aren't Permanent. It reports whether the cache was used.
+func (r *segmentRun) synthesize(seg Segment, path string) (bool, error) {
+	key := SegmentKey(r.cacheKey, seg)
+	if r.base.Cache.Get(key, path) {
+		return true, nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= config.TTSSegmentAttempts; attempt++ {
+		if err = r.base.synth.SynthesizeSegment(seg, path); err == nil {
+			break
+		}
+		if IsPermanent(err) || r.abortErr() != nil {
+			return false, err
 		}
+		logger.LogDebug("%s: segment %d attempt %d failed: %v", r.base.Name, seg.Index+1, attempt, err)
+		if attempt < config.TTSSegmentAttempts {
+			time.Sleep(time.Duration(attempt) * r.base.RetryDelay)
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("segment %d failed after %d attempts: %w", seg.Index+1, config.TTSSegmentAttempts, err)
+	}
+
+	if cacheErr := r.base.Cache.Put(key, path); cacheErr != nil {
+		logger.LogError("%s: %v", r.base.Name, cacheErr)
+	}
+	return false, nil
+}
 
-		// Collect speech paths
-		for i, path := range results {
-			if path != "" {
-				speechPaths[jobs[i].Index] = path
-			}
+// fit re-synthesizes a segment that overruns its window at the native rate
+// that should make it fit, measuring again since providers don't scale
+// length exactly. A failed re-synthesis keeps the audio already there.
+func (r *segmentRun) fit(seg Segment, path string) {
+	if r.maxRate <= 1 || seg.Duration <= 0 {
+		return
+	}
+	window := seg.Duration.Seconds()
+	limit := window + config.TTSFitTolerance.Seconds()
+
+	length, err := r.base.FFmpeg.GetAudioDuration(path)
+	if err != nil || length <= limit {
+		return
+	}
+	if length/window*config.TTSRateHeadroom < r.minRate {
+		return // Too small a speed-up to be worth a request; atempo handles it well
+	}
+
+	original := length
+	rate := 1.0
+	for attempt := 0; attempt < config.TTSRateAttempts && length > limit && rate < r.maxRate; attempt++ {
+		faster := seg
+		faster.Rate = min(math.Ceil(rate*length/window*config.TTSRateHeadroom*100)/100, r.maxRate)
+
+		fasterPath := RatePath(path)
+		if _, err := r.synthesize(faster, fasterPath); err != nil {
+			logger.LogDebug("%s: segment %d at %.2fx failed: %v", r.base.Name, seg.Index+1, faster.Rate, err)
+			break
 		}
+		fasterLength, err := r.base.FFmpeg.GetAudioDuration(fasterPath)
+		if err != nil || os.Rename(fasterPath, path) != nil {
+			os.Remove(fasterPath)
+			break
+		}
+		rate, length = faster.Rate, fasterLength
 	}
 
-	// Build final audio using AudioAssembler
-	assembler := media.NewAudioAssembler(b.FFmpeg, segmentDir)
-	if err := assembler.AssembleFromSpeechPaths(subs, speechPaths, outputPath); err != nil {
-		return fmt.Errorf("failed to assemble audio: %w", err)
+	if rate > 1 {
+		r.faster.Add(1)
+		logger.LogInfo("%s: segment %d spoken at %.2fx for its %.2fs window (%.2fs -> %.2fs)",
+			r.base.Name, seg.Index+1, rate, window, original, length)
 	}
+}
 
-	return nil
+func (r *segmentRun) abort(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *segmentRun) abortErr() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
 }
 
 // CreateSegmentDir creates a unique temp directory for segments.
@@ -161,6 +313,12 @@ func SpeechPath(segmentDir string, index int) string {
 	return filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", index))
 }
 
+// RatePath returns where a faster re-synthesis of the speech at path is
+// written before it replaces it.
+func RatePath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "_rate.wav"
+}
+
 // AdjustedPath returns the path for an adjusted speech segment.
 func AdjustedPath(segmentDir string, index int) string {
 	return filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", index))
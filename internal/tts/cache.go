@@ -0,0 +1,109 @@
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"video-translator/internal/diskcache"
+)
+
+// SegmentCache stores synthesized segments on disk keyed by the provider's
+// CacheKey plus the text and emotion, so re-dubbing a video (another voice
+// for one speaker, a fixed line) only pays for lines that changed. Entries
+// are evicted least-recently-used once the cache grows past maxBytes.
+//
+// All methods are safe on a nil *SegmentCache, which acts as a disabled
+// cache.
+type SegmentCache struct {
+	files *diskcache.Cache
+}
+
+// DefaultCacheDir returns ~/.cache/video-translator/speech
+func DefaultCacheDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cache", "video-translator", "speech")
+}
+
+// NewSegmentCache creates a cache in dir limited to maxBytes (0 = unlimited)
+func NewSegmentCache(dir string, maxBytes int64) *SegmentCache {
+	return &SegmentCache{
+		files: diskcache.New("Speech cache", dir, ".wav", maxBytes),
+	}
+}
+
+// SegmentKey returns the cache entry name for seg spoken by a provider
+// whose CacheKey is providerKey, or "" when the provider opts out. The rate
+// is only part of the key when it isn't normal.
+func SegmentKey(providerKey string, seg Segment) string {
+	if providerKey == "" {
+		return ""
+	}
+	parts := []string{providerKey, seg.Emotion, seg.Text}
+	if rate := seg.SpeakingRate(); rate != 1 {
+		parts = append(parts, strconv.FormatFloat(rate, 'f', 2, 64))
+	}
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+// Get copies the cached audio for key to outputPath, refreshing its LRU
+// timestamp. It reports false on a miss.
+func (c *SegmentCache) Get(key, outputPath string) bool {
+	if c == nil || key == "" {
+		return false
+	}
+	return c.files.Get(key, func(path string) error {
+		return copyFile(path, outputPath)
+	})
+}
+
+// Put stores the audio at path under key and evicts old entries if over
+// the size limit
+func (c *SegmentCache) Put(key, path string) error {
+	if c == nil || key == "" {
+		return nil
+	}
+	return c.files.Put(key, func(entry string) error {
+		return copyFile(path, entry)
+	})
+}
+
+// Size returns the total size in bytes and number of cached segments
+func (c *SegmentCache) Size() (int64, int) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.files.Size()
+}
+
+// Clear removes every cached segment
+func (c *SegmentCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+	return c.files.Clear()
+}
+
+// copyFile copies src to dst, replacing dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -2,16 +2,100 @@
 package tts
 
 import (
+	"errors"
+	"net/http"
+	"time"
+
 	"video-translator/internal/subtitle"
 )
 
 // ProgressCallback is called during synthesis to report progress.
 type ProgressCallback func(current, total int)
 
+// Segment is one line of speech to synthesize.
+type Segment struct {
+	Index    int           // Position in the subtitle list
+	Text     string        // What the voice reads
+	Emotion  string        // Delivery for expressive voices ("" = neutral)
+	Duration time.Duration // Window the speech is fitted into
+	Rate     float64       // Speaking-rate multiplier (0 = provider's normal, 1.2 = 20% faster)
+	Voice    string        // The run's RunOptions.Voice ("" = the service's own)
+	Usage    UsageRecorder // The run's RunOptions.Usage (nil = not recorded)
+}
+
+// VoiceOr returns the segment's voice, or fallback when it has none.
+func (s Segment) VoiceOr(fallback string) string {
+	if s.Voice == "" {
+		return fallback
+	}
+	return s.Voice
+}
+
+// SpeakingRate returns the segment's rate multiplier, 1 when unset.
+func (s Segment) SpeakingRate() float64 {
+	if s.Rate <= 0 {
+		return 1
+	}
+	return s.Rate
+}
+
+// RecordUsage records text synthesized for the segment on its run's
+// UsageRecorder, if any
+func (s Segment) RecordUsage(stage, provider, model, text string) {
+	if s.Usage != nil {
+		s.Usage.RecordCharacters(stage, provider, model, text)
+	}
+}
+
+// UsageRecorder records billable characters for one job.
+type UsageRecorder interface {
+	RecordCharacters(stage, provider, model, text string)
+}
+
+// RunOptions carries one job's settings into SynthesizeWithOptions. They
+// are passed per call rather than set on the service, which is shared by
+// jobs running side by side.
+type RunOptions struct {
+	Voice string        // Voice for this run ("" = the service's own)
+	Usage UsageRecorder // Where synthesized characters are recorded (nil = not recorded)
+
+	// Native speed-ups for segments that overrun their window (RateMax <= 1
+	// = off). A segment is re-synthesized when it needs at least RateMin to
+	// fit, and never faster than RateMax or the provider's MaxRate.
+	RateMin float64
+	RateMax float64
+}
+
+// Synthesizer is the one thing a TTS provider implements: speaking a single
+// segment into a WAV file. BaseTTS builds everything else on top of it.
+type Synthesizer interface {
+	// CheckInstalled verifies the provider is usable before a run starts.
+	CheckInstalled() error
+
+	// SynthesizeSegment writes the segment's speech to outputPath (WAV).
+	// Errors wrapped with Permanent stop the run instead of being retried.
+	SynthesizeSegment(seg Segment, outputPath string) error
+
+	// CacheKey identifies everything besides the text that changes the
+	// audio (model, voice, speed) when speaking with voice ("" = the
+	// service's own). "" disables caching for the provider.
+	CacheKey(voice string) string
+}
+
+// RateSynthesizer is a Synthesizer that speaks faster natively when asked
+// through Segment.Rate. BaseTTS re-synthesizes over-long segments with it
+// instead of leaving them to ffmpeg's atempo, which sounds far worse.
+type RateSynthesizer interface {
+	Synthesizer
+
+	// MaxRate is the fastest Segment.Rate the provider accepts with its
+	// current settings (a base speed of 1.5 leaves less headroom).
+	MaxRate() float64
+}
+
 // Service is the interface for all TTS services.
 type Service interface {
-	// CheckInstalled verifies the TTS service is available.
-	CheckInstalled() error
+	Synthesizer
 
 	// SetVoice sets the voice for synthesis.
 	SetVoice(voice string)
@@ -28,6 +112,9 @@ type Service interface {
 	// SynthesizeWithCallback generates audio with progress callback.
 	SynthesizeWithCallback(subs subtitle.List, outputPath string, onProgress ProgressCallback) error
 
+	// SynthesizeWithOptions generates audio with one job's options.
+	SynthesizeWithOptions(subs subtitle.List, outputPath string, opts RunOptions, onProgress ProgressCallback) error
+
 	// EstimateCost estimates synthesis cost (0 for free services).
 	EstimateCost(charCount int) float64
 }
@@ -58,17 +145,38 @@ const (
 	ProviderOpenAI    ProviderType = "openai"
 	ProviderEdgeTTS   ProviderType = "edge-tts"
 	ProviderCosyVoice ProviderType = "cosyvoice"
+	ProviderFishAudio ProviderType = "fish-audio"
 )
 
-// Job represents a TTS synthesis job.
-type Job struct {
-	Index int
-	Sub   subtitle.Subtitle
+// permanentError marks a failure that retrying can't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so BaseTTS stops the run instead of retrying
+// (missing API key, missing voice sample, rejected request).
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
 }
 
-// Result represents the result of a TTS synthesis job.
-type Result struct {
-	Index int
-	Path  string
-	Err   error
+// PermanentForStatus marks err Permanent when an API answered with a status
+// every other segment would get too (bad key, no credit).
+func PermanentForStatus(status int, err error) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden:
+		return Permanent(err)
+	}
+	return err
 }
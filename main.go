@@ -1,6 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
+	"video-translator/models"
+	"video-translator/services"
 	"video-translator/ui"
 	"video-translator/ui/theme"
 
@@ -9,6 +14,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "usage" {
+		os.Exit(runUsage(os.Args[2:]))
+	}
+
 	a := app.New()
 	a.Settings().SetTheme(&theme.VideoTranslatorTheme{})
 
@@ -19,4 +28,28 @@ func main() {
 	w.SetContent(mainUI.Build())
 
 	w.ShowAndRun()
+	mainUI.Close()
+}
+
+// runUsage prints the usage ledger: video-translator usage [job|day|provider]
+func runUsage(args []string) int {
+	by := services.UsageByJob
+	if len(args) > 0 {
+		by = args[0]
+	}
+
+	config, err := models.LoadConfig()
+	if err != nil {
+		config = models.DefaultConfig()
+	}
+	records, err := services.NewUsageLedger(services.DefaultUsageLedgerPath(), config).Records()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := services.WriteUsageReport(os.Stdout, records, by); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return 0
 }
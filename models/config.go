@@ -24,7 +24,7 @@ type Config struct {
 
 	// Provider selection (whisper-cpp, faster-whisper, openai)
 	TranscriptionProvider string `json:"transcription_provider"`
-	// Provider selection (argos, openai, deepseek)
+	// Provider selection (argos, openai, deepseek, grok, ollama, lmstudio, llama-cpp, llm)
 	TranslationProvider string `json:"translation_provider"`
 	// Provider selection (piper, openai, cosyvoice)
 	TTSProvider string `json:"tts_provider"`
@@ -42,6 +42,53 @@ type Config struct {
 	// Grok API settings (xAI's Grok for translation - cheap)
 	GrokAPIKey string `json:"grok_api_key"`
 
+	// OpenAI-compatible LLM translation (local servers or a custom endpoint).
+	// Zero values keep the selected provider's preset.
+	LLMBaseURL     string  `json:"llm_base_url"` // e.g. http://localhost:11434/v1
+	LLMModel       string  `json:"llm_model"`    // e.g. qwen2.5:7b
+	LLMAPIKey      string  `json:"llm_api_key"`  // Only for the custom "llm" provider
+	LLMMaxTokens   int     `json:"llm_max_tokens"`
+	LLMTemperature float64 `json:"llm_temperature"`
+	LLMConcurrency int     `json:"llm_concurrency"`
+
+	// LibreTranslate server (self-hosted machine translation over HTTP)
+	LibreTranslateURL    string `json:"libretranslate_url"`     // e.g. http://localhost:5000
+	LibreTranslateAPIKey string `json:"libretranslate_api_key"` // Only if the server requires keys
+
+	// Context sent with every LLM translation batch so pronouns, gender and
+	// terminology stay consistent across batch boundaries
+	TranslationContextLines   int  `json:"translation_context_lines"`   // Read-only lines before/after each batch
+	TranslationRunningSummary bool `json:"translation_running_summary"` // Carry a story-so-far summary (batches run in order)
+	TranslationDocumentBrief  bool `json:"translation_document_brief"`  // One-time topic/speakers/tone pass before translating
+
+	// Fit translations to segment durations (length budgets from the voice's
+	// speaking rate; over-long lines are paraphrased shorter before TTS)
+	FitTranslationToDuration bool `json:"fit_translation_to_duration"`
+
+	// Glossary name (file in the glossaries folder, without extension) whose
+	// term translations and do-not-translate list every job must honour
+	Glossary string `json:"glossary"`
+
+	// Translation profiles (style, formality, locale variant) for LLM
+	// providers and the one jobs use by default ("" = none)
+	TranslationProfiles []TranslationProfile `json:"translation_profiles"`
+	TranslationProfile  string               `json:"translation_profile"`
+
+	// Reuse translations of lines seen in earlier jobs (exact matches skip
+	// the provider, similar ones are suggested to LLMs)
+	TranslationMemoryEnabled bool `json:"translation_memory_enabled"`
+
+	// Tag each line with an emotion while translating (LLM providers) so
+	// the TTS voice can act it out; providers without emotion control
+	// ignore the tags
+	EmotionTagging bool `json:"emotion_tagging"`
+
+	// Spell out numbers, dates, money, units, abbreviations and URLs in
+	// the translation before TTS, then apply the pronunciation lexicon.
+	// Subtitles keep the text as written.
+	SpeechNormalization  bool           `json:"speech_normalization"`
+	PronunciationLexicon []LexiconEntry `json:"pronunciation_lexicon"`
+
 	// Whisper settings (for whisper-cpp)
 	WhisperModel string `json:"whisper_model"`
 
@@ -62,6 +109,7 @@ type Config struct {
 	CosyVoiceMode        string `json:"cosyvoice_mode"`    // local, api
 	CosyVoiceAPIURL      string `json:"cosyvoice_api_url"` // API endpoint if using api mode
 	VoiceCloneSamplePath string `json:"voice_clone_sample"`
+	AutoVoiceReference   bool   `json:"auto_voice_reference"` // Clone each speaker from a sample cut from the video
 
 	// Edge TTS settings (FREE neural TTS from Microsoft)
 	EdgeTTSVoice string `json:"edge_tts_voice"` // en-US-AriaNeural, en-US-GuyNeural, etc.
@@ -75,6 +123,48 @@ type Config struct {
 	// Smart segmentation (uses LLM to improve sentence boundaries)
 	UseSmartSegmentation bool `json:"use_smart_segmentation"`
 
+	// Hallucination filter (drops "Thanks for watching!", looped lines, etc.)
+	FilterHallucinations bool `json:"filter_hallucinations"`
+
+	// Speaker diarization (dub each speaker with its own voice via pyannote)
+	DiarizationEnabled bool   `json:"diarization_enabled"`
+	HuggingFaceToken   string `json:"huggingface_token"` // Required for pyannote models
+	MaxSpeakers        int    `json:"max_speakers"`      // 0 = auto-detect
+
+	// Transcription cache (reuses transcripts of identical audio)
+	TranscriptionCacheEnabled bool `json:"transcription_cache_enabled"`
+	TranscriptionCacheMaxMB   int  `json:"transcription_cache_max_mb"`
+
+	// Speech cache (reuses synthesized lines with the same text and voice)
+	SpeechCacheEnabled bool `json:"speech_cache_enabled"`
+	SpeechCacheMaxMB   int  `json:"speech_cache_max_mb"`
+
+	// Native rate fitting: lines too long for their slot are re-synthesized
+	// faster by the TTS provider (ffmpeg atempo only handles what's left).
+	// A line needing less than SpeechRateMin is left to atempo; 0 for
+	// SpeechRateMax turns re-synthesis off.
+	SpeechRateMin float64 `json:"speech_rate_min"`
+	SpeechRateMax float64 `json:"speech_rate_max"`
+
+	// Custom vocabulary (product/people names) passed to ASR as a prompt
+	TranscriptionVocabulary []string `json:"transcription_vocabulary"`
+	VocabularyFuzzyFix      bool     `json:"vocabulary_fuzzy_fix"` // Snap near-misses to canonical spelling
+
+	// Prices used to cost the usage ledger (see DefaultUsagePrices); saved
+	// entries override the defaults key by key
+	UsagePrices map[string]UsagePrice `json:"usage_prices"`
+
+	// Request, token and character rates per provider (see
+	// DefaultRateLimits), shared by all running jobs
+	RateLimits map[string]RateLimit `json:"rate_limits"`
+
+	// Spending caps in USD (0 = no cap). Before each paid stage the
+	// projected cost is checked against them; BudgetAction decides whether
+	// an over-budget stage aborts the job or switches to a free provider.
+	JobBudget    float64 `json:"job_budget"`
+	DailyBudget  float64 `json:"daily_budget"`
+	BudgetAction string  `json:"budget_action"` // downgrade, abort
+
 	// Audio mixing settings (keep background music/sounds)
 	KeepBackgroundAudio   bool    `json:"keep_background_audio"`
 	BackgroundAudioVolume float64 `json:"background_audio_volume"` // 0.0-1.0, default 0.3
@@ -111,6 +201,35 @@ func DefaultConfig() *Config {
 		// Groq settings
 		GroqAPIKey: "",
 
+		// OpenAI-compatible LLM translation (presets decide the rest)
+		LLMBaseURL: "",
+		LLMModel:   "qwen2.5:7b",
+
+		// LibreTranslate
+		LibreTranslateURL:    "http://localhost:5000",
+		LibreTranslateAPIKey: "",
+
+		// Translation context
+		TranslationContextLines:   3,
+		TranslationRunningSummary: false,
+		TranslationDocumentBrief:  false,
+
+		// Duration fitting
+		FitTranslationToDuration: true,
+
+		// Translation profiles
+		TranslationProfiles: []TranslationProfile{{Name: "default"}},
+		TranslationProfile:  "default",
+
+		// Translation memory
+		TranslationMemoryEnabled: true,
+
+		// Emotion tagging (expressive dubbing on every TTS that supports it)
+		EmotionTagging: true,
+
+		// Spoken-form text for TTS
+		SpeechNormalization: true,
+
 		// Whisper settings
 		WhisperModel: "base",
 
@@ -131,6 +250,7 @@ func DefaultConfig() *Config {
 		CosyVoiceMode:        "local",
 		CosyVoiceAPIURL:      "",
 		VoiceCloneSamplePath: "",
+		AutoVoiceReference:   true,
 
 		// Edge TTS settings (FREE)
 		EdgeTTSVoice: "en-US-AriaNeural",
@@ -144,6 +264,39 @@ func DefaultConfig() *Config {
 		// Smart segmentation
 		UseSmartSegmentation: false,
 
+		// Hallucination filter
+		FilterHallucinations: true,
+
+		// Speaker diarization (off by default, needs pyannote + HF token)
+		DiarizationEnabled: false,
+		HuggingFaceToken:   "",
+		MaxSpeakers:        0,
+
+		// Transcription cache
+		TranscriptionCacheEnabled: true,
+		TranscriptionCacheMaxMB:   200,
+
+		// Speech cache
+		SpeechCacheEnabled: true,
+		SpeechCacheMaxMB:   500,
+
+		// Native rate fitting (up to 1.5x; smaller speed-ups go to atempo)
+		SpeechRateMin: 1.05,
+		SpeechRateMax: 1.5,
+
+		// Custom vocabulary
+		TranscriptionVocabulary: nil,
+		VocabularyFuzzyFix:      true,
+
+		// Usage ledger prices
+		UsagePrices: DefaultUsagePrices(),
+
+		// Provider rate limits
+		RateLimits: DefaultRateLimits(),
+
+		// Budget caps (off by default)
+		BudgetAction: "downgrade",
+
 		// Audio mixing (keep background music at 30% volume)
 		KeepBackgroundAudio:   true,
 		BackgroundAudioVolume: 0.3,
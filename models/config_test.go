@@ -140,3 +140,52 @@ func TestConfig_Fields(t *testing.T) {
 		}
 	}
 }
+
+func TestConfig_Profile(t *testing.T) {
+	config := DefaultConfig()
+	if p, ok := config.Profile(config.TranslationProfile); !ok || !p.IsEmpty() {
+		t.Errorf("default profile = %+v, %v; want an empty profile", p, ok)
+	}
+
+	config.TranslationProfiles = append(config.TranslationProfiles, TranslationProfile{Name: "kids", Audience: "children"})
+	if p, ok := config.Profile("kids"); !ok || p.Audience != "children" || p.IsEmpty() {
+		t.Errorf("Profile(kids) = %+v, %v", p, ok)
+	}
+	if _, ok := config.Profile("missing"); ok {
+		t.Error("unknown profile should not be found")
+	}
+}
+
+func TestConfig_Price(t *testing.T) {
+	config := DefaultConfig()
+
+	p, ok := config.Price("tts", "openai", "tts-1-hd")
+	if !ok || p.PerMCharacters != 30 {
+		t.Errorf("tts/openai/tts-1-hd = %+v, %v", p, ok)
+	}
+	// Provider-wide price when the model has no entry
+	if p, ok := config.Price("tts", "fish-audio", "speech-1.6"); !ok || p.PerMCharacters != 15 {
+		t.Errorf("tts/fish-audio fallback = %+v, %v", p, ok)
+	}
+	if _, ok := config.Price("tts", "piper", "en_US-amy-medium"); ok {
+		t.Error("local providers should have no price")
+	}
+
+	if cost := p.Cost(0, 0, 1000, 0); cost != 0.03 {
+		t.Errorf("1000 characters at $30/M = %v, want 0.03", cost)
+	}
+	minute := UsagePrice{PerMinute: 0.006, InputPerMTokens: 1, OutputPerMTokens: 2}
+	if cost := minute.Cost(1e6, 5e5, 0, 30); cost != 2.003 {
+		t.Errorf("cost = %v, want 2.003", cost)
+	}
+}
+
+func TestConfig_RateLimit(t *testing.T) {
+	config := DefaultConfig()
+	if l, ok := config.RateLimit("translation", "openai"); !ok || l.TokensPerMinute == 0 || l.MaxConcurrency != 20 {
+		t.Errorf("translation/openai = %+v, %v", l, ok)
+	}
+	if _, ok := config.RateLimit("tts", "piper"); ok {
+		t.Error("local providers should not be rate limited")
+	}
+}
@@ -14,6 +14,7 @@ func ToInternalSubtitles(subs SubtitleList) subtitle.List {
 			StartTime: sub.StartTime,
 			EndTime:   sub.EndTime,
 			Text:      sub.Text,
+			Emotion:   sub.Emotion,
 		}
 	}
 	return result
@@ -29,6 +30,7 @@ func FromInternalSubtitles(subs subtitle.List) SubtitleList {
 			StartTime: sub.StartTime,
 			EndTime:   sub.EndTime,
 			Text:      sub.Text,
+			Emotion:   sub.Emotion,
 		}
 	}
 	return result
@@ -2,6 +2,7 @@ package models
 
 import (
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,14 +35,47 @@ type TranslationJob struct {
 	CompletedAt  *time.Time
 
 	// Translation settings
-	SourceLang string
+	SourceLang string // "auto" detects the language before transcription
 	TargetLang string
 	Voice      string
 
+	// Set when SourceLang was "auto": the detected language and the
+	// provider's confidence in it (0 if the provider doesn't report one)
+	DetectedLang           string
+	DetectedLangConfidence float64
+
+	// Vocabulary overrides Config.TranscriptionVocabulary for this job
+	Vocabulary []string
+
+	// Glossary overrides Config.Glossary for this job
+	Glossary string
+
+	// TranslationProfile overrides Config.TranslationProfile for this job
+	TranslationProfile string
+
+	// SpeakerVoices maps diarization speaker IDs to TTS voices; speakers
+	// without an entry are assigned voices automatically
+	SpeakerVoices map[string]string
+
 	// Intermediate files
 	AudioPath      string
 	TranscriptPath string
 	DubbedAudioPath string
+
+	// Report of segments dropped/flagged by the hallucination filter
+	FilterReportPath string
+
+	// Low-confidence / QA-flagged segments for a human to check
+	ReviewListPath string
+	ReviewCount    int
+
+	// Segments whose translation is still too long to speak in their
+	// window after shortening (0 when duration fitting is off)
+	OverBudgetCount int
+
+	// Why stages were switched to a free provider to stay within the
+	// spending caps (empty when none were)
+	BudgetNotes []string
 }
 
 func NewTranslationJob(inputPath string) *TranslationJob {
@@ -96,6 +130,9 @@ func (j *TranslationJob) StatusText() string {
 	case StatusMuxing:
 		return "Creating video..."
 	case StatusCompleted:
+		if len(j.BudgetNotes) > 0 {
+			return "Completed! " + strings.Join(j.BudgetNotes, "; ")
+		}
 		return "Completed!"
 	case StatusFailed:
 		if j.Error != nil {
@@ -199,6 +199,13 @@ func TestStatusText_UnknownStatus(t *testing.T) {
 	}
 }
 
+func TestStatusText_BudgetNotes(t *testing.T) {
+	job := &TranslationJob{Status: StatusCompleted, BudgetNotes: []string{"Used free edge-tts: over the job budget"}}
+	if got := job.StatusText(); got != "Completed! Used free edge-tts: over the job budget" {
+		t.Errorf("StatusText() = %q", got)
+	}
+}
+
 func TestNewTranslationJob_DefaultVoice(t *testing.T) {
 	job := NewTranslationJob("/path/to/video.mp4")
 	if job.Voice != "en-US-AriaNeural" {
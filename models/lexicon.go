@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LexiconEntry tells TTS voices how to say a word they get wrong (product
+// and people names). The respelling is spoken in the word's place;
+// providers that take phonemes use the IPA transcription when there is one.
+type LexiconEntry struct {
+	Word       string `json:"word"`
+	Respelling string `json:"respelling,omitempty"` // e.g. "koo-ber-NET-eez"
+	Phoneme    string `json:"phoneme,omitempty"`    // IPA, e.g. "ˌkuːbɚˈnɛtiːz"
+	Lang       string `json:"lang,omitempty"`       // Target language it applies to ("" = all)
+}
+
+// ParseLexicon reads entries written one per line as
+//
+//	[lang:] word = respelling [/ipa/]
+//
+// e.g. "Kubernetes = koo-ber-NET-eez", "de: Nginx = Engine X" or
+// "Huawei = WAH-way /ˈwɑːweɪ/". Blank lines and lines starting with # are
+// skipped.
+func ParseLexicon(text string) ([]LexiconEntry, error) {
+	var entries []LexiconEntry
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		left, right, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("lexicon line %d: expected \"word = respelling\"", i+1)
+		}
+
+		var entry LexiconEntry
+		if lang, word, ok := strings.Cut(left, ":"); ok && isLangTag(strings.TrimSpace(lang)) {
+			entry.Lang, left = strings.TrimSpace(lang), word
+		}
+		entry.Word = strings.TrimSpace(left)
+
+		right = strings.TrimSpace(right)
+		if strings.HasSuffix(right, "/") {
+			if start := strings.LastIndex(right[:len(right)-1], "/"); start >= 0 {
+				entry.Phoneme = strings.TrimSpace(right[start+1 : len(right)-1])
+				right = strings.TrimSpace(right[:start])
+			}
+		}
+		entry.Respelling = right
+
+		if entry.Word == "" || (entry.Respelling == "" && entry.Phoneme == "") {
+			return nil, fmt.Errorf("lexicon line %d: needs a word and a respelling or /phonemes/", i+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// isLangTag reports whether s looks like "de" or "pt-BR"
+func isLangTag(s string) bool {
+	primary, _, _ := strings.Cut(s, "-")
+	if len(primary) < 2 || len(primary) > 3 {
+		return false
+	}
+	for _, c := range primary {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatLexicon writes entries one per line the way ParseLexicon reads them
+func FormatLexicon(entries []LexiconEntry) string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		line := e.Word + " ="
+		if e.Lang != "" {
+			line = e.Lang + ": " + line
+		}
+		if e.Respelling != "" {
+			line += " " + e.Respelling
+		}
+		if e.Phoneme != "" {
+			line += " /" + e.Phoneme + "/"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,35 @@
+package models
+
+// RateLimit is what a provider allows per minute. Zero fields are not
+// limited.
+type RateLimit struct {
+	RequestsPerMinute   int `json:"requests_per_minute,omitempty"`
+	TokensPerMinute     int `json:"tokens_per_minute,omitempty"`     // LLM prompt + completion tokens
+	CharactersPerMinute int `json:"characters_per_minute,omitempty"` // TTS / machine translation input
+	MaxConcurrency      int `json:"max_concurrency,omitempty"`       // Requests in flight across all jobs
+}
+
+// DefaultRateLimits are conservative entry-tier limits of the API
+// providers, keyed "stage/provider" like the price table. Concurrency caps
+// match the per-call worker counts, but are now shared by every job.
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		"transcription/openai": {RequestsPerMinute: 50},
+		"transcription/groq":   {RequestsPerMinute: 20},
+
+		"translation/openai":         {RequestsPerMinute: 500, TokensPerMinute: 200000, MaxConcurrency: 20},
+		"translation/deepseek":       {MaxConcurrency: 25}, // No published limits
+		"translation/grok":           {RequestsPerMinute: 480, MaxConcurrency: 25},
+		"translation/libretranslate": {MaxConcurrency: 4},
+
+		"tts/openai":     {RequestsPerMinute: 500, MaxConcurrency: 25},
+		"tts/fish-audio": {MaxConcurrency: 5},
+		"tts/edge-tts":   {MaxConcurrency: 30},
+	}
+}
+
+// RateLimit returns the limit of a provider at a pipeline stage
+func (c *Config) RateLimit(stage, provider string) (RateLimit, bool) {
+	l, ok := c.RateLimits[stage+"/"+provider]
+	return l, ok
+}
@@ -7,7 +7,20 @@ type Subtitle struct {
 	StartTime time.Duration
 	EndTime   time.Duration
 	Text      string
-	Emotion   string // Fish Audio emotion tag (happy, sad, excited, etc.)
+	Emotion   string // Emotion tag voiced by the TTS provider (happy, sad, excited, etc.)
+	Speaker   string // Diarization speaker ID (SPEAKER_00, ...), empty if unknown
+
+	// Whisper decoder scores, only meaningful when HasScores is set.
+	// whisper-cpp reports token probabilities only, so NoSpeechProb is 0.
+	AvgLogprob   float64
+	NoSpeechProb float64
+	HasScores    bool
+
+	// Length budget for isochronous dubbing: the most characters the target
+	// voice can speak in the segment window (0 = no budget). FitsBudget is
+	// only meaningful when CharBudget is set.
+	CharBudget int
+	FitsBudget bool
 }
 
 type SubtitleList []Subtitle
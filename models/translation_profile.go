@@ -0,0 +1,36 @@
+package models
+
+// Translation profile formality settings
+const (
+	FormalityDefault  = ""
+	FormalityFormal   = "formal"
+	FormalityInformal = "informal"
+)
+
+// TranslationProfile is a named translation style for LLM providers:
+// how formally to address the viewer, the register, who the audience is,
+// which regional variant of the target language to write and any extra
+// instructions. Empty fields leave the choice to the model.
+type TranslationProfile struct {
+	Name         string `json:"name"`
+	Formality    string `json:"formality,omitempty"`    // FormalityFormal (T-V "you" polite form) or FormalityInformal
+	Register     string `json:"register,omitempty"`     // e.g. "casual", "technical", "literary"
+	Audience     string `json:"audience,omitempty"`     // e.g. "children", "software developers"
+	Locale       string `json:"locale,omitempty"`       // Regional variant, e.g. "pt-BR", "es-MX"
+	Instructions string `json:"instructions,omitempty"` // Free-form extra instructions
+}
+
+// IsEmpty reports whether the profile leaves every choice to the model
+func (p TranslationProfile) IsEmpty() bool {
+	return p.Formality == "" && p.Register == "" && p.Audience == "" && p.Locale == "" && p.Instructions == ""
+}
+
+// Profile returns the translation profile called name
+func (c *Config) Profile(name string) (TranslationProfile, bool) {
+	for _, p := range c.TranslationProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return TranslationProfile{}, false
+}
@@ -0,0 +1,46 @@
+package models
+
+// UsagePrice is what a provider charges, in USD. Only the units the
+// provider bills in are set.
+type UsagePrice struct {
+	InputPerMTokens  float64 `json:"input_per_m_tokens,omitempty"`  // LLM prompt tokens
+	OutputPerMTokens float64 `json:"output_per_m_tokens,omitempty"` // LLM completion tokens
+	PerMCharacters   float64 `json:"per_m_characters,omitempty"`    // TTS input
+	PerMinute        float64 `json:"per_minute,omitempty"`          // Transcribed audio
+}
+
+// Cost prices a quantity of usage
+func (p UsagePrice) Cost(inputTokens, outputTokens, characters int, audioSeconds float64) float64 {
+	return float64(inputTokens)/1e6*p.InputPerMTokens +
+		float64(outputTokens)/1e6*p.OutputPerMTokens +
+		float64(characters)/1e6*p.PerMCharacters +
+		audioSeconds/60*p.PerMinute
+}
+
+// DefaultUsagePrices are list prices of the paid providers, keyed
+// "stage/provider/model" or "stage/provider" for every model. Local and
+// free providers have no entry and cost nothing.
+func DefaultUsagePrices() map[string]UsagePrice {
+	return map[string]UsagePrice{
+		"transcription/openai": {PerMinute: 0.006},
+		"transcription/groq":   {PerMinute: 0.00185}, // $0.111/hour
+
+		"translation/openai/gpt-4o-mini":               {InputPerMTokens: 0.15, OutputPerMTokens: 0.60},
+		"translation/deepseek/deepseek-chat":           {InputPerMTokens: 0.28, OutputPerMTokens: 0.42},
+		"translation/grok/grok-4-1-fast-non-reasoning": {InputPerMTokens: 0.20, OutputPerMTokens: 0.50},
+
+		"tts/openai/tts-1":           {PerMCharacters: 15},
+		"tts/openai/tts-1-hd":        {PerMCharacters: 30},
+		"tts/openai/gpt-4o-mini-tts": {PerMCharacters: 15}, // Billed per audio token; ~$0.015/min
+		"tts/fish-audio":             {PerMCharacters: 15}, // Billed per UTF-8 byte
+	}
+}
+
+// Price returns the price of a provider's model at a pipeline stage
+func (c *Config) Price(stage, provider, model string) (UsagePrice, bool) {
+	if p, ok := c.UsagePrices[stage+"/"+provider+"/"+model]; ok && model != "" {
+		return p, true
+	}
+	p, ok := c.UsagePrices[stage+"/"+provider]
+	return p, ok
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/models"
+)
+
+// What happens when a paid stage would go over a spending cap
+const (
+	BudgetActionDowngrade = "downgrade" // Switch the stage to a free provider
+	BudgetActionAbort     = "abort"     // Fail the job
+)
+
+// budgetFallbacks are the free providers each stage downgrades to
+var budgetFallbacks = map[string]string{
+	UsageStageTranscription: "whisper-cpp",
+	UsageStageTranslation:   "argos",
+	UsageStageTTS:           "edge-tts",
+}
+
+// Rough token counts for projecting LLM translation cost. Prompts carry
+// instructions, context and the JSON wrapping around every line, so a
+// request costs a multiple of the text it translates.
+const (
+	budgetCharsPerToken     = 3.0
+	budgetInputTokenFactor  = 2.5
+	budgetOutputTokenFactor = 1.5
+	budgetBriefOutputTokens = 300 // A few sentences on topic, speakers and tone
+)
+
+// ProjectTranscriptionCost estimates transcribing audioSeconds of audio
+func ProjectTranscriptionCost(cfg *models.Config, provider, model string, audioSeconds float64) float64 {
+	price, ok := cfg.Price(UsageStageTranscription, provider, model)
+	if !ok {
+		return 0
+	}
+	return price.Cost(0, 0, 0, audioSeconds)
+}
+
+// ProjectTranslationCost estimates translating subs from their length
+func ProjectTranslationCost(cfg *models.Config, provider, model string, subs models.SubtitleList) float64 {
+	price, ok := cfg.Price(UsageStageTranslation, provider, model)
+	if !ok {
+		return 0
+	}
+	chars := subtitleChars(subs)
+	tokens := float64(chars) / budgetCharsPerToken
+	return price.Cost(int(tokens*budgetInputTokenFactor), int(tokens*budgetOutputTokenFactor), chars, 0)
+}
+
+// ProjectDocumentBriefCost estimates the one request that briefs the model
+// on the whole transcript before translating (see documentBrief)
+func ProjectDocumentBriefCost(cfg *models.Config, provider, model string, subs models.SubtitleList) float64 {
+	price, ok := cfg.Price(UsageStageTranslation, provider, model)
+	if !ok {
+		return 0
+	}
+	chars := min(subtitleChars(subs), documentBriefMaxRunes)
+	tokens := float64(chars) / budgetCharsPerToken
+	return price.Cost(int(tokens), budgetBriefOutputTokens, chars, 0)
+}
+
+// ProjectShortenCost estimates paraphrasing the lines of subs that don't
+// fit their window. Each request carries the source line too, which is
+// about as long as its translation.
+func ProjectShortenCost(cfg *models.Config, provider, model string, subs models.SubtitleList) float64 {
+	var over models.SubtitleList
+	for _, sub := range subs {
+		if sub.CharBudget > 0 && !sub.FitsBudget {
+			over = append(over, sub)
+		}
+	}
+	return 2 * ProjectTranslationCost(cfg, provider, model, over)
+}
+
+// ProjectTTSCost estimates speaking subs
+func ProjectTTSCost(cfg *models.Config, provider, model string, subs models.SubtitleList) float64 {
+	price, ok := cfg.Price(UsageStageTTS, provider, model)
+	if !ok {
+		return 0
+	}
+	return price.Cost(0, 0, subtitleChars(subs), 0)
+}
+
+func subtitleChars(subs models.SubtitleList) int {
+	var n int
+	for _, sub := range subs {
+		n += utf8.RuneCountInString(sub.Text)
+	}
+	return n
+}
+
+// BudgetExceededError reports a stage whose projected cost would take
+// spending over a cap
+type BudgetExceededError struct {
+	Stage     string
+	Provider  string
+	Projected float64
+	Scope     string // "job" or "daily"
+	Spent     float64
+	Limit     float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s with %s would cost about %s, over the %s budget (%s of %s spent)",
+		e.Stage, e.Provider, FormatCost(e.Projected), e.Scope, FormatCost(e.Spent), FormatCost(e.Limit))
+}
+
+// CheckBudget returns a *BudgetExceededError when spending projected more
+// on stage would exceed the job or daily cap. jobSpent is what the job has
+// already cost; it is part of today's ledger too.
+func CheckBudget(cfg *models.Config, ledger *UsageLedger, stage, provider string, projected, jobSpent float64) error {
+	if projected <= 0 {
+		return nil
+	}
+	if cfg.JobBudget > 0 && jobSpent+projected > cfg.JobBudget {
+		return &BudgetExceededError{Stage: stage, Provider: provider, Projected: projected,
+			Scope: "job", Spent: jobSpent, Limit: cfg.JobBudget}
+	}
+	if cfg.DailyBudget > 0 {
+		spent, err := ledger.SpentOn(time.Now())
+		if err != nil {
+			return fmt.Errorf("cannot check the daily budget: %w", err)
+		}
+		if spent+projected > cfg.DailyBudget {
+			return &BudgetExceededError{Stage: stage, Provider: provider, Projected: projected,
+				Scope: "daily", Spent: spent, Limit: cfg.DailyBudget}
+		}
+	}
+	return nil
+}
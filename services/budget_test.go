@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func TestProjectCosts(t *testing.T) {
+	cfg := models.DefaultConfig()
+	subs := models.SubtitleList{{Text: strings.Repeat("a", 600)}, {Text: strings.Repeat("б", 400)}}
+
+	// 10 min at $0.006
+	if got := ProjectTranscriptionCost(cfg, "openai", "whisper-1", 600); !closeTo(got, 0.06) {
+		t.Errorf("transcription = %v, want 0.06", got)
+	}
+	if got := ProjectTranscriptionCost(cfg, "whisper-cpp", "base", 600); got != 0 {
+		t.Errorf("local transcription = %v, want 0", got)
+	}
+
+	// 1000 chars ≈ 333 tokens: 833 in, 500 out
+	want := (833*0.28 + 500*0.42) / 1e6
+	if got := ProjectTranslationCost(cfg, "deepseek", "deepseek-chat", subs); !closeTo(got, want) {
+		t.Errorf("translation = %v, want %v", got, want)
+	}
+
+	// The brief sends the transcript once and gets a few sentences back
+	want = (333*0.28 + budgetBriefOutputTokens*0.42) / 1e6
+	if got := ProjectDocumentBriefCost(cfg, "deepseek", "deepseek-chat", subs); !closeTo(got, want) {
+		t.Errorf("brief = %v, want %v", got, want)
+	}
+
+	// Only lines over their budget are paraphrased
+	fitted := models.SubtitleList{{Text: strings.Repeat("a", 600), CharBudget: 100}, {Text: "ok", CharBudget: 100, FitsBudget: true}}
+	want = 2 * ProjectTranslationCost(cfg, "deepseek", "deepseek-chat", fitted[:1])
+	if got := ProjectShortenCost(cfg, "deepseek", "deepseek-chat", fitted); !closeTo(got, want) {
+		t.Errorf("shorten = %v, want %v", got, want)
+	}
+
+	if got := ProjectTTSCost(cfg, "openai", "tts-1-hd", subs); !closeTo(got, 0.03) {
+		t.Errorf("tts = %v, want 0.03", got)
+	}
+	if got := ProjectTTSCost(cfg, "edge-tts", "", subs); got != 0 {
+		t.Errorf("edge-tts = %v, want 0", got)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	cfg := models.DefaultConfig()
+	ledger := NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"), cfg)
+
+	if err := CheckBudget(cfg, ledger, UsageStageTTS, "openai", 100, 0); err != nil {
+		t.Errorf("no caps: %v", err)
+	}
+
+	cfg.JobBudget = 1
+	if err := CheckBudget(cfg, ledger, UsageStageTTS, "openai", 0.5, 0.4); err != nil {
+		t.Errorf("within job budget: %v", err)
+	}
+	err := CheckBudget(cfg, ledger, UsageStageTTS, "openai", 0.5, 0.6)
+	var exceeded *BudgetExceededError
+	if !errors.As(err, &exceeded) || exceeded.Scope != "job" {
+		t.Fatalf("over job budget: %v", err)
+	}
+	if msg := err.Error(); msg != "tts with openai would cost about $0.50, over the job budget ($0.60 of $1.00 spent)" {
+		t.Errorf("message = %q", msg)
+	}
+
+	// Today's spending counts towards the daily cap; yesterday's doesn't
+	cfg.JobBudget = 0
+	cfg.DailyBudget = 2
+	ledger.Record(UsageRecord{Time: time.Now().AddDate(0, 0, -1), Stage: UsageStageTTS, Provider: "openai", Model: "tts-1", Characters: 100000})
+	ledger.Record(UsageRecord{Stage: UsageStageTTS, Provider: "openai", Model: "tts-1", Characters: 100000}) // $1.50
+	if err := CheckBudget(cfg, ledger, UsageStageTTS, "openai", 0.4, 0); err != nil {
+		t.Errorf("within daily budget: %v", err)
+	}
+	err = CheckBudget(cfg, ledger, UsageStageTTS, "openai", 0.6, 0)
+	if !errors.As(err, &exceeded) || exceeded.Scope != "daily" || !closeTo(exceeded.Spent, 1.5) {
+		t.Errorf("over daily budget: %v", err)
+	}
+
+	// Free stages are never over budget
+	if err := CheckBudget(cfg, ledger, UsageStageTTS, "edge-tts", 0, 10); err != nil {
+		t.Errorf("free stage: %v", err)
+	}
+}
+
+func TestPipeline_WithinBudget(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.JobBudget = 0.01
+	cfg.BudgetAction = BudgetActionAbort
+	p := NewPipeline(cfg)
+	p.usage = NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"), cfg)
+	job := models.NewTranslationJob("talk.mp4")
+	meter := p.usage.Meter(job)
+
+	if provider, err := p.withinBudget(job, meter, UsageStageTTS, "openai", 0.005); err != nil || provider != "openai" {
+		t.Errorf("within budget = %q, %v", provider, err)
+	}
+
+	_, err := p.withinBudget(job, meter, UsageStageTTS, "openai", 0.05)
+	var exceeded *BudgetExceededError
+	if !errors.As(err, &exceeded) {
+		t.Errorf("abort = %v, want BudgetExceededError", err)
+	}
+	if len(job.BudgetNotes) != 0 {
+		t.Errorf("notes after abort = %v", job.BudgetNotes)
+	}
+}
+
+func TestUsageLedger_SpentOnKeepsRunningTotal(t *testing.T) {
+	cfg := models.DefaultConfig()
+	ledger := NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"), cfg)
+	ledger.Record(UsageRecord{Time: time.Now().AddDate(0, 0, -1), Stage: UsageStageTTS, Provider: "openai", Model: "tts-1", Characters: 100000})
+	ledger.Record(UsageRecord{Stage: UsageStageTTS, Provider: "openai", Model: "tts-1", Characters: 100000})
+
+	if spent, err := ledger.SpentOn(time.Now()); err != nil || !closeTo(spent, 1.5) {
+		t.Errorf("spent = %v, %v; want 1.5", spent, err)
+	}
+	// Records after the file was read are added to the running total
+	ledger.Record(UsageRecord{Stage: UsageStageTTS, Provider: "openai", Model: "tts-1", Characters: 100000})
+	if spent, _ := ledger.SpentOn(time.Now()); !closeTo(spent, 3) {
+		t.Errorf("spent after another record = %v, want 3", spent)
+	}
+	if spent, _ := ledger.SpentOn(time.Now().AddDate(0, 0, -1)); !closeTo(spent, 1.5) {
+		t.Errorf("spent yesterday = %v, want 1.5", spent)
+	}
+
+	if err := ledger.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if spent, _ := ledger.SpentOn(time.Now()); spent != 0 {
+		t.Errorf("spent after Clear = %v", spent)
+	}
+}
+
+func TestPipeline_VoiceRateWithinBudget(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.TTSProvider = "openai"
+	cfg.OpenAIKey = "sk-test"
+	cfg.JobBudget = 0.0001
+	p := NewPipeline(cfg)
+	p.usage = NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"), cfg)
+	meter := p.usage.Meter(models.NewTranslationJob("talk.mp4"))
+
+	// The probe is refused before anything is sent to the API
+	spoken := models.SubtitleList{{Text: strings.Repeat("word ", 40)}}
+	_, err := p.voiceRate("openai", "alloy", "en", spoken, meter, t.TempDir())
+	var exceeded *BudgetExceededError
+	if !errors.As(err, &exceeded) {
+		t.Errorf("voice rate over budget = %v, want BudgetExceededError", err)
+	}
+}
+
+func TestPipeline_BudgetFallbackVoice(t *testing.T) {
+	cfg := models.DefaultConfig()
+	p := NewPipeline(cfg)
+	if got := p.budgetFallbackVoice("en"); got != cfg.EdgeTTSVoice {
+		t.Errorf("en voice = %q, want configured %q", got, cfg.EdgeTTSVoice)
+	}
+	if got := p.budgetFallbackVoice("de"); !strings.HasPrefix(got, "de-") {
+		t.Errorf("de voice = %q", got)
+	}
+}
+
+func TestNewPipeline_CreatesBudgetFallbackTTS(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.TTSProvider = "openai"
+	if p := NewPipeline(cfg); p.edgeTTS != nil {
+		t.Error("Edge TTS created without a budget")
+	}
+
+	// Jobs share the pipeline, so the fallback exists before any runs
+	cfg.JobBudget = 1
+	if p := NewPipeline(cfg); p.edgeTTS == nil {
+		t.Error("Edge TTS not created for downgrading jobs over budget")
+	}
+	cfg.BudgetAction = BudgetActionAbort
+	if p := NewPipeline(cfg); p.edgeTTS != nil {
+		t.Error("Edge TTS created though jobs over budget abort")
+	}
+}
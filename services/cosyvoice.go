@@ -11,36 +11,59 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"video-translator/internal/config"
 	internalhttp "video-translator/internal/http"
 	"video-translator/internal/logger"
-	"video-translator/internal/media"
-	"video-translator/internal/worker"
-	"video-translator/models"
+	"video-translator/internal/tts"
 )
 
-// cosyJobData contains data for a CosyVoice TTS job.
-type cosyJobData struct {
-	index int
-	text  string
-	start time.Duration
-	end   time.Duration
-}
-
 // CosyVoiceService handles text-to-speech with voice cloning using CosyVoice
 // CosyVoice is an open-source TTS system from Alibaba that supports zero-shot voice cloning
 type CosyVoiceService struct {
+	*tts.BaseTTS
+
 	installPath     string // Path to CosyVoice installation
 	mode            string // "local" or "api"
 	apiURL          string // API endpoint if using api mode
 	voiceSamplePath string // Path to voice sample for cloning
 	pythonPath      string
-	ffmpeg          *FFmpegService
-	tempDir         string
+
+	// Local mode keeps the model loaded in one worker; concurrent lines
+	// queue on it rather than loading the model several times over
+	localWorker *PythonWorker
 }
 
+// cosyVoiceWorkerScript loads the model once and caches recent voice samples
+const cosyVoiceWorkerScript = `import torch
+import torchaudio
+from collections import OrderedDict
+from cosyvoice.cli.cosyvoice import CosyVoice
+from cosyvoice.utils.file_utils import load_wav
+
+cosyvoice = CosyVoice('pretrained_models/CosyVoice-300M')
+# Loaded samples, least recently used first; jobs pass per-job temp paths,
+# so only the last few are kept
+_prompts = OrderedDict()
+_MAX_PROMPTS = 8
+
+def handle_synthesize(params):
+    sample = params["sample"]
+    if sample in _prompts:
+        _prompts.move_to_end(sample)
+    else:
+        _prompts[sample] = load_wav(sample, 22050)
+        if len(_prompts) > _MAX_PROMPTS:
+            _prompts.popitem(last=False)
+    output = cosyvoice.inference_zero_shot(params["text"], "Target voice sample", _prompts[sample])
+    for audio in output:
+        torchaudio.save(params["output"], audio['tts_speech'], 22050)
+        return "ok"
+    raise RuntimeError("no audio generated")
+`
+
+var _ tts.Service = (*CosyVoiceService)(nil)
+
 // NewCosyVoiceService creates a new CosyVoice TTS service
 func NewCosyVoiceService(installPath, mode, apiURL, voiceSamplePath, pythonPath string) *CosyVoiceService {
 	if mode == "" {
@@ -55,18 +78,30 @@ func NewCosyVoiceService(installPath, mode, apiURL, voiceSamplePath, pythonPath
 		installPath = filepath.Join(homeDir, ".cosyvoice")
 	}
 
-	tempDir := filepath.Join(os.TempDir(), "video-translator-cosyvoice")
-	os.MkdirAll(tempDir, 0755)
-
-	return &CosyVoiceService{
+	s := &CosyVoiceService{
 		installPath:     installPath,
 		mode:            mode,
 		apiURL:          apiURL,
 		voiceSamplePath: voiceSamplePath,
 		pythonPath:      pythonPath,
-		ffmpeg:          NewFFmpegService(),
-		tempDir:         tempDir,
-	}
+		localWorker: NewPythonWorker(PythonWorkerConfig{
+			Name:             "CosyVoice",
+			PythonPath:       pythonPath,
+			Dir:              installPath,
+			Script:           cosyVoiceWorkerScript,
+			StartTimeout:     config.PythonWorkerStartTimeout,
+			RequestTimeout:   config.ExecTimeoutPython,
+			HealthCheckAfter: config.PythonWorkerHealthCheckAfter,
+		}),
+	}
+	// GPU-intensive local TTS
+	s.BaseTTS = tts.NewBaseTTS("CosyVoice", "video-translator-cosyvoice", config.DynamicWorkerCount("tts-local"), s)
+	return s
+}
+
+// Close stops the local model worker
+func (s *CosyVoiceService) Close() {
+	s.localWorker.Close()
 }
 
 // CheckInstalled verifies CosyVoice is available
@@ -111,26 +146,16 @@ func (s *CosyVoiceService) SetVoiceSample(samplePath string) error {
 	return nil
 }
 
-// ExtractVoiceSample extracts a voice sample from a video/audio file
+// ExtractVoiceSample extracts a voice sample from a video/audio file and
+// clones it from then on
 func (s *CosyVoiceService) ExtractVoiceSample(inputPath, outputPath string, startSec, durationSec float64) error {
 	if durationSec == 0 {
 		durationSec = 10 // Default 10 seconds
 	}
 
-	args := []string{
-		"-i", inputPath,
-		"-ss", fmt.Sprintf("%.2f", startSec),
-		"-t", fmt.Sprintf("%.2f", durationSec),
-		"-ar", "22050", // 22kHz for CosyVoice
-		"-ac", "1",     // Mono
-		"-y",
-		outputPath,
-	}
-
-	cmd := exec.Command(s.ffmpeg.GetPath(), args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to extract voice sample: %w\nOutput: %s", err, string(output))
+	ffmpeg := NewFFmpegServiceWithPath(s.FFmpeg.GetPath())
+	if err := ffmpeg.ExtractVoiceSample(inputPath, outputPath, startSec, durationSec); err != nil {
+		return err
 	}
 
 	s.voiceSamplePath = outputPath
@@ -139,68 +164,74 @@ func (s *CosyVoiceService) ExtractVoiceSample(inputPath, outputPath string, star
 
 // Synthesize generates audio from text using CosyVoice with voice cloning
 func (s *CosyVoiceService) Synthesize(text, outputPath string) error {
-	logger.LogInfo("CosyVoice: mode=%s sample=%s", s.mode, s.voiceSamplePath)
+	return s.SynthesizeSegment(tts.Segment{Text: text}, outputPath)
+}
+
+// SynthesizeSegment generates a segment's audio in the sample's voice
+// (CosyVoice has no emotion control)
+func (s *CosyVoiceService) SynthesizeSegment(seg tts.Segment, outputPath string) error {
+	sample := seg.VoiceOr(s.voiceSamplePath)
+	logger.LogInfo("CosyVoice: mode=%s sample=%s", s.mode, sample)
 
-	if text == "" {
-		return fmt.Errorf("empty text provided")
+	if seg.Text == "" {
+		return tts.Permanent(fmt.Errorf("empty text provided"))
 	}
 
-	if s.voiceSamplePath == "" {
-		return fmt.Errorf("voice sample is required for voice cloning")
+	if sample == "" {
+		return tts.Permanent(fmt.Errorf("voice sample is required for voice cloning. Use SetVoiceSample() first"))
 	}
 
+	var err error
 	if s.mode == "api" {
-		return s.synthesizeViaAPI(text, outputPath)
+		err = s.synthesizeViaAPI(seg.Text, sample, outputPath)
+	} else {
+		err = s.synthesizeLocal(seg.Text, sample, outputPath)
 	}
-
-	return s.synthesizeLocal(text, outputPath)
+	if err != nil {
+		return err
+	}
+	seg.RecordUsage(UsageStageTTS, "cosyvoice", s.mode, seg.Text)
+	return nil
 }
 
-// synthesizeLocal uses local CosyVoice installation
-func (s *CosyVoiceService) synthesizeLocal(text, outputPath string) error {
-	// Python script to run CosyVoice for zero-shot voice cloning
-	script := fmt.Sprintf(`
-import sys
-import torch
-from cosyvoice.cli.cosyvoice import CosyVoice
-from cosyvoice.utils.file_utils import load_wav
-import torchaudio
-
-# Load CosyVoice model
-cosyvoice = CosyVoice('pretrained_models/CosyVoice-300M')
-
-# Load voice sample for cloning
-prompt_speech = load_wav("%s", 22050)
-
-# Synthesize with voice cloning (zero-shot)
-text = """%s"""
-
-output = cosyvoice.inference_zero_shot(
-    text,
-    "Target voice sample",
-    prompt_speech
-)
+// CacheKey identifies the cloned voice for the segment cache. The sample
+// file's size and modification time stand in for its content, so
+// re-extracting a sample to the same path doesn't reuse stale speech.
+func (s *CosyVoiceService) CacheKey(voice string) string {
+	if voice == "" {
+		voice = s.voiceSamplePath
+	}
+	info, err := os.Stat(voice)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("cosyvoice|%s|%s|%d|%d", s.mode, voice, info.Size(), info.ModTime().UnixNano())
+}
 
-# Save output
-for i, audio in enumerate(output):
-    torchaudio.save("%s", audio['tts_speech'], 22050)
-    break  # Only need first output
+// SetVoice sets the voice sample to clone (the sample is the voice)
+func (s *CosyVoiceService) SetVoice(samplePath string) {
+	if samplePath != "" {
+		s.voiceSamplePath = samplePath
+	}
+}
 
-print("DONE")
-`, s.voiceSamplePath, text, outputPath)
+// GetVoice returns the voice sample being cloned
+func (s *CosyVoiceService) GetVoice() string {
+	return s.voiceSamplePath
+}
 
-	cmd := exec.Command(s.pythonPath, "-c", script)
-	cmd.Dir = s.installPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("CosyVoice synthesis failed: %w\nOutput: %s", err, string(output))
+// synthesizeLocal uses the local CosyVoice installation for zero-shot
+// voice cloning
+func (s *CosyVoiceService) synthesizeLocal(text, samplePath, outputPath string) error {
+	params := map[string]string{"text": text, "sample": samplePath, "output": outputPath}
+	if err := s.localWorker.Call("synthesize", params, nil); err != nil {
+		return fmt.Errorf("CosyVoice synthesis failed: %w", err)
 	}
-
 	return nil
 }
 
 // synthesizeViaAPI uses CosyVoice API
-func (s *CosyVoiceService) synthesizeViaAPI(text, outputPath string) error {
+func (s *CosyVoiceService) synthesizeViaAPI(text, samplePath, outputPath string) error {
 	// Create multipart form with text and voice sample
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
@@ -211,13 +242,13 @@ func (s *CosyVoiceService) synthesizeViaAPI(text, outputPath string) error {
 	}
 
 	// Add voice sample file
-	sampleFile, err := os.Open(s.voiceSamplePath)
+	sampleFile, err := os.Open(samplePath)
 	if err != nil {
 		return fmt.Errorf("failed to open voice sample: %w", err)
 	}
 	defer sampleFile.Close()
 
-	part, err := writer.CreateFormFile("voice_sample", filepath.Base(s.voiceSamplePath))
+	part, err := writer.CreateFormFile("voice_sample", filepath.Base(samplePath))
 	if err != nil {
 		return fmt.Errorf("failed to create form file: %w", err)
 	}
@@ -264,113 +295,6 @@ func (s *CosyVoiceService) synthesizeViaAPI(text, outputPath string) error {
 	return nil
 }
 
-// SynthesizeSubtitles generates audio for all subtitles with proper timing
-func (s *CosyVoiceService) SynthesizeSubtitles(subs models.SubtitleList, outputPath string) error {
-	return s.SynthesizeWithCallback(subs, outputPath, nil)
-}
-
-// SynthesizeWithCallback generates audio for subtitles with progress callback
-// Uses internal worker pool for parallel processing (GPU-intensive, keep workers low)
-func (s *CosyVoiceService) SynthesizeWithCallback(
-	subs models.SubtitleList,
-	outputPath string,
-	onProgress func(current, total int),
-) error {
-	logger.LogInfo("CosyVoice: synthesizing %d subtitles with %d workers", len(subs), config.DynamicWorkerCount("tts-local"))
-
-	if len(subs) == 0 {
-		return fmt.Errorf("no subtitles provided")
-	}
-
-	if err := s.CheckInstalled(); err != nil {
-		return err
-	}
-
-	if s.voiceSamplePath == "" {
-		return fmt.Errorf("voice sample is required for voice cloning. Use SetVoiceSample() first")
-	}
-
-	// Create temp directory for segments
-	segmentDir := filepath.Join(s.tempDir, fmt.Sprintf("segments_%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(segmentDir, 0755); err != nil {
-		return err
-	}
-	defer os.RemoveAll(segmentDir)
-
-	// Identify which subtitles need TTS (non-empty text)
-	var jobs []cosyJobData
-	for i, sub := range subs {
-		if strings.TrimSpace(sub.Text) != "" {
-			jobs = append(jobs, cosyJobData{
-				index: i,
-				text:  sub.Text,
-				start: sub.StartTime,
-				end:   sub.EndTime,
-			})
-		}
-	}
-
-	// Process TTS jobs in parallel using internal worker pool
-	speechPaths := make(map[int]string)
-	total := len(subs)
-
-	if len(jobs) > 0 {
-		// Process function for worker pool
-		processJob := func(job worker.Job[cosyJobData]) (string, error) {
-			data := job.Data
-			speechPath := filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", data.index))
-
-			// Synthesize the text
-			if err := s.Synthesize(data.text, speechPath); err != nil {
-				return "", err
-			}
-
-			// Adjust duration if needed
-			targetDuration := (data.end - data.start).Seconds()
-			if targetDuration > 0.2 {
-				adjustedPath := filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", data.index))
-				if err := s.ffmpeg.AdjustAudioDuration(speechPath, adjustedPath, targetDuration); err == nil {
-					return adjustedPath, nil
-				}
-			}
-
-			return speechPath, nil
-		}
-
-		// Progress callback adapter
-		var progressCallback worker.ProgressFunc
-		if onProgress != nil {
-			progressCallback = func(completed, _ int) {
-				onProgress(completed, total)
-			}
-		}
-
-		// Run worker pool with dynamic worker count (GPU-intensive local TTS)
-		workers := config.DynamicWorkerCount("tts-local")
-		results, err := worker.Process(jobs, workers, processJob, progressCallback)
-		if err != nil {
-			return fmt.Errorf("TTS synthesis failed: %w", err)
-		}
-
-		// Collect speech paths by index
-		for i, path := range results {
-			speechPaths[jobs[i].index] = path
-		}
-	}
-
-	// Build final audio using AudioAssembler with parallel gap processing
-	internalSubs := models.ToInternalSubtitles(subs)
-	ffmpegMedia := media.NewFFmpegServiceWithPath(s.ffmpeg.GetPath())
-	assembler := media.NewAudioAssembler(ffmpegMedia, segmentDir)
-
-	if err := assembler.AssembleFromSpeechPathsParallel(internalSubs, speechPaths, outputPath); err != nil {
-		return fmt.Errorf("failed to assemble audio: %w", err)
-	}
-
-	return nil
-}
-
-
 // GetVoiceSamplePath returns the current voice sample path
 func (s *CosyVoiceService) GetVoiceSamplePath() string {
 	return s.voiceSamplePath
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// SpeakerTurn is a stretch of audio attributed to one speaker
+type SpeakerTurn struct {
+	Start   float64 `json:"start"` // seconds
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker"`
+}
+
+// Diarizer splits audio into speaker turns ("who spoke when")
+type Diarizer interface {
+	// CheckInstalled verifies the diarization backend is available
+	CheckInstalled() error
+
+	// Diarize returns speaker turns for the audio file in time order
+	Diarize(audioPath string) ([]SpeakerTurn, error)
+}
+
+// PyannoteDiarizer runs pyannote.audio through an inline Python script,
+// the same way FasterWhisperService drives faster-whisper
+type PyannoteDiarizer struct {
+	pythonPath  string
+	hfToken     string // HuggingFace token (pyannote models are gated)
+	maxSpeakers int    // 0 lets pyannote decide
+}
+
+// pyannoteModel is the pretrained diarization pipeline to load
+const pyannoteModel = "pyannote/speaker-diarization-3.1"
+
+// NewPyannoteDiarizer creates a diarizer backed by pyannote.audio
+func NewPyannoteDiarizer(pythonPath, hfToken string, maxSpeakers int) *PyannoteDiarizer {
+	if pythonPath == "" {
+		pythonPath = "python3"
+	}
+	return &PyannoteDiarizer{
+		pythonPath:  pythonPath,
+		hfToken:     hfToken,
+		maxSpeakers: maxSpeakers,
+	}
+}
+
+// CheckInstalled verifies pyannote.audio is importable
+func (d *PyannoteDiarizer) CheckInstalled() error {
+	cmd := exec.Command(d.pythonPath, "-c", "import pyannote.audio; print('ok')")
+	if output, err := cmd.Output(); err != nil || !strings.Contains(string(output), "ok") {
+		return fmt.Errorf("pyannote.audio not installed. Run: pip install pyannote.audio")
+	}
+	if d.hfToken == "" {
+		return fmt.Errorf("pyannote requires a HuggingFace token (accept the %s model terms first)", pyannoteModel)
+	}
+	return nil
+}
+
+// Diarize runs the pyannote pipeline and parses one JSON turn per stdout line
+func (d *PyannoteDiarizer) Diarize(audioPath string) ([]SpeakerTurn, error) {
+	logger.LogInfo("Pyannote: diarizing %s (max_speakers=%d)", filepath.Base(audioPath), d.maxSpeakers)
+
+	if err := d.CheckInstalled(); err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`
+import sys, json
+from pyannote.audio import Pipeline
+
+print("LOADING_MODEL", file=sys.stderr, flush=True)
+pipeline = Pipeline.from_pretrained("%s", use_auth_token='%s')
+
+try:
+    import torch
+    if torch.cuda.is_available():
+        pipeline.to(torch.device("cuda"))
+except Exception:
+    pass
+
+kwargs = {}
+if %d > 0:
+    kwargs["max_speakers"] = %d
+
+print("DIARIZING", file=sys.stderr, flush=True)
+diarization = pipeline('%s', **kwargs)
+
+for turn, _, speaker in diarization.itertracks(yield_label=True):
+    print(json.dumps({"start": turn.start, "end": turn.end, "speaker": speaker}), flush=True)
+`, pyannoteModel, text.EscapeForPython(d.hfToken), d.maxSpeakers, d.maxSpeakers, text.EscapeForPython(audioPath))
+
+	cmd := exec.Command(d.pythonPath, "-c", script)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			logger.LogError("pyannote failed. Output:\n%s", string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("pyannote diarization failed: %w", err)
+	}
+
+	return parseSpeakerTurns(string(output))
+}
+
+// parseSpeakerTurns parses line-delimited JSON turns, ignoring non-JSON lines
+func parseSpeakerTurns(output string) ([]SpeakerTurn, error) {
+	var turns []SpeakerTurn
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var turn SpeakerTurn
+		if err := json.Unmarshal([]byte(line), &turn); err != nil {
+			return nil, fmt.Errorf("failed to parse speaker turn %q: %w", line, err)
+		}
+		turns = append(turns, turn)
+	}
+
+	sort.Slice(turns, func(i, j int) bool { return turns[i].Start < turns[j].Start })
+	return turns, nil
+}
+
+// AssignSpeakers labels each subtitle with the speaker whose turns overlap it
+// the most. Subtitles that overlap no turn take the nearest turn's speaker.
+func AssignSpeakers(subs models.SubtitleList, turns []SpeakerTurn) models.SubtitleList {
+	if len(turns) == 0 {
+		return subs
+	}
+
+	result := make(models.SubtitleList, len(subs))
+	for i, sub := range subs {
+		start, end := sub.StartTime.Seconds(), sub.EndTime.Seconds()
+
+		overlap := make(map[string]float64)
+		for _, t := range turns {
+			if lo, hi := max(start, t.Start), min(end, t.End); hi > lo {
+				overlap[t.Speaker] += hi - lo
+			}
+		}
+
+		best, bestOverlap := "", 0.0
+		for speaker, o := range overlap {
+			if o > bestOverlap || (o == bestOverlap && speaker < best) {
+				best, bestOverlap = speaker, o
+			}
+		}
+		if best == "" {
+			best = nearestSpeaker(start, end, turns)
+		}
+
+		sub.Speaker = best
+		result[i] = sub
+	}
+
+	return result
+}
+
+func nearestSpeaker(start, end float64, turns []SpeakerTurn) string {
+	best, bestGap := "", -1.0
+	for _, t := range turns {
+		gap := max(t.Start-end, start-t.End)
+		if bestGap < 0 || gap < bestGap {
+			best, bestGap = t.Speaker, gap
+		}
+	}
+	return best
+}
+
+// Speakers returns the distinct speaker IDs in order of first appearance
+func Speakers(subs models.SubtitleList) []string {
+	var speakers []string
+	seen := make(map[string]bool)
+	for _, sub := range subs {
+		if sub.Speaker != "" && !seen[sub.Speaker] {
+			seen[sub.Speaker] = true
+			speakers = append(speakers, sub.Speaker)
+		}
+	}
+	return speakers
+}
+
+// AssignSpeakerVoices maps every speaker to a TTS voice. Explicit mappings
+// win; the first unmapped speaker gets the job's primary voice and the rest
+// get unused candidates in order. When candidates run out, voices repeat.
+func AssignSpeakerVoices(speakers []string, explicit map[string]string, primary string, candidates []string) map[string]string {
+	voices := make(map[string]string, len(speakers))
+	used := make(map[string]bool)
+	for _, speaker := range speakers {
+		if v, ok := explicit[speaker]; ok && v != "" {
+			voices[speaker] = v
+			used[v] = true
+		}
+	}
+
+	var pool []string
+	if !used[primary] {
+		pool = append(pool, primary)
+	}
+	for _, c := range candidates {
+		if c != primary && !used[c] {
+			pool = append(pool, c)
+		}
+	}
+	if len(pool) == 0 {
+		pool = []string{primary}
+	}
+
+	next := 0
+	for _, speaker := range speakers {
+		if _, ok := voices[speaker]; ok {
+			continue
+		}
+		voices[speaker] = pool[next%len(pool)]
+		next++
+	}
+
+	return voices
+}
+
+// filterBySpeaker returns the subtitles spoken by speaker
+func filterBySpeaker(subs models.SubtitleList, speaker string) models.SubtitleList {
+	var result models.SubtitleList
+	for _, sub := range subs {
+		if sub.Speaker == speaker {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+// copySpeakers carries speaker labels from the source transcript onto the
+// translated subtitles, which translation services rebuild from scratch
+func copySpeakers(source, translated models.SubtitleList) {
+	byStart := make(map[time.Duration]string, len(source))
+	for _, sub := range source {
+		byStart[sub.StartTime] = sub.Speaker
+	}
+	for i := range translated {
+		if speaker, ok := byStart[translated[i].StartTime]; ok {
+			translated[i].Speaker = speaker
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+// fakeDiarizer returns canned speaker turns
+type fakeDiarizer struct {
+	turns []SpeakerTurn
+	err   error
+}
+
+func (f *fakeDiarizer) CheckInstalled() error { return f.err }
+
+func (f *fakeDiarizer) Diarize(audioPath string) ([]SpeakerTurn, error) {
+	return f.turns, f.err
+}
+
+var _ Diarizer = (*fakeDiarizer)(nil)
+var _ Diarizer = (*PyannoteDiarizer)(nil)
+
+func dialogue() models.SubtitleList {
+	return models.SubtitleList{
+		{Index: 1, StartTime: 0, EndTime: 2 * time.Second, Text: "Hi, how are you?"},
+		{Index: 2, StartTime: 2500 * time.Millisecond, EndTime: 4 * time.Second, Text: "Fine, thanks."},
+		{Index: 3, StartTime: 4 * time.Second, EndTime: 7 * time.Second, Text: "Great to hear."},
+		{Index: 4, StartTime: 20 * time.Second, EndTime: 21 * time.Second, Text: "Bye."},
+	}
+}
+
+func TestAssignSpeakers(t *testing.T) {
+	d := &fakeDiarizer{turns: []SpeakerTurn{
+		{Start: 0, End: 2.2, Speaker: "SPEAKER_00"},
+		{Start: 2.3, End: 4.1, Speaker: "SPEAKER_01"},
+		{Start: 4.1, End: 7, Speaker: "SPEAKER_00"},
+		{Start: 7, End: 7.5, Speaker: "SPEAKER_01"},
+	}}
+	turns, err := d.Diarize("audio.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := AssignSpeakers(dialogue(), turns)
+
+	want := []string{"SPEAKER_00", "SPEAKER_01", "SPEAKER_00", "SPEAKER_01"} // last one: nearest turn
+	for i, w := range want {
+		if got[i].Speaker != w {
+			t.Errorf("subtitle %d speaker = %q, want %q", i+1, got[i].Speaker, w)
+		}
+	}
+
+	speakers := Speakers(got)
+	if len(speakers) != 2 || speakers[0] != "SPEAKER_00" || speakers[1] != "SPEAKER_01" {
+		t.Errorf("Speakers() = %v", speakers)
+	}
+}
+
+func TestAssignSpeakers_NoTurns(t *testing.T) {
+	d := &fakeDiarizer{err: errors.New("no model")}
+	turns, _ := d.Diarize("audio.wav")
+
+	got := AssignSpeakers(dialogue(), turns)
+	if len(Speakers(got)) != 0 {
+		t.Error("subtitles should stay unlabelled without turns")
+	}
+}
+
+func TestAssignSpeakerVoices(t *testing.T) {
+	speakers := []string{"A", "B", "C"}
+	candidates := []string{"en-US-AriaNeural", "en-US-GuyNeural", "en-US-JennyNeural"}
+
+	voices := AssignSpeakerVoices(speakers, map[string]string{"B": "en-US-AriaNeural"}, "en-US-AriaNeural", candidates)
+
+	if voices["B"] != "en-US-AriaNeural" {
+		t.Errorf("explicit mapping ignored: B = %q", voices["B"])
+	}
+	if voices["A"] != "en-US-GuyNeural" || voices["C"] != "en-US-JennyNeural" {
+		t.Errorf("unexpected automatic voices: %v", voices)
+	}
+
+	// Primary goes to the first speaker when it isn't mapped explicitly
+	voices = AssignSpeakerVoices(speakers, nil, "nova", []string{"alloy", "nova"})
+	if voices["A"] != "nova" || voices["B"] != "alloy" || voices["C"] != "nova" {
+		t.Errorf("unexpected voices with wrap-around: %v", voices)
+	}
+}
+
+func TestParseSpeakerTurns(t *testing.T) {
+	output := `some warning from torch
+{"start": 3.5, "end": 5.0, "speaker": "SPEAKER_01"}
+{"start": 0.0, "end": 3.2, "speaker": "SPEAKER_00"}
+`
+	turns, err := parseSpeakerTurns(output)
+	if err != nil {
+		t.Fatalf("parseSpeakerTurns() error = %v", err)
+	}
+	if len(turns) != 2 || turns[0].Speaker != "SPEAKER_00" || turns[1].Start != 3.5 {
+		t.Errorf("parseSpeakerTurns() = %+v", turns)
+	}
+
+	if _, err := parseSpeakerTurns(`{"start": "x"}`); err == nil {
+		t.Error("parseSpeakerTurns() should fail on malformed JSON")
+	}
+}
+
+func TestCopySpeakers(t *testing.T) {
+	source := AssignSpeakers(dialogue(), []SpeakerTurn{
+		{Start: 0, End: 3, Speaker: "A"},
+		{Start: 3, End: 30, Speaker: "B"},
+	})
+	translated := dialogue() // translation services drop the speaker field
+
+	copySpeakers(source, translated)
+
+	for i := range translated {
+		if translated[i].Speaker != source[i].Speaker {
+			t.Errorf("subtitle %d speaker = %q, want %q", i+1, translated[i].Speaker, source[i].Speaker)
+		}
+	}
+	if n := len(filterBySpeaker(translated, "B")); n != 3 {
+		t.Errorf("filterBySpeaker(B) returned %d subtitles, want 3", n)
+	}
+}
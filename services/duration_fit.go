@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/internal/logger"
+	"video-translator/models"
+)
+
+// DurationBudgeter turns segment windows into per-segment character budgets
+// so translations can be spoken in the time the original speaker took,
+// instead of being sped up and then cut off by AdjustAudioDuration.
+type DurationBudgeter struct {
+	// CharsPerSecond is the target voice's speaking rate. Starts from a
+	// per-language estimate and is replaced by a measurement when possible.
+	CharsPerSecond float64
+
+	// SpeedUpAllowance is how much faster than natural the TTS stage may
+	// play a line (AdjustAudioDuration caps at 1.3x; stay well below that)
+	SpeedUpAllowance float64
+
+	// MinBudget keeps very short windows from getting unusable budgets
+	MinBudget int
+}
+
+// Typical TTS speaking rates in characters per second. Scripts that pack a
+// syllable or word into one character are much slower per character.
+var defaultCharsPerSecond = map[string]float64{
+	"zh": 5,
+	"ja": 7,
+	"ko": 7,
+	"th": 11,
+	"ar": 12,
+	"he": 12,
+	"hi": 12,
+	"ru": 14,
+	"uk": 14,
+	"de": 14,
+	"en": 15,
+	"fr": 15,
+	"it": 15,
+	"pt": 15,
+	"es": 16,
+}
+
+// fallbackCharsPerSecond is used for languages missing from the table
+const fallbackCharsPerSecond = 14.0
+
+// Plausible measured rates; anything outside means the measurement failed
+// (silence padding, a truncated file) rather than an unusual voice
+const (
+	minPlausibleCharsPerSecond = 3.0
+	maxPlausibleCharsPerSecond = 40.0
+)
+
+// NewDurationBudgeter creates a budgeter with the estimated rate for lang
+func NewDurationBudgeter(lang string) *DurationBudgeter {
+	cps, ok := defaultCharsPerSecond[lang]
+	if !ok {
+		cps = fallbackCharsPerSecond
+	}
+	return &DurationBudgeter{
+		CharsPerSecond:   cps,
+		SpeedUpAllowance: 1.1,
+		MinBudget:        8,
+	}
+}
+
+// Budget returns the most characters that fit in a window of d
+func (b *DurationBudgeter) Budget(d time.Duration) int {
+	budget := int(d.Seconds() * b.CharsPerSecond * b.SpeedUpAllowance)
+	return max(budget, b.MinBudget)
+}
+
+// Apply sets CharBudget on every non-empty subtitle from its window.
+// spoken, when given, is subs as the voice will read them (see
+// PrepareSpeech): the budget stays one for the written text, less however
+// much longer each line is spoken, so "2024" costs "twenty twenty-four".
+func (b *DurationBudgeter) Apply(subs, spoken models.SubtitleList) {
+	for i := range subs {
+		if strings.TrimSpace(subs[i].Text) == "" {
+			subs[i].CharBudget = 0
+			continue
+		}
+		budget := b.Budget(subs[i].EndTime - subs[i].StartTime)
+		if i < len(spoken) {
+			budget -= textLength(spoken[i].Text) - textLength(subs[i].Text)
+		}
+		subs[i].CharBudget = max(budget, 1) // 0 would mean no budget
+	}
+}
+
+// textLength counts the characters a line's budget is measured in
+func textLength(s string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(s))
+}
+
+// MarkFit records on each budgeted subtitle whether its text fits, and
+// returns how many don't
+func MarkFit(subs models.SubtitleList) int {
+	over := 0
+	for i := range subs {
+		if subs[i].CharBudget <= 0 {
+			continue
+		}
+		subs[i].FitsBudget = textLength(subs[i].Text) <= subs[i].CharBudget
+		if !subs[i].FitsBudget {
+			over++
+		}
+	}
+	return over
+}
+
+// speechSynthesizer is the single-line synthesis every TTS service provides
+type speechSynthesizer interface {
+	Synthesize(text, outputPath string) error
+}
+
+// voiceRateSampleRunes is how much translated text is spoken to measure a
+// voice's rate: long enough to average out pauses, short enough to be quick
+const voiceRateSampleRunes = 150
+
+// voiceRateSample joins translated lines into a sample for measuring the
+// voice's speaking rate. Very short lines are skipped since their leading
+// and trailing silence dominates.
+func voiceRateSample(subs models.SubtitleList) string {
+	var parts []string
+	runes := 0
+	for _, sub := range subs {
+		t := strings.TrimSpace(sub.Text)
+		n := utf8.RuneCountInString(t)
+		if n < 20 {
+			continue
+		}
+		parts = append(parts, t)
+		runes += n
+		if runes >= voiceRateSampleRunes {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// MeasureVoiceRate synthesizes sample with synth and returns the speaking
+// rate in characters per second
+func MeasureVoiceRate(synth speechSynthesizer, ffmpeg *FFmpegService, sample, workDir string) (float64, error) {
+	if sample == "" {
+		return 0, fmt.Errorf("no text long enough to measure the voice")
+	}
+
+	path := filepath.Join(workDir, fmt.Sprintf("voice_rate_%d.wav", time.Now().UnixNano()))
+	if err := synth.Synthesize(sample, path); err != nil {
+		return 0, fmt.Errorf("failed to synthesize sample: %w", err)
+	}
+	duration, err := ffmpeg.GetAudioDuration(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure sample: %w", err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("sample has no duration")
+	}
+
+	rate := float64(utf8.RuneCountInString(sample)) / duration
+	if rate < minPlausibleCharsPerSecond || rate > maxPlausibleCharsPerSecond {
+		return 0, fmt.Errorf("implausible rate %.1f chars/sec", rate)
+	}
+	logger.LogDebug("Voice rate: %d chars in %.2fs = %.1f chars/sec", utf8.RuneCountInString(sample), duration, rate)
+	return rate, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func TestDurationBudgeter_Budget(t *testing.T) {
+	b := NewDurationBudgeter("en")
+	b.CharsPerSecond = 15
+	b.SpeedUpAllowance = 1.0
+
+	if got := b.Budget(2 * time.Second); got != 30 {
+		t.Errorf("Budget(2s) = %d, want 30", got)
+	}
+	if got := b.Budget(100 * time.Millisecond); got != b.MinBudget {
+		t.Errorf("Budget(100ms) = %d, want MinBudget %d", got, b.MinBudget)
+	}
+
+	if NewDurationBudgeter("ja").CharsPerSecond >= NewDurationBudgeter("en").CharsPerSecond {
+		t.Error("Japanese should be budgeted fewer characters per second than English")
+	}
+	if NewDurationBudgeter("xx").CharsPerSecond != fallbackCharsPerSecond {
+		t.Error("unknown language should use the fallback rate")
+	}
+}
+
+func TestDurationBudgeter_ApplyAndMarkFit(t *testing.T) {
+	b := &DurationBudgeter{CharsPerSecond: 10, SpeedUpAllowance: 1.0, MinBudget: 1}
+	subs := models.SubtitleList{
+		{StartTime: 0, EndTime: time.Second, Text: "short"},
+		{StartTime: time.Second, EndTime: 2 * time.Second, Text: "this line is far too long"},
+		{StartTime: 2 * time.Second, EndTime: 3 * time.Second, Text: "  "},
+		{StartTime: 3 * time.Second, EndTime: 4 * time.Second, Text: "привет мир"}, // 10 runes, 19 bytes
+	}
+	b.Apply(subs, nil)
+
+	if subs[0].CharBudget != 10 || subs[2].CharBudget != 0 {
+		t.Errorf("budgets = %d, %d; want 10, 0", subs[0].CharBudget, subs[2].CharBudget)
+	}
+
+	if over := MarkFit(subs); over != 1 {
+		t.Errorf("MarkFit = %d over budget, want 1", over)
+	}
+	if !subs[0].FitsBudget || subs[1].FitsBudget || !subs[3].FitsBudget {
+		t.Errorf("fits = %v %v %v, want true false true", subs[0].FitsBudget, subs[1].FitsBudget, subs[3].FitsBudget)
+	}
+}
+
+func TestDurationBudgeter_ApplySpoken(t *testing.T) {
+	b := &DurationBudgeter{CharsPerSecond: 15, SpeedUpAllowance: 1.0, MinBudget: 1}
+	subs := models.SubtitleList{
+		{StartTime: 0, EndTime: 1500 * time.Millisecond, Text: "Founded in 2024."},
+		{StartTime: 2 * time.Second, EndTime: 3 * time.Second, Text: "It cost $5M."},
+		{StartTime: 3 * time.Second, EndTime: 4 * time.Second, Text: "Hello there."},
+	}
+	spoken := PrepareSpeech(subs, "en", "piper", true, nil)
+	b.Apply(subs, spoken)
+
+	// Each fits as written, but not once numbers are spelled out
+	if over := MarkFit(subs); over != 2 {
+		t.Errorf("MarkFit = %d over budget, want 2 (budgets %d %d %d, spoken %q %q)",
+			over, subs[0].CharBudget, subs[1].CharBudget, subs[2].CharBudget, spoken[0].Text, spoken[1].Text)
+	}
+	if !subs[2].FitsBudget || subs[2].CharBudget != 15 {
+		t.Errorf("unchanged line: budget %d, fits %v", subs[2].CharBudget, subs[2].FitsBudget)
+	}
+	if subs[0].Text != "Founded in 2024." {
+		t.Errorf("written text changed: %q", subs[0].Text)
+	}
+}
+
+func TestVoiceRateSample(t *testing.T) {
+	subs := models.SubtitleList{
+		{Text: "Yes."},
+		{Text: strings.Repeat("a", 100)},
+		{Text: strings.Repeat("b", 60)},
+		{Text: strings.Repeat("c", 60)},
+	}
+	sample := voiceRateSample(subs)
+	if strings.Contains(sample, "Yes") {
+		t.Error("short lines should be skipped")
+	}
+	if strings.Contains(sample, "c") {
+		t.Error("sample should stop once it is long enough")
+	}
+
+	if voiceRateSample(models.SubtitleList{{Text: "Hi"}}) != "" {
+		t.Error("no usable lines should give an empty sample")
+	}
+}
@@ -11,17 +11,16 @@ import (
 
 	"video-translator/internal/config"
 	"video-translator/internal/logger"
-	"video-translator/internal/media"
-	"video-translator/internal/worker"
-	"video-translator/models"
+	"video-translator/internal/text"
+	"video-translator/internal/tts"
 )
 
 // EdgeTTSService handles text-to-speech using Microsoft Edge TTS (FREE)
 type EdgeTTSService struct {
+	*tts.BaseTTS
+
 	voice       string
 	edgeTTSPath string
-	ffmpeg      *FFmpegService
-	tempDir     string
 }
 
 // Edge TTS voices - common high-quality neural voices
@@ -48,21 +47,28 @@ var EdgeTTSVoices = map[string]string{
 	"ru-RU-DmitryNeural":   "Dmitry (Russian Male)",
 }
 
+func init() {
+	// Voices are named by locale ("en-US-AriaNeural", "en_US-amy-medium"),
+	// so the catalog is the provider's language list
+	text.SetProviderLanguages(text.StageTTS, "edge-tts", voiceNames(EdgeTTSVoices))
+}
+
+var _ tts.Service = (*EdgeTTSService)(nil)
+var _ tts.RateSynthesizer = (*EdgeTTSService)(nil)
+
 // NewEdgeTTSService creates a new Edge TTS service
 func NewEdgeTTSService(voice string) *EdgeTTSService {
 	if voice == "" {
 		voice = "en-US-AriaNeural"
 	}
 
-	tempDir := filepath.Join(os.TempDir(), "video-translator-edge-tts")
-	os.MkdirAll(tempDir, 0755)
-
-	return &EdgeTTSService{
+	s := &EdgeTTSService{
 		voice:       voice,
 		edgeTTSPath: findExecutable("edge-tts"),
-		ffmpeg:      NewFFmpegService(),
-		tempDir:     tempDir,
 	}
+	// Free service with generous rate limits; the shared limiter backs off on 429s
+	s.BaseTTS = tts.NewBaseTTS("Edge TTS", "video-translator-edge-tts", config.DynamicWorkerCount("tts-api"), s)
+	return s
 }
 
 // CheckInstalled verifies edge-tts is installed
@@ -86,16 +92,26 @@ func (s *EdgeTTSService) SetVoice(voice string) {
 	}
 }
 
+// GetVoice returns the current voice
+func (s *EdgeTTSService) GetVoice() string {
+	return s.voice
+}
+
 // Synthesize generates audio from text using Edge TTS
 func (s *EdgeTTSService) Synthesize(text, outputPath string) error {
-	logger.LogInfo("Edge TTS: voice=%s", s.voice)
-
-	if text == "" {
-		return fmt.Errorf("empty text provided")
-	}
+	return s.SynthesizeSegment(tts.Segment{Text: text}, outputPath)
+}
 
+// SynthesizeSegment generates a segment's audio with the prosody of its
+// emotion (see edgeEmotionProsody) and the segment's rate
+func (s *EdgeTTSService) SynthesizeSegment(seg tts.Segment, outputPath string) error {
 	// Clean the voice name
-	voice := strings.TrimSpace(s.voice)
+	voice := strings.TrimSpace(seg.VoiceOr(s.voice))
+	logger.LogInfo("Edge TTS: voice=%s emotion=%s rate=%.2f", voice, seg.Emotion, seg.SpeakingRate())
+
+	if seg.Text == "" {
+		return tts.Permanent(fmt.Errorf("empty text provided"))
+	}
 
 	// Ensure output directory exists
 	outputDir := filepath.Dir(outputPath)
@@ -123,35 +139,38 @@ func (s *EdgeTTSService) Synthesize(text, outputPath string) error {
 	}()
 
 	// Write text to temp file
-	if _, err := tempFile.WriteString(text); err != nil {
+	if _, err := tempFile.WriteString(seg.Text); err != nil {
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 	tempFile.Close()
 
-	// Retry logic (3 attempts like KrillinAI)
-	maxRetries := 3
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := s.attemptTTS(tempFileName, voice, absOutputPath, attempt)
-		if err == nil {
-			// Verify output file exists
-			if _, statErr := os.Stat(absOutputPath); os.IsNotExist(statErr) {
-				return fmt.Errorf("edge-tts output file not found: %s", absOutputPath)
-			}
-			return nil
-		}
+	if err := s.attemptTTS(tempFileName, voice, edgeProsodyArgs(seg.Emotion, seg.SpeakingRate()), absOutputPath); err != nil {
+		return err
+	}
 
-		// Wait before retry (exponential backoff)
-		if attempt < maxRetries {
-			waitTime := time.Duration(attempt) * 2 * time.Second
-			time.Sleep(waitTime)
-		}
+	// Verify output file exists
+	if _, statErr := os.Stat(absOutputPath); os.IsNotExist(statErr) {
+		return fmt.Errorf("edge-tts output file not found: %s", absOutputPath)
+	}
+	seg.RecordUsage(UsageStageTTS, "edge-tts", voice, seg.Text)
+	return nil
+}
+
+// CacheKey identifies the voice for the segment cache
+func (s *EdgeTTSService) CacheKey(voice string) string {
+	if voice == "" {
+		voice = s.voice
 	}
+	return "edge-tts|" + strings.TrimSpace(voice)
+}
 
-	return fmt.Errorf("edge-tts failed after %d attempts", maxRetries)
+// MaxRate is the fastest --rate applied on top of an emotion's prosody
+func (s *EdgeTTSService) MaxRate() float64 {
+	return edgeMaxRate
 }
 
 // attemptTTS makes a single TTS attempt
-func (s *EdgeTTSService) attemptTTS(tempFileName, voice, outputPath string, _ int) error {
+func (s *EdgeTTSService) attemptTTS(tempFileName, voice string, prosody []string, outputPath string) error {
 	// Determine output format based on extension
 	ext := strings.ToLower(filepath.Ext(outputPath))
 
@@ -169,13 +188,18 @@ func (s *EdgeTTSService) attemptTTS(tempFileName, voice, outputPath string, _ in
 		"--voice", voice,
 		"--write-media", mp3Path,
 	}
+	cmdArgs = append(cmdArgs, prosody...)
 
 	// Create context with timeout (60 seconds)
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	// The service answers bursts with 429 (edge-tts prints the status)
+	limiter := rateLimiterFor(UsageStageTTS, "edge-tts")
+	limiter.Acquire(RateCost{})
 	cmd := exec.CommandContext(ctx, s.edgeTTSPath, cmdArgs...)
 	output, err := cmd.CombinedOutput()
+	limiter.Release(RateCost{}, err != nil && strings.Contains(string(output), "429"), 0)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("edge-tts timeout")
@@ -185,7 +209,7 @@ func (s *EdgeTTSService) attemptTTS(tempFileName, voice, outputPath string, _ in
 
 	// Convert MP3 to WAV if needed
 	if needsConversion {
-		if err := s.ffmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
+		if err := s.FFmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
 			os.Remove(mp3Path)
 			return fmt.Errorf("failed to convert to WAV: %w", err)
 		}
@@ -195,166 +219,6 @@ func (s *EdgeTTSService) attemptTTS(tempFileName, voice, outputPath string, _ in
 	return nil
 }
 
-// SynthesizeSubtitles generates audio for all subtitles with proper timing
-func (s *EdgeTTSService) SynthesizeSubtitles(subs models.SubtitleList, outputPath string) error {
-	return s.SynthesizeWithCallback(subs, outputPath, nil)
-}
-
-// edgeJobData contains data for an Edge TTS job.
-type edgeJobData struct {
-	index int
-	text  string
-	start time.Duration
-	end   time.Duration
-}
-
-// SynthesizeWithCallback generates audio for subtitles with progress callback
-// Uses internal worker pool for parallel processing (Edge TTS is FREE with generous rate limits)
-func (s *EdgeTTSService) SynthesizeWithCallback(
-	subs models.SubtitleList,
-	outputPath string,
-	onProgress func(current, total int),
-) error {
-	if len(subs) == 0 {
-		return fmt.Errorf("no subtitles provided")
-	}
-
-	if err := s.CheckInstalled(); err != nil {
-		return err
-	}
-
-	// Create temp directory for segments
-	segmentDir := filepath.Join(s.tempDir, fmt.Sprintf("segments_%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(segmentDir, 0755); err != nil {
-		return err
-	}
-	defer os.RemoveAll(segmentDir)
-
-	// Identify which subtitles need TTS (non-empty text)
-	var jobs []edgeJobData
-	for i, sub := range subs {
-		if strings.TrimSpace(sub.Text) != "" {
-			jobs = append(jobs, edgeJobData{
-				index: i,
-				text:  sub.Text,
-				start: sub.StartTime,
-				end:   sub.EndTime,
-			})
-		}
-	}
-
-	// Process TTS jobs in parallel using internal worker pool
-	speechPaths := make(map[int]string)
-	total := len(subs)
-
-	if len(jobs) > 0 {
-		// Process function for worker pool
-		processJob := func(job worker.Job[edgeJobData]) (string, error) {
-			data := job.Data
-			speechPath := filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", data.index))
-
-			// Synthesize the text
-			if err := s.synthesizeSingle(data.text, speechPath); err != nil {
-				return "", err
-			}
-
-			// Adjust duration if needed
-			targetDuration := (data.end - data.start).Seconds()
-			if targetDuration > 0.2 {
-				adjustedPath := filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", data.index))
-				if err := s.ffmpeg.AdjustAudioDuration(speechPath, adjustedPath, targetDuration); err == nil {
-					return adjustedPath, nil
-				}
-			}
-
-			return speechPath, nil
-		}
-
-		// Progress callback adapter
-		var progressCallback worker.ProgressFunc
-		if onProgress != nil {
-			progressCallback = func(completed, _ int) {
-				onProgress(completed, total)
-			}
-		}
-
-		// Run worker pool with error tolerance (Edge TTS generates silence for failed segments)
-		// Use dynamic worker count for optimal parallelism based on system resources
-		workers := config.DynamicWorkerCount("tts-api")
-		results, errors := worker.ProcessWithErrors(jobs, workers, processJob, progressCallback)
-
-		// Build speech paths map, using silence for failed jobs
-		ffmpegMedia := media.NewFFmpegServiceWithPath(s.ffmpeg.GetPath())
-		for i, path := range results {
-			jobData := jobs[i]
-			if errors != nil && i < len(errors) && errors[i] != nil {
-				// Generate silence for failed segment (like KrillinAI behavior)
-				duration := (jobData.end - jobData.start).Seconds()
-				if duration > 0 {
-					silencePath := filepath.Join(segmentDir, fmt.Sprintf("silence_fail_%04d.wav", jobData.index))
-					if err := ffmpegMedia.GenerateSilence(duration, silencePath); err == nil {
-						speechPaths[jobData.index] = silencePath
-					}
-				}
-			} else if path != "" {
-				speechPaths[jobData.index] = path
-			}
-		}
-	}
-
-	// Build final audio using AudioAssembler with parallel gap processing
-	internalSubs := models.ToInternalSubtitles(subs)
-	ffmpegMediaFinal := media.NewFFmpegServiceWithPath(s.ffmpeg.GetPath())
-	assembler := media.NewAudioAssembler(ffmpegMediaFinal, segmentDir)
-
-	if err := assembler.AssembleFromSpeechPathsParallel(internalSubs, speechPaths, outputPath); err != nil {
-		return fmt.Errorf("failed to assemble audio: %w", err)
-	}
-
-	return nil
-}
-
-
-// synthesizeSingle synthesizes a single text segment with retry
-func (s *EdgeTTSService) synthesizeSingle(text, outputPath string) error {
-	if text == "" {
-		return fmt.Errorf("empty text")
-	}
-
-	// Clean the voice name
-	voice := strings.TrimSpace(s.voice)
-
-	// Create temp file for text input
-	tempFile, err := os.CreateTemp(filepath.Dir(outputPath), "edge_tts_*.txt")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tempFileName := tempFile.Name()
-	defer func() {
-		tempFile.Close()
-		os.Remove(tempFileName)
-	}()
-
-	if _, err := tempFile.WriteString(text); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-	tempFile.Close()
-
-	// Retry logic
-	maxRetries := 3
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := s.attemptTTS(tempFileName, voice, outputPath, attempt)
-		if err == nil {
-			return nil
-		}
-		if attempt < maxRetries {
-			time.Sleep(time.Duration(attempt) * time.Second)
-		}
-	}
-
-	return fmt.Errorf("edge-tts failed after %d retries", maxRetries)
-}
-
 // GetEdgeTTSVoices returns the list of available Edge TTS voices
 func GetEdgeTTSVoices() map[string]string {
 	return EdgeTTSVoices
@@ -374,8 +238,3 @@ func GetEdgeTTSVoiceList() []string {
 		"ru-RU-SvetlanaNeural",
 	}
 }
-
-// EstimateCost returns 0 since Edge TTS is FREE
-func (s *EdgeTTSService) EstimateCost(charCount int) float64 {
-	return 0.0 // FREE!
-}
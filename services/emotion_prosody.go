@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"math"
+)
+
+// Emotion tags (see validEmotions) are voiced differently by each TTS
+// provider: Fish Audio reads them inline as "(happy) text", the others get
+// them translated into the controls they expose. An empty tag and "calm"
+// mean the provider's neutral delivery.
+
+// TTSSupportsEmotions reports whether a TTS provider voices emotion tags.
+// CosyVoice clones the reference clip's delivery instead.
+func TTSSupportsEmotions(provider string) bool {
+	switch provider {
+	case "fish-audio", "edge-tts", "openai", "piper":
+		return true
+	}
+	return false
+}
+
+// edgeProsody is an emotion expressed through the prosody edge-tts accepts.
+// The Edge read-aloud endpoint rejects custom SSML, so mstts:express-as
+// styles can't be requested; rate, pitch and volume are what's left.
+type edgeProsody struct {
+	Rate   int // Percent faster (+) or slower (-)
+	Pitch  int // Hz higher (+) or lower (-)
+	Volume int // Percent louder (+) or quieter (-)
+}
+
+var edgeEmotionProsody = map[string]edgeProsody{
+	"happy":      {Rate: 5, Pitch: 8, Volume: 0},
+	"sad":        {Rate: -10, Pitch: -8, Volume: -10},
+	"excited":    {Rate: 12, Pitch: 12, Volume: 10},
+	"angry":      {Rate: 8, Pitch: -3, Volume: 15},
+	"surprised":  {Rate: 5, Pitch: 15, Volume: 5},
+	"nervous":    {Rate: 8, Pitch: 5, Volume: -5},
+	"confident":  {Rate: 0, Pitch: -3, Volume: 10},
+	"curious":    {Rate: 0, Pitch: 6, Volume: 0},
+	"empathetic": {Rate: -8, Pitch: -3, Volume: -5},
+	"worried":    {Rate: -3, Pitch: 3, Volume: -8},
+	"frustrated": {Rate: 5, Pitch: -5, Volume: 10},
+}
+
+// edgeMaxRate is the fastest rate multiplier fitting asks edge-tts for;
+// beyond +100% neural voices start swallowing syllables
+const edgeMaxRate = 2.0
+
+// edgeProsodyArgs returns the edge-tts flags for emotion sped up by rate,
+// nil for neutral delivery at normal rate. The "--flag=value" form
+// keeps argparse from reading "-10%" as a flag.
+func edgeProsodyArgs(emotion string, rate float64) []string {
+	p, ok := edgeEmotionProsody[emotion]
+	if !ok && rate == 1 {
+		return nil
+	}
+	args := []string{fmt.Sprintf("--rate=%+d%%", int(math.Round(((1+float64(p.Rate)/100)*rate-1)*100)))}
+	if ok {
+		args = append(args,
+			fmt.Sprintf("--pitch=%+dHz", p.Pitch),
+			fmt.Sprintf("--volume=%+d%%", p.Volume),
+		)
+	}
+	return args
+}
+
+// openAIEmotionInstructions are the voice directions sent as "instructions"
+// to models that take them (gpt-4o-mini-tts; tts-1 ignores emotion)
+var openAIEmotionInstructions = map[string]string{
+	"happy":      "Speak in a warm, cheerful and upbeat tone.",
+	"sad":        "Speak softly and slowly, in a sad, subdued tone.",
+	"excited":    "Speak with high energy and enthusiasm, slightly faster than usual.",
+	"angry":      "Speak in a firm, tense and angry tone, with clipped delivery.",
+	"surprised":  "Speak with audible surprise, rising pitch on the key words.",
+	"nervous":    "Speak hesitantly and a little quickly, sounding nervous and unsure.",
+	"confident":  "Speak in a steady, assured and authoritative tone.",
+	"curious":    "Speak in an inquisitive, interested tone, as if wondering aloud.",
+	"empathetic": "Speak gently and warmly, sounding understanding and supportive.",
+	"worried":    "Speak in a quiet, concerned and worried tone.",
+	"frustrated": "Speak in an exasperated, frustrated tone.",
+}
+
+// openAITTSSupportsInstructions reports whether model accepts "instructions"
+func openAITTSSupportsInstructions(model string) bool {
+	return model == OpenAITTSModelMini
+}
+
+// piperScales are the Piper synthesis parameters an emotion adjusts:
+// length_scale (speaking rate, >1 is slower), noise_scale (variability in
+// pronunciation, i.e. expressiveness) and noise_w (phoneme duration variance)
+type piperScales struct {
+	LengthScale float64
+	NoiseScale  float64
+	NoiseW      float64
+}
+
+// piperMaxRate is the fastest rate multiplier fitting asks Piper for
+// (length_scale is divided by it)
+const piperMaxRate = 1.8
+
+// piperNeutralScales is Piper's balanced delivery
+var piperNeutralScales = piperScales{LengthScale: 1.0, NoiseScale: 0.667, NoiseW: 0.8}
+
+var piperEmotionScales = map[string]piperScales{
+	"happy":      {LengthScale: 0.95, NoiseScale: 0.75, NoiseW: 0.85},
+	"sad":        {LengthScale: 1.15, NoiseScale: 0.5, NoiseW: 0.7},
+	"excited":    {LengthScale: 0.88, NoiseScale: 0.85, NoiseW: 0.9},
+	"angry":      {LengthScale: 0.92, NoiseScale: 0.8, NoiseW: 0.6},
+	"surprised":  {LengthScale: 0.95, NoiseScale: 0.85, NoiseW: 0.9},
+	"nervous":    {LengthScale: 0.93, NoiseScale: 0.8, NoiseW: 1.0},
+	"confident":  {LengthScale: 1.0, NoiseScale: 0.6, NoiseW: 0.7},
+	"curious":    {LengthScale: 1.0, NoiseScale: 0.75, NoiseW: 0.85},
+	"empathetic": {LengthScale: 1.08, NoiseScale: 0.6, NoiseW: 0.75},
+	"worried":    {LengthScale: 1.05, NoiseScale: 0.7, NoiseW: 0.9},
+	"frustrated": {LengthScale: 0.95, NoiseScale: 0.75, NoiseW: 0.7},
+}
+
+// piperScalesFor returns the Piper parameters for emotion
+func piperScalesFor(emotion string) piperScales {
+	if s, ok := piperEmotionScales[emotion]; ok {
+		return s
+	}
+	return piperNeutralScales
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmotionMappings_CoverEveryEmotion(t *testing.T) {
+	for emotion := range validEmotions {
+		if emotion == "calm" {
+			continue
+		}
+		if _, ok := edgeEmotionProsody[emotion]; !ok {
+			t.Errorf("edge-tts has no prosody for %q", emotion)
+		}
+		if _, ok := openAIEmotionInstructions[emotion]; !ok {
+			t.Errorf("OpenAI has no instructions for %q", emotion)
+		}
+		if _, ok := piperEmotionScales[emotion]; !ok {
+			t.Errorf("Piper has no scales for %q", emotion)
+		}
+	}
+}
+
+func TestEdgeProsodyArgs(t *testing.T) {
+	got := strings.Join(edgeProsodyArgs("sad", 1), " ")
+	if got != "--rate=-10% --pitch=-8Hz --volume=-10%" {
+		t.Errorf("sad = %q", got)
+	}
+	if got := strings.Join(edgeProsodyArgs("excited", 1), " "); got != "--rate=+12% --pitch=+12Hz --volume=+10%" {
+		t.Errorf("excited = %q", got)
+	}
+	for _, neutral := range []string{"", "calm", "bogus"} {
+		if args := edgeProsodyArgs(neutral, 1); args != nil {
+			t.Errorf("%q = %v, want no flags", neutral, args)
+		}
+	}
+
+	// A fitting rate multiplies the emotion's own rate
+	if got := strings.Join(edgeProsodyArgs("sad", 1.2), " "); got != "--rate=+8% --pitch=-8Hz --volume=-10%" {
+		t.Errorf("sad at 1.2x = %q", got)
+	}
+	if got := strings.Join(edgeProsodyArgs("", 1.25), " "); got != "--rate=+25%" {
+		t.Errorf("neutral at 1.25x = %q", got)
+	}
+}
+
+func TestOpenAITTS_RequestInstructions(t *testing.T) {
+	mini := NewOpenAITTSService("key", OpenAITTSModelMini, "nova", 1.0)
+	body := mini.requestBody("Hi", "alloy", "happy", 1.0)
+	if body["instructions"] != openAIEmotionInstructions["happy"] {
+		t.Errorf("instructions = %v", body["instructions"])
+	}
+	if _, ok := mini.requestBody("Hi", "alloy", "calm", 1.0)["instructions"]; ok {
+		t.Error("calm should not send instructions")
+	}
+
+	// tts-1 rejects the field
+	standard := NewOpenAITTSService("key", OpenAITTSModelStandard, "nova", 1.0)
+	if _, ok := standard.requestBody("Hi", "alloy", "happy", 1.0)["instructions"]; ok {
+		t.Error("tts-1 should not get instructions")
+	}
+}
+
+func TestPiperScalesFor(t *testing.T) {
+	if got := piperScalesFor(""); got != piperNeutralScales {
+		t.Errorf("neutral = %+v", got)
+	}
+	if sad := piperScalesFor("sad"); sad.LengthScale <= 1 {
+		t.Errorf("sad should be slower, got %+v", sad)
+	}
+	if excited := piperScalesFor("excited"); excited.LengthScale >= 1 {
+		t.Errorf("excited should be faster, got %+v", excited)
+	}
+}
+
+func TestTTSSupportsEmotions(t *testing.T) {
+	for _, p := range []string{"fish-audio", "edge-tts", "openai", "piper"} {
+		if !TTSSupportsEmotions(p) {
+			t.Errorf("%s should voice emotions", p)
+		}
+	}
+	if TTSSupportsEmotions("cosyvoice") {
+		t.Error("cosyvoice doesn't voice emotion tags")
+	}
+}
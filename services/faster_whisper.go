@@ -2,6 +2,7 @@ package services
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -78,14 +79,15 @@ except ImportError:
 	return nil
 }
 
-// Transcribe converts audio to text with timestamps
-func (s *FasterWhisperService) Transcribe(audioPath, language string) (models.SubtitleList, error) {
-	return s.TranscribeWithProgress(audioPath, language, 0, nil)
+// Transcribe converts audio to text with timestamps. prompt is passed as
+// faster-whisper's initial_prompt; empty disables it.
+func (s *FasterWhisperService) Transcribe(audioPath, language, prompt string) (models.SubtitleList, error) {
+	return s.TranscribeWithProgress(audioPath, language, prompt, 0, nil)
 }
 
 // TranscribeWithProgress transcribes audio while reporting progress via callback
 func (s *FasterWhisperService) TranscribeWithProgress(
-	audioPath, language string,
+	audioPath, language, prompt string,
 	audioDuration float64,
 	onProgress func(currentSec float64, percent int),
 ) (models.SubtitleList, error) {
@@ -122,6 +124,7 @@ print("TRANSCRIBING", file=sys.stderr, flush=True)
 segments, info = model.transcribe(
     "%s",
     language="%s",
+    initial_prompt=%s,
     beam_size=1,        # Faster than beam_size=5, minimal accuracy loss
     vad_filter=True,    # Skip silence for faster processing
     vad_parameters={"min_silence_duration_ms": 500},
@@ -145,11 +148,12 @@ with open("%s", "w", encoding="utf-8") as f:
         f.write(f"{start} --> {end}\n")
         f.write(f"{text}\n\n")
 
-        # Print progress to stderr
+        # Print decoder scores and progress to stderr
+        print(f"SCORES:{i} {segment.avg_logprob:.4f} {segment.no_speech_prob:.4f}", file=sys.stderr, flush=True)
         print(f"PROGRESS:{segment.end:.2f}", file=sys.stderr, flush=True)
 
 print("DONE", file=sys.stderr, flush=True)
-`, s.device, s.model, audioPath, language, srtPath)
+`, s.device, s.model, audioPath, language, pythonPrompt(prompt), srtPath)
 
 	cmd := exec.Command(s.pythonPath, "-c", script)
 
@@ -172,12 +176,21 @@ print("DONE", file=sys.stderr, flush=True)
 
 	// Read stderr and capture all output for debugging
 	var stderrLines []string
+	scores := make(map[int][2]float64) // SRT index -> avg_logprob, no_speech_prob
 	scanner := bufio.NewScanner(stderr)
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		stderrLines = append(stderrLines, line)
 
+		if matches := text.WhisperScoresRegex.FindStringSubmatch(line); len(matches) > 3 {
+			idx, _ := strconv.Atoi(matches[1])
+			avgLogprob, _ := strconv.ParseFloat(matches[2], 64)
+			noSpeechProb, _ := strconv.ParseFloat(matches[3], 64)
+			scores[idx] = [2]float64{avgLogprob, noSpeechProb}
+			continue
+		}
+
 		if matches := text.WhisperProgressRegex.FindStringSubmatch(line); len(matches) > 1 {
 			currentSec, _ := strconv.ParseFloat(matches[1], 64)
 			if audioDuration > 0 && onProgress != nil {
@@ -210,12 +223,60 @@ print("DONE", file=sys.stderr, flush=True)
 	// Clean up the temporary SRT file
 	os.Remove(srtPath)
 
-	return models.FromInternalSubtitles(internalSubs), nil
+	subs := models.FromInternalSubtitles(internalSubs)
+	for i := range subs {
+		if sc, ok := scores[subs[i].Index]; ok {
+			subs[i].AvgLogprob = sc[0]
+			subs[i].NoSpeechProb = sc[1]
+			subs[i].HasScores = true
+		}
+	}
+
+	return subs, nil
+}
+
+// DetectLanguage runs faster-whisper's language detection. transcribe()
+// detects the language up front and returns segments lazily, so nothing
+// beyond the first window is decoded.
+func (s *FasterWhisperService) DetectLanguage(audioPath string) (LanguageDetection, error) {
+	if err := s.CheckInstalled(); err != nil {
+		return LanguageDetection{}, err
+	}
+
+	script := fmt.Sprintf(`
+import json
+from faster_whisper import WhisperModel
+
+device = "%s"
+if device == "auto":
+    import torch
+    device = "cuda" if torch.cuda.is_available() else "cpu"
+
+compute_type = "float16" if device == "cuda" else "int8"
+model = WhisperModel("%s", device=device, compute_type=compute_type)
+_, info = model.transcribe('%s', beam_size=1)
+print(json.dumps({"language": info.language, "probability": info.language_probability}))
+`, s.device, s.model, text.EscapeForPython(audioPath))
+
+	output, err := exec.Command(s.pythonPath, "-c", script).Output()
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("faster-whisper language detection failed: %w", err)
+	}
+
+	var result struct {
+		Language    string  `json:"language"`
+		Probability float64 `json:"probability"`
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &result); err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to parse faster-whisper language output: %w", err)
+	}
+	return LanguageDetection{Language: result.Language, Confidence: result.Probability}, nil
 }
 
 // TranscribeToText transcribes audio to plain text (no timestamps)
 func (s *FasterWhisperService) TranscribeToText(audioPath, language string) (string, error) {
-	subs, err := s.Transcribe(audioPath, language)
+	subs, err := s.Transcribe(audioPath, language, "")
 	if err != nil {
 		return "", err
 	}
@@ -233,7 +294,7 @@ func (s *FasterWhisperService) TranscribeToText(audioPath, language string) (str
 // Each chunk's subtitles are adjusted with the correct offset timestamp.
 func (s *FasterWhisperService) TranscribeChunksParallel(
 	chunks []ChunkInfo,
-	language string,
+	language, prompt string,
 	onProgress func(completed, total int),
 ) (models.SubtitleList, error) {
 	if len(chunks) == 0 {
@@ -242,7 +303,7 @@ func (s *FasterWhisperService) TranscribeChunksParallel(
 
 	// Single chunk - use regular transcription
 	if len(chunks) == 1 {
-		return s.Transcribe(chunks[0].Path, language)
+		return s.Transcribe(chunks[0].Path, language, prompt)
 	}
 
 	logger.LogInfo("FasterWhisper: transcribing %d chunks in parallel", len(chunks))
@@ -265,7 +326,7 @@ func (s *FasterWhisperService) TranscribeChunksParallel(
 		AcquireTranscriptionSlot()
 		defer ReleaseTranscriptionSlot()
 
-		subs, err := s.Transcribe(chunk.Path, language)
+		subs, err := s.Transcribe(chunk.Path, language, prompt)
 		if err != nil {
 			return nil, fmt.Errorf("chunk %d transcription failed: %w", chunk.Index, err)
 		}
@@ -343,3 +404,11 @@ func mergeChunkSubtitlesFW(chunkResults []models.SubtitleList) models.SubtitleLi
 
 	return merged
 }
+
+// pythonPrompt renders the prompt as a Python literal for the inline script
+func pythonPrompt(prompt string) string {
+	if prompt == "" {
+		return "None"
+	}
+	return "'" + text.EscapeForPython(prompt) + "'"
+}
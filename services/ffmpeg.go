@@ -217,6 +217,40 @@ func (s *FFmpegService) MuxVideoAudioWithOriginal(videoPath, audioPath, outputPa
 	return nil
 }
 
+// MixAudioTracks overlays several audio tracks (e.g. one per speaker) into
+// one. Tracks keep their own timing; the output is as long as the longest.
+// A single track is moved to outputPath as-is.
+func (s *FFmpegService) MixAudioTracks(inputPaths []string, outputPath string) error {
+	if len(inputPaths) == 0 {
+		return fmt.Errorf("no audio tracks to mix")
+	}
+	if len(inputPaths) == 1 {
+		return os.Rename(inputPaths[0], outputPath)
+	}
+
+	var args []string
+	for _, p := range inputPaths {
+		args = append(args, "-i", p)
+	}
+	// normalize=0 keeps each speaker at full volume instead of 1/N
+	args = append(args,
+		"-filter_complex", fmt.Sprintf("amix=inputs=%d:duration=longest:normalize=0", len(inputPaths)),
+		"-ar", "24000", // Match ConvertToWAV
+		"-ac", "1",
+		"-y",
+		outputPath,
+	)
+
+	cmd, cancel := s.newCmd(args...)
+	defer cancel()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg track mixing failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // GetVideoDuration returns the duration of a video in seconds
 func (s *FFmpegService) GetVideoDuration(videoPath string) (float64, error) {
 	// Use ffprobe to get duration
@@ -454,6 +488,33 @@ func (s *FFmpegService) ConvertToWAV(inputPath, outputPath string) error {
 	return nil
 }
 
+// ExtractClip cuts durationSecs of audio starting at startSecs into a
+// 16kHz mono WAV (the format every Whisper backend expects)
+func (s *FFmpegService) ExtractClip(inputPath, outputPath string, startSecs, durationSecs float64) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	args := []string{
+		"-ss", fmt.Sprintf("%.3f", startSecs),
+		"-t", fmt.Sprintf("%.3f", durationSecs),
+		"-i", inputPath,
+		"-ar", "16000",
+		"-ac", "1",
+		"-y",
+		outputPath,
+	}
+
+	cmd, cancel := s.newCmd(args...)
+	defer cancel()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg clip extraction failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // SplitAudioIntoChunks splits an audio file into chunks for parallel transcription.
 // Each chunk has a configurable duration with overlap to prevent word cutoff at boundaries.
 // Returns a list of ChunkInfo with paths to the chunk files.
@@ -547,3 +608,144 @@ func (s *FFmpegService) CleanupChunks(chunks []ChunkInfo) {
 		os.Remove(chunk.Path)
 	}
 }
+
+// SilenceInterval is a stretch of audio that ffmpeg's silencedetect filter
+// considers silent (times in seconds)
+type SilenceInterval struct {
+	Start float64
+	End   float64
+}
+
+// DetectSilence runs ffmpeg's silencedetect filter and returns the silent
+// intervals. noiseDB is the threshold (e.g. -35) and minDuration the shortest
+// gap in seconds that counts as silence. Used as a lightweight VAD.
+func (s *FFmpegService) DetectSilence(audioPath string, noiseDB, minDuration float64) ([]SilenceInterval, error) {
+	args := []string{
+		"-i", audioPath,
+		"-af", fmt.Sprintf("silencedetect=noise=%.0fdB:d=%.2f", noiseDB, minDuration),
+		"-f", "null",
+		"-",
+	}
+
+	cmd, cancel := s.newCmd(args...)
+	defer cancel()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg silence detection failed: %w", err)
+	}
+
+	duration, _ := s.GetAudioDuration(audioPath)
+	intervals := parseSilenceDetect(string(output), duration)
+	logger.LogDebug("FFmpeg: detected %d silent intervals", len(intervals))
+	return intervals, nil
+}
+
+// parseSilenceDetect extracts silence_start/silence_end pairs from ffmpeg's
+// stderr. A trailing silence_start without an end runs to totalDuration.
+func parseSilenceDetect(output string, totalDuration float64) []SilenceInterval {
+	var intervals []SilenceInterval
+	start := -1.0
+
+	for _, line := range strings.Split(output, "\n") {
+		if idx := strings.Index(line, "silence_start:"); idx >= 0 {
+			var v float64
+			if _, err := fmt.Sscanf(strings.TrimSpace(line[idx+len("silence_start:"):]), "%f", &v); err == nil {
+				start = v
+			}
+			continue
+		}
+		if idx := strings.Index(line, "silence_end:"); idx >= 0 && start >= 0 {
+			var v float64
+			if _, err := fmt.Sscanf(strings.TrimSpace(line[idx+len("silence_end:"):]), "%f", &v); err == nil {
+				intervals = append(intervals, SilenceInterval{Start: start, End: v})
+			}
+			start = -1
+		}
+	}
+
+	if start >= 0 && totalDuration > start {
+		intervals = append(intervals, SilenceInterval{Start: start, End: totalDuration})
+	}
+
+	return intervals
+}
+
+// LoudnessSample is ffmpeg ebur128's momentary loudness: the LUFS of the
+// 400 ms of audio ending at Time (seconds)
+type LoudnessSample struct {
+	Time float64
+	LUFS float64
+}
+
+// MeasureLoudness runs ffmpeg's ebur128 filter over audioPath and returns
+// its momentary loudness every 100 ms
+func (s *FFmpegService) MeasureLoudness(audioPath string) ([]LoudnessSample, error) {
+	args := []string{
+		"-nostats",
+		"-i", audioPath,
+		"-af", "ebur128",
+		"-f", "null",
+		"-",
+	}
+
+	cmd, cancel := s.newCmd(args...)
+	defer cancel()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg loudness measurement failed: %w", err)
+	}
+
+	samples := parseEBUR128(string(output))
+	logger.LogDebug("FFmpeg: measured %d loudness samples", len(samples))
+	return samples, nil
+}
+
+// parseEBUR128 extracts the "t:" and "M:" values of ebur128's per-frame
+// log lines; the summary at the end has no "t:" and is skipped
+func parseEBUR128(output string) []LoudnessSample {
+	var samples []LoudnessSample
+	for _, line := range strings.Split(output, "\n") {
+		t := strings.Index(line, " t:")
+		m := strings.Index(line, " M:")
+		if t < 0 || m < 0 {
+			continue
+		}
+		var sample LoudnessSample
+		if _, err := fmt.Sscanf(strings.TrimSpace(line[t+len(" t:"):]), "%f", &sample.Time); err != nil {
+			continue
+		}
+		if _, err := fmt.Sscanf(strings.TrimSpace(line[m+len(" M:"):]), "%f", &sample.LUFS); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// ExtractVoiceSample cuts durationSecs starting at startSecs into a 22kHz
+// mono WAV, the reference format voice-cloning TTS expects
+func (s *FFmpegService) ExtractVoiceSample(inputPath, outputPath string, startSecs, durationSecs float64) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	args := []string{
+		"-ss", fmt.Sprintf("%.3f", startSecs),
+		"-t", fmt.Sprintf("%.3f", durationSecs),
+		"-i", inputPath,
+		"-vn",
+		"-ar", "22050",
+		"-ac", "1",
+		"-y",
+		outputPath,
+	}
+
+	cmd, cancel := s.newCmd(args...)
+	defer cancel()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to extract voice sample: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
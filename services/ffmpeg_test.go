@@ -275,3 +275,45 @@ func TestFFmpegService_ConcatAudioFiles_SingleFile(t *testing.T) {
 		t.Error("ConcatAudioFiles() should return error for nonexistent input")
 	}
 }
+
+func TestParseSilenceDetect(t *testing.T) {
+	output := `[silencedetect @ 0x600] silence_start: 0
+[silencedetect @ 0x600] silence_end: 1.504 | silence_duration: 1.504
+size=N/A time=00:00:10.00 bitrate=N/A speed= 500x
+[silencedetect @ 0x600] silence_start: 5.25
+[silencedetect @ 0x600] silence_end: 7.5 | silence_duration: 2.25
+[silencedetect @ 0x600] silence_start: 9.1`
+
+	got := parseSilenceDetect(output, 10)
+	want := []SilenceInterval{{0, 1.504}, {5.25, 7.5}, {9.1, 10}}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseSilenceDetect() returned %d intervals, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("interval %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseEBUR128(t *testing.T) {
+	output := `[Parsed_ebur128_0 @ 0x600] t: 0.1        TARGET:-23 LUFS    M:-120.7 S:-120.7     I: -70.0 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x600] t: 0.2        TARGET:-23 LUFS    M: -21.4 S:-120.7     I: -21.4 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x600] Summary:
+
+  Integrated loudness:
+    I:         -21.4 LUFS
+    Threshold: -31.4 LUFS`
+
+	got := parseEBUR128(output)
+	want := []LoudnessSample{{0.1, -120.7}, {0.2, -21.4}}
+	if len(got) != len(want) {
+		t.Fatalf("parseEBUR128() returned %d samples, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sample %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -10,11 +10,11 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"video-translator/internal/config"
 	"video-translator/internal/logger"
-	"video-translator/internal/media"
-	"video-translator/internal/worker"
+	"video-translator/internal/tts"
 	"video-translator/models"
 )
 
@@ -39,12 +39,12 @@ const (
 
 // FishAudioTTSService handles text-to-speech using Fish Audio's API (Fish Speech quality)
 type FishAudioTTSService struct {
+	*tts.BaseTTS
+
 	apiKey      string
 	model       string  // s1, speech-1.5, speech-1.6
 	referenceID string  // Voice model ID
 	speed       float64 // Speech speed (0.5-2.0)
-	ffmpeg      *FFmpegService
-	tempDir     string
 }
 
 // FishAudioVoices contains pre-built voice options from Fish Audio
@@ -55,26 +55,30 @@ var FishAudioVoices = map[string]string{
 	"custom":   "Use custom reference ID from your account",
 }
 
+var _ tts.Service = (*FishAudioTTSService)(nil)
+var _ tts.RateSynthesizer = (*FishAudioTTSService)(nil)
+
+// fishAudioMaxSpeed is the fastest prosody speed the API accepts
+const fishAudioMaxSpeed = 2.0
+
 // NewFishAudioTTSService creates a new Fish Audio TTS service
 func NewFishAudioTTSService(apiKey, model, referenceID string, speed float64) *FishAudioTTSService {
 	if model == "" {
 		model = FishAudioModelS1 // S1 is the flagship model with best quality (same price)
 	}
-	if speed <= 0 || speed > 2.0 {
+	if speed <= 0 || speed > fishAudioMaxSpeed {
 		speed = 1.0
 	}
 
-	tempDir := filepath.Join(os.TempDir(), "video-translator-fish-audio")
-	os.MkdirAll(tempDir, 0755)
-
-	return &FishAudioTTSService{
+	s := &FishAudioTTSService{
 		apiKey:      apiKey,
 		model:       model,
 		referenceID: referenceID,
 		speed:       speed,
-		ffmpeg:      NewFFmpegService(),
-		tempDir:     tempDir,
 	}
+	// Fish Audio starter tier allows 5 concurrent requests
+	s.BaseTTS = tts.NewBaseTTS("Fish Audio", "video-translator-fish-audio", config.WorkersFishAudio, s)
+	return s
 }
 
 // CheckInstalled verifies the API key is set
@@ -92,6 +96,11 @@ func (s *FishAudioTTSService) SetVoice(referenceID string) {
 	}
 }
 
+// GetVoice returns the current voice reference ID
+func (s *FishAudioTTSService) GetVoice() string {
+	return s.referenceID
+}
+
 // SetModel changes the TTS model
 func (s *FishAudioTTSService) SetModel(model string) {
 	if model != "" {
@@ -120,26 +129,31 @@ type fishAudioProsody struct {
 	Volume float64 `json:"volume"`
 }
 
-// SynthesizeWithEmotion generates audio from text with an emotion tag for Fish Audio TTS
-func (s *FishAudioTTSService) SynthesizeWithEmotion(text, emotion, outputPath string) error {
-	// Prepend emotion tag if provided (Fish Audio emotion control)
-	// Format: (emotion) text - e.g., "(happy) Hello world!"
-	if emotion != "" && emotion != "calm" {
-		text = fmt.Sprintf("(%s) %s", emotion, text)
-	}
-	return s.Synthesize(text, outputPath)
-}
-
 // Synthesize generates audio from text using Fish Audio TTS
 func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
-	logger.LogInfo("Fish Audio TTS: model=%s reference_id=%s speed=%.2f", s.model, s.referenceID, s.speed)
+	return s.SynthesizeSegment(tts.Segment{Text: text}, outputPath)
+}
+
+// SynthesizeSegment generates a segment's audio with its emotion tag, at
+// the configured speed times the segment's rate
+func (s *FishAudioTTSService) SynthesizeSegment(seg tts.Segment, outputPath string) error {
+	speed := min(s.speed*seg.SpeakingRate(), fishAudioMaxSpeed)
+	referenceID := seg.VoiceOr(s.referenceID)
+	logger.LogInfo("Fish Audio TTS: model=%s reference_id=%s speed=%.2f emotion=%s", s.model, referenceID, speed, seg.Emotion)
 
+	text := seg.Text
 	if text == "" {
-		return fmt.Errorf("empty text provided")
+		return tts.Permanent(fmt.Errorf("empty text provided"))
 	}
 
 	if s.apiKey == "" {
-		return fmt.Errorf("Fish Audio API key is required")
+		return tts.Permanent(fmt.Errorf("Fish Audio API key is required"))
+	}
+
+	// Prepend emotion tag if provided (Fish Audio emotion control)
+	// Format: (emotion) text - e.g., "(happy) Hello world!"
+	if seg.Emotion != "" && seg.Emotion != "calm" {
+		text = fmt.Sprintf("(%s) %s", seg.Emotion, text)
 	}
 
 	// Build request body with optimized parameters for voice consistency
@@ -154,7 +168,7 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 		RepetitionPenalty: 1.2,   // Prevents glitches/stuttering
 		MaxNewTokens:      1024,  // Safe generation limit
 		Prosody: &fishAudioProsody{
-			Speed:  s.speed,
+			Speed:  speed,
 			Volume: 0,
 		},
 		Format:     "mp3",
@@ -164,8 +178,8 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 	}
 
 	// Add reference ID if specified
-	if s.referenceID != "" && s.referenceID != "default" {
-		reqBody.ReferenceID = s.referenceID
+	if referenceID != "" && referenceID != "default" {
+		reqBody.ReferenceID = referenceID
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -182,7 +196,8 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("model", s.model)
 
-	resp, err := fishAudioClient.Do(req)
+	resp, err := doRateLimited(rateLimiterFor(UsageStageTTS, "fish-audio"), fishAudioClient, req,
+		RateCost{Characters: utf8.RuneCountInString(text)})
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -195,18 +210,17 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 			Message string `json:"message"`
 			Detail  string `json:"detail"`
 		}
+		err := fmt.Errorf("Fish Audio API error (status %d): %s", resp.StatusCode, string(respBody))
 		if json.Unmarshal(respBody, &errResp) == nil {
 			if errResp.Message != "" {
-				return fmt.Errorf("Fish Audio API error: %s", errResp.Message)
-			}
-			if errResp.Error != "" {
-				return fmt.Errorf("Fish Audio API error: %s", errResp.Error)
-			}
-			if errResp.Detail != "" {
-				return fmt.Errorf("Fish Audio API error: %s", errResp.Detail)
+				err = fmt.Errorf("Fish Audio API error: %s", errResp.Message)
+			} else if errResp.Error != "" {
+				err = fmt.Errorf("Fish Audio API error: %s", errResp.Error)
+			} else if errResp.Detail != "" {
+				err = fmt.Errorf("Fish Audio API error: %s", errResp.Detail)
 			}
 		}
-		return fmt.Errorf("Fish Audio API error (status %d): %s", resp.StatusCode, string(respBody))
+		return tts.PermanentForStatus(resp.StatusCode, err)
 	}
 
 	// Response is raw audio bytes (MP3)
@@ -214,6 +228,7 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read audio response: %w", err)
 	}
+	seg.RecordUsage(UsageStageTTS, "fish-audio", s.model, text)
 
 	// Write to temp MP3 file
 	mp3Path := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".mp3"
@@ -223,7 +238,7 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 
 	// Convert MP3 to WAV for consistency with other TTS services
 	if strings.HasSuffix(outputPath, ".wav") {
-		if err := s.ffmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
+		if err := s.FFmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
 			os.Remove(mp3Path)
 			return fmt.Errorf("failed to convert to WAV: %w", err)
 		}
@@ -237,116 +252,17 @@ func (s *FishAudioTTSService) Synthesize(text, outputPath string) error {
 	return nil
 }
 
-// SynthesizeSubtitles generates audio for all subtitles with proper timing
-func (s *FishAudioTTSService) SynthesizeSubtitles(subs models.SubtitleList, outputPath string) error {
-	return s.SynthesizeWithCallback(subs, outputPath, nil)
-}
-
-// fishAudioJobData contains data for a Fish Audio TTS job
-type fishAudioJobData struct {
-	index   int
-	text    string
-	emotion string // Fish Audio emotion tag (happy, sad, excited, etc.)
-	start   time.Duration
-	end     time.Duration
-}
-
-// SynthesizeWithCallback generates audio for subtitles with progress callback
-func (s *FishAudioTTSService) SynthesizeWithCallback(
-	subs models.SubtitleList,
-	outputPath string,
-	onProgress func(current, total int),
-) error {
-	if len(subs) == 0 {
-		return fmt.Errorf("no subtitles provided")
-	}
-
-	if err := s.CheckInstalled(); err != nil {
-		return err
-	}
-
-	// Create temp directory for segments
-	segmentDir := filepath.Join(s.tempDir, fmt.Sprintf("segments_%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(segmentDir, 0755); err != nil {
-		return err
-	}
-	defer os.RemoveAll(segmentDir)
-
-	// Identify which subtitles need TTS (non-empty text)
-	var jobs []fishAudioJobData
-	for i, sub := range subs {
-		if strings.TrimSpace(sub.Text) != "" {
-			jobs = append(jobs, fishAudioJobData{
-				index:   i,
-				text:    sub.Text,
-				emotion: sub.Emotion, // Fish Audio emotion tag for expressive speech
-				start:   sub.StartTime,
-				end:     sub.EndTime,
-			})
-		}
-	}
-
-	// Process TTS jobs in parallel using internal worker pool
-	speechPaths := make(map[int]string)
-	total := len(subs)
-
-	if len(jobs) > 0 {
-		// Process function for worker pool
-		processJob := func(job worker.Job[fishAudioJobData]) (string, error) {
-			data := job.Data
-			speechPath := filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", data.index))
-
-			// Synthesize the text with emotion (if set)
-			if err := s.SynthesizeWithEmotion(data.text, data.emotion, speechPath); err != nil {
-				return "", err
-			}
-
-			// Adjust duration if needed
-			targetDuration := (data.end - data.start).Seconds()
-			if targetDuration > 0.2 {
-				adjustedPath := filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", data.index))
-				if err := s.ffmpeg.AdjustAudioDuration(speechPath, adjustedPath, targetDuration); err == nil {
-					return adjustedPath, nil
-				}
-			}
-
-			return speechPath, nil
-		}
-
-		// Progress callback adapter
-		var progressCallback worker.ProgressFunc
-		if onProgress != nil {
-			progressCallback = func(completed, _ int) {
-				onProgress(completed, total)
-			}
-		}
-
-		// Run worker pool with limited workers (Fish Audio starter tier = 5 concurrent)
-		workers := config.WorkersFishAudio
-		if workers == 0 {
-			workers = 5 // Default to starter tier
-		}
-		results, err := worker.Process(jobs, workers, processJob, progressCallback)
-		if err != nil {
-			return fmt.Errorf("TTS synthesis failed: %w", err)
-		}
-
-		// Collect speech paths by index
-		for i, path := range results {
-			speechPaths[jobs[i].index] = path
-		}
-	}
-
-	// Build final audio using AudioAssembler with parallel gap processing
-	internalSubs := models.ToInternalSubtitles(subs)
-	ffmpegMedia := media.NewFFmpegServiceWithPath(s.ffmpeg.GetPath())
-	assembler := media.NewAudioAssembler(ffmpegMedia, segmentDir)
-
-	if err := assembler.AssembleFromSpeechPathsParallel(internalSubs, speechPaths, outputPath); err != nil {
-		return fmt.Errorf("failed to assemble audio: %w", err)
+// CacheKey identifies the model, voice and speed for the segment cache
+func (s *FishAudioTTSService) CacheKey(voice string) string {
+	if voice == "" {
+		voice = s.referenceID
 	}
+	return fmt.Sprintf("fish-audio|%s|%s|%.2f", s.model, voice, s.speed)
+}
 
-	return nil
+// MaxRate is how much faster than the configured speed the API still allows
+func (s *FishAudioTTSService) MaxRate() float64 {
+	return fishAudioMaxSpeed / s.speed
 }
 
 // GetFishAudioVoices returns the list of available Fish Audio voices
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"video-translator/models"
+)
+
+// GlossaryTerm is a required translation of a source-language term
+type GlossaryTerm struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Glossary pins the translation of brand names, product terms and character
+// names. Terms must be translated as given; DoNotTranslate terms must come
+// through verbatim. Matching is case-insensitive on whole words.
+type Glossary struct {
+	Name           string         `json:"-"`
+	Terms          []GlossaryTerm `json:"terms"`
+	DoNotTranslate []string       `json:"do_not_translate"`
+
+	termPatterns []*regexp.Regexp
+	dntPatterns  []*regexp.Regexp
+}
+
+// GlossaryViolation is a glossary term the translation didn't honour
+type GlossaryViolation struct {
+	Term     string // Source term found in the original
+	Expected string // What the translation should contain
+}
+
+func (v GlossaryViolation) String() string {
+	return fmt.Sprintf("%q must be translated as %q", v.Term, v.Expected)
+}
+
+// NewGlossary builds a glossary, dropping blank and duplicate entries
+func NewGlossary(name string, terms []GlossaryTerm, doNotTranslate []string) *Glossary {
+	g := &Glossary{Name: name}
+	seen := make(map[string]bool)
+	for _, t := range terms {
+		src, tgt := strings.TrimSpace(t.Source), strings.TrimSpace(t.Target)
+		if src == "" || seen[strings.ToLower(src)] {
+			continue
+		}
+		seen[strings.ToLower(src)] = true
+		if tgt == "" {
+			// No target means keep the term as is
+			g.DoNotTranslate = append(g.DoNotTranslate, src)
+			continue
+		}
+		g.Terms = append(g.Terms, GlossaryTerm{Source: src, Target: tgt})
+	}
+	for _, term := range doNotTranslate {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		g.DoNotTranslate = append(g.DoNotTranslate, term)
+	}
+
+	// Longest first so "Acme Cloud Pro" wins over "Acme Cloud" when protecting
+	sort.SliceStable(g.Terms, func(i, j int) bool { return len(g.Terms[i].Source) > len(g.Terms[j].Source) })
+	sort.SliceStable(g.DoNotTranslate, func(i, j int) bool { return len(g.DoNotTranslate[i]) > len(g.DoNotTranslate[j]) })
+
+	for _, t := range g.Terms {
+		g.termPatterns = append(g.termPatterns, termPattern(t.Source))
+	}
+	for _, term := range g.DoNotTranslate {
+		g.dntPatterns = append(g.dntPatterns, termPattern(term))
+	}
+	return g
+}
+
+// termPattern matches term case-insensitively as a whole word or phrase
+func termPattern(term string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + regexp.QuoteMeta(term) + `)(?:$|[^\p{L}\p{N}])`)
+}
+
+// DefaultGlossaryDir returns where named glossaries are kept
+func DefaultGlossaryDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "video-translator", "glossaries")
+}
+
+// ListGlossaries returns the names of the glossaries in dir (file names
+// without .csv/.json), sorted
+func ListGlossaries(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".csv" && ext != ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadNamedGlossary loads glossary name from dir, trying .json then .csv
+func LoadNamedGlossary(dir, name string) (*Glossary, error) {
+	for _, ext := range []string{".json", ".csv"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return LoadGlossary(path)
+		}
+	}
+	return nil, fmt.Errorf("glossary %q not found in %s", name, dir)
+}
+
+// LoadGlossary reads a glossary file.
+//
+// CSV: one "source,target" row per term; a row without a target (or with the
+// target equal to the source) is a do-not-translate term. A "source,target"
+// header and lines starting with # are skipped.
+//
+// JSON: {"terms": [{"source": "...", "target": "..."}], "do_not_translate": ["..."]}
+func LoadGlossary(path string) (*Glossary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open glossary: %w", err)
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var raw Glossary
+		if err := json.NewDecoder(f).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to parse glossary %s: %w", path, err)
+		}
+		return NewGlossary(name, raw.Terms, raw.DoNotTranslate), nil
+	}
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	var terms []GlossaryTerm
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse glossary %s: %w", path, err)
+		}
+		if len(record) == 0 {
+			continue
+		}
+		term := GlossaryTerm{Source: record[0]}
+		if len(record) > 1 {
+			term.Target = record[1]
+		}
+		if line == 1 && strings.EqualFold(term.Source, "source") && strings.EqualFold(term.Target, "target") {
+			continue
+		}
+		if strings.TrimSpace(term.Target) == strings.TrimSpace(term.Source) {
+			term.Target = ""
+		}
+		terms = append(terms, term)
+	}
+	return NewGlossary(name, terms, nil), nil
+}
+
+// Empty reports whether the glossary has no entries
+func (g *Glossary) Empty() bool {
+	return g == nil || (len(g.Terms) == 0 && len(g.DoNotTranslate) == 0)
+}
+
+// Relevant returns the part of the glossary that occurs in texts, so
+// prompts only carry the terms a batch actually needs
+func (g *Glossary) Relevant(texts []string) *Glossary {
+	if g.Empty() {
+		return nil
+	}
+	joined := strings.Join(texts, "\n")
+	var terms []GlossaryTerm
+	var dnt []string
+	for i, t := range g.Terms {
+		if g.termPatterns[i].MatchString(joined) {
+			terms = append(terms, t)
+		}
+	}
+	for i, term := range g.DoNotTranslate {
+		if g.dntPatterns[i].MatchString(joined) {
+			dnt = append(dnt, term)
+		}
+	}
+	sub := NewGlossary(g.Name, terms, dnt)
+	if sub.Empty() {
+		return nil
+	}
+	return sub
+}
+
+// PromptSection renders the glossary as instructions for an LLM prompt
+func (g *Glossary) PromptSection() string {
+	if g.Empty() {
+		return ""
+	}
+	var b strings.Builder
+	if len(g.Terms) > 0 {
+		b.WriteString("GLOSSARY (always translate these terms exactly as given):\n")
+		for _, t := range g.Terms {
+			fmt.Fprintf(&b, "- %s → %s\n", t.Source, t.Target)
+		}
+		b.WriteString("\n")
+	}
+	if len(g.DoNotTranslate) > 0 {
+		b.WriteString("DO NOT TRANSLATE (keep exactly as written):\n")
+		for _, term := range g.DoNotTranslate {
+			fmt.Fprintf(&b, "- %s\n", term)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Verify checks that every glossary term in source is rendered as required
+// in translated
+func (g *Glossary) Verify(source, translated string) []GlossaryViolation {
+	if g.Empty() {
+		return nil
+	}
+	var violations []GlossaryViolation
+	for i, t := range g.Terms {
+		if g.termPatterns[i].MatchString(source) && !containsFold(translated, t.Target) {
+			violations = append(violations, GlossaryViolation{Term: t.Source, Expected: t.Target})
+		}
+	}
+	for i, term := range g.DoNotTranslate {
+		if g.dntPatterns[i].MatchString(source) && !g.dntPatterns[i].MatchString(translated) {
+			violations = append(violations, GlossaryViolation{Term: term, Expected: term})
+		}
+	}
+	return violations
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// Glossary placeholders for machine translation that can't take
+// instructions (Argos). Letters-and-digits tokens survive translation
+// models best; the restore pattern tolerates inserted spaces and case changes.
+var glossaryPlaceholderRegex = regexp.MustCompile(`(?i)zqx\s*(\d+)\s*qz`)
+
+func glossaryPlaceholder(i int) string {
+	return "ZQX" + strconv.Itoa(i) + "QZ"
+}
+
+// Protect replaces glossary terms in s with placeholders and returns the
+// text for each placeholder: the required target for terms, the term
+// itself for do-not-translate entries
+func (g *Glossary) Protect(s string) (string, []string) {
+	if g.Empty() {
+		return s, nil
+	}
+	var values []string
+	replace := func(re *regexp.Regexp, value func(match string) string) {
+		s = replaceGroup(re, s, func(match string) string {
+			values = append(values, value(match))
+			return glossaryPlaceholder(len(values) - 1)
+		})
+	}
+	for i, t := range g.Terms {
+		target := t.Target
+		replace(g.termPatterns[i], func(string) string { return target })
+	}
+	for i := range g.DoNotTranslate {
+		// Keep the spelling used in the original
+		replace(g.dntPatterns[i], func(match string) string { return match })
+	}
+	return s, values
+}
+
+// Restore puts the protected values back in place of their placeholders
+func Restore(s string, values []string) string {
+	if len(values) == 0 {
+		return s
+	}
+	return glossaryPlaceholderRegex.ReplaceAllStringFunc(s, func(m string) string {
+		i, err := strconv.Atoi(glossaryPlaceholderRegex.FindStringSubmatch(m)[1])
+		if err != nil || i >= len(values) {
+			return m
+		}
+		return values[i]
+	})
+}
+
+// replaceGroup replaces capture group 1 of every match of re in s
+func replaceGroup(re *regexp.Regexp, s string, repl func(match string) string) string {
+	var b strings.Builder
+	last := 0
+	// Matches consume the boundary characters around a term, so adjacent
+	// terms separated by one space need a second pass
+	for pass := 0; pass < 2; pass++ {
+		b.Reset()
+		last = 0
+		matches := re.FindAllStringSubmatchIndex(s, -1)
+		if len(matches) == 0 {
+			return s
+		}
+		for _, m := range matches {
+			b.WriteString(s[last:m[2]])
+			b.WriteString(repl(s[m[2]:m[3]]))
+			last = m[3]
+		}
+		b.WriteString(s[last:])
+		s = b.String()
+	}
+	return s
+}
+
+// ProtectSubtitles applies Protect to every subtitle, returning the
+// protected copy and each subtitle's placeholder values
+func (g *Glossary) ProtectSubtitles(subs models.SubtitleList) (models.SubtitleList, [][]string) {
+	protected := make(models.SubtitleList, len(subs))
+	values := make([][]string, len(subs))
+	for i, sub := range subs {
+		protected[i] = sub
+		protected[i].Text, values[i] = g.Protect(sub.Text)
+	}
+	return protected, values
+}
+
+// RestoreSubtitles undoes ProtectSubtitles on the translated subtitles,
+// which must be in the same order
+func RestoreSubtitles(subs models.SubtitleList, values [][]string) {
+	for i := range subs {
+		if i < len(values) {
+			subs[i].Text = Restore(subs[i].Text, values[i])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"video-translator/models"
+)
+
+func writeGlossaryFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadGlossary(t *testing.T) {
+	dir := t.TempDir()
+	writeGlossaryFile(t, dir, "brand.csv", `source,target
+# product names
+Облако Акме,Acme Cloud
+Kubernetes,
+"Иван, Петрович",Ivan Petrovich
+Acme,Acme
+`)
+	writeGlossaryFile(t, dir, "show.json", `{
+		"terms": [{"source": "Хогвартс", "target": "Hogwarts"}],
+		"do_not_translate": ["Quidditch", " "]
+	}`)
+	writeGlossaryFile(t, dir, "notes.txt", "ignored")
+
+	if got := ListGlossaries(dir); !reflect.DeepEqual(got, []string{"brand", "show"}) {
+		t.Errorf("ListGlossaries = %v", got)
+	}
+
+	brand, err := LoadNamedGlossary(dir, "brand")
+	if err != nil {
+		t.Fatalf("LoadNamedGlossary: %v", err)
+	}
+	wantTerms := []GlossaryTerm{{"Иван, Петрович", "Ivan Petrovich"}, {"Облако Акме", "Acme Cloud"}}
+	if !reflect.DeepEqual(brand.Terms, wantTerms) {
+		t.Errorf("terms = %v, want %v", brand.Terms, wantTerms)
+	}
+	if !reflect.DeepEqual(brand.DoNotTranslate, []string{"Kubernetes", "Acme"}) {
+		t.Errorf("do-not-translate = %v", brand.DoNotTranslate)
+	}
+
+	show, err := LoadNamedGlossary(dir, "show")
+	if err != nil {
+		t.Fatalf("LoadNamedGlossary: %v", err)
+	}
+	if len(show.Terms) != 1 || !reflect.DeepEqual(show.DoNotTranslate, []string{"Quidditch"}) {
+		t.Errorf("show = %+v", show)
+	}
+
+	if _, err := LoadNamedGlossary(dir, "missing"); err == nil {
+		t.Error("missing glossary should fail")
+	}
+}
+
+func TestGlossary_RelevantAndPrompt(t *testing.T) {
+	g := NewGlossary("test", []GlossaryTerm{{"Облако", "Cloud"}, {"Акме", "Acme"}}, []string{"Kubernetes"})
+
+	sub := g.Relevant([]string{"Запускаем в облако сегодня"})
+	if sub == nil || len(sub.Terms) != 1 || len(sub.DoNotTranslate) != 0 {
+		t.Fatalf("Relevant = %+v, want only Облако", sub)
+	}
+	if prompt := sub.PromptSection(); !strings.Contains(prompt, "- Облако → Cloud") || strings.Contains(prompt, "DO NOT TRANSLATE") {
+		t.Errorf("PromptSection = %q", prompt)
+	}
+
+	// Whole words only: "Акмеология" is not "Акме"
+	if g.Relevant([]string{"Акмеология"}) != nil {
+		t.Error("partial word should not match")
+	}
+
+	var none *Glossary
+	if none.PromptSection() != "" || none.Verify("a", "b") != nil || none.Relevant([]string{"a"}) != nil {
+		t.Error("nil glossary should be a no-op")
+	}
+}
+
+func TestGlossary_Verify(t *testing.T) {
+	g := NewGlossary("test", []GlossaryTerm{{"Облако Акме", "Acme Cloud"}}, []string{"Kubernetes"})
+
+	if v := g.Verify("Облако Акме на Kubernetes", "acme cloud on Kubernetes"); len(v) != 0 {
+		t.Errorf("compliant translation flagged: %v", v)
+	}
+
+	v := g.Verify("Облако Акме на Kubernetes", "Akme Oblako on Кубернетес")
+	if len(v) != 2 {
+		t.Fatalf("violations = %v, want 2", v)
+	}
+	if v[0].String() != `"Облако Акме" must be translated as "Acme Cloud"` {
+		t.Errorf("violation = %q", v[0].String())
+	}
+}
+
+func TestGlossary_ProtectRestore(t *testing.T) {
+	g := NewGlossary("test", []GlossaryTerm{{"Облако Акме", "Acme Cloud"}, {"Акме", "Acme"}}, []string{"Kubernetes"})
+
+	protected, values := g.Protect("Облако Акме и Акме работают на kubernetes.")
+	if strings.Contains(protected, "Акме") || strings.Contains(protected, "kubernetes") {
+		t.Fatalf("terms left unprotected: %q", protected)
+	}
+	if !reflect.DeepEqual(values, []string{"Acme Cloud", "Acme", "kubernetes"}) {
+		t.Errorf("values = %v", values)
+	}
+
+	// Translation engines may change case or split tokens
+	translated := strings.Replace(protected, "ZQX0QZ", "zqx 0 QZ", 1)
+	translated = strings.Replace(translated, "и", "and", 1)
+	if got := Restore(translated, values); got != "Acme Cloud and Acme работают на kubernetes." {
+		t.Errorf("Restore = %q", got)
+	}
+}
+
+func TestGlossary_ProtectSubtitles(t *testing.T) {
+	g := NewGlossary("test", nil, []string{"Acme"})
+	subs := models.SubtitleList{{Index: 1, Text: "Acme rocks"}, {Index: 2, Text: "no terms"}}
+
+	protected, values := g.ProtectSubtitles(subs)
+	if subs[0].Text != "Acme rocks" {
+		t.Error("ProtectSubtitles must not modify its input")
+	}
+	if protected[0].Text != "ZQX0QZ rocks" || protected[1].Text != "no terms" {
+		t.Errorf("protected = %q, %q", protected[0].Text, protected[1].Text)
+	}
+
+	translated := models.SubtitleList{{Text: "ZQX0QZ rockt"}, {Text: "keine Begriffe"}}
+	RestoreSubtitles(translated, values)
+	if translated[0].Text != "Acme rockt" {
+		t.Errorf("restored = %q", translated[0].Text)
+	}
+}
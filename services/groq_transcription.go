@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"video-translator/internal/logger"
@@ -45,14 +46,15 @@ func (s *GroqTranscriptionService) CheckInstalled() error {
 }
 
 // Transcribe transcribes audio using Groq's Whisper API.
-// Returns subtitles with timestamps.
-func (s *GroqTranscriptionService) Transcribe(audioPath, language string) (models.SubtitleList, error) {
-	return s.TranscribeWithProgress(audioPath, language, nil)
+// Returns subtitles with timestamps. prompt biases the spelling of names
+// and terms; empty disables it.
+func (s *GroqTranscriptionService) Transcribe(audioPath, language, prompt string) (models.SubtitleList, error) {
+	return s.TranscribeWithProgress(audioPath, language, prompt, nil)
 }
 
 // TranscribeWithProgress transcribes audio with progress callbacks.
 func (s *GroqTranscriptionService) TranscribeWithProgress(
-	audioPath, language string,
+	audioPath, language, prompt string,
 	onProgress func(percent int, message string),
 ) (models.SubtitleList, error) {
 	logger.LogInfo("Groq Whisper API: model=%s lang=%s file=%s", groqWhisperModel, language, filepath.Base(audioPath))
@@ -71,15 +73,24 @@ func (s *GroqTranscriptionService) TranscribeWithProgress(
 	const maxFileSize = 25 * 1024 * 1024
 	if fileInfo.Size() > maxFileSize {
 		// Compress audio for upload
-		return s.transcribeCompressed(audioPath, language, onProgress)
+		return s.transcribeCompressed(audioPath, language, prompt, onProgress)
 	}
 
-	return s.transcribeDirect(audioPath, language, onProgress)
+	return s.transcribeDirect(audioPath, language, prompt, onProgress)
+}
+
+// DetectLanguage asks Groq's Whisper for the language of a (short) clip.
+func (s *GroqTranscriptionService) DetectLanguage(audioPath string) (LanguageDetection, error) {
+	if err := s.CheckInstalled(); err != nil {
+		return LanguageDetection{}, err
+	}
+	return detectLanguageViaAPI(rateLimiterFor(UsageStageTranscription, "groq"),
+		groqTranscriptionEndpoint, s.apiKey, groqWhisperModel, audioPath)
 }
 
 // transcribeDirect transcribes audio directly without compression.
 func (s *GroqTranscriptionService) transcribeDirect(
-	audioPath, language string,
+	audioPath, language, prompt string,
 	onProgress func(percent int, message string),
 ) (models.SubtitleList, error) {
 	if onProgress != nil {
@@ -112,6 +123,9 @@ func (s *GroqTranscriptionService) transcribeDirect(
 		writer.WriteField("language", language)
 	}
 	writer.WriteField("response_format", "verbose_json") // Get timestamps
+	if prompt != "" {
+		writer.WriteField("prompt", prompt)
+	}
 	writer.Close()
 
 	if onProgress != nil {
@@ -128,7 +142,7 @@ func (s *GroqTranscriptionService) transcribeDirect(
 
 	// Groq is fast, but use reasonable timeout
 	client := &http.Client{Timeout: 5 * time.Minute}
-	resp, err := client.Do(req)
+	resp, err := doRateLimited(rateLimiterFor(UsageStageTranscription, "groq"), client, req, RateCost{})
 	if err != nil {
 		return nil, fmt.Errorf("Groq API request failed: %w", err)
 	}
@@ -157,7 +171,7 @@ func (s *GroqTranscriptionService) transcribeDirect(
 	}
 
 	// Parse verbose_json response
-	subtitles, err := s.parseVerboseJSON(respBody)
+	subtitles, err := parseVerboseJSON(respBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Groq response: %w", err)
 	}
@@ -169,13 +183,16 @@ func (s *GroqTranscriptionService) transcribeDirect(
 	return subtitles, nil
 }
 
-// parseVerboseJSON parses Groq's verbose_json response format.
-func (s *GroqTranscriptionService) parseVerboseJSON(data []byte) (models.SubtitleList, error) {
+// parseVerboseJSON parses the verbose_json response format shared by Groq
+// and OpenAI, keeping each segment's decoder scores.
+func parseVerboseJSON(data []byte) (models.SubtitleList, error) {
 	var response struct {
 		Segments []struct {
-			Start float64 `json:"start"`
-			End   float64 `json:"end"`
-			Text  string  `json:"text"`
+			Start        float64 `json:"start"`
+			End          float64 `json:"end"`
+			Text         string  `json:"text"`
+			AvgLogprob   float64 `json:"avg_logprob"`
+			NoSpeechProb float64 `json:"no_speech_prob"`
 		} `json:"segments"`
 	}
 
@@ -184,11 +201,15 @@ func (s *GroqTranscriptionService) parseVerboseJSON(data []byte) (models.Subtitl
 	}
 
 	var subtitles models.SubtitleList
-	for _, seg := range response.Segments {
+	for i, seg := range response.Segments {
 		subtitles = append(subtitles, models.Subtitle{
-			StartTime: time.Duration(seg.Start * float64(time.Second)),
-			EndTime:   time.Duration(seg.End * float64(time.Second)),
-			Text:      seg.Text,
+			Index:        i + 1,
+			StartTime:    time.Duration(seg.Start * float64(time.Second)),
+			EndTime:      time.Duration(seg.End * float64(time.Second)),
+			Text:         strings.TrimSpace(seg.Text),
+			AvgLogprob:   seg.AvgLogprob,
+			NoSpeechProb: seg.NoSpeechProb,
+			HasScores:    true,
 		})
 	}
 
@@ -197,7 +218,7 @@ func (s *GroqTranscriptionService) parseVerboseJSON(data []byte) (models.Subtitl
 
 // transcribeCompressed compresses audio before upload for large files.
 func (s *GroqTranscriptionService) transcribeCompressed(
-	audioPath, language string,
+	audioPath, language, prompt string,
 	onProgress func(percent int, message string),
 ) (models.SubtitleList, error) {
 	if onProgress != nil {
@@ -211,7 +232,7 @@ func (s *GroqTranscriptionService) transcribeCompressed(
 	}
 	defer os.Remove(compressedPath)
 
-	return s.transcribeDirect(compressedPath, language, onProgress)
+	return s.transcribeDirect(compressedPath, language, prompt, onProgress)
 }
 
 // EstimateTime estimates transcription time for audio duration.
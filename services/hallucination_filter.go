@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// Actions recorded in a FilterReport entry
+const (
+	FilterActionRemoved = "removed"
+	FilterActionFlagged = "flagged"
+)
+
+// HallucinationFilter drops or flags junk segments that Whisper produces
+// during music, silence or credits ("Thanks for watching!", repeated lines,
+// subtitle credits) before they reach translation and TTS.
+type HallucinationFilter struct {
+	// Whisper's own no-speech rule: drop when NoSpeechProb is above this
+	// AND AvgLogprob is below LogprobThreshold
+	NoSpeechThreshold float64
+	LogprobThreshold  float64
+	// Segments with AvgLogprob below this are flagged (kept) for review
+	FlagLogprobThreshold float64
+
+	// Consecutive identical segments allowed before the rest are suspect
+	MaxRepeats int
+	// Segments whose words are this fraction duplicates are looped output
+	MaxRepetitionRatio float64
+
+	// Drop segments whose time overlaps detected speech by less than this
+	// fraction (only applied when silence intervals are supplied)
+	MinSpeechOverlap float64
+
+	// Known phrases and repeated lines are real dialogue too ("Bye bye",
+	// a line said three times), so they are only dropped when the segment
+	// is likely silent: no_speech_prob above NoSpeechThreshold, or less
+	// than this fraction overlapping detected speech. Otherwise they are
+	// kept and flagged.
+	ConfirmSpeechOverlap float64
+}
+
+// FilteredSegment describes one segment the filter removed or flagged
+type FilteredSegment struct {
+	Index   int      `json:"index"`
+	Start   string   `json:"start"`
+	End     string   `json:"end"`
+	Text    string   `json:"text"`
+	Action  string   `json:"action"`
+	Reasons []string `json:"reasons"`
+}
+
+// FilterReport summarizes what the hallucination filter did to a transcript
+type FilterReport struct {
+	Language      string            `json:"language"`
+	TotalSegments int               `json:"total_segments"`
+	Removed       int               `json:"removed"`
+	Flagged       int               `json:"flagged"`
+	Segments      []FilteredSegment `json:"segments"`
+}
+
+// NewHallucinationFilter creates a filter with thresholds that match
+// Whisper's defaults and are conservative enough not to eat real speech
+func NewHallucinationFilter() *HallucinationFilter {
+	return &HallucinationFilter{
+		NoSpeechThreshold:    0.6,
+		LogprobThreshold:     -1.0,
+		FlagLogprobThreshold: -1.5,
+		MaxRepeats:           2,
+		MaxRepetitionRatio:   0.7,
+		MinSpeechOverlap:     0.1,
+		ConfirmSpeechOverlap: 0.5,
+	}
+}
+
+// Filter returns the subtitles that survived filtering and a report of what
+// was dropped or flagged. silences may be nil when no VAD data is available.
+func (f *HallucinationFilter) Filter(subs models.SubtitleList, lang string, silences []SilenceInterval) (models.SubtitleList, *FilterReport) {
+	report := &FilterReport{
+		Language:      lang,
+		TotalSegments: len(subs),
+	}
+
+	kept := make(models.SubtitleList, 0, len(subs))
+	prevText := ""
+	repeats := 0
+
+	for i, sub := range subs {
+		var removeReasons, flagReasons, suspectReasons []string
+		normalized := text.NormalizeForMatch(sub.Text)
+
+		if normalized == "" {
+			removeReasons = append(removeReasons, "empty or punctuation-only text")
+		}
+
+		if phrase, ok := text.MatchHallucinationPhrase(sub.Text, lang); ok {
+			suspectReasons = append(suspectReasons, fmt.Sprintf("known hallucination phrase %q", phrase))
+		}
+
+		if sub.HasScores {
+			if sub.NoSpeechProb > f.NoSpeechThreshold && sub.AvgLogprob < f.LogprobThreshold {
+				removeReasons = append(removeReasons, fmt.Sprintf("no_speech_prob %.2f with avg_logprob %.2f", sub.NoSpeechProb, sub.AvgLogprob))
+			} else if sub.AvgLogprob < f.FlagLogprobThreshold {
+				flagReasons = append(flagReasons, fmt.Sprintf("low avg_logprob %.2f", sub.AvgLogprob))
+			}
+		}
+
+		// Repetition across segments: the same line looping N times
+		if normalized != "" && normalized == prevText {
+			repeats++
+		} else {
+			repeats = 1
+		}
+		prevText = normalized
+		if repeats > f.MaxRepeats {
+			suspectReasons = append(suspectReasons, fmt.Sprintf("repeated %d times in a row", repeats))
+		}
+
+		// Repetition within a segment: "I'm sorry. I'm sorry. I'm sorry..."
+		if ratio := repetitionRatio(normalized); ratio > f.MaxRepetitionRatio {
+			removeReasons = append(removeReasons, fmt.Sprintf("%.0f%% repeated words", ratio*100))
+		}
+
+		if len(silences) > 0 {
+			overlap := speechOverlap(sub.StartTime, sub.EndTime, silences)
+			if overlap < f.MinSpeechOverlap {
+				removeReasons = append(removeReasons, fmt.Sprintf("only %.0f%% overlaps detected speech", overlap*100))
+			}
+		}
+
+		if len(suspectReasons) > 0 {
+			if evidence := f.silenceEvidence(sub, silences); len(removeReasons) > 0 || evidence != "" {
+				removeReasons = append(removeReasons, suspectReasons...)
+				if evidence != "" {
+					removeReasons = append(removeReasons, evidence)
+				}
+			} else {
+				logger.LogInfo("Filter: keeping #%d %q (%s) as nothing shows it was silent",
+					i+1, sub.Text, strings.Join(suspectReasons, "; "))
+				flagReasons = append(flagReasons, suspectReasons...)
+			}
+		}
+
+		switch {
+		case len(removeReasons) > 0:
+			report.Removed++
+			report.Segments = append(report.Segments, newFilteredSegment(i+1, sub, FilterActionRemoved, removeReasons))
+		case len(flagReasons) > 0:
+			report.Flagged++
+			report.Segments = append(report.Segments, newFilteredSegment(i+1, sub, FilterActionFlagged, flagReasons))
+			kept = append(kept, sub)
+		default:
+			kept = append(kept, sub)
+		}
+	}
+
+	// Renumber so downstream SRT output stays sequential
+	for i := range kept {
+		kept[i].Index = i + 1
+	}
+
+	return kept, report
+}
+
+// silenceEvidence says why sub is likely not speech, "" when nothing does
+func (f *HallucinationFilter) silenceEvidence(sub models.Subtitle, silences []SilenceInterval) string {
+	if sub.HasScores && sub.NoSpeechProb > f.NoSpeechThreshold {
+		return fmt.Sprintf("no_speech_prob %.2f", sub.NoSpeechProb)
+	}
+	if len(silences) > 0 {
+		if overlap := speechOverlap(sub.StartTime, sub.EndTime, silences); overlap < f.ConfirmSpeechOverlap {
+			return fmt.Sprintf("only %.0f%% overlaps detected speech", overlap*100)
+		}
+	}
+	return ""
+}
+
+// Summary returns a one-line description for logs and progress messages
+func (r *FilterReport) Summary() string {
+	return fmt.Sprintf("removed %d, flagged %d of %d segments", r.Removed, r.Flagged, r.TotalSegments)
+}
+
+// WriteJSON saves the report as indented JSON
+func (r *FilterReport) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write filter report: %w", err)
+	}
+	logger.LogInfo("Filter: report written to %s", path)
+	return nil
+}
+
+func newFilteredSegment(index int, sub models.Subtitle, action string, reasons []string) FilteredSegment {
+	return FilteredSegment{
+		Index:   index,
+		Start:   formatReportTime(sub.StartTime),
+		End:     formatReportTime(sub.EndTime),
+		Text:    sub.Text,
+		Action:  action,
+		Reasons: reasons,
+	}
+}
+
+func formatReportTime(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := d.Seconds() - float64(h*3600+m*60)
+	return fmt.Sprintf("%02d:%02d:%06.3f", h, m, s)
+}
+
+// repetitionRatio returns the fraction of words that repeat an earlier word
+// sequence. Short segments are skipped since "no no" is ordinary speech.
+func repetitionRatio(normalized string) float64 {
+	words := strings.Fields(normalized)
+	if len(words) < 8 {
+		return 0
+	}
+
+	// Count words covered by n-grams (n=1..4) seen earlier in the segment
+	best := 0.0
+	for n := 1; n <= 4; n++ {
+		seen := make(map[string]bool)
+		repeated := 0
+		for i := 0; i+n <= len(words); i += n {
+			gram := strings.Join(words[i:i+n], " ")
+			if seen[gram] {
+				repeated += n
+			}
+			seen[gram] = true
+		}
+		if ratio := float64(repeated) / float64(len(words)); ratio > best {
+			best = ratio
+		}
+	}
+	return best
+}
+
+// speechOverlap returns the fraction of [start, end] that is NOT silent
+func speechOverlap(start, end time.Duration, silences []SilenceInterval) float64 {
+	segStart, segEnd := start.Seconds(), end.Seconds()
+	total := segEnd - segStart
+	if total <= 0 {
+		return 1
+	}
+
+	silent := 0.0
+	for _, s := range silences {
+		lo := max(segStart, s.Start)
+		hi := min(segEnd, s.End)
+		if hi > lo {
+			silent += hi - lo
+		}
+	}
+
+	return 1 - silent/total
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func filterSub(start, end float64, text string) models.Subtitle {
+	return models.Subtitle{
+		StartTime: time.Duration(start * float64(time.Second)),
+		EndTime:   time.Duration(end * float64(time.Second)),
+		Text:      text,
+	}
+}
+
+// silentSub is a segment Whisper scored as probably not speech
+func silentSub(start, end float64, text string) models.Subtitle {
+	sub := filterSub(start, end, text)
+	sub.HasScores = true
+	sub.NoSpeechProb = 0.8
+	sub.AvgLogprob = -0.3
+	return sub
+}
+
+func TestHallucinationFilter_KnownPhrases(t *testing.T) {
+	subs := models.SubtitleList{
+		filterSub(0, 2, "Привет, как дела?"),
+		silentSub(2, 4, "Спасибо за просмотр!"),
+		silentSub(4, 6, "Thanks for watching!"),
+		silentSub(6, 8, "Subtitles by the Amara.org community"),
+	}
+
+	kept, report := NewHallucinationFilter().Filter(subs, "ru", nil)
+
+	// "Thanks for watching" is only an English hallucination, so with
+	// lang=ru it survives; the Russian phrase and Amara credit do not.
+	if len(kept) != 2 {
+		t.Fatalf("kept %d segments, want 2: %+v", len(kept), kept)
+	}
+	if kept[0].Text != "Привет, как дела?" || kept[1].Text != "Thanks for watching!" {
+		t.Errorf("unexpected kept segments: %+v", kept)
+	}
+	if report.Removed != 2 {
+		t.Errorf("Removed = %d, want 2", report.Removed)
+	}
+}
+
+func TestHallucinationFilter_KeepsSentenceContainingPhrase(t *testing.T) {
+	subs := models.SubtitleList{
+		filterSub(0, 3, "Thanks for watching the whole thing with me yesterday."),
+	}
+
+	kept, _ := NewHallucinationFilter().Filter(subs, "en", nil)
+	if len(kept) != 1 {
+		t.Errorf("real sentence was removed")
+	}
+}
+
+func TestHallucinationFilter_ConsecutiveRepeats(t *testing.T) {
+	subs := models.SubtitleList{
+		silentSub(0, 2, "I love you."),
+		silentSub(2, 4, "I love you."),
+		silentSub(4, 6, "I love you!"),
+		silentSub(6, 8, "I love you."),
+		filterSub(8, 10, "Something else."),
+	}
+
+	kept, report := NewHallucinationFilter().Filter(subs, "en", nil)
+
+	if len(kept) != 3 {
+		t.Fatalf("kept %d segments, want 3", len(kept))
+	}
+	if report.Removed != 2 {
+		t.Errorf("Removed = %d, want 2", report.Removed)
+	}
+	for i, s := range kept {
+		if s.Index != i+1 {
+			t.Errorf("kept[%d].Index = %d, want %d", i, s.Index, i+1)
+		}
+	}
+}
+
+func TestHallucinationFilter_KeepsUnconfirmedDialogue(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+	}{
+		{"en", "You."},
+		{"en", "Bye bye!"},
+		{"en", "See you next time."},
+		{"en", "See you in the next video!"},
+		{"fr", "À bientôt !"},
+		{"es", "¡Hasta la próxima!"},
+		{"ru", "Всем пока!"},
+		{"ru", "До новых встреч."},
+	}
+	for _, tt := range tests {
+		// Scored as speech, and VAD heard speech throughout
+		sub := filterSub(0, 2, tt.text)
+		sub.HasScores = true
+		sub.NoSpeechProb = 0.05
+		sub.AvgLogprob = -0.2
+
+		kept, report := NewHallucinationFilter().Filter(models.SubtitleList{sub}, tt.lang, []SilenceInterval{{Start: 5, End: 6}})
+		if len(kept) != 1 {
+			t.Errorf("%q was removed: %+v", tt.text, report.Segments)
+			continue
+		}
+		if report.Flagged != 1 || report.Segments[0].Action != FilterActionFlagged {
+			t.Errorf("%q should be flagged for review, report %+v", tt.text, report)
+		}
+	}
+}
+
+func TestHallucinationFilter_KeepsRealRepeats(t *testing.T) {
+	// Chanting "Go!" three times is speech, not Whisper looping
+	subs := models.SubtitleList{
+		filterSub(0, 1, "Go!"),
+		filterSub(1, 2, "Go!"),
+		filterSub(2, 3, "Go!"),
+	}
+	kept, report := NewHallucinationFilter().Filter(subs, "en", nil)
+	if len(kept) != 3 {
+		t.Fatalf("kept %d segments, want 3", len(kept))
+	}
+	if report.Flagged != 1 || report.Removed != 0 {
+		t.Errorf("Removed=%d Flagged=%d, want 0 and 1", report.Removed, report.Flagged)
+	}
+}
+
+func TestHallucinationFilter_VADConfirmsPhrase(t *testing.T) {
+	// 70% of the segment is silent: too much speech for MinSpeechOverlap to
+	// drop it alone, but enough silence to confirm the phrase
+	subs := models.SubtitleList{filterSub(0, 10, "Thanks for watching!")}
+	kept, report := NewHallucinationFilter().Filter(subs, "en", []SilenceInterval{{Start: 3, End: 10}})
+	if len(kept) != 0 || report.Removed != 1 {
+		t.Errorf("kept %+v, report %+v; want the phrase removed", kept, report)
+	}
+}
+
+func TestHallucinationFilter_RepetitionWithinSegment(t *testing.T) {
+	subs := models.SubtitleList{
+		filterSub(0, 10, "I'm sorry. I'm sorry. I'm sorry. I'm sorry. I'm sorry. I'm sorry."),
+		filterSub(10, 14, "We went to the market and then we went back home."),
+	}
+
+	kept, _ := NewHallucinationFilter().Filter(subs, "en", nil)
+	if len(kept) != 1 || kept[0].StartTime != 10*time.Second {
+		t.Errorf("expected only the normal sentence to survive, got %+v", kept)
+	}
+}
+
+func TestHallucinationFilter_Scores(t *testing.T) {
+	noSpeech := filterSub(0, 2, "Hello there")
+	noSpeech.HasScores = true
+	noSpeech.NoSpeechProb = 0.9
+	noSpeech.AvgLogprob = -1.2
+
+	lowConfidence := filterSub(2, 4, "General Kenobi")
+	lowConfidence.HasScores = true
+	lowConfidence.NoSpeechProb = 0.1
+	lowConfidence.AvgLogprob = -1.8
+
+	confident := filterSub(4, 6, "You are a bold one")
+	confident.HasScores = true
+	confident.NoSpeechProb = 0.9
+	confident.AvgLogprob = -0.2
+
+	kept, report := NewHallucinationFilter().Filter(models.SubtitleList{noSpeech, lowConfidence, confident}, "en", nil)
+
+	if len(kept) != 2 {
+		t.Fatalf("kept %d segments, want 2", len(kept))
+	}
+	if report.Removed != 1 || report.Flagged != 1 {
+		t.Errorf("Removed=%d Flagged=%d, want 1 and 1", report.Removed, report.Flagged)
+	}
+	if report.Segments[1].Action != FilterActionFlagged {
+		t.Errorf("second report entry action = %q, want %q", report.Segments[1].Action, FilterActionFlagged)
+	}
+}
+
+func TestHallucinationFilter_VADOverlap(t *testing.T) {
+	subs := models.SubtitleList{
+		filterSub(0, 4, "Real speech here"),
+		filterSub(10, 14, "Phantom line during music"),
+	}
+	silences := []SilenceInterval{{Start: 5, End: 20}}
+
+	kept, report := NewHallucinationFilter().Filter(subs, "en", silences)
+	if len(kept) != 1 || kept[0].Text != "Real speech here" {
+		t.Errorf("unexpected kept segments: %+v", kept)
+	}
+	if report.Removed != 1 {
+		t.Errorf("Removed = %d, want 1", report.Removed)
+	}
+}
+
+func TestFilterReport_WriteJSON(t *testing.T) {
+	_, report := NewHallucinationFilter().Filter(models.SubtitleList{
+		filterSub(61.5, 63, "Thanks for watching!"),
+	}, "en", nil)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := report.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded FilterReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if len(decoded.Segments) != 1 || decoded.Segments[0].Start != "00:01:01.500" {
+		t.Errorf("unexpected report contents: %+v", decoded)
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"video-translator/internal/text"
+)
+
+// AutoSourceLang is the job source language that asks the pipeline to
+// detect the spoken language before transcribing
+const AutoSourceLang = "auto"
+
+// LanguageDetection is a provider's guess at the spoken language
+type LanguageDetection struct {
+	Language   string  // ISO 639-1 code
+	Confidence float64 // 0-1, 0 when the provider doesn't report one
+}
+
+// Probe clip settings for language detection. Starting a little way in
+// skips intros, which are often music or silence.
+const (
+	languageProbeOffsetRatio = 0.1
+	languageProbeDuration    = 30.0 // seconds, Whisper's window size
+)
+
+// detectLanguageViaAPI sends audio to an OpenAI-compatible transcription
+// endpoint without a language and reads the language from verbose_json.
+// These APIs report the language by name ("russian") and give no probability.
+// Requests go through the provider's limiter.
+func detectLanguageViaAPI(limiter *RateLimiter, endpoint, apiKey, model, audioPath string) (LanguageDetection, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to copy file: %w", err)
+	}
+	writer.WriteField("model", model)
+	writer.WriteField("response_format", "verbose_json")
+	writer.Close()
+
+	req, err := http.NewRequest("POST", endpoint, body)
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := doRateLimited(limiter, client, req, RateCost{})
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			return LanguageDetection{}, fmt.Errorf("API error: %s", errResp.Error.Message)
+		}
+		return LanguageDetection{}, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	return parseVerboseJSONLanguage(respBody)
+}
+
+// parseVerboseJSONLanguage extracts the language from a verbose_json response
+func parseVerboseJSONLanguage(data []byte) (LanguageDetection, error) {
+	var response struct {
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return LanguageDetection{}, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	code := text.LanguageCodeFromName(response.Language)
+	if code == "" {
+		return LanguageDetection{}, fmt.Errorf("unrecognized language %q", response.Language)
+	}
+	return LanguageDetection{Language: code}, nil
+}
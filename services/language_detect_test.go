@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"video-translator/internal/text"
+)
+
+func TestParseVerboseJSONLanguage(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{`{"language":"russian","text":"привет"}`, "ru", false},
+		{`{"language":"English"}`, "en", false},
+		{`{"language":"de"}`, "de", false},
+		{`{"language":"javanese"}`, "jv", false},
+		{`{"language":"jw"}`, "jv", false},
+		{`{"language":"castilian"}`, "es", false},
+		{`{"language":"klingon"}`, "", true},
+		{`{"text":"no language"}`, "", true},
+		{`not json`, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseVerboseJSONLanguage([]byte(tt.body))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseVerboseJSONLanguage(%s) = %+v, want error", tt.body, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseVerboseJSONLanguage(%s) error: %v", tt.body, err)
+			continue
+		}
+		if got.Language != tt.want {
+			t.Errorf("parseVerboseJSONLanguage(%s) = %q, want %q", tt.body, got.Language, tt.want)
+		}
+	}
+}
+
+func TestWhisperDetectedLangRegex(t *testing.T) {
+	output := "whisper_init_state: kv self size = 6.29 MB\n" +
+		"whisper_full_with_state: auto-detected language: ru (p = 0.976562)\n"
+
+	matches := text.WhisperDetectedLangRegex.FindStringSubmatch(output)
+	if len(matches) < 3 {
+		t.Fatalf("no match in %q", output)
+	}
+	if matches[1] != "ru" || matches[2] != "0.976562" {
+		t.Errorf("got lang=%q p=%q, want ru 0.976562", matches[1], matches[2])
+	}
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"video-translator/internal/config"
+	internalhttp "video-translator/internal/http"
+	"video-translator/internal/logger"
+	textutil "video-translator/internal/text"
+	"video-translator/internal/worker"
+	"video-translator/models"
+)
+
+// TranslationProviderLibreTranslate is the provider name for LibreTranslate
+const TranslationProviderLibreTranslate = "libretranslate"
+
+// DefaultLibreTranslateURL is where a self-hosted server listens by default
+const DefaultLibreTranslateURL = "http://localhost:5000"
+
+// LibreTranslateService translates through a LibreTranslate server's HTTP
+// API, so machines need no local Python or language packages
+type LibreTranslateService struct {
+	baseURL string
+	apiKey  string // Only for servers started with --api-keys
+	client  *http.Client
+	retry   internalhttp.RetryConfig
+
+	// Languages reported by /languages, loaded on first use
+	langMu    sync.Mutex
+	languages []libreLanguage
+}
+
+// libreLanguage is one entry of GET /languages
+type libreLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"` // Missing on old servers: any language
+}
+
+// NewLibreTranslateService creates a client for the server at baseURL
+func NewLibreTranslateService(baseURL, apiKey string) *LibreTranslateService {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultLibreTranslateURL
+	}
+	return &LibreTranslateService{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		client:  internalhttp.NewDefaultClient(),
+		retry:   internalhttp.DefaultRetryConfig(),
+	}
+}
+
+// CheckInstalled verifies the server is reachable
+func (s *LibreTranslateService) CheckInstalled() error {
+	_, err := s.Languages()
+	return err
+}
+
+// Languages returns the codes the server translates from, in its order
+func (s *LibreTranslateService) Languages() ([]string, error) {
+	langs, err := s.loadLanguages()
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(langs))
+	for i, l := range langs {
+		codes[i] = l.Code
+	}
+	return codes, nil
+}
+
+// loadLanguages fetches /languages once; a failure is retried next call
+func (s *LibreTranslateService) loadLanguages() ([]libreLanguage, error) {
+	s.langMu.Lock()
+	defer s.langMu.Unlock()
+	if s.languages != nil {
+		return s.languages, nil
+	}
+
+	req, err := http.NewRequest("GET", s.baseURL+"/languages", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := internalhttp.DoWithRetry(s.client, req, s.retry)
+	if err != nil {
+		return nil, fmt.Errorf("LibreTranslate server not reachable at %s: %w", s.baseURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LibreTranslate languages: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, libreTranslateError(resp.StatusCode, body)
+	}
+
+	var langs []libreLanguage
+	if err := json.Unmarshal(body, &langs); err != nil {
+		return nil, fmt.Errorf("unexpected LibreTranslate languages response: %w", err)
+	}
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("LibreTranslate server at %s has no languages installed", s.baseURL)
+	}
+	logger.LogInfo("LibreTranslate: %s serves %d languages", s.baseURL, len(langs))
+	s.languages = langs
+	return langs, nil
+}
+
+// resolveLanguage maps one of our language codes onto the server's: "zh"
+// is "zh-Hans" on newer servers, "pt" may be "pt-BR". ok is false when the
+// server doesn't have the language.
+func resolveLanguage(langs []libreLanguage, lang string) (libreLanguage, bool) {
+	if code := textutil.ProviderCode(textutil.StageTranslation, TranslationProviderLibreTranslate, lang); code != lang {
+		for _, l := range langs {
+			if strings.EqualFold(l.Code, code) {
+				return l, true
+			}
+		}
+	}
+	for _, l := range langs {
+		if strings.EqualFold(l.Code, lang) {
+			return l, true
+		}
+	}
+	for _, l := range langs {
+		if primaryLangTag(l.Code) == primaryLangTag(lang) {
+			return l, true
+		}
+	}
+	return libreLanguage{}, false
+}
+
+// resolvePair returns the server's codes for a language pair, or why the
+// server can't translate it
+func (s *LibreTranslateService) resolvePair(sourceLang, targetLang string) (string, string, error) {
+	langs, err := s.loadLanguages()
+	if err != nil {
+		return "", "", err
+	}
+	source, ok := resolveLanguage(langs, sourceLang)
+	if !ok {
+		return "", "", fmt.Errorf("LibreTranslate server does not support source language %q", sourceLang)
+	}
+	target, ok := resolveLanguage(langs, targetLang)
+	if !ok {
+		return "", "", fmt.Errorf("LibreTranslate server does not support target language %q", targetLang)
+	}
+	if len(source.Targets) > 0 {
+		supported := false
+		for _, t := range source.Targets {
+			if t == target.Code {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return "", "", fmt.Errorf("LibreTranslate server cannot translate %s → %s", source.Code, target.Code)
+		}
+	}
+	return source.Code, target.Code, nil
+}
+
+// CheckLanguagePair verifies the server can translate sourceLang → targetLang
+func (s *LibreTranslateService) CheckLanguagePair(sourceLang, targetLang string) error {
+	_, _, err := s.resolvePair(sourceLang, targetLang)
+	return err
+}
+
+// Translate translates a single text
+func (s *LibreTranslateService) Translate(text, sourceLang, targetLang string) (string, error) {
+	translated, err := s.TranslateBatch([]string{text}, sourceLang, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return translated[0], nil
+}
+
+// TranslateBatch translates texts in one request (a "q" array)
+func (s *LibreTranslateService) TranslateBatch(texts []string, sourceLang, targetLang string) ([]string, error) {
+	if len(texts) == 0 {
+		return texts, nil
+	}
+	source, target, err := s.resolvePair(sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	processedTexts := make([]string, len(texts))
+	for i, text := range texts {
+		processedTexts[i] = textutil.Preprocess(text)
+	}
+	reqBody := map[string]interface{}{
+		"q":      processedTexts,
+		"source": source,
+		"target": target,
+		"format": "text",
+	}
+	if s.apiKey != "" {
+		reqBody["api_key"] = s.apiKey
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", s.baseURL+"/translate", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// DoWithRetry waits out 429s itself, so the limiter only shares the
+	// server's concurrency between jobs
+	limiter := rateLimiterFor(UsageStageTranslation, TranslationProviderLibreTranslate)
+	cost := RateCost{Characters: utf8.RuneCountInString(strings.Join(texts, "\n"))}
+	limiter.Acquire(cost)
+	resp, err := internalhttp.DoWithRetry(s.client, req, s.retry)
+	limiter.Release(cost, false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("LibreTranslate request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LibreTranslate response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, libreTranslateError(resp.StatusCode, body)
+	}
+
+	var result struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unexpected LibreTranslate response: %w", err)
+	}
+	if len(result.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("LibreTranslate returned %d texts for %d", len(result.TranslatedText), len(texts))
+	}
+
+	translated := make([]string, len(texts))
+	for i, r := range result.TranslatedText {
+		translated[i] = textutil.Postprocess(strings.TrimSpace(r))
+	}
+	return translated, nil
+}
+
+// TranslateSubtitlesWithProgress translates subtitles in batches of
+// ChunkSizeLibreTranslate, a few requests at a time. Translated characters
+// are recorded on usage (nil = not recorded).
+func (s *LibreTranslateService) TranslateSubtitlesWithProgress(
+	subs models.SubtitleList,
+	sourceLang, targetLang string,
+	usage *UsageMeter,
+	onProgress func(current, total int),
+) (models.SubtitleList, error) {
+	logger.LogInfo("LibreTranslate: %d subtitles (%s → %s) via %s", len(subs), sourceLang, targetLang, s.baseURL)
+
+	if len(subs) == 0 {
+		return subs, nil
+	}
+	// Fail on an unsupported pair before sending any batch
+	if err := s.CheckLanguagePair(sourceLang, targetLang); err != nil {
+		return nil, err
+	}
+
+	var batches [][]string
+	for i := 0; i < len(subs); i += config.ChunkSizeLibreTranslate {
+		end := min(i+config.ChunkSizeLibreTranslate, len(subs))
+		texts := make([]string, 0, end-i)
+		for _, sub := range subs[i:end] {
+			texts = append(texts, sub.Text)
+		}
+		batches = append(batches, texts)
+	}
+
+	total := len(subs)
+	var progressCallback worker.ProgressFunc
+	if onProgress != nil {
+		progressCallback = func(completed, _ int) {
+			onProgress(min(completed*config.ChunkSizeLibreTranslate, total), total)
+		}
+	}
+	results, err := worker.Process(batches, config.LibreTranslateConcurrency, func(job worker.Job[[]string]) ([]string, error) {
+		translated, err := s.TranslateBatch(job.Data, sourceLang, targetLang)
+		if err == nil {
+			usage.RecordCharacters(UsageStageTranslation, TranslationProviderLibreTranslate, "", strings.Join(job.Data, "\n"))
+		}
+		return translated, err
+	}, progressCallback)
+	if err != nil {
+		return nil, fmt.Errorf("translation failed: %w", err)
+	}
+
+	translated := make(models.SubtitleList, 0, total)
+	for _, batch := range results {
+		for _, text := range batch {
+			sub := subs[len(translated)]
+			sub.Text = text
+			translated = append(translated, sub)
+		}
+	}
+	return translated, nil
+}
+
+// libreTranslateError turns an error response ({"error": "..."}) into an error
+func libreTranslateError(status int, body []byte) error {
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		return fmt.Errorf("LibreTranslate error (status %d): %s", status, errResp.Error)
+	}
+	return fmt.Errorf("LibreTranslate error (status %d): %s", status, strings.TrimSpace(string(body)))
+}
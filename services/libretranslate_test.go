@@ -0,0 +1,148 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+// newLibreTranslateStub serves /languages and a /translate that upper-cases
+// its input. failFirst requests to /translate get a 503 first.
+func newLibreTranslateStub(t *testing.T, failFirst int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var translateCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/languages":
+			fmt.Fprint(w, `[
+				{"code": "en", "name": "English", "targets": ["de", "zh-Hans"]},
+				{"code": "de", "name": "German", "targets": ["en"]},
+				{"code": "zh-Hans", "name": "Chinese", "targets": ["en"]}
+			]`)
+		case "/translate":
+			n := translateCalls.Add(1)
+			var req struct {
+				Q      []string `json:"q"`
+				Source string   `json:"source"`
+				Target string   `json:"target"`
+				APIKey string   `json:"api_key"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, `{"error": "bad body"}`, http.StatusBadRequest)
+				return
+			}
+			if n <= failFirst {
+				http.Error(w, `{"error": "busy"}`, http.StatusServiceUnavailable)
+				return
+			}
+			if req.APIKey != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error": "Invalid API key"}`)
+				return
+			}
+			out := make([]string, len(req.Q))
+			for i, q := range req.Q {
+				out[i] = strings.ToUpper(q) + " [" + req.Source + ">" + req.Target + "]"
+			}
+			json.NewEncoder(w).Encode(map[string][]string{"translatedText": out})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &translateCalls
+}
+
+func newTestLibreTranslate(url, apiKey string) *LibreTranslateService {
+	s := NewLibreTranslateService(url, apiKey)
+	s.retry.InitialDelay = time.Millisecond
+	return s
+}
+
+func TestLibreTranslate_CheckLanguagePair(t *testing.T) {
+	server, _ := newLibreTranslateStub(t, 0)
+	s := newTestLibreTranslate(server.URL+"/", "secret")
+
+	if err := s.CheckInstalled(); err != nil {
+		t.Fatalf("CheckInstalled: %v", err)
+	}
+	for _, pair := range [][2]string{{"en", "de"}, {"de", "en"}, {"en", "zh"}} {
+		if err := s.CheckLanguagePair(pair[0], pair[1]); err != nil {
+			t.Errorf("%s → %s: %v", pair[0], pair[1], err)
+		}
+	}
+	if err := s.CheckLanguagePair("de", "zh"); err == nil {
+		t.Error("de → zh is not offered by the server")
+	}
+	if err := s.CheckLanguagePair("en", "ja"); err == nil || !strings.Contains(err.Error(), `"ja"`) {
+		t.Errorf("unknown target error = %v", err)
+	}
+}
+
+func TestLibreTranslate_TranslateSubtitles(t *testing.T) {
+	server, calls := newLibreTranslateStub(t, 0)
+	s := newTestLibreTranslate(server.URL, "secret")
+
+	subs := make(models.SubtitleList, 60)
+	for i := range subs {
+		subs[i] = models.Subtitle{Index: i + 1, StartTime: time.Duration(i) * time.Second, Text: fmt.Sprintf("line %d", i), Speaker: "A"}
+	}
+	var lastProgress int
+	got, err := s.TranslateSubtitlesWithProgress(subs, "en", "zh", nil, func(current, total int) {
+		lastProgress = current
+	})
+	if err != nil {
+		t.Fatalf("TranslateSubtitlesWithProgress: %v", err)
+	}
+	if len(got) != len(subs) {
+		t.Fatalf("got %d subtitles, want %d", len(got), len(subs))
+	}
+	for i, sub := range got {
+		want := fmt.Sprintf("LINE %d [en>zh-Hans]", i)
+		if sub.Text != want || sub.StartTime != subs[i].StartTime || sub.Speaker != "A" {
+			t.Errorf("sub %d = %+v, want text %q with timing and speaker kept", i, sub, want)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("%d requests, want 3 batches of up to 25", n)
+	}
+	if lastProgress != len(subs) {
+		t.Errorf("final progress = %d, want %d", lastProgress, len(subs))
+	}
+}
+
+func TestLibreTranslate_RetriesWithBody(t *testing.T) {
+	server, calls := newLibreTranslateStub(t, 2)
+	s := newTestLibreTranslate(server.URL, "secret")
+
+	got, err := s.Translate("hello", "en", "de")
+	if err != nil {
+		t.Fatalf("Translate after 503s: %v", err)
+	}
+	if got != "HELLO [en>de]" {
+		t.Errorf("got %q", got)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("%d requests, want 3", n)
+	}
+}
+
+func TestLibreTranslate_Errors(t *testing.T) {
+	server, _ := newLibreTranslateStub(t, 0)
+	_, err := newTestLibreTranslate(server.URL, "wrong").Translate("hello", "en", "de")
+	if err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("bad key error = %v", err)
+	}
+
+	down := newTestLibreTranslate("http://127.0.0.1:1", "")
+	if err := down.CheckInstalled(); err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("unreachable server error = %v", err)
+	}
+}
@@ -0,0 +1,1061 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/internal/config"
+	internalhttp "video-translator/internal/http"
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// LLM translation providers. Each is a preset of LLMTranslationService;
+// "llm" is a fully user-configured OpenAI-compatible endpoint.
+const (
+	LLMProviderOpenAI   = "openai"
+	LLMProviderDeepSeek = "deepseek"
+	LLMProviderGrok     = "grok"
+	LLMProviderOllama   = "ollama"
+	LLMProviderLMStudio = "lmstudio"
+	LLMProviderLlamaCpp = "llama-cpp"
+	LLMProviderCustom   = "llm"
+)
+
+// LLMTranslationConfig describes an OpenAI-compatible chat completions API
+type LLMTranslationConfig struct {
+	Name        string  // Display name for logs and errors ("DeepSeek")
+	Provider    string  // Provider ID for usage records ("deepseek")
+	BaseURL     string  // API root, e.g. https://api.deepseek.com/v1
+	Model       string  // Model ID sent with every request
+	APIKey      string  // Bearer token; local servers usually need none
+	RequireKey  bool    // Fail fast when APIKey is empty (cloud APIs)
+	MaxTokens   int     // max_tokens per request
+	Temperature float64 // Sampling temperature
+	Concurrency int     // Parallel requests
+	BatchSize   int     // Subtitles per request
+	JSONMode    bool    // Send response_format json_object (LM Studio rejects it)
+	Timeout     time.Duration
+
+	// Context for coherence across batches
+	ContextLines   int  // Read-only source lines sent before and after each batch
+	RunningSummary bool // Carry a story-so-far summary between batches (runs them in order)
+	DocumentBrief  bool // Prepend a one-time brief of the whole transcript to every batch
+}
+
+// OpenAITranslationPreset returns settings for OpenAI GPT-4o-mini (~$0.05 per 5h of subtitles)
+func OpenAITranslationPreset(apiKey string) LLMTranslationConfig {
+	return LLMTranslationConfig{
+		Name:        "OpenAI",
+		BaseURL:     config.OpenAIAPIEndpoint,
+		Model:       config.OpenAITranslationModel,
+		APIKey:      apiKey,
+		RequireKey:  true,
+		MaxTokens:   config.TranslationMaxTokens,
+		Temperature: config.TranslationTemperature,
+		Concurrency: config.WorkersOpenAI,
+		BatchSize:   config.ChunkSizeOpenAI,
+		JSONMode:    true,
+		Timeout:     config.HTTPTimeout,
+	}
+}
+
+// DeepSeekTranslationPreset returns settings for deepseek-chat (~$0.04 per 5h of subtitles)
+func DeepSeekTranslationPreset(apiKey string) LLMTranslationConfig {
+	return LLMTranslationConfig{
+		Name:        "DeepSeek",
+		BaseURL:     config.DeepSeekAPIBaseURL,
+		Model:       config.DeepSeekModel,
+		APIKey:      apiKey,
+		RequireKey:  true,
+		MaxTokens:   config.DeepSeekMaxTokens,
+		Temperature: config.TranslationTemperature,
+		Concurrency: config.WorkersDeepSeek,
+		BatchSize:   config.ChunkSizeDeepSeek,
+		JSONMode:    true,
+		Timeout:     config.HTTPTimeout,
+	}
+}
+
+// GrokTranslationPreset returns settings for xAI's cheapest Grok model
+// ($0.20/$0.50 per million tokens)
+func GrokTranslationPreset(apiKey string) LLMTranslationConfig {
+	return LLMTranslationConfig{
+		Name:        "Grok",
+		BaseURL:     config.GrokAPIBaseURL,
+		Model:       config.GrokModel,
+		APIKey:      apiKey,
+		RequireKey:  true,
+		MaxTokens:   config.GrokMaxTokens,
+		Temperature: config.TranslationTemperature,
+		Concurrency: config.WorkersGrok,
+		BatchSize:   config.ChunkSizeGrok,
+		JSONMode:    true,
+		Timeout:     config.HTTPTimeout,
+	}
+}
+
+// localLLMPreset returns settings for a model served on this machine. Local
+// servers handle one or two requests at a time and are much slower per
+// token, so concurrency is low and the timeout generous.
+func localLLMPreset(name, baseURL, model string) LLMTranslationConfig {
+	return LLMTranslationConfig{
+		Name:        name,
+		BaseURL:     baseURL,
+		Model:       model,
+		MaxTokens:   config.TranslationMaxTokens,
+		Temperature: config.TranslationTemperature,
+		Concurrency: 2,
+		BatchSize:   10,
+		Timeout:     10 * time.Minute,
+	}
+}
+
+// OllamaTranslationPreset returns settings for Ollama's OpenAI-compatible API
+func OllamaTranslationPreset(model string) LLMTranslationConfig {
+	c := localLLMPreset("Ollama", "http://localhost:11434/v1", model)
+	c.JSONMode = true
+	return c
+}
+
+// LMStudioTranslationPreset returns settings for LM Studio's local server
+func LMStudioTranslationPreset(model string) LLMTranslationConfig {
+	return localLLMPreset("LM Studio", "http://localhost:1234/v1", model)
+}
+
+// LlamaCppTranslationPreset returns settings for llama.cpp's llama-server.
+// The server ignores the model name and uses whatever it was started with.
+func LlamaCppTranslationPreset(model string) LLMTranslationConfig {
+	c := localLLMPreset("llama.cpp", "http://localhost:8080/v1", model)
+	c.JSONMode = true
+	return c
+}
+
+// IsLLMTranslationProvider reports whether provider is served by LLMTranslationService
+func IsLLMTranslationProvider(provider string) bool {
+	switch provider {
+	case LLMProviderOpenAI, LLMProviderDeepSeek, LLMProviderGrok,
+		LLMProviderOllama, LLMProviderLMStudio, LLMProviderLlamaCpp, LLMProviderCustom:
+		return true
+	}
+	return false
+}
+
+// LLMTranslationConfigFor builds the settings for a translation provider from
+// the app config. Non-zero LLM* overrides in cfg apply on top of any preset.
+func LLMTranslationConfigFor(provider string, cfg *models.Config) (LLMTranslationConfig, error) {
+	var c LLMTranslationConfig
+	switch provider {
+	case LLMProviderOpenAI:
+		c = OpenAITranslationPreset(cfg.OpenAIKey)
+	case LLMProviderDeepSeek:
+		c = DeepSeekTranslationPreset(cfg.DeepSeekKey)
+	case LLMProviderGrok:
+		c = GrokTranslationPreset(cfg.GrokAPIKey)
+	case LLMProviderOllama:
+		c = OllamaTranslationPreset(cfg.LLMModel)
+	case LLMProviderLMStudio:
+		c = LMStudioTranslationPreset(cfg.LLMModel)
+	case LLMProviderLlamaCpp:
+		c = LlamaCppTranslationPreset(cfg.LLMModel)
+	case LLMProviderCustom:
+		c = localLLMPreset("LLM", cfg.LLMBaseURL, cfg.LLMModel)
+		c.APIKey = cfg.LLMAPIKey
+	default:
+		return LLMTranslationConfig{}, fmt.Errorf("unknown LLM translation provider %q", provider)
+	}
+	c.Provider = provider
+
+	// Local servers listen wherever the user started them
+	if cfg.LLMBaseURL != "" && !c.RequireKey {
+		c.BaseURL = cfg.LLMBaseURL
+	}
+	if cfg.LLMMaxTokens > 0 {
+		c.MaxTokens = cfg.LLMMaxTokens
+	}
+	if cfg.LLMTemperature > 0 {
+		c.Temperature = cfg.LLMTemperature
+	}
+	if cfg.LLMConcurrency > 0 {
+		c.Concurrency = cfg.LLMConcurrency
+	}
+
+	c.ContextLines = cfg.TranslationContextLines
+	c.RunningSummary = cfg.TranslationRunningSummary
+	c.DocumentBrief = cfg.TranslationDocumentBrief
+	return c, nil
+}
+
+// LLMTranslationService translates subtitles through any OpenAI-compatible
+// chat completions API (OpenAI, DeepSeek, xAI, Ollama, LM Studio, llama.cpp).
+// Subtitles are deduplicated, batched as JSON arrays of {id, text} and sent in
+// parallel; replies are matched back to subtitles strictly by ID.
+type LLMTranslationService struct {
+	cfg    LLMTranslationConfig
+	client *http.Client
+}
+
+// TranslateOptions carries one job's settings into a translation call.
+// They are passed per call rather than set on the service, which is shared
+// by jobs running side by side.
+type TranslateOptions struct {
+	Glossary *Glossary                 // Required term translations (nil = none)
+	Profile  models.TranslationProfile // Style, formality and locale variant
+	Prompts  *PromptTemplates          // nil = built-in prompts
+
+	// Memory serves exact matches and suggests similar lines; MemoryScope
+	// keeps this provider's and profile's entries apart from others'
+	Memory      *TranslationMemory // nil = none
+	MemoryScope string
+
+	Usage *UsageMeter // Where the calls' token usage is recorded (nil = not recorded)
+}
+
+// NewLLMTranslationService creates a translation service for cfg
+func NewLLMTranslationService(cfg LLMTranslationConfig) *LLMTranslationService {
+	if cfg.Name == "" {
+		cfg.Name = "LLM"
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = config.TranslationMaxTokens
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = config.HTTPTimeout
+	}
+	if cfg.ContextLines < 0 {
+		cfg.ContextLines = 0
+	}
+	clientCfg := internalhttp.DefaultClientConfig()
+	clientCfg.Timeout = cfg.Timeout
+
+	return &LLMTranslationService{
+		cfg:    cfg,
+		client: internalhttp.NewPooledClient(clientCfg),
+	}
+}
+
+// NewDeepSeekService creates a DeepSeek translation service
+func NewDeepSeekService(apiKey string) *LLMTranslationService {
+	return NewLLMTranslationService(DeepSeekTranslationPreset(apiKey))
+}
+
+// NewGrokTranslationService creates a Grok (xAI) translation service
+func NewGrokTranslationService(apiKey string) *LLMTranslationService {
+	return NewLLMTranslationService(GrokTranslationPreset(apiKey))
+}
+
+// NewOpenAITranslationService creates an OpenAI translation service
+func NewOpenAITranslationService(apiKey string) *LLMTranslationService {
+	return NewLLMTranslationService(OpenAITranslationPreset(apiKey))
+}
+
+// memoryScopeFor separates emotion-tagged translations, which must carry an
+// emotion when served from memory
+func (o TranslateOptions) memoryScopeFor(withEmotions bool) string {
+	if withEmotions {
+		return o.MemoryScope + ":emotions"
+	}
+	return o.MemoryScope
+}
+
+// Name returns the provider's display name
+func (s *LLMTranslationService) Name() string {
+	return s.cfg.Name
+}
+
+// Model returns the model ID requests are sent with
+func (s *LLMTranslationService) Model() string {
+	return s.cfg.Model
+}
+
+// CheckAPIKey validates that the service is configured well enough to call
+func (s *LLMTranslationService) CheckAPIKey() error {
+	if s.cfg.RequireKey && s.cfg.APIKey == "" {
+		return fmt.Errorf("%s API key is not configured", s.cfg.Name)
+	}
+	if s.cfg.BaseURL == "" {
+		return fmt.Errorf("%s base URL is not configured", s.cfg.Name)
+	}
+	if s.cfg.Model == "" {
+		return fmt.Errorf("%s model is not configured", s.cfg.Name)
+	}
+	return nil
+}
+
+// llmTranslation is one translated subtitle; emotion is empty unless
+// emotion tagging was requested
+type llmTranslation struct {
+	text    string
+	emotion string
+}
+
+// translationDocument is the transcript a set of unique texts came from,
+// used to give each batch its surrounding context
+type translationDocument struct {
+	lines    []string // Non-empty source lines in subtitle order
+	position []int    // Index in lines of each unique text's first occurrence
+	budgets  []int    // Character budget of each unique text (0 = none)
+	brief    string   // Optional one-time document brief
+}
+
+// contextFor returns the read-only context for unique texts [start, end)
+func (d *translationDocument) contextFor(start, end, contextLines int) batchContext {
+	ctx := batchContext{brief: d.brief}
+	if end <= len(d.budgets) {
+		ctx.budgets = d.budgets[start:end]
+	}
+	if contextLines <= 0 || end > len(d.position) {
+		return ctx
+	}
+	first, last := d.position[start], d.position[end-1]
+	ctx.before = d.lines[max(0, first-contextLines):first]
+	ctx.after = d.lines[last+1 : min(len(d.lines), last+1+contextLines)]
+	return ctx
+}
+
+// batchContext is what the model sees besides the segments to translate
+type batchContext struct {
+	brief         string
+	summary       string
+	before        []string
+	after         []string
+	updateSummary bool      // Ask for an updated summary in the reply
+	budgets       []int     // Per-segment character budgets, aligned with the batch
+	glossary      *Glossary // Glossary entries that occur in the batch
+	suggestions   []TMMatch // Translation memory entries similar to batch lines
+}
+
+// render formats the context as a prompt section, empty when there is none
+func (c batchContext) render() string {
+	var b strings.Builder
+	b.WriteString(c.glossary.PromptSection())
+	if len(c.suggestions) > 0 {
+		b.WriteString("TRANSLATION MEMORY (earlier translations of similar lines; reuse their wording where it fits):\n")
+		for _, m := range c.suggestions {
+			fmt.Fprintf(&b, "- %s → %s\n", m.Source, m.Target)
+		}
+		b.WriteString("\n")
+	}
+	if c.brief != "" {
+		b.WriteString("ABOUT THIS VIDEO:\n" + c.brief + "\n\n")
+	}
+	if c.summary != "" {
+		b.WriteString("STORY SO FAR:\n" + c.summary + "\n\n")
+	}
+	if len(c.before) > 0 {
+		b.WriteString("PRECEDING LINES (context only, do not translate):\n" + strings.Join(c.before, "\n") + "\n\n")
+	}
+	if len(c.after) > 0 {
+		b.WriteString("FOLLOWING LINES (context only, do not translate):\n" + strings.Join(c.after, "\n") + "\n\n")
+	}
+	if c.updateSummary {
+		b.WriteString(`Also add a "summary" field to the reply object: the story so far updated with these segments, ` +
+			"at most 100 words, naming who speaks, how they address each other and any terms you chose.\n\n")
+	}
+	return b.String()
+}
+
+// TranslateSubtitles translates subtitles, deduplicating repeated lines
+func (s *LLMTranslationService) TranslateSubtitles(
+	subs models.SubtitleList,
+	sourceLang, targetLang string,
+	opts TranslateOptions,
+	onProgress func(current, total int),
+) (models.SubtitleList, error) {
+	return s.translateSubtitles(subs, sourceLang, targetLang, false, opts, onProgress)
+}
+
+// TranslateSubtitlesWithEmotions translates subtitles and tags each with an
+// emotion for Fish Audio TTS ((happy), (sad), ...) so the dub sounds expressive
+func (s *LLMTranslationService) TranslateSubtitlesWithEmotions(
+	subs models.SubtitleList,
+	sourceLang, targetLang string,
+	opts TranslateOptions,
+	onProgress func(current, total int),
+) (models.SubtitleList, error) {
+	return s.translateSubtitles(subs, sourceLang, targetLang, true, opts, onProgress)
+}
+
+// Translate translates a single text, without any job's options
+func (s *LLMTranslationService) Translate(text, sourceLang, targetLang string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	if err := s.CheckAPIKey(); err != nil {
+		return "", err
+	}
+
+	results, _, err := s.translateBatch([]string{text}, batchContext{}, sourceLang, targetLang, false, TranslateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return results[0].text, nil
+}
+
+func (s *LLMTranslationService) translateSubtitles(
+	subs models.SubtitleList,
+	sourceLang, targetLang string,
+	withEmotions bool,
+	opts TranslateOptions,
+	onProgress func(current, total int),
+) (models.SubtitleList, error) {
+	if err := s.CheckAPIKey(); err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return subs, nil
+	}
+
+	total := len(subs)
+	translatedSubs := make(models.SubtitleList, total)
+	for i, sub := range subs {
+		translatedSubs[i] = models.Subtitle{
+			Index:      sub.Index,
+			StartTime:  sub.StartTime,
+			EndTime:    sub.EndTime,
+			Text:       sub.Text,
+			CharBudget: sub.CharBudget,
+		}
+		if withEmotions {
+			translatedSubs[i].Emotion = "calm"
+		}
+	}
+
+	// Deduplicate: repeated lines (choruses, "Yes.", "Thank you.") are
+	// translated once
+	uniqueIndex := make(map[string]int)
+	var uniqueTexts []string
+	var doc translationDocument
+	subToUnique := make([]int, total)
+	for i, sub := range subs {
+		t := strings.TrimSpace(sub.Text)
+		if t == "" {
+			subToUnique[i] = -1
+			continue
+		}
+		idx, ok := uniqueIndex[t]
+		if !ok {
+			idx = len(uniqueTexts)
+			uniqueIndex[t] = idx
+			uniqueTexts = append(uniqueTexts, t)
+			doc.position = append(doc.position, len(doc.lines))
+			doc.budgets = append(doc.budgets, sub.CharBudget)
+		} else if b := sub.CharBudget; b > 0 && (doc.budgets[idx] == 0 || b < doc.budgets[idx]) {
+			// A repeated line must fit its tightest window
+			doc.budgets[idx] = b
+		}
+		doc.lines = append(doc.lines, t)
+		subToUnique[i] = idx
+	}
+
+	uniqueCount := len(uniqueTexts)
+	logger.LogInfo("%s Translation: model=%s %d subtitles → %d unique (%s → %s), emotions=%v, %d workers",
+		s.cfg.Name, s.cfg.Model, total, uniqueCount, sourceLang, targetLang, withEmotions, s.cfg.Concurrency)
+	if uniqueCount == 0 {
+		return translatedSubs, nil
+	}
+
+	// Lines translated in earlier jobs are served from memory; only the
+	// rest go to the API, keeping their place in the document for context
+	scope := opts.memoryScopeFor(withEmotions)
+	translations := make([]llmTranslation, uniqueCount)
+	var pending []int
+	for i, t := range uniqueTexts {
+		e, ok := opts.Memory.Lookup(t, sourceLang, targetLang, scope)
+		if ok && (!withEmotions || e.Emotion != "") && len(opts.Glossary.Verify(t, e.Target)) == 0 {
+			translations[i] = llmTranslation{text: e.Target, emotion: normalizeEmotion(e.Emotion)}
+			continue
+		}
+		pending = append(pending, i)
+	}
+	hits := uniqueCount - len(pending)
+	if hits > 0 {
+		logger.LogInfo("%s Translation: %d/%d unique lines served from translation memory", s.cfg.Name, hits, uniqueCount)
+	}
+
+	if len(pending) > 0 {
+		pendingTexts := make([]string, len(pending))
+		pendingDoc := translationDocument{lines: doc.lines}
+		for j, i := range pending {
+			pendingTexts[j] = uniqueTexts[i]
+			pendingDoc.position = append(pendingDoc.position, doc.position[i])
+			pendingDoc.budgets = append(pendingDoc.budgets, doc.budgets[i])
+		}
+		if s.cfg.DocumentBrief {
+			pendingDoc.brief = s.documentBrief(doc.lines, sourceLang, opts)
+		}
+
+		results, err := s.translateUnique(pendingTexts, &pendingDoc, sourceLang, targetLang, withEmotions, opts, func(done int) {
+			if onProgress != nil {
+				onProgress((hits+done)*total/uniqueCount, total)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range pending {
+			translations[i] = results[j]
+			// Untranslated fallbacks and glossary breaches are not worth reusing
+			if results[j].text != pendingTexts[j] && len(opts.Glossary.Verify(pendingTexts[j], results[j].text)) == 0 {
+				opts.Memory.Add(pendingTexts[j], results[j].text, sourceLang, targetLang, scope, results[j].emotion)
+			}
+		}
+	}
+
+	for i, idx := range subToUnique {
+		if idx < 0 {
+			continue
+		}
+		translatedSubs[i].Text = translations[idx].text
+		if withEmotions {
+			translatedSubs[i].Emotion = translations[idx].emotion
+		}
+	}
+
+	return translatedSubs, nil
+}
+
+// translateUnique translates texts in parallel batches, preserving order.
+// With RunningSummary the batches run one at a time so each can pass its
+// summary on to the next.
+func (s *LLMTranslationService) translateUnique(
+	texts []string,
+	doc *translationDocument,
+	sourceLang, targetLang string,
+	withEmotions bool,
+	opts TranslateOptions,
+	onDone func(done int),
+) ([]llmTranslation, error) {
+	type batch struct{ start, end int }
+	type batchResult struct {
+		batch
+		translated []llmTranslation
+		err        error
+	}
+
+	var batches []batch
+	for i := 0; i < len(texts); i += s.cfg.BatchSize {
+		batches = append(batches, batch{i, min(i+s.cfg.BatchSize, len(texts))})
+	}
+
+	jobs := make(chan batch, len(batches))
+	results := make(chan batchResult, len(batches))
+
+	workers := min(s.cfg.Concurrency, len(batches))
+	if s.cfg.RunningSummary {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var summary string
+			for b := range jobs {
+				ctx := doc.contextFor(b.start, b.end, s.cfg.ContextLines)
+				ctx.glossary = opts.Glossary.Relevant(texts[b.start:b.end])
+				ctx.suggestions = opts.Memory.Suggestions(texts[b.start:b.end], sourceLang, targetLang, opts.memoryScopeFor(withEmotions), translationMemorySuggestions)
+				ctx.summary = summary
+				ctx.updateSummary = s.cfg.RunningSummary
+
+				translated, next, err := s.translateBatch(texts[b.start:b.end], ctx, sourceLang, targetLang, withEmotions, opts)
+				if next != "" {
+					summary = next
+				}
+				results <- batchResult{batch: b, translated: translated, err: err}
+			}
+		}()
+	}
+
+	for _, b := range batches {
+		jobs <- b
+	}
+	close(jobs)
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	out := make([]llmTranslation, len(texts))
+	done := 0
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s translation batch at %d failed: %w", s.cfg.Name, r.start, r.err)
+			}
+			continue
+		}
+		copy(out[r.start:r.end], r.translated)
+		done += r.end - r.start
+		onDone(done)
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// llmSegment is one entry of the JSON batch protocol. Requests carry id and
+// text; replies add emotion when emotion tagging was asked for.
+type llmSegment struct {
+	ID       json.Number `json:"id"`
+	Source   string      `json:"source,omitempty"` // Original line, when shortening a translation
+	Text     string      `json:"text"`
+	MaxChars int         `json:"max_chars,omitempty"`
+	Fix      string      `json:"fix,omitempty"` // Why a re-requested translation was rejected
+	Emotion  string      `json:"emotion,omitempty"`
+}
+
+// translateBatch translates one batch. Every text is sent with a 1-based ID;
+// IDs missing from the reply or returned malformed are re-requested on their
+// own for up to TranslationRepairRounds more rounds, after which they keep
+// their original text. Translations that break the glossary are re-requested
+// with the violation spelled out; if they never comply the last attempt is
+// kept, for the review list to flag. Returns the updated summary when ctx
+// asked for one.
+func (s *LLMTranslationService) translateBatch(texts []string, ctx batchContext, sourceLang, targetLang string, withEmotions bool, opts TranslateOptions) ([]llmTranslation, string, error) {
+	translations := make([]llmTranslation, len(texts))
+	var summary string
+	fixes := make(map[int]string) // Glossary violations of the last attempt
+	pending := make([]int, len(texts))
+	for i := range texts {
+		pending[i] = i
+	}
+
+	for round := 0; round <= config.TranslationRepairRounds && len(pending) > 0; round++ {
+		if round > 0 {
+			logger.LogInfo("%s Translation: re-requesting %d of %d segments (round %d)",
+				s.cfg.Name, len(pending), len(texts), round)
+		}
+
+		segments := make([]llmSegment, len(pending))
+		for j, i := range pending {
+			segments[j] = llmSegment{ID: json.Number(strconv.Itoa(i + 1)), Text: texts[i], Fix: fixes[i]}
+			if i < len(ctx.budgets) {
+				segments[j].MaxChars = ctx.budgets[i]
+			}
+		}
+
+		prompt, err := s.buildTranslationPrompt(segments, ctx, sourceLang, targetLang, withEmotions, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		content, err := internalhttp.Retry(func() (string, error) {
+			return s.chat(prompt, true, opts.Usage)
+		}, config.DefaultMaxRetries, config.DefaultRetryDelayBase)
+		if err != nil {
+			return nil, "", err
+		}
+
+		got, replySummary, err := parseTranslationReply(content, len(texts), withEmotions)
+		if replySummary != "" {
+			summary = replySummary
+		}
+		if err != nil {
+			logger.LogError("%s Translation: unusable reply: %v", s.cfg.Name, err)
+		}
+
+		var missing []int
+		for _, i := range pending {
+			t, ok := got[i+1]
+			if !ok {
+				missing = append(missing, i)
+				continue
+			}
+			translations[i] = t
+			delete(fixes, i)
+			if violations := ctx.glossary.Verify(texts[i], t.text); len(violations) > 0 {
+				fixes[i] = describeViolations(violations)
+				missing = append(missing, i)
+			}
+		}
+		pending = missing
+	}
+
+	for _, i := range pending {
+		if fix, ok := fixes[i]; ok {
+			logger.LogError("%s Translation: segment %d still breaks the glossary (%s), keeping it for review",
+				s.cfg.Name, i+1, fix)
+			continue
+		}
+		logger.LogError("%s Translation: no valid translation for segment %d after %d attempts, keeping original",
+			s.cfg.Name, i+1, config.TranslationRepairRounds+1)
+		translations[i] = llmTranslation{text: texts[i]}
+		if withEmotions {
+			translations[i].emotion = "calm"
+		}
+	}
+
+	return translations, summary, nil
+}
+
+// describeViolations turns glossary violations into a correction note
+func describeViolations(violations []GlossaryViolation) string {
+	notes := make([]string, len(violations))
+	for i, v := range violations {
+		notes[i] = v.String()
+	}
+	return strings.Join(notes, "; ")
+}
+
+// buildTranslationPrompt renders the prompt for a batch of segments
+func (s *LLMTranslationService) buildTranslationPrompt(segments []llmSegment, ctx batchContext, sourceLang, targetLang string, withEmotions bool, opts TranslateOptions) (string, error) {
+	input, _ := json.MarshalIndent(segments, "", "  ")
+	name := PromptTranslate
+	if withEmotions {
+		name = PromptTranslateEmotions
+	}
+	return opts.Prompts.Render(name, opts.promptData(sourceLang, targetLang, ctx.render(), string(input)))
+}
+
+// promptData fills in the languages and the profile's style for a prompt
+func (o TranslateOptions) promptData(sourceLang, targetLang, context, input string) PromptData {
+	data := PromptData{
+		Profile: o.Profile,
+		Context: context,
+		Input:   input,
+	}
+	if sourceLang != "" {
+		data.SourceLanguage = text.GetLanguageName(sourceLang)
+	}
+	if targetLang != "" {
+		data.TargetLanguage = targetLanguageName(targetLang, o.Profile)
+		data.Style = renderStyle(o.Profile, targetLang)
+	}
+	return data
+}
+
+// ShortenSubtitles paraphrases translations longer than their CharBudget.
+// source and translated are paired by start time so the model can check the
+// paraphrase against the original. A shorter version replaces the current one
+// even if it still doesn't fit; lines still over budget after the repair
+// rounds keep the shortest version seen. Subtitles without a budget are left
+// alone. Returns the number of lines that were shortened.
+func (s *LLMTranslationService) ShortenSubtitles(source, translated models.SubtitleList, targetLang string, opts TranslateOptions) (int, error) {
+	if err := s.CheckAPIKey(); err != nil {
+		return 0, err
+	}
+
+	sourceByStart := make(map[time.Duration]string, len(source))
+	for _, sub := range source {
+		sourceByStart[sub.StartTime] = sub.Text
+	}
+
+	var over []int
+	for i, sub := range translated {
+		if sub.CharBudget > 0 && utf8.RuneCountInString(strings.TrimSpace(sub.Text)) > sub.CharBudget {
+			over = append(over, i)
+		}
+	}
+	if len(over) == 0 {
+		return 0, nil
+	}
+	logger.LogInfo("%s Translation: shortening %d lines that are too long to speak in time", s.cfg.Name, len(over))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	shortened := 0
+	sem := make(chan struct{}, s.cfg.Concurrency)
+
+	for start := 0; start < len(over); start += s.cfg.BatchSize {
+		batch := over[start:min(start+s.cfg.BatchSize, len(over))]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			n, err := s.shortenBatch(batch, sourceByStart, translated, targetLang, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			shortened += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	return shortened, firstErr
+}
+
+// shortenBatch runs the repair rounds for one batch of over-budget lines,
+// updating translated in place. Batches touch disjoint indices.
+func (s *LLMTranslationService) shortenBatch(batch []int, sourceByStart map[time.Duration]string, translated models.SubtitleList, targetLang string, opts TranslateOptions) (int, error) {
+	shortened := make(map[int]bool)
+	pending := batch
+	for round := 0; round <= config.TranslationRepairRounds && len(pending) > 0; round++ {
+		segments := make([]llmSegment, len(pending))
+		for j, i := range pending {
+			sub := translated[i]
+			segments[j] = llmSegment{
+				ID:       json.Number(strconv.Itoa(j + 1)),
+				Source:   sourceByStart[sub.StartTime],
+				Text:     strings.TrimSpace(sub.Text),
+				MaxChars: sub.CharBudget,
+			}
+		}
+
+		var sources []string
+		for _, seg := range segments {
+			sources = append(sources, seg.Source)
+		}
+		input, _ := json.MarshalIndent(segments, "", "  ")
+		glossary := opts.Glossary.Relevant(sources).PromptSection()
+		prompt, err := opts.Prompts.Render(PromptShorten, opts.promptData("", targetLang, glossary, string(input)))
+		if err != nil {
+			return len(shortened), err
+		}
+		content, err := internalhttp.Retry(func() (string, error) {
+			return s.chat(prompt, true, opts.Usage)
+		}, config.DefaultMaxRetries, config.DefaultRetryDelayBase)
+		if err != nil {
+			return len(shortened), fmt.Errorf("%s shortening failed: %w", s.cfg.Name, err)
+		}
+
+		got, _, err := parseTranslationReply(content, len(pending), false)
+		if err != nil {
+			logger.LogError("%s Translation: unusable shortening reply: %v", s.cfg.Name, err)
+		}
+
+		var still []int
+		for j, i := range pending {
+			current := utf8.RuneCountInString(strings.TrimSpace(translated[i].Text))
+			t, ok := got[j+1]
+			if ok && len(opts.Glossary.Verify(segments[j].Source, t.text)) > len(opts.Glossary.Verify(segments[j].Source, translated[i].Text)) {
+				// Shorter but no longer honours the glossary
+				ok = false
+			}
+			if ok && utf8.RuneCountInString(t.text) < current {
+				translated[i].Text = t.text
+				shortened[i] = true
+				current = utf8.RuneCountInString(t.text)
+			}
+			if current > translated[i].CharBudget {
+				still = append(still, i)
+			}
+		}
+		pending = still
+	}
+	return len(shortened), nil
+}
+
+// documentBrief asks the model once for a short description of the whole
+// transcript. Failures are logged and translation continues without it.
+func (s *LLMTranslationService) documentBrief(lines []string, sourceLang string, opts TranslateOptions) string {
+	transcript := []rune(strings.Join(lines, "\n"))
+	if len(transcript) > documentBriefMaxRunes {
+		transcript = transcript[:documentBriefMaxRunes]
+	}
+
+	prompt, err := opts.Prompts.Render(PromptDocumentBrief, opts.promptData(sourceLang, "", "", string(transcript)))
+	if err != nil {
+		logger.LogError("%s Translation: document brief skipped: %v", s.cfg.Name, err)
+		return ""
+	}
+	brief, err := internalhttp.Retry(func() (string, error) {
+		return s.chat(prompt, false, opts.Usage)
+	}, config.DefaultMaxRetries, config.DefaultRetryDelayBase)
+	if err != nil {
+		logger.LogError("%s Translation: document brief failed, continuing without it: %v", s.cfg.Name, err)
+		return ""
+	}
+
+	brief = strings.TrimSpace(brief)
+	logger.LogInfo("%s Translation: document brief: %s", s.cfg.Name, brief)
+	return brief
+}
+
+// parseTranslationReply extracts the translated segments from a model reply,
+// keyed by ID, and the running summary if the reply has one. The reply may be
+// {"translations": [...]} (JSON mode) or a bare array, optionally inside a
+// code fence. Entries with an ID outside 1..count, a duplicated ID or empty
+// text are dropped so the caller re-requests them.
+func parseTranslationReply(content string, count int, withEmotions bool) (map[int]llmTranslation, string, error) {
+	content = strings.TrimSpace(content)
+	if start := strings.IndexAny(content, "[{"); start > 0 {
+		content = content[start:]
+	}
+	if end := strings.LastIndexAny(content, "]}"); end >= 0 {
+		content = content[:end+1]
+	}
+
+	var segments []llmSegment
+	var summary string
+	if strings.HasPrefix(content, "{") {
+		var wrapped struct {
+			Translations []llmSegment `json:"translations"`
+			Summary      string       `json:"summary"`
+		}
+		if err := json.Unmarshal([]byte(content), &wrapped); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON: %w", err)
+		}
+		segments = wrapped.Translations
+		summary = strings.TrimSpace(wrapped.Summary)
+	} else if err := json.Unmarshal([]byte(content), &segments); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	result := make(map[int]llmTranslation, len(segments))
+	seen := make(map[int]bool, len(segments))
+	for _, seg := range segments {
+		id64, err := seg.ID.Int64()
+		id := int(id64)
+		if err != nil || id < 1 || id > count {
+			logger.LogDebug("LLM translation: ignoring segment with unexpected id %q", seg.ID)
+			continue
+		}
+		if seen[id] {
+			// Two answers for one ID: trust neither
+			delete(result, id)
+			continue
+		}
+		seen[id] = true
+
+		t := strings.TrimSpace(seg.Text)
+		if t == "" {
+			continue
+		}
+		tr := llmTranslation{text: text.Postprocess(t)}
+		if withEmotions {
+			tr.emotion = normalizeEmotion(seg.Emotion)
+		}
+		result[id] = tr
+	}
+
+	if len(segments) == 0 {
+		return result, summary, fmt.Errorf("reply contains no segments")
+	}
+	return result, summary, nil
+}
+
+// chat sends a single-message chat completion and returns the reply text.
+// jsonReply requests JSON mode on providers that support it; the tokens
+// used are recorded on usage.
+func (s *LLMTranslationService) chat(prompt string, jsonReply bool, usage *UsageMeter) (string, error) {
+	reqBody := map[string]interface{}{
+		"model": s.cfg.Model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"temperature": s.cfg.Temperature,
+		"max_tokens":  s.cfg.MaxTokens,
+	}
+	if jsonReply && s.cfg.JSONMode {
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(s.cfg.BaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doRateLimited(rateLimiterFor(UsageStageTranslation, s.cfg.Provider), s.client, req, promptRateCost(prompt))
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			return "", fmt.Errorf("%s API error: %s", s.cfg.Name, errResp.Error.Message)
+		}
+		return "", fmt.Errorf("%s API error (status %d): %s", s.cfg.Name, resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	usage.Record(UsageRecord{
+		Stage:        UsageStageTranslation,
+		Provider:     s.cfg.Provider,
+		Model:        s.cfg.Model,
+		InputTokens:  result.Usage.PromptTokens,
+		OutputTokens: result.Usage.CompletionTokens,
+	})
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no response from %s", s.cfg.Name)
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
+
+// validEmotions are the emotion tags the model may choose from; every TTS
+// provider that voices emotions maps each of them (see emotion_prosody.go)
+var validEmotions = map[string]bool{
+	"happy": true, "sad": true, "excited": true, "calm": true,
+	"angry": true, "surprised": true, "nervous": true, "confident": true,
+	"curious": true, "empathetic": true, "worried": true, "frustrated": true,
+}
+
+// normalizeEmotion cleans up a model-supplied emotion ("(Happy)" → "happy"),
+// falling back to calm for anything not in validEmotions
+func normalizeEmotion(emotion string) string {
+	emotion = strings.TrimSpace(emotion)
+	emotion = strings.TrimPrefix(emotion, "(")
+	emotion = strings.TrimSuffix(emotion, ")")
+	emotion = strings.ToLower(emotion)
+	if validEmotions[emotion] {
+		return emotion
+	}
+	return "calm"
+}
+
+// translationMemorySuggestions caps the similar earlier translations shown
+// per batch; a few examples set the style without crowding the prompt
+const translationMemorySuggestions = 5
+
+// documentBriefMaxRunes caps the transcript sent for the document brief
+// (~6k tokens); the opening is enough to establish topic and speakers
+const documentBriefMaxRunes = 20000
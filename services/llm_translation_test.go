@@ -0,0 +1,559 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+// fakeChatServer answers chat completions by upper-casing every segment in
+// the prompt, tagging each with an emotion when the prompt asks for one.
+// reply, when set, can rewrite the outgoing segments per request.
+func fakeChatServer(t *testing.T, requests *int32, check func(r *http.Request, body map[string]interface{}), reply func(call int32, segs []llmSegment) []llmSegment) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := atomic.AddInt32(requests, 1)
+		if r.URL.Path != "/v1/chat/completions" {
+			http.Error(w, `{"error":{"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		if check != nil {
+			check(r, body)
+		}
+
+		prompt := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+		if strings.Contains(prompt, "TRANSCRIPT:") {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{
+					{"message": map[string]string{"content": "A cooking show with two hosts."}},
+				},
+			})
+			return
+		}
+
+		var segs []llmSegment
+		if err := json.Unmarshal([]byte(prompt[strings.Index(prompt, "INPUT:\n")+len("INPUT:\n"):]), &segs); err != nil {
+			t.Errorf("prompt input is not a JSON array: %v", err)
+		}
+		for i := range segs {
+			segs[i].Text = strings.ToUpper(segs[i].Text)
+			if strings.Contains(prompt, `"emotion"`) {
+				segs[i].Emotion = "Happy"
+			}
+		}
+		if reply != nil {
+			segs = reply(call, segs)
+		}
+
+		content, _ := json.Marshal(map[string]interface{}{
+			"translations": segs,
+			"summary":      fmt.Sprintf("summary after call %d", call),
+		})
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": string(content)}},
+			},
+			"usage": map[string]int{"prompt_tokens": 100, "completion_tokens": 20},
+		})
+	}))
+}
+
+func testLLMConfig(baseURL string) LLMTranslationConfig {
+	return LLMTranslationConfig{
+		Name:        "Test",
+		BaseURL:     baseURL + "/v1",
+		Model:       "test-model",
+		MaxTokens:   1234,
+		Temperature: 0.5,
+		Concurrency: 3,
+		BatchSize:   2,
+		Timeout:     5 * time.Second,
+	}
+}
+
+func TestLLMTranslationService_TranslateSubtitles(t *testing.T) {
+	var requests int32
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		if body["model"] != "test-model" {
+			t.Errorf("model = %v, want test-model", body["model"])
+		}
+		if body["max_tokens"] != float64(1234) || body["temperature"] != 0.5 {
+			t.Errorf("max_tokens/temperature = %v/%v", body["max_tokens"], body["temperature"])
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("no API key configured, got Authorization %q", auth)
+		}
+		if _, ok := body["response_format"]; ok {
+			t.Error("response_format sent without JSONMode")
+		}
+	}, nil)
+	defer server.Close()
+
+	subs := models.SubtitleList{
+		{Index: 1, StartTime: 0, EndTime: time.Second, Text: "one"},
+		{Index: 2, StartTime: time.Second, EndTime: 2 * time.Second, Text: "two"},
+		{Index: 3, StartTime: 2 * time.Second, EndTime: 3 * time.Second, Text: "one"}, // duplicate
+		{Index: 4, StartTime: 3 * time.Second, EndTime: 4 * time.Second, Text: "  "},
+		{Index: 5, StartTime: 4 * time.Second, EndTime: 5 * time.Second, Text: "three"},
+	}
+
+	var lastProgress int
+	s := NewLLMTranslationService(testLLMConfig(server.URL))
+	got, err := s.TranslateSubtitles(subs, "en", "de", TranslateOptions{}, func(current, total int) { lastProgress = current })
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+
+	want := []string{"ONE", "TWO", "ONE", "  ", "THREE"}
+	for i, sub := range got {
+		if sub.Text != want[i] {
+			t.Errorf("sub %d = %q, want %q", i, sub.Text, want[i])
+		}
+		if sub.StartTime != subs[i].StartTime || sub.Index != subs[i].Index {
+			t.Errorf("sub %d timing/index not preserved", i)
+		}
+	}
+
+	// 3 unique texts in batches of 2
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if lastProgress != len(subs) {
+		t.Errorf("final progress = %d, want %d", lastProgress, len(subs))
+	}
+}
+
+func TestLLMTranslationService_Emotions(t *testing.T) {
+	var requests int32
+	server := fakeChatServer(t, &requests, nil, nil)
+	defer server.Close()
+
+	subs := models.SubtitleList{{Index: 1, Text: "great news"}}
+	got, err := NewLLMTranslationService(testLLMConfig(server.URL)).TranslateSubtitlesWithEmotions(subs, "en", "de", TranslateOptions{}, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitlesWithEmotions: %v", err)
+	}
+	if got[0].Text != "GREAT NEWS" || got[0].Emotion != "happy" {
+		t.Errorf("got %q (%s), want GREAT NEWS (happy)", got[0].Text, got[0].Emotion)
+	}
+}
+
+func TestLLMTranslationService_RerequestsMissingIDs(t *testing.T) {
+	var requests int32
+	var secondCall []string
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		format, _ := body["response_format"].(map[string]interface{})
+		if format["type"] != "json_object" {
+			t.Errorf("response_format = %v, want json_object", body["response_format"])
+		}
+	}, func(call int32, segs []llmSegment) []llmSegment {
+		if call == 1 {
+			// Drop id 2, blank id 3 and answer an id that was never asked for
+			return []llmSegment{segs[0], {ID: "3", Text: " "}, segs[3], {ID: "9", Text: "STRAY"}}
+		}
+		for _, seg := range segs {
+			secondCall = append(secondCall, seg.ID.String())
+		}
+		return segs
+	})
+	defer server.Close()
+
+	cfg := testLLMConfig(server.URL)
+	cfg.BatchSize = 10
+	cfg.JSONMode = true
+	subs := models.SubtitleList{{Text: "a"}, {Text: "b"}, {Text: "c"}, {Text: "d"}}
+	got, err := NewLLMTranslationService(cfg).TranslateSubtitles(subs, "en", "de", TranslateOptions{}, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+
+	for i, want := range []string{"A", "B", "C", "D"} {
+		if got[i].Text != want {
+			t.Errorf("sub %d = %q, want %q", i, got[i].Text, want)
+		}
+	}
+	if strings.Join(secondCall, ",") != "2,3" {
+		t.Errorf("re-request ids = %v, want [2 3]", secondCall)
+	}
+}
+
+func TestLLMTranslationService_KeepsOriginalAfterRepairRounds(t *testing.T) {
+	var requests int32
+	server := fakeChatServer(t, &requests, nil, func(call int32, segs []llmSegment) []llmSegment {
+		var out []llmSegment
+		for _, seg := range segs {
+			if seg.ID != "2" {
+				out = append(out, seg)
+			}
+		}
+		return out
+	})
+	defer server.Close()
+
+	subs := models.SubtitleList{{Text: "a"}, {Text: "b"}}
+	cfg := testLLMConfig(server.URL)
+	got, err := NewLLMTranslationService(cfg).TranslateSubtitles(subs, "en", "de", TranslateOptions{}, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if got[0].Text != "A" || got[1].Text != "b" {
+		t.Errorf("got %q, %q; want A, b (original kept)", got[0].Text, got[1].Text)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 1 + 2 repair rounds", requests)
+	}
+}
+
+func TestParseTranslationReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[int]string
+		wantErr bool
+	}{
+		{"wrapped", `{"translations":[{"id":1,"text":"Hallo"},{"id":2,"text":"Welt"}]}`, map[int]string{1: "Hallo", 2: "Welt"}, false},
+		{"bare array in fence", "```json\n[{\"id\":\"2\",\"text\":\"Welt\"}]\n```", map[int]string{2: "Welt"}, false},
+		{"duplicate id", `[{"id":1,"text":"A"},{"id":1,"text":"B"},{"id":2,"text":"C"}]`, map[int]string{2: "C"}, false},
+		{"out of range", `[{"id":0,"text":"A"},{"id":3,"text":"B"}]`, map[int]string{}, false},
+		{"not json", `Hallo |||SUBTITLE||| Welt`, nil, true},
+		{"empty", `{"translations":[]}`, map[int]string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := parseTranslationReply(tt.content, 2, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for id, text := range tt.want {
+				if got[id].text != text {
+					t.Errorf("id %d = %q, want %q", id, got[id].text, text)
+				}
+			}
+		})
+	}
+}
+
+func TestLLMTranslationService_Context(t *testing.T) {
+	var requests int32
+	var prompts []string
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		prompt := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+		prompts = append(prompts, prompt)
+		if strings.Contains(prompt, "TRANSCRIPT:") {
+			if _, ok := body["response_format"]; ok {
+				t.Error("document brief must not request JSON mode")
+			}
+		}
+	}, nil)
+	defer server.Close()
+
+	cfg := testLLMConfig(server.URL)
+	cfg.JSONMode = true
+	cfg.ContextLines = 1
+	cfg.RunningSummary = true
+	cfg.DocumentBrief = true
+	subs := models.SubtitleList{{Text: "one"}, {Text: "two"}, {Text: "one"}, {Text: "three"}, {Text: "four"}}
+	if _, err := NewLLMTranslationService(cfg).TranslateSubtitles(subs, "en", "de", TranslateOptions{}, nil); err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+
+	// Brief, then batches [one two] and [three four] in order
+	if len(prompts) != 3 {
+		t.Fatalf("got %d requests, want 3", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "one\ntwo\none\nthree\nfour") {
+		t.Errorf("brief prompt lacks the transcript:\n%s", prompts[0])
+	}
+
+	first, second := prompts[1], prompts[2]
+	for _, p := range []string{first, second} {
+		if !strings.Contains(p, "ABOUT THIS VIDEO:\nA cooking show with two hosts.") {
+			t.Errorf("batch prompt lacks the brief:\n%s", p)
+		}
+		if !strings.Contains(p, `"summary" field`) {
+			t.Errorf("batch prompt does not ask for a summary:\n%s", p)
+		}
+	}
+	if !strings.Contains(first, "FOLLOWING LINES (context only, do not translate):\none\n") || strings.Contains(first, "PRECEDING LINES") {
+		t.Errorf("first batch context wrong:\n%s", first)
+	}
+	if !strings.Contains(second, "PRECEDING LINES (context only, do not translate):\none\n") || strings.Contains(second, "FOLLOWING LINES") {
+		t.Errorf("second batch context wrong:\n%s", second)
+	}
+	if !strings.Contains(second, "STORY SO FAR:\nsummary after call 2") {
+		t.Errorf("second batch lacks the first batch's summary:\n%s", second)
+	}
+}
+
+func TestLLMTranslationService_SendsBudgets(t *testing.T) {
+	var requests int32
+	var maxChars []int
+	server := fakeChatServer(t, &requests, nil, func(call int32, segs []llmSegment) []llmSegment {
+		for _, seg := range segs {
+			maxChars = append(maxChars, seg.MaxChars)
+		}
+		return segs
+	})
+	defer server.Close()
+
+	subs := models.SubtitleList{
+		{Text: "again", CharBudget: 30},
+		{Text: "other"},
+		{Text: "again", CharBudget: 12}, // repeat with a tighter window
+	}
+	got, err := NewLLMTranslationService(testLLMConfig(server.URL)).TranslateSubtitles(subs, "en", "de", TranslateOptions{}, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if len(maxChars) != 2 || maxChars[0] != 12 || maxChars[1] != 0 {
+		t.Errorf("max_chars sent = %v, want [12 0]", maxChars)
+	}
+	if got[0].CharBudget != 30 || got[2].CharBudget != 12 {
+		t.Error("translated subtitles should keep their budgets")
+	}
+}
+
+func TestLLMTranslationService_ShortenSubtitles(t *testing.T) {
+	var requests int32
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		prompt := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+		if !strings.Contains(prompt, `"source": "Das ist ein sehr langer Satz"`) {
+			t.Errorf("shortening prompt lacks the source line:\n%s", prompt)
+		}
+	}, func(call int32, segs []llmSegment) []llmSegment {
+		// First round is still too long, second fits
+		if call == 1 {
+			return []llmSegment{{ID: "1", Text: "THIS IS STILL LONG"}}
+		}
+		return []llmSegment{{ID: "1", Text: "SHORT"}}
+	})
+	defer server.Close()
+
+	source := models.SubtitleList{
+		{StartTime: 0, Text: "Das ist ein sehr langer Satz"},
+		{StartTime: time.Second, Text: "Kurz"},
+	}
+	translated := models.SubtitleList{
+		{StartTime: 0, Text: "This is a very long sentence indeed", CharBudget: 10},
+		{StartTime: time.Second, Text: "Short", CharBudget: 10},
+	}
+
+	n, err := NewLLMTranslationService(testLLMConfig(server.URL)).ShortenSubtitles(source, translated, "en", TranslateOptions{})
+	if err != nil {
+		t.Fatalf("ShortenSubtitles: %v", err)
+	}
+	if n != 1 || translated[0].Text != "SHORT" || translated[1].Text != "Short" {
+		t.Errorf("shortened %d: %q, %q", n, translated[0].Text, translated[1].Text)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestLLMTranslationService_Glossary(t *testing.T) {
+	var requests int32
+	var fixes []string
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		prompt := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+		if !strings.Contains(prompt, "- acme → ACME CLOUD") {
+			t.Errorf("prompt lacks the glossary:\n%s", prompt)
+		}
+	}, func(call int32, segs []llmSegment) []llmSegment {
+		for _, seg := range segs {
+			fixes = append(fixes, seg.Fix)
+		}
+		segs[0].Text = "ACME CLOUD"
+		if call == 1 {
+			segs[0].Text = "AKME"
+		}
+		return segs
+	})
+	defer server.Close()
+
+	s := NewLLMTranslationService(testLLMConfig(server.URL))
+	opts := TranslateOptions{Glossary: NewGlossary("test", []GlossaryTerm{{"acme", "ACME CLOUD"}}, nil)}
+
+	got, err := s.TranslateSubtitles(models.SubtitleList{{Text: "acme"}}, "en", "de", opts, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if got[0].Text != "ACME CLOUD" {
+		t.Errorf("got %q, want the corrected translation", got[0].Text)
+	}
+	if requests != 2 {
+		t.Fatalf("requests = %d, want a re-request after the violation", requests)
+	}
+	if fixes[0] != "" || !strings.Contains(fixes[1], `"acme" must be translated as "ACME CLOUD"`) {
+		t.Errorf("fixes = %q", fixes)
+	}
+}
+
+func TestLLMTranslationService_Memory(t *testing.T) {
+	var requests int32
+	var sent, prompts []string
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		prompts = append(prompts, body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string))
+	}, func(call int32, segs []llmSegment) []llmSegment {
+		for _, seg := range segs {
+			sent = append(sent, strings.ToLower(seg.Text))
+		}
+		return segs
+	})
+	defer server.Close()
+
+	tm, err := OpenTranslationMemory(t.TempDir() + "/tm.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.Add("Welcome to the show!", "Willkommen zur Show!", "en", "de", "test", "")
+	tm.Add("welcome to the show, friends", "Willkommen zur Show, Freunde", "en", "de", "", "")
+
+	s := NewLLMTranslationService(testLLMConfig(server.URL))
+	opts := TranslateOptions{Memory: tm, MemoryScope: "test"}
+
+	subs := models.SubtitleList{
+		{StartTime: 0, Text: "welcome  to the SHOW!"},
+		{StartTime: time.Second, Text: "welcome to the show, friend"},
+	}
+	got, err := s.TranslateSubtitles(subs, "en", "de", opts, nil)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if got[0].Text != "Willkommen zur Show!" {
+		t.Errorf("exact match not served from memory: %q", got[0].Text)
+	}
+	if requests != 1 || len(sent) != 1 || sent[0] != "welcome to the show, friend" {
+		t.Errorf("requests = %d, sent %q; want only the unseen line", requests, sent)
+	}
+	if !strings.Contains(prompts[0], "TRANSLATION MEMORY") || !strings.Contains(prompts[0], "- welcome to the show, friends → Willkommen zur Show, Freunde") {
+		t.Errorf("prompt lacks the fuzzy suggestion:\n%s", prompts[0])
+	}
+
+	// The new translation is remembered for the next job
+	if e, ok := tm.Lookup("Welcome to the show, friend", "en", "de", "test"); !ok || e.Target != "WELCOME TO THE SHOW, FRIEND" {
+		t.Errorf("Lookup = %+v, %v", e, ok)
+	}
+
+	// Emotion runs need entries that carry an emotion
+	requests = 0
+	if _, err := s.TranslateSubtitlesWithEmotions(subs[:1], "en", "de", opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, plain entry should not serve an emotion run", requests)
+	}
+}
+
+func TestLLMTranslationService_Profile(t *testing.T) {
+	var requests int32
+	var prompts []string
+	server := fakeChatServer(t, &requests, func(r *http.Request, body map[string]interface{}) {
+		prompts = append(prompts, body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string))
+	}, nil)
+	defer server.Close()
+
+	s := NewLLMTranslationService(testLLMConfig(server.URL))
+	opts := TranslateOptions{Profile: models.TranslationProfile{Name: "formal-br", Formality: models.FormalityFormal, Locale: "pt-BR"}}
+
+	if _, err := s.TranslateSubtitles(models.SubtitleList{{Text: "hello"}}, "en", "pt", opts, nil); err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if !strings.Contains(prompts[0], "from English to Brazilian Portuguese") || !strings.Contains(prompts[0], "polite form") {
+		t.Errorf("prompt lacks the profile:\n%s", prompts[0])
+	}
+
+	// User templates replace the built-in prompt
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, PromptTranslate+".tmpl"), []byte("CUSTOM {{.TargetLanguage}}\n{{.Style}}INPUT:\n{{.Input}}"), 0644)
+	custom, err := LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Prompts = custom
+	if _, err := s.TranslateSubtitles(models.SubtitleList{{Text: "bye"}}, "en", "pt", opts, nil); err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if !strings.HasPrefix(prompts[1], "CUSTOM Brazilian Portuguese\nSTYLE:") {
+		t.Errorf("custom prompt = %q", prompts[1])
+	}
+}
+
+func TestLLMTranslationService_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key"}}`)
+	}))
+	defer server.Close()
+
+	cfg := testLLMConfig(server.URL)
+	cfg.APIKey = "sk-test"
+	_, err := NewLLMTranslationService(cfg).Translate("hello", "en", "de")
+	if err == nil || !strings.Contains(err.Error(), "Test API error: invalid api key") {
+		t.Errorf("err = %v, want provider API error", err)
+	}
+}
+
+func TestLLMTranslationService_RequiresKey(t *testing.T) {
+	s := NewDeepSeekService("")
+	if err := s.CheckAPIKey(); err == nil {
+		t.Error("DeepSeek preset without key should fail CheckAPIKey")
+	}
+	if _, err := s.TranslateSubtitles(models.SubtitleList{{Text: "x"}}, "en", "de", TranslateOptions{}, nil); err == nil {
+		t.Error("TranslateSubtitles without key should fail")
+	}
+}
+
+func TestLLMTranslationConfigFor(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.DeepSeekKey = "sk-ds"
+	cfg.LLMBaseURL = "http://gpu-box:11434/v1"
+	cfg.LLMModel = "llama3.1:8b"
+	cfg.LLMConcurrency = 4
+
+	deepseek, err := LLMTranslationConfigFor(LLMProviderDeepSeek, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deepseek.APIKey != "sk-ds" || deepseek.BaseURL != "https://api.deepseek.com/v1" {
+		t.Errorf("deepseek preset = %+v (base URL override must not apply to cloud presets)", deepseek)
+	}
+	if deepseek.Concurrency != 4 {
+		t.Errorf("deepseek concurrency = %d, want override 4", deepseek.Concurrency)
+	}
+
+	ollama, err := LLMTranslationConfigFor(LLMProviderOllama, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ollama.BaseURL != "http://gpu-box:11434/v1" || ollama.Model != "llama3.1:8b" || ollama.RequireKey {
+		t.Errorf("ollama preset = %+v", ollama)
+	}
+
+	if _, err := LLMTranslationConfigFor("argos", cfg); err == nil {
+		t.Error("argos is not an LLM provider")
+	}
+	if IsLLMTranslationProvider("argos") || !IsLLMTranslationProvider(LLMProviderGrok) {
+		t.Error("IsLLMTranslationProvider mismatch")
+	}
+}
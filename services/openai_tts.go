@@ -9,13 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
+	"unicode/utf8"
 
 	"video-translator/internal/config"
 	internalhttp "video-translator/internal/http"
 	"video-translator/internal/logger"
-	"video-translator/internal/media"
-	"video-translator/internal/worker"
+	"video-translator/internal/tts"
 	"video-translator/models"
 )
 
@@ -26,18 +25,19 @@ var openaiTTSClient = internalhttp.OpenAIClient
 
 // OpenAI TTS models
 const (
-	OpenAITTSModelStandard = "tts-1"    // Faster, lower quality
-	OpenAITTSModelHD       = "tts-1-hd" // Slower, higher quality
+	OpenAITTSModelStandard = "tts-1"           // Faster, lower quality
+	OpenAITTSModelHD       = "tts-1-hd"        // Slower, higher quality
+	OpenAITTSModelMini     = "gpt-4o-mini-tts" // Steerable: takes tone instructions
 )
 
 // OpenAITTSService handles text-to-speech using OpenAI's API (high quality voices)
 type OpenAITTSService struct {
-	apiKey  string
-	model   string  // tts-1 or tts-1-hd
-	voice   string  // alloy, echo, fable, onyx, nova, shimmer
-	speed   float64 // 0.25 to 4.0, default 1.15 for dubbing
-	ffmpeg  *FFmpegService
-	tempDir string
+	*tts.BaseTTS
+
+	apiKey string
+	model  string  // tts-1, tts-1-hd or gpt-4o-mini-tts
+	voice  string  // alloy, echo, fable, onyx, nova, shimmer
+	speed  float64 // 0.25 to 4.0, default 1.15 for dubbing
 }
 
 // OpenAI TTS voices with descriptions
@@ -50,6 +50,12 @@ var OpenAIVoices = map[string]string{
 	"shimmer": "Shimmer (Female, soft)",
 }
 
+var _ tts.Service = (*OpenAITTSService)(nil)
+var _ tts.RateSynthesizer = (*OpenAITTSService)(nil)
+
+// openAITTSMaxSpeed is the fastest speed the API accepts
+const openAITTSMaxSpeed = 4.0
+
 // NewOpenAITTSService creates a new OpenAI TTS service
 func NewOpenAITTSService(apiKey, model, voice string, speed float64) *OpenAITTSService {
 	if model == "" {
@@ -58,21 +64,18 @@ func NewOpenAITTSService(apiKey, model, voice string, speed float64) *OpenAITTSS
 	if voice == "" {
 		voice = "nova"
 	}
-	if speed <= 0 || speed > 4.0 {
+	if speed <= 0 || speed > openAITTSMaxSpeed {
 		speed = 1.0 // Natural speed - atempo handles timing adjustments if needed
 	}
 
-	tempDir := filepath.Join(os.TempDir(), "video-translator-openai-tts")
-	os.MkdirAll(tempDir, 0755)
-
-	return &OpenAITTSService{
-		apiKey:  apiKey,
-		model:   model,
-		voice:   voice,
-		speed:   speed,
-		ffmpeg:  NewFFmpegService(),
-		tempDir: tempDir,
+	s := &OpenAITTSService{
+		apiKey: apiKey,
+		model:  model,
+		voice:  voice,
+		speed:  speed,
 	}
+	s.BaseTTS = tts.NewBaseTTS("OpenAI TTS", "video-translator-openai-tts", config.DynamicWorkerCount("tts-api"), s)
+	return s
 }
 
 // CheckInstalled verifies the API key is set (OpenAI TTS requires no local installation)
@@ -90,6 +93,11 @@ func (s *OpenAITTSService) SetVoice(voice string) {
 	}
 }
 
+// GetVoice returns the current voice
+func (s *OpenAITTSService) GetVoice() string {
+	return s.voice
+}
+
 // SetModel changes the TTS model
 func (s *OpenAITTSService) SetModel(model string) {
 	if model != "" {
@@ -99,26 +107,27 @@ func (s *OpenAITTSService) SetModel(model string) {
 
 // Synthesize generates audio from text using OpenAI TTS
 func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
-	logger.LogInfo("OpenAI TTS: model=%s voice=%s speed=%.2f", s.model, s.voice, s.speed)
+	return s.SynthesizeSegment(tts.Segment{Text: text}, outputPath)
+}
+
+// SynthesizeSegment generates a segment's audio spoken with its emotion
+// (only models that take instructions can act on it) at the configured
+// speed times the segment's rate
+func (s *OpenAITTSService) SynthesizeSegment(seg tts.Segment, outputPath string) error {
+	speed := min(s.speed*seg.SpeakingRate(), openAITTSMaxSpeed)
+	voice := seg.VoiceOr(s.voice)
+	logger.LogInfo("OpenAI TTS: model=%s voice=%s speed=%.2f emotion=%s", s.model, voice, speed, seg.Emotion)
 
+	text := seg.Text
 	if text == "" {
-		return fmt.Errorf("empty text provided")
+		return tts.Permanent(fmt.Errorf("empty text provided"))
 	}
 
 	if s.apiKey == "" {
-		return fmt.Errorf("OpenAI API key is required")
-	}
-
-	// Build request body
-	reqBody := map[string]interface{}{
-		"model":           s.model,
-		"input":           text,
-		"voice":           s.voice,
-		"speed":           s.speed,
-		"response_format": "mp3",
+		return tts.Permanent(fmt.Errorf("OpenAI API key is required"))
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
+	jsonBody, err := json.Marshal(s.requestBody(text, voice, seg.Emotion, speed))
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -131,7 +140,8 @@ func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
 	req.Header.Set("Authorization", "Bearer "+s.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := openaiTTSClient.Do(req)
+	resp, err := doRateLimited(rateLimiterFor(UsageStageTTS, "openai"), openaiTTSClient, req,
+		RateCost{Characters: utf8.RuneCountInString(text)})
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -144,10 +154,11 @@ func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
 				Message string `json:"message"`
 			} `json:"error"`
 		}
+		err := fmt.Errorf("OpenAI TTS API error (status %d): %s", resp.StatusCode, string(respBody))
 		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
-			return fmt.Errorf("OpenAI TTS API error: %s", errResp.Error.Message)
+			err = fmt.Errorf("OpenAI TTS API error: %s", errResp.Error.Message)
 		}
-		return fmt.Errorf("OpenAI TTS API error (status %d): %s", resp.StatusCode, string(respBody))
+		return tts.PermanentForStatus(resp.StatusCode, err)
 	}
 
 	// Response is raw audio bytes (MP3)
@@ -155,6 +166,7 @@ func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read audio response: %w", err)
 	}
+	seg.RecordUsage(UsageStageTTS, "openai", s.model, text)
 
 	// Write to temp MP3 file
 	mp3Path := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".mp3"
@@ -164,7 +176,7 @@ func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
 
 	// Convert MP3 to WAV for consistency with other TTS services
 	if strings.HasSuffix(outputPath, ".wav") {
-		if err := s.ffmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
+		if err := s.FFmpeg.ConvertToWAV(mp3Path, outputPath); err != nil {
 			// Clean up MP3 on error
 			os.Remove(mp3Path)
 			return fmt.Errorf("failed to convert to WAV: %w", err)
@@ -181,115 +193,35 @@ func (s *OpenAITTSService) Synthesize(text, outputPath string) error {
 	return nil
 }
 
-// SynthesizeSubtitles generates audio for all subtitles with proper timing
-func (s *OpenAITTSService) SynthesizeSubtitles(subs models.SubtitleList, outputPath string) error {
-	return s.SynthesizeWithCallback(subs, outputPath, nil)
+// CacheKey identifies the model, voice and speed for the segment cache
+func (s *OpenAITTSService) CacheKey(voice string) string {
+	if voice == "" {
+		voice = s.voice
+	}
+	return fmt.Sprintf("openai|%s|%s|%.2f", s.model, voice, s.speed)
 }
 
-// openaiJobData contains data for an OpenAI TTS job.
-type openaiJobData struct {
-	index int
-	text  string
-	start time.Duration
-	end   time.Duration
+// MaxRate is how much faster than the configured speed the API still allows
+func (s *OpenAITTSService) MaxRate() float64 {
+	return openAITTSMaxSpeed / s.speed
 }
 
-// SynthesizeWithCallback generates audio for subtitles with progress callback
-// Uses internal worker pool for parallel processing (5-10x faster synthesis)
-func (s *OpenAITTSService) SynthesizeWithCallback(
-	subs models.SubtitleList,
-	outputPath string,
-	onProgress func(current, total int),
-) error {
-	if len(subs) == 0 {
-		return fmt.Errorf("no subtitles provided")
-	}
-
-	if err := s.CheckInstalled(); err != nil {
-		return err
-	}
-
-	// Create temp directory for segments
-	segmentDir := filepath.Join(s.tempDir, fmt.Sprintf("segments_%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(segmentDir, 0755); err != nil {
-		return err
-	}
-	defer os.RemoveAll(segmentDir)
-
-	// Identify which subtitles need TTS (non-empty text)
-	var jobs []openaiJobData
-	for i, sub := range subs {
-		if strings.TrimSpace(sub.Text) != "" {
-			jobs = append(jobs, openaiJobData{
-				index: i,
-				text:  sub.Text,
-				start: sub.StartTime,
-				end:   sub.EndTime,
-			})
-		}
-	}
-
-	// Process TTS jobs in parallel using internal worker pool
-	speechPaths := make(map[int]string)
-	total := len(subs)
-
-	if len(jobs) > 0 {
-		// Process function for worker pool
-		processJob := func(job worker.Job[openaiJobData]) (string, error) {
-			data := job.Data
-			speechPath := filepath.Join(segmentDir, fmt.Sprintf("speech_%04d.wav", data.index))
-
-			// Synthesize the text
-			if err := s.Synthesize(data.text, speechPath); err != nil {
-				return "", err
-			}
-
-			// Adjust duration if needed
-			targetDuration := (data.end - data.start).Seconds()
-			if targetDuration > 0.2 {
-				adjustedPath := filepath.Join(segmentDir, fmt.Sprintf("adjusted_%04d.wav", data.index))
-				if err := s.ffmpeg.AdjustAudioDuration(speechPath, adjustedPath, targetDuration); err == nil {
-					return adjustedPath, nil
-				}
-			}
-
-			return speechPath, nil
-		}
-
-		// Progress callback adapter
-		var progressCallback worker.ProgressFunc
-		if onProgress != nil {
-			progressCallback = func(completed, _ int) {
-				onProgress(completed, total)
-			}
-		}
-
-		// Run worker pool with dynamic worker count
-		workers := config.DynamicWorkerCount("tts-api")
-		results, err := worker.Process(jobs, workers, processJob, progressCallback)
-		if err != nil {
-			return fmt.Errorf("TTS synthesis failed: %w", err)
-		}
-
-		// Collect speech paths by index
-		for i, path := range results {
-			speechPaths[jobs[i].index] = path
-		}
+// requestBody builds the speech request; emotion becomes "instructions" for
+// models that support them
+func (s *OpenAITTSService) requestBody(text, voice, emotion string, speed float64) map[string]interface{} {
+	reqBody := map[string]interface{}{
+		"model":           s.model,
+		"input":           text,
+		"voice":           voice,
+		"speed":           speed,
+		"response_format": "mp3",
 	}
-
-	// Build final audio using AudioAssembler with parallel gap processing
-	internalSubs := models.ToInternalSubtitles(subs)
-	ffmpegMedia := media.NewFFmpegServiceWithPath(s.ffmpeg.GetPath())
-	assembler := media.NewAudioAssembler(ffmpegMedia, segmentDir)
-
-	if err := assembler.AssembleFromSpeechPathsParallel(internalSubs, speechPaths, outputPath); err != nil {
-		return fmt.Errorf("failed to assemble audio: %w", err)
+	if instructions, ok := openAIEmotionInstructions[emotion]; ok && openAITTSSupportsInstructions(s.model) {
+		reqBody["instructions"] = instructions
 	}
-
-	return nil
+	return reqBody
 }
 
-
 // GetVoices returns the list of available OpenAI TTS voices
 func GetOpenAIVoices() map[string]string {
 	return OpenAIVoices
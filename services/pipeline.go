@@ -1,14 +1,19 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"video-translator/internal/config"
 	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/internal/tts"
 	"video-translator/models"
 )
 
@@ -26,8 +31,8 @@ type Pipeline struct {
 
 	// Translation providers
 	translator *TranslatorService
-	deepseek   *DeepSeekService
-	grok       *GrokTranslationService
+	llm        *LLMTranslationService // OpenAI-compatible translation provider, nil for argos
+	libre      *LibreTranslateService // nil unless libretranslate is selected
 
 	// TTS providers
 	tts         *TTSService
@@ -36,14 +41,41 @@ type Pipeline struct {
 	edgeTTS     *EdgeTTSService
 	fishAudioTTS *FishAudioTTSService
 
+	// Transcripts of previously seen audio (nil when disabled)
+	transcriptCache *TranscriptionCache
+
+	// Previously synthesized lines, shared by every TTS provider (nil when disabled)
+	speechCache *tts.SegmentCache
+
+	// Priced record of every provider call
+	usage *UsageLedger
+
+	// Voices of each TTS provider, fetched and cached on disk
+	voices *VoiceCatalog
+
+	// Speaker diarization (nil when disabled)
+	diarizer Diarizer
+
+	// Measured speaking rates (chars/sec) by provider|voice|language
+	voiceRates   map[string]float64
+	voiceRatesMu sync.Mutex
+
 	onProgress ProgressCallback
 	tempDir    string
 }
 
+// VoiceCatalog returns the TTS voices the pipeline picks from
+func (p *Pipeline) VoiceCatalog() *VoiceCatalog {
+	return p.voices
+}
+
 func NewPipeline(config *models.Config) *Pipeline {
 	tempDir := filepath.Join(os.TempDir(), "video-translator")
 	os.MkdirAll(tempDir, 0755)
 
+	// Limits are process-wide: every pipeline's jobs share them
+	ConfigureRateLimits(config.RateLimits)
+
 	p := &Pipeline{
 		ffmpeg:     NewFFmpegService(),
 		config:     config,
@@ -51,6 +83,31 @@ func NewPipeline(config *models.Config) *Pipeline {
 		whisper:    NewWhisperService(),
 		translator: NewTranslatorService(),
 		tts:        NewTTSService(config.DefaultVoice),
+		voiceRates: make(map[string]float64),
+		usage:      NewUsageLedger(DefaultUsageLedgerPath(), config),
+		voices:     NewVoiceCatalog(DefaultVoiceCatalogDir(), config.FishAudioAPIKey),
+	}
+	p.voices.CloneSample = config.VoiceCloneSamplePath
+
+	// Transcription cache (skips stage 2 for audio we've already transcribed)
+	if config.TranscriptionCacheEnabled {
+		p.transcriptCache = NewTranscriptionCache(
+			DefaultTranscriptionCacheDir(),
+			int64(config.TranscriptionCacheMaxMB)*1024*1024,
+		)
+	}
+
+	// Speech cache (skips TTS calls for lines already spoken in that voice)
+	if config.SpeechCacheEnabled {
+		p.speechCache = tts.NewSegmentCache(tts.DefaultCacheDir(), int64(config.SpeechCacheMaxMB)*1024*1024)
+	}
+	// TTS services are shared by parallel jobs, so each gets the cache once
+	// here rather than per run
+	p.tts.SetCache(p.speechCache)
+
+	// Speaker diarization for multi-voice dubbing
+	if config.DiarizationEnabled {
+		p.diarizer = NewPyannoteDiarizer(config.PythonPath, config.HuggingFaceToken, config.MaxSpeakers)
 	}
 
 	// Initialize FasterWhisper if selected (dev only)
@@ -76,14 +133,16 @@ func NewPipeline(config *models.Config) *Pipeline {
 		p.groq = NewGroqTranscriptionService(config.GroqAPIKey)
 	}
 
-	// Initialize DeepSeek if API key is provided
-	if config.DeepSeekKey != "" {
-		p.deepseek = NewDeepSeekService(config.DeepSeekKey)
+	// Initialize the LLM translator (OpenAI, DeepSeek, Grok or a local server)
+	if provider := p.getTranslationProvider(); IsLLMTranslationProvider(provider) {
+		if llmConfig, err := LLMTranslationConfigFor(provider, config); err == nil {
+			p.llm = NewLLMTranslationService(llmConfig)
+		}
 	}
 
-	// Initialize Grok (xAI) if API key is provided
-	if config.GrokAPIKey != "" {
-		p.grok = NewGrokTranslationService(config.GrokAPIKey)
+	// Initialize LibreTranslate if selected (self-hosted HTTP server)
+	if p.getTranslationProvider() == TranslationProviderLibreTranslate {
+		p.libre = NewLibreTranslateService(config.LibreTranslateURL, config.LibreTranslateAPIKey)
 	}
 
 	// Initialize OpenAI TTS if selected and API key available
@@ -94,6 +153,7 @@ func NewPipeline(config *models.Config) *Pipeline {
 			config.OpenAITTSVoice,
 			config.OpenAITTSSpeed,
 		)
+		p.openaiTTS.SetCache(p.speechCache)
 	}
 
 	// Initialize CosyVoice if selected
@@ -105,11 +165,15 @@ func NewPipeline(config *models.Config) *Pipeline {
 			config.VoiceCloneSamplePath,
 			config.PythonPath,
 		)
+		p.cosyvoice.SetCache(p.speechCache)
 	}
 
-	// Initialize Edge TTS if selected (FREE neural TTS)
-	if config.TTSProvider == "edge-tts" {
+	// Initialize Edge TTS if selected (FREE neural TTS), or as the free
+	// fallback for jobs over budget
+	budgeted := config.JobBudget > 0 || config.DailyBudget > 0
+	if config.TTSProvider == "edge-tts" || (budgeted && config.BudgetAction != BudgetActionAbort) {
 		p.edgeTTS = NewEdgeTTSService(config.EdgeTTSVoice)
+		p.edgeTTS.SetCache(p.speechCache)
 	}
 
 	// Initialize Fish Audio TTS if selected and API key available
@@ -120,6 +184,7 @@ func NewPipeline(config *models.Config) *Pipeline {
 			config.FishAudioReferenceID,
 			config.FishAudioSpeed,
 		)
+		p.fishAudioTTS.SetCache(p.speechCache)
 	}
 
 	return p
@@ -129,6 +194,15 @@ func (p *Pipeline) SetProgressCallback(cb ProgressCallback) {
 	p.onProgress = cb
 }
 
+// Close stops the persistent Python workers (Argos, CosyVoice local). Jobs
+// still running on this pipeline will fail.
+func (p *Pipeline) Close() {
+	p.translator.Close()
+	if p.cosyvoice != nil {
+		p.cosyvoice.Close()
+	}
+}
+
 func (p *Pipeline) progress(stage string, percent int, message string) {
 	if p.onProgress != nil {
 		p.onProgress(stage, percent, message)
@@ -157,6 +231,9 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 	}
 	defer os.RemoveAll(jobTempDir) // Cleanup on completion
 
+	// Every provider call from here on is recorded against this job
+	meter := p.usage.Meter(job)
+
 	// Update job settings if not set
 	if job.SourceLang == "" {
 		job.SourceLang = p.config.DefaultSourceLang
@@ -181,18 +258,685 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 	job.AudioPath = audioPath
 	reportProgress("Extracting", config.ProgressExtractEnd, "Audio extracted")
 
+	// Resolve "auto" before transcribing so every later stage (cache key,
+	// hallucination phrases, translation) sees a concrete language
+	if job.SourceLang == AutoSourceLang {
+		reportProgress("Transcribing", config.ProgressTranscribeStart, "Detecting spoken language...")
+		detection, err := p.detectSourceLanguage(job, audioPath, jobTempDir, meter)
+		if err != nil {
+			err = fmt.Errorf("language detection failed (choose the source language manually): %w", err)
+			job.Fail(err)
+			return err
+		}
+		job.SourceLang = detection.Language
+		job.DetectedLang = detection.Language
+		job.DetectedLangConfidence = detection.Confidence
+		logger.LogInfo("Pipeline: detected source language %s (confidence %.2f)", detection.Language, detection.Confidence)
+		reportProgress("Transcribing", config.ProgressTranscribeStart,
+			fmt.Sprintf("Detected language: %s", text.GetLanguageName(detection.Language)))
+	}
+
 	// Stage 2: Transcribe (with parallel chunking for long audio)
 	provider := p.getTranscriptionProvider()
 	logger.LogInfo("Pipeline: Stage 2/5 - Transcribing with %s (lang=%s)", provider, job.SourceLang)
 	reportProgress("Transcribing", config.ProgressTranscribeStart, "Starting transcription...")
 	job.SetStatus(models.StatusTranscribing, "Transcribing audio", config.ProgressTranscribeStart)
 
-	// Get audio duration to determine if chunking is beneficial
-	audioDuration, _ := p.ffmpeg.GetVideoDuration(audioPath)
+	// Bias the recognizer towards the job's vocabulary
+	vocabulary := p.vocabularyFor(job)
+	prompt := text.BuildVocabularyPrompt(vocabulary)
 
 	var subtitles models.SubtitleList
 	var err error
 
+	// Identical audio (e.g. re-running with another target language or voice)
+	// is served from the transcription cache instead of being re-transcribed
+	cacheKey := p.transcriptionCacheKey(audioPath, provider, job.SourceLang, prompt)
+	cached, ok := p.transcriptCache.Get(cacheKey)
+	if !ok {
+		// Only a provider that is actually called has to fit the budget
+		budgetProvider, budgetErr := p.withinBudget(job, meter, UsageStageTranscription, provider,
+			p.projectTranscriptionCost(provider, audioPath))
+		if budgetErr != nil {
+			job.Fail(budgetErr)
+			return budgetErr
+		}
+		if budgetProvider != provider {
+			provider = budgetProvider
+			reportProgress("Transcribing", config.ProgressTranscribeStart, fmt.Sprintf("Over budget, transcribing with free %s", provider))
+			cacheKey = p.transcriptionCacheKey(audioPath, provider, job.SourceLang, prompt)
+			cached, ok = p.transcriptCache.Get(cacheKey)
+		}
+	}
+	if ok {
+		logger.LogInfo("Pipeline: transcription cache hit (%d segments)", len(cached))
+		reportProgress("Transcribing", config.ProgressTranscribeEnd, "Using cached transcription")
+		subtitles = cached
+	} else {
+		subtitles, err = p.transcribe(job, provider, audioPath, prompt, jobTempDir, reportProgress)
+		if err == nil {
+			p.recordTranscription(meter, provider, audioPath)
+		}
+		if err == nil && len(subtitles) > 0 {
+			if cacheErr := p.transcriptCache.Put(cacheKey, subtitles); cacheErr != nil {
+				logger.LogError("Pipeline: failed to cache transcription: %v", cacheErr)
+			}
+		}
+	}
+
+	if err != nil {
+		job.Fail(err)
+		return fmt.Errorf("transcription failed: %w", err)
+	}
+
+	if len(subtitles) == 0 {
+		job.Fail(fmt.Errorf("no speech detected in audio"))
+		return fmt.Errorf("no speech detected in audio")
+	}
+
+	// Drop Whisper hallucinations before they get translated and spoken
+	if p.config.FilterHallucinations {
+		reportProgress("Transcribing", config.ProgressTranscribeEnd, "Filtering hallucinated segments...")
+		subtitles = p.filterHallucinations(job, subtitles, audioPath)
+		if len(subtitles) == 0 {
+			job.Fail(fmt.Errorf("no speech left after hallucination filter"))
+			return fmt.Errorf("no speech left after hallucination filter")
+		}
+	}
+
+	// Snap misrecognized names to their canonical spelling
+	if p.config.VocabularyFuzzyFix && len(vocabulary) > 0 {
+		subtitles, _ = ApplyVocabulary(subtitles, vocabulary)
+	}
+
+	// Label segments with speakers so each can get its own voice.
+	// Diarization is best-effort: on failure everyone shares job.Voice.
+	if p.diarizer != nil {
+		reportProgress("Transcribing", config.ProgressTranscribeEnd, "Identifying speakers...")
+		turns, diarizeErr := p.diarizer.Diarize(audioPath)
+		if diarizeErr != nil {
+			logger.LogError("Pipeline: diarization failed, using a single voice: %v", diarizeErr)
+		} else {
+			subtitles = AssignSpeakers(subtitles, turns)
+			logger.LogInfo("Pipeline: diarization found %d speakers", len(Speakers(subtitles)))
+		}
+	}
+
+	reportProgress("Transcribing", config.ProgressTranscribeEnd, fmt.Sprintf("Transcribed %d segments", len(subtitles)))
+
+	// Length budgets go to the translator so lines are written to fit their
+	// window; they are refined with the voice's measured rate afterwards
+	var budgeter *DurationBudgeter
+	if p.config.FitTranslationToDuration {
+		budgeter = NewDurationBudgeter(job.TargetLang)
+		budgeter.Apply(subtitles, nil)
+	}
+
+	// Stage 3: Translate
+	glossary, err := p.glossaryFor(job)
+	if err != nil {
+		job.Fail(err)
+		return err
+	}
+
+	transProvider := p.getTranslationProvider()
+	logger.LogInfo("Pipeline: Stage 3/5 - Translating with %s (%s → %s)", transProvider, job.SourceLang, job.TargetLang)
+	reportProgress("Translating", config.ProgressTranslateStart, "Translating text...")
+	job.SetStatus(models.StatusTranslating, "Translating text", config.ProgressTranslateStart)
+
+	var translatedSubs models.SubtitleList
+	var opts TranslateOptions
+	if job.SourceLang == job.TargetLang {
+		// Nothing to translate (e.g. auto-detect found the target language);
+		// re-voice the original transcript
+		logger.LogInfo("Pipeline: source and target are both %s, skipping translation", job.SourceLang)
+		translatedSubs = make(models.SubtitleList, len(subtitles))
+		copy(translatedSubs, subtitles)
+		opts, err = p.translateOptions(job, transProvider, glossary, nil, meter) // Lines may still be shortened
+	} else {
+		projected := ProjectTranslationCost(p.config, transProvider, p.translationModel(transProvider), subtitles)
+		if p.config.TranslationDocumentBrief {
+			projected += ProjectDocumentBriefCost(p.config, transProvider, p.translationModel(transProvider), subtitles)
+		}
+		budgetProvider, budgetErr := p.withinBudget(job, meter, UsageStageTranslation, transProvider, projected)
+		if budgetErr != nil {
+			job.Fail(budgetErr)
+			return budgetErr
+		}
+		if budgetProvider != transProvider {
+			transProvider = budgetProvider
+			reportProgress("Translating", config.ProgressTranslateStart, fmt.Sprintf("Over budget, translating with free %s", transProvider))
+		}
+		memory := p.translationMemory()
+		opts, err = p.translateOptions(job, transProvider, glossary, memory, meter)
+		if err == nil {
+			translatedSubs, err = p.translate(job, transProvider, subtitles, opts, reportProgress)
+		}
+		if saveErr := memory.Save(); saveErr != nil {
+			logger.LogError("Pipeline: failed to save translation memory: %v", saveErr)
+		}
+	}
+
+	if err != nil {
+		job.Fail(err)
+		return fmt.Errorf("translation failed: %w", err)
+	}
+	if budgeter != nil {
+		p.fitToDuration(job, transProvider, budgeter, subtitles, translatedSubs, opts, jobTempDir, reportProgress)
+	}
+	copySpeakers(subtitles, translatedSubs)
+	p.reviewSegments(job, subtitles, translatedSubs, glossary)
+	reportProgress("Translating", config.ProgressTranslateEnd, "Translation complete")
+
+	// Stage 4: Text-to-Speech
+	ttsProvider := p.getTTSProvider()
+	voice, speakerVoices := job.Voice, job.SpeakerVoices
+	spokenSubs := p.speechText(job, ttsProvider, translatedSubs)
+	budgetProvider, err := p.withinBudget(job, meter, UsageStageTTS, ttsProvider,
+		ProjectTTSCost(p.config, ttsProvider, p.ttsModel(ttsProvider), spokenSubs))
+	if err != nil {
+		job.Fail(err)
+		return err
+	}
+	if budgetProvider != ttsProvider {
+		// The job's voices belong to the paid provider
+		ttsProvider = budgetProvider
+		voice, speakerVoices = p.budgetFallbackVoice(job.TargetLang), nil
+		spokenSubs = p.speechText(job, ttsProvider, translatedSubs) // Phoneme markup is per provider
+		reportProgress("Synthesizing", config.ProgressSynthesizeStart, fmt.Sprintf("Over budget, speaking with free %s", ttsProvider))
+	}
+	if p.config.AutoVoiceReference && TTSClonesVoices(ttsProvider) {
+		// Clone the video's own speakers instead of a fixed sample
+		reportProgress("Synthesizing", config.ProgressSynthesizeStart, "Finding reference samples of each voice...")
+		voice, speakerVoices = withVoiceReferences(voice, speakerVoices, p.voiceReferences(job, subtitles, audioPath, jobTempDir))
+	}
+	logger.LogInfo("Pipeline: Stage 4/5 - Synthesizing with %s (voice=%s)", ttsProvider, voice)
+	reportProgress("Synthesizing", config.ProgressSynthesizeStart, "Generating speech...")
+	job.SetStatus(models.StatusSynthesizing, "Generating dubbed audio", config.ProgressSynthesizeStart)
+
+	dubbedAudioPath := filepath.Join(jobTempDir, "dubbed.wav")
+	if speakers := Speakers(spokenSubs); len(speakers) > 1 {
+		err = p.synthesizeSpeakers(job, ttsProvider, voice, speakerVoices, speakers, spokenSubs, dubbedAudioPath, jobTempDir, meter, reportProgress)
+	} else {
+		err = p.synthesize(ttsProvider, voice, spokenSubs, dubbedAudioPath, meter,
+			config.ProgressSynthesizeStart, config.ProgressSynthesizeEnd, reportProgress)
+	}
+
+	if err != nil {
+		job.Fail(err)
+		return fmt.Errorf("speech synthesis failed: %w", err)
+	}
+	job.DubbedAudioPath = dubbedAudioPath
+	reportProgress("Synthesizing", config.ProgressSynthesizeEnd, "Speech synthesis complete")
+
+	// Stage 5: Mux Video
+	logger.LogInfo("Pipeline: Stage 5/5 - Muxing final video")
+	reportProgress("Muxing", config.ProgressMuxStart, "Creating final video...")
+	job.SetStatus(models.StatusMuxing, "Creating final video", config.ProgressMuxStart)
+
+	// Generate output path
+	outputPath := p.generateOutputPath(job.InputPath)
+
+	// Mux video with audio - optionally keep background audio
+	var muxErr error
+	if p.config.KeepBackgroundAudio && p.config.BackgroundAudioVolume > 0 {
+		reportProgress("Muxing", config.ProgressMuxStart+5, "Mixing dubbed audio with original background...")
+		muxErr = p.ffmpeg.MuxVideoAudioWithOriginal(job.InputPath, dubbedAudioPath, outputPath, p.config.BackgroundAudioVolume)
+	} else {
+		muxErr = p.ffmpeg.MuxVideoAudio(job.InputPath, dubbedAudioPath, outputPath)
+	}
+	if muxErr != nil {
+		job.Fail(muxErr)
+		return fmt.Errorf("video muxing failed: %w", muxErr)
+	}
+
+	job.Complete(outputPath)
+	usage := meter.Total()
+	logger.LogInfo("Pipeline: Complete! Output: %s (%d provider calls, %s)", outputPath, usage.Calls, FormatCost(usage.Cost))
+	reportProgress("Complete", config.ProgressMuxEnd, fmt.Sprintf("Translation complete! Provider cost: %s", FormatCost(usage.Cost)))
+
+	return nil
+}
+
+// translate runs stage 3 with the given provider. LLMs get the glossary in
+// their prompts; for machine translation (Argos, LibreTranslate) its terms
+// are swapped for placeholders that survive translation and are restored
+// afterwards. Lines found in memory are not sent to the provider; new
+// translations are added to it.
+func (p *Pipeline) translate(job *models.TranslationJob, transProvider string, subtitles models.SubtitleList, opts TranslateOptions, reportProgress ProgressCallback) (models.SubtitleList, error) {
+	translateRange := config.ProgressTranslateEnd - config.ProgressTranslateStart
+	if IsLLMTranslationProvider(transProvider) {
+		if p.llm == nil {
+			return nil, fmt.Errorf("%s translation is not configured", transProvider)
+		}
+		name := p.llm.Name()
+		progress := func(label string) func(current, total int) {
+			return func(current, total int) {
+				percent := config.ProgressTranslateStart + (current*translateRange)/total
+				reportProgress("Translating", percent, fmt.Sprintf("%s: %d/%d segments", label, current, total))
+			}
+		}
+
+		// Tag emotions for TTS providers that can act them out
+		if p.config.EmotionTagging && TTSSupportsEmotions(p.getTTSProvider()) {
+			reportProgress("Translating", config.ProgressTranslateStart+1, fmt.Sprintf("Using %s (%s) with emotion detection...", name, p.llm.Model()))
+			return p.llm.TranslateSubtitlesWithEmotions(subtitles, job.SourceLang, job.TargetLang, opts, progress(name+" (emotions)"))
+		}
+		reportProgress("Translating", config.ProgressTranslateStart+1, fmt.Sprintf("Using %s (%s)...", name, p.llm.Model()))
+		return p.llm.TranslateSubtitles(subtitles, job.SourceLang, job.TargetLang, opts, progress(name))
+	}
+
+	// Machine translation: "argos" or "libretranslate"
+	mt, label, err := p.machineTranslator(transProvider)
+	if err != nil {
+		return nil, err
+	}
+	reportProgress("Translating", config.ProgressTranslateStart+1, fmt.Sprintf("Using %s...", label))
+	glossary, memory := opts.Glossary, opts.Memory
+	translated, pending := ApplyTranslationMemory(memory, subtitles, job.SourceLang, job.TargetLang, opts.MemoryScope, glossary)
+	if hits := len(subtitles) - len(pending); memory != nil && hits > 0 {
+		logger.LogInfo("Pipeline: %d/%d segments served from translation memory", hits, len(subtitles))
+	}
+	if len(pending) == 0 {
+		return translated, nil
+	}
+
+	todo := make(models.SubtitleList, len(pending))
+	for j, i := range pending {
+		todo[j] = subtitles[i]
+	}
+	var protected [][]string
+	if !glossary.Empty() {
+		todo, protected = glossary.ProtectSubtitles(todo)
+	}
+	results, err := mt.TranslateSubtitlesWithProgress(
+		todo,
+		job.SourceLang,
+		job.TargetLang,
+		opts.Usage,
+		func(current, total int) {
+			percent := config.ProgressTranslateStart + (current*translateRange)/total
+			msg := fmt.Sprintf("%s: %d/%d segments", label, current, total)
+			reportProgress("Translating", percent, msg)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	RestoreSubtitles(results, protected)
+
+	for j, i := range pending {
+		if j >= len(results) {
+			break
+		}
+		translated[i].Text = results[j].Text
+		memory.Add(subtitles[i].Text, results[j].Text, job.SourceLang, job.TargetLang, opts.MemoryScope, "")
+	}
+	return translated, nil
+}
+
+// translateOptions gathers the job's settings for a translation call: its
+// glossary, memory and usage meter, plus the profile and prompt templates
+// LLMs use
+func (p *Pipeline) translateOptions(job *models.TranslationJob, provider string, glossary *Glossary, memory *TranslationMemory, meter *UsageMeter) (TranslateOptions, error) {
+	opts := TranslateOptions{Glossary: glossary, Memory: memory, MemoryScope: provider, Usage: meter}
+	if !IsLLMTranslationProvider(provider) {
+		return opts, nil
+	}
+
+	profile, err := p.translationProfileFor(job)
+	if err != nil {
+		return opts, err
+	}
+	prompts, err := LoadPromptTemplates(DefaultPromptDir())
+	if err != nil {
+		return opts, err
+	}
+	opts.Profile = profile
+	opts.Prompts = prompts
+	// A profile changes how lines read, so its translations are kept apart
+	if !profile.IsEmpty() {
+		opts.MemoryScope += "/" + profile.Name
+	}
+	return opts, nil
+}
+
+// machineTranslator translates line by line without prompts (Argos and
+// LibreTranslate)
+type machineTranslator interface {
+	TranslateSubtitlesWithProgress(subs models.SubtitleList, sourceLang, targetLang string, usage *UsageMeter, onProgress func(current, total int)) (models.SubtitleList, error)
+}
+
+// machineTranslator returns the non-LLM provider's translator and a label
+// for progress messages
+func (p *Pipeline) machineTranslator(provider string) (machineTranslator, string, error) {
+	if provider == TranslationProviderLibreTranslate {
+		if p.libre == nil {
+			return nil, "", fmt.Errorf("LibreTranslate is not configured")
+		}
+		return p.libre, "LibreTranslate", nil
+	}
+	return p.translator, "Argos", nil
+}
+
+// translationProfileFor returns the job's translation profile (or the
+// configured default); the zero profile when none is selected
+func (p *Pipeline) translationProfileFor(job *models.TranslationJob) (models.TranslationProfile, error) {
+	name := job.TranslationProfile
+	if name == "" {
+		name = p.config.TranslationProfile
+	}
+	if name == "" {
+		return models.TranslationProfile{}, nil
+	}
+	profile, ok := p.config.Profile(name)
+	if !ok {
+		return models.TranslationProfile{}, fmt.Errorf("unknown translation profile %q", name)
+	}
+	return profile, nil
+}
+
+// translationMemory returns the shared translation memory, nil when it is
+// disabled or unreadable (translation then proceeds without it). Settings
+// imports and clears through the same instance, so jobs see them.
+func (p *Pipeline) translationMemory() *TranslationMemory {
+	if !p.config.TranslationMemoryEnabled {
+		return nil
+	}
+	tm, err := SharedTranslationMemory(DefaultTranslationMemoryPath())
+	if err != nil {
+		logger.LogError("Pipeline: translation memory unavailable: %v", err)
+		return nil
+	}
+	return tm
+}
+
+// glossaryFor loads the job's glossary (or the configured default), nil
+// when none is selected
+func (p *Pipeline) glossaryFor(job *models.TranslationJob) (*Glossary, error) {
+	name := job.Glossary
+	if name == "" {
+		name = p.config.Glossary
+	}
+	if name == "" {
+		return nil, nil
+	}
+	g, err := LoadNamedGlossary(DefaultGlossaryDir(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load glossary: %w", err)
+	}
+	logger.LogInfo("Pipeline: glossary %s (%d terms, %d do-not-translate)", name, len(g.Terms), len(g.DoNotTranslate))
+	return g, nil
+}
+
+// fitToDuration re-budgets the translation with the target voice's measured
+// speaking rate, has the LLM paraphrase lines that are still too long and
+// records on each segment whether it fits. Lines are measured as the voice
+// will say them (see speechText), while the written text is what gets
+// shortened. Every step is best-effort: a failure leaves the translation
+// as it was.
+func (p *Pipeline) fitToDuration(job *models.TranslationJob, transProvider string, budgeter *DurationBudgeter, source, translated models.SubtitleList, opts TranslateOptions, jobTempDir string, reportProgress ProgressCallback) {
+	ttsProvider := p.getTTSProvider()
+	spoken := p.speechText(job, ttsProvider, translated)
+	if rate, err := p.voiceRate(ttsProvider, job.Voice, job.TargetLang, spoken, opts.Usage, jobTempDir); err != nil {
+		logger.LogError("Pipeline: could not measure %s voice rate, using %.0f chars/sec estimate: %v",
+			ttsProvider, budgeter.CharsPerSecond, err)
+	} else {
+		budgeter.CharsPerSecond = rate
+	}
+	budgeter.Apply(translated, spoken)
+
+	// A job downgraded to a free translator isn't shortened by the paid LLM,
+	// nor is one the paraphrasing would take over budget
+	over := MarkFit(translated)
+	shorten := over > 0 && p.llm != nil && transProvider == p.getTranslationProvider()
+	if shorten {
+		projected := ProjectShortenCost(p.config, transProvider, p.translationModel(transProvider), translated)
+		if err := CheckBudget(p.config, p.usage, UsageStageTranslation, transProvider, projected, opts.Usage.Total().Cost); err != nil {
+			logger.LogInfo("Pipeline: not shortening %d over-long lines: %v", over, err)
+			shorten = false
+		}
+	}
+	if shorten {
+		reportProgress("Translating", config.ProgressTranslateEnd, fmt.Sprintf("Shortening %d lines to fit timing...", over))
+		shortened, err := p.llm.ShortenSubtitles(source, translated, job.TargetLang, opts)
+		if err != nil {
+			logger.LogError("Pipeline: %v", err)
+		}
+		logger.LogInfo("Pipeline: shortened %d of %d over-long lines", shortened, over)
+		if shortened > 0 {
+			// A paraphrase may drop or add numbers that are spelled out
+			budgeter.Apply(translated, p.speechText(job, ttsProvider, translated))
+		}
+	}
+
+	job.OverBudgetCount = MarkFit(translated)
+	logger.LogInfo("Pipeline: %d of %d segments fit their window (%.1f chars/sec)",
+		len(translated)-job.OverBudgetCount, len(translated), budgeter.CharsPerSecond)
+}
+
+// voiceRate returns the speaking rate of a TTS voice in characters per
+// second, measuring it on a sample of the spoken translation the first
+// time. The measurement's characters are recorded on meter.
+func (p *Pipeline) voiceRate(provider, voice, lang string, spoken models.SubtitleList, meter *UsageMeter, jobTempDir string) (float64, error) {
+	key := provider + "|" + voice + "|" + lang
+	p.voiceRatesMu.Lock()
+	rate, ok := p.voiceRates[key]
+	p.voiceRatesMu.Unlock()
+	if ok {
+		return rate, nil
+	}
+
+	svc, resolved := p.ttsService(provider, voice)
+	if svc == nil {
+		return 0, fmt.Errorf("%s TTS is not configured", provider)
+	}
+	sample := voiceRateSample(spoken)
+	projected := ProjectTTSCost(p.config, provider, p.ttsModel(provider), models.SubtitleList{{Text: sample}})
+	if err := CheckBudget(p.config, p.usage, UsageStageTTS, provider, projected, meter.Total().Cost); err != nil {
+		return 0, err
+	}
+	synth := voiceSynthesizer{svc: svc, voice: resolved, usage: meter}
+	rate, err := MeasureVoiceRate(synth, p.ffmpeg, sample, jobTempDir)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.LogInfo("Pipeline: %s voice %s speaks %.1f chars/sec", provider, voice, rate)
+	p.voiceRatesMu.Lock()
+	p.voiceRates[key] = rate
+	p.voiceRatesMu.Unlock()
+	return rate, nil
+}
+
+// voiceSynthesizer speaks single lines through a shared TTS service in one
+// job's voice, recording them on the job's meter
+type voiceSynthesizer struct {
+	svc   tts.Synthesizer
+	voice string
+	usage *UsageMeter
+}
+
+func (v voiceSynthesizer) Synthesize(text, outputPath string) error {
+	return v.svc.SynthesizeSegment(tts.Segment{Text: text, Voice: v.voice, Usage: v.usage}, outputPath)
+}
+
+// ttsService returns a TTS provider's service and the voice to pass it
+// per call, or a nil service when the provider isn't configured. Services
+// are shared by parallel jobs, so the voice is never set on them.
+func (p *Pipeline) ttsService(provider, voice string) (tts.Service, string) {
+	switch provider {
+	case "openai":
+		if p.openaiTTS == nil {
+			return nil, ""
+		}
+		return p.openaiTTS, voice
+	case "cosyvoice":
+		if p.cosyvoice == nil {
+			return nil, ""
+		}
+		// The voice is a sample to clone: one picked from the catalog, else
+		// the configured sample
+		if _, err := os.Stat(voice); voice == "" || err != nil {
+			voice = p.config.VoiceCloneSamplePath
+		}
+		return p.cosyvoice, voice
+	case "edge-tts":
+		if p.edgeTTS == nil {
+			return nil, ""
+		}
+		return p.edgeTTS, voice
+	case "fish-audio":
+		if p.fishAudioTTS == nil {
+			return nil, ""
+		}
+		// Voice ID selected from the dropdown (or assigned per speaker)
+		return p.fishAudioTTS, voice
+	default: // "piper"
+		return p.tts, voice
+	}
+}
+
+// ttsDescriptions introduce each provider in the progress messages
+var ttsDescriptions = map[string]struct{ name, note string }{
+	"openai":     {"OpenAI TTS", "high quality"},
+	"cosyvoice":  {"CosyVoice", "voice cloning"},
+	"edge-tts":   {"Edge TTS", "FREE neural"},
+	"fish-audio": {"Fish Audio", "Fish Speech quality"},
+	"piper":      {"Piper", ""},
+}
+
+// synthesize runs stage 4 for one voice, mapping provider progress onto
+// [from, to] so per-speaker passes can share the stage's progress range.
+// Synthesized characters are recorded on meter.
+func (p *Pipeline) synthesize(provider, voice string, subs models.SubtitleList, outputPath string, meter *UsageMeter, from, to int, reportProgress ProgressCallback) error {
+	svc, voice := p.ttsService(provider, voice)
+	if svc == nil {
+		return fmt.Errorf("%s TTS is not configured", provider)
+	}
+
+	desc, ok := ttsDescriptions[provider]
+	if !ok {
+		desc = ttsDescriptions["piper"]
+	}
+	if desc.note != "" {
+		reportProgress("Synthesizing", from+1, fmt.Sprintf("Using %s (%s)...", desc.name, desc.note))
+	} else {
+		reportProgress("Synthesizing", from+1, fmt.Sprintf("Using %s TTS...", desc.name))
+	}
+
+	synthesizeRange := to - from
+	opts := tts.RunOptions{
+		Voice:   voice,
+		Usage:   meter,
+		RateMin: p.config.SpeechRateMin,
+		RateMax: p.config.SpeechRateMax,
+	}
+	return svc.SynthesizeWithOptions(models.ToInternalSubtitles(subs), outputPath, opts, func(current, total int) {
+		progress := from + (current*synthesizeRange)/total
+		reportProgress("Synthesizing", progress, fmt.Sprintf("%s: %d/%d", desc.name, current, total))
+	})
+}
+
+// speechText returns what the TTS provider reads for the translation: its
+// spoken form (see PrepareSpeech). The translation itself keeps the text
+// as written for subtitles.
+func (p *Pipeline) speechText(job *models.TranslationJob, provider string, translated models.SubtitleList) models.SubtitleList {
+	return PrepareSpeech(translated, job.TargetLang, provider, p.config.SpeechNormalization, p.config.PronunciationLexicon)
+}
+
+// synthesizeSpeakers dubs each speaker with its own voice into a separate
+// track and mixes the tracks. Segments of other speakers become silence in
+// each track, so timing is preserved when the tracks are overlaid.
+func (p *Pipeline) synthesizeSpeakers(job *models.TranslationJob, provider, voice string, speakerVoices map[string]string, speakers []string, subs models.SubtitleList, outputPath, jobTempDir string, meter *UsageMeter, reportProgress ProgressCallback) error {
+	voices := AssignSpeakerVoices(speakers, speakerVoices, voice, p.speakerVoiceCandidates(provider, job.TargetLang))
+	logger.LogInfo("Pipeline: %d speakers, voices=%v", len(speakers), voices)
+
+	synthesizeRange := config.ProgressSynthesizeEnd - config.ProgressSynthesizeStart
+	var tracks []string
+	for i, speaker := range speakers {
+		from := config.ProgressSynthesizeStart + i*synthesizeRange/len(speakers)
+		to := config.ProgressSynthesizeStart + (i+1)*synthesizeRange/len(speakers)
+		reportProgress("Synthesizing", from, fmt.Sprintf("Speaker %d/%d (%s)...", i+1, len(speakers), voices[speaker]))
+
+		trackPath := filepath.Join(jobTempDir, fmt.Sprintf("dubbed_speaker_%02d.wav", i))
+		if err := p.synthesize(provider, voices[speaker], filterBySpeaker(subs, speaker), trackPath, meter, from, to, reportProgress); err != nil {
+			return fmt.Errorf("speaker %s: %w", speaker, err)
+		}
+		tracks = append(tracks, trackPath)
+	}
+
+	return p.ffmpeg.MixAudioTracks(tracks, outputPath)
+}
+
+// voiceReferences cuts each speaker's cleanest stretch of speech (see
+// PickVoiceReferences) from the source video into jobTempDir. It is
+// best-effort: speakers without a usable stretch keep the job's voice.
+func (p *Pipeline) voiceReferences(job *models.TranslationJob, subs models.SubtitleList, audioPath, jobTempDir string) []VoiceReference {
+	// Shorter than the hallucination filter's, to catch pauses inside lines
+	silences, err := p.ffmpeg.DetectSilence(audioPath, -35, 0.3)
+	if err != nil {
+		logger.LogError("Pipeline: silence detection failed, picking voice references without VAD: %v", err)
+		silences = nil
+	}
+	loudness, err := p.ffmpeg.MeasureLoudness(audioPath)
+	if err != nil {
+		logger.LogError("Pipeline: loudness measurement failed, picking voice references by timing: %v", err)
+		loudness = nil
+	}
+
+	var refs []VoiceReference
+	for i, ref := range PickVoiceReferences(subs, silences, loudness) {
+		// The video's own audio track keeps more of the voice than the 16kHz transcription copy
+		path := filepath.Join(jobTempDir, fmt.Sprintf("voice_reference_%02d.wav", i))
+		if err := p.ffmpeg.ExtractVoiceSample(job.InputPath, path, ref.Start, ref.Duration()); err != nil {
+			logger.LogError("Pipeline: voice reference for %q not extracted: %v", ref.Speaker, err)
+			continue
+		}
+		ref.Path = path
+		logger.LogInfo("Pipeline: voice reference for %q at %.1fs-%.1fs (score %.2f)", ref.Speaker, ref.Start, ref.End, ref.Score)
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		logger.LogInfo("Pipeline: no clean stretch of speech found, cloning the configured sample")
+	}
+	return refs
+}
+
+// speakerVoiceCandidates lists voices that can be handed out to additional
+// speakers, restricted to the target language where the provider has one
+func (p *Pipeline) speakerVoiceCandidates(provider, targetLang string) []string {
+	var candidates []string
+	switch provider {
+	case "edge-tts", "openai":
+		for _, voice := range p.voices.VoicesFor(provider, targetLang) {
+			candidates = append(candidates, voice.ID)
+		}
+	case "piper":
+		// Only voices already on disk; downloading mid-job is too slow
+		for _, voice := range p.voices.VoicesFor(provider, targetLang) {
+			if VoiceModelExists(voice.ID) {
+				candidates = append(candidates, voice.ID)
+			}
+		}
+	}
+	// Fish Audio and CosyVoice voices are account/sample specific, so extra
+	// speakers reuse the primary voice unless mapped explicitly
+	sort.Strings(candidates)
+	return candidates
+}
+
+// transcribe runs stage 2 with the given provider, splitting long audio into
+// chunks for parallel processing on local providers. prompt biases the
+// recognizer towards the job's vocabulary.
+func (p *Pipeline) transcribe(job *models.TranslationJob, provider, audioPath, prompt, jobTempDir string, reportProgress ProgressCallback) (models.SubtitleList, error) {
+	var subtitles models.SubtitleList
+	var err error
+	lang := text.ProviderCode(text.StageTranscription, provider, job.SourceLang) // Whisper calls Javanese "jw"
+
+	// Get audio duration to determine if chunking is beneficial
+	audioDuration, _ := p.ffmpeg.GetVideoDuration(audioPath)
+
 	// Use parallel chunking for audio longer than 30 seconds (for local providers)
 	// Skip chunking for API providers (openai, groq) - they handle it internally
 	useChunking := audioDuration > config.MinChunkDuration.Seconds() && provider != "openai" && provider != "groq"
@@ -221,7 +965,8 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 					fmt.Sprintf("FasterWhisper: processing %d chunks in parallel...", len(chunks)))
 				subtitles, err = p.fasterWhisper.TranscribeChunksParallel(
 					chunks,
-					job.SourceLang,
+					lang,
+					prompt,
 					func(completed, total int) {
 						percent := config.ProgressTranscribeStart + (completed*transcribeRange)/total
 						reportProgress("Transcribing", percent,
@@ -233,7 +978,8 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 					fmt.Sprintf("Whisper: processing %d chunks in parallel...", len(chunks)))
 				subtitles, err = p.whisper.TranscribeChunksParallel(
 					chunks,
-					job.SourceLang,
+					lang,
+					prompt,
 					func(completed, total int) {
 						percent := config.ProgressTranscribeStart + (completed*transcribeRange)/total
 						reportProgress("Transcribing", percent,
@@ -251,7 +997,8 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 			reportProgress("Transcribing", config.ProgressTranscribeStart+1, "Using FasterWhisper (GPU accelerated)...")
 			subtitles, err = p.fasterWhisper.TranscribeWithProgress(
 				audioPath,
-				job.SourceLang,
+				lang,
+				prompt,
 				audioDuration,
 				func(currentSec float64, percent int) {
 					remaining := audioDuration - currentSec
@@ -267,14 +1014,15 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 
 		case "whisperkit":
 			reportProgress("Transcribing", config.ProgressTranscribeStart+1, "Using WhisperKit (Apple Silicon)...")
-			subtitles, err = p.whisperkit.Transcribe(audioPath, job.SourceLang)
+			subtitles, err = p.whisperkit.Transcribe(audioPath, lang, prompt)
 
 		case "openai":
 			reportProgress("Transcribing", config.ProgressTranscribeStart+1, "Using OpenAI Whisper API...")
 			subtitles, err = p.whisper.TranscribeWithOpenAI(
 				audioPath,
 				p.config.OpenAIKey,
-				job.SourceLang,
+				lang,
+				prompt,
 				func(percent int, message string) {
 					reportProgress("Transcribing", percent, message)
 				},
@@ -284,7 +1032,8 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 			reportProgress("Transcribing", config.ProgressTranscribeStart+1, "Using Groq Whisper (ultra-fast)...")
 			subtitles, err = p.groq.TranscribeWithProgress(
 				audioPath,
-				job.SourceLang,
+				lang,
+				prompt,
 				func(percent int, message string) {
 					reportProgress("Transcribing", percent, message)
 				},
@@ -294,7 +1043,8 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 			reportProgress("Transcribing", config.ProgressTranscribeStart+1, "Using local Whisper...")
 			subtitles, err = p.whisper.TranscribeWithProgress(
 				audioPath,
-				job.SourceLang,
+				lang,
+				prompt,
 				audioDuration,
 				func(currentSec float64, percent int) {
 					remaining := audioDuration - currentSec
@@ -310,222 +1060,263 @@ func (p *Pipeline) ProcessWithCallback(job *models.TranslationJob, onProgress Pr
 		}
 	}
 
-	if err != nil {
-		job.Fail(err)
-		return fmt.Errorf("transcription failed: %w", err)
+	return subtitles, err
+}
+
+// detectSourceLanguage identifies the spoken language with the configured
+// transcription provider. Detection runs on a short probe clip taken a little
+// way into the audio, which skips intros and keeps API uploads small. A
+// paid provider has to fit the job's budget like any other stage.
+func (p *Pipeline) detectSourceLanguage(job *models.TranslationJob, audioPath, jobTempDir string, meter *UsageMeter) (LanguageDetection, error) {
+	probePath := audioPath
+	if duration, err := p.ffmpeg.GetAudioDuration(audioPath); err == nil && duration > languageProbeDuration {
+		start := min(duration*languageProbeOffsetRatio, duration-languageProbeDuration)
+		clipPath := filepath.Join(jobTempDir, "language_probe.wav")
+		if err := p.ffmpeg.ExtractClip(audioPath, clipPath, start, languageProbeDuration); err != nil {
+			logger.LogError("Pipeline: failed to cut language probe, using full audio: %v", err)
+		} else {
+			probePath = clipPath
+		}
 	}
 
-	if len(subtitles) == 0 {
-		job.Fail(fmt.Errorf("no speech detected in audio"))
-		return fmt.Errorf("no speech detected in audio")
+	provider, err := p.withinBudget(job, meter, UsageStageTranscription, p.getTranscriptionProvider(),
+		p.projectTranscriptionCost(p.getTranscriptionProvider(), probePath))
+	if err != nil {
+		return LanguageDetection{}, err
 	}
 
-	reportProgress("Transcribing", config.ProgressTranscribeEnd, fmt.Sprintf("Transcribed %d segments", len(subtitles)))
+	var detection LanguageDetection
+	switch provider {
+	case "faster-whisper":
+		detection, err = p.fasterWhisper.DetectLanguage(probePath)
+	case "whisperkit":
+		detection, err = p.whisperkit.DetectLanguage(probePath)
+	case "openai":
+		detection, err = p.whisper.DetectLanguageWithOpenAI(probePath, p.config.OpenAIKey)
+	case "groq":
+		detection, err = p.groq.DetectLanguage(probePath)
+	default: // "whisper-cpp"
+		detection, err = p.whisper.DetectLanguage(probePath)
+	}
+	if err != nil {
+		return LanguageDetection{}, err
+	}
+	p.recordTranscription(meter, provider, probePath)
+	if detection.Language == "" {
+		return LanguageDetection{}, fmt.Errorf("provider did not report a language")
+	}
+	return detection, nil
+}
 
-	// Stage 3: Translate
-	transProvider := p.getTranslationProvider()
-	logger.LogInfo("Pipeline: Stage 3/5 - Translating with %s (%s → %s)", transProvider, job.SourceLang, job.TargetLang)
-	reportProgress("Translating", config.ProgressTranslateStart, "Translating text...")
-	job.SetStatus(models.StatusTranslating, "Translating text", config.ProgressTranslateStart)
+// withinBudget returns the provider to run stage with: provider itself if
+// its projected cost fits the spending caps, otherwise the stage's free
+// fallback when BudgetAction allows downgrading. The returned error stops
+// the job and says which cap would have been exceeded.
+func (p *Pipeline) withinBudget(job *models.TranslationJob, meter *UsageMeter, stage, provider string, projected float64) (string, error) {
+	err := CheckBudget(p.config, p.usage, stage, provider, projected, meter.Total().Cost)
+	if err == nil {
+		return provider, nil
+	}
+	var exceeded *BudgetExceededError
+	if !errors.As(err, &exceeded) || p.config.BudgetAction == BudgetActionAbort {
+		return "", err
+	}
 
-	translateRange := config.ProgressTranslateEnd - config.ProgressTranslateStart
-	var translatedSubs models.SubtitleList
-	switch transProvider {
-	case "deepseek":
-		// Use emotion-aware translation when TTS is Fish Audio (enables expressive speech)
-		if p.config.TTSProvider == "fish-audio" {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using DeepSeek with emotion detection...")
-			translatedSubs, err = p.deepseek.TranslateSubtitlesWithEmotions(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("DeepSeek (emotions): %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
-		} else {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using DeepSeek (cost-effective)...")
-			translatedSubs, err = p.deepseek.TranslateSubtitles(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("DeepSeek: %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
-		}
+	fallback := budgetFallbacks[stage]
+	if readyErr := p.budgetFallbackReady(job, stage); readyErr != nil {
+		return "", fmt.Errorf("%w, and free %s is unavailable: %v", err, fallback, readyErr)
+	}
+	note := fmt.Sprintf("Used free %s: %v", fallback, err)
+	job.BudgetNotes = append(job.BudgetNotes, note)
+	logger.LogInfo("Pipeline: %s", note)
+	return fallback, nil
+}
 
-	case "openai":
-		// Use emotion-aware translation when TTS is Fish Audio (enables expressive speech)
-		if p.config.TTSProvider == "fish-audio" {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using OpenAI GPT-4o-mini with emotion detection...")
-			translatedSubs, err = p.translator.TranslateWithOpenAIEmotions(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				p.config.OpenAIKey,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("GPT-4o-mini (emotions): %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
-		} else {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using OpenAI GPT-4o-mini...")
-			translatedSubs, err = p.translator.TranslateWithOpenAI(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				p.config.OpenAIKey,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("GPT-4o-mini: %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
+// budgetFallbackReady checks that stage's free fallback can run the job
+func (p *Pipeline) budgetFallbackReady(job *models.TranslationJob, stage string) error {
+	switch stage {
+	case UsageStageTranscription:
+		return p.whisper.CheckInstalled()
+	case UsageStageTranslation:
+		if err := p.translator.CheckInstalled(); err != nil {
+			return err
 		}
-
-	case "grok":
-		// Use emotion-aware translation when TTS is Fish Audio (enables expressive speech)
-		if p.config.TTSProvider == "fish-audio" {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using Grok (xAI) with emotion detection...")
-			translatedSubs, err = p.grok.TranslateSubtitlesWithEmotions(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("Grok (emotions): %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
-		} else {
-			reportProgress("Translating", config.ProgressTranslateStart+1, "Using Grok (xAI)...")
-			translatedSubs, err = p.grok.TranslateSubtitles(
-				subtitles,
-				job.SourceLang,
-				job.TargetLang,
-				func(current, total int) {
-					percent := config.ProgressTranslateStart + (current*translateRange)/total
-					msg := fmt.Sprintf("Grok: %d/%d segments", current, total)
-					reportProgress("Translating", percent, msg)
-				},
-			)
+		return p.translator.CheckLanguagePackage(job.SourceLang, job.TargetLang)
+	case UsageStageTTS:
+		if p.edgeTTS == nil {
+			return fmt.Errorf("Edge TTS is not configured")
 		}
+		return p.edgeTTS.CheckInstalled()
+	}
+	return fmt.Errorf("no free provider for %s", stage)
+}
 
-	default: // "argos"
-		reportProgress("Translating", config.ProgressTranslateStart+1, "Using local Argos Translate...")
-		translatedSubs, err = p.translator.TranslateSubtitlesWithProgress(
-			subtitles,
-			job.SourceLang,
-			job.TargetLang,
-			func(current, total int) {
-				percent := config.ProgressTranslateStart + (current*translateRange)/total
-				msg := fmt.Sprintf("Argos: %d/%d segments", current, total)
-				reportProgress("Translating", percent, msg)
-			},
-		)
+// budgetFallbackVoice picks the Edge TTS voice for a downgraded job: the
+// configured one if it speaks targetLang, else the first that does
+func (p *Pipeline) budgetFallbackVoice(targetLang string) string {
+	if strings.HasPrefix(strings.ToLower(p.config.EdgeTTSVoice), strings.ToLower(targetLang)+"-") {
+		return p.config.EdgeTTSVoice
 	}
+	if candidates := p.speakerVoiceCandidates("edge-tts", targetLang); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return p.config.EdgeTTSVoice
+}
 
+// projectTranscriptionCost estimates transcribing audioPath with provider
+func (p *Pipeline) projectTranscriptionCost(provider, audioPath string) float64 {
+	if _, ok := p.config.Price(UsageStageTranscription, provider, p.transcriptionModel(provider)); !ok {
+		return 0
+	}
+	duration, err := p.ffmpeg.GetAudioDuration(audioPath)
 	if err != nil {
-		job.Fail(err)
-		return fmt.Errorf("translation failed: %w", err)
+		logger.LogError("Pipeline: can't project transcription cost: %v", err)
+		return 0
 	}
-	reportProgress("Translating", config.ProgressTranslateEnd, "Translation complete")
+	return ProjectTranscriptionCost(p.config, provider, p.transcriptionModel(provider), duration)
+}
 
-	// Stage 4: Text-to-Speech
-	ttsProvider := p.getTTSProvider()
-	logger.LogInfo("Pipeline: Stage 4/5 - Synthesizing with %s (voice=%s)", ttsProvider, job.Voice)
-	reportProgress("Synthesizing", config.ProgressSynthesizeStart, "Generating speech...")
-	job.SetStatus(models.StatusSynthesizing, "Generating dubbed audio", config.ProgressSynthesizeStart)
+// translationModel returns the model an LLM translation provider uses
+func (p *Pipeline) translationModel(provider string) string {
+	if p.llm != nil && provider == p.getTranslationProvider() {
+		return p.llm.cfg.Model
+	}
+	return ""
+}
 
-	synthesizeRange := config.ProgressSynthesizeEnd - config.ProgressSynthesizeStart
-	dubbedAudioPath := filepath.Join(jobTempDir, "dubbed.wav")
-	switch ttsProvider {
+// ttsModel returns the model a TTS provider bills for
+func (p *Pipeline) ttsModel(provider string) string {
+	switch provider {
 	case "openai":
-		reportProgress("Synthesizing", config.ProgressSynthesizeStart+1, "Using OpenAI TTS (high quality)...")
-		if p.openaiTTS != nil {
-			p.openaiTTS.SetVoice(job.Voice)
-		}
-		err = p.openaiTTS.SynthesizeWithCallback(translatedSubs, dubbedAudioPath, func(current, total int) {
-			progress := config.ProgressSynthesizeStart + (current*synthesizeRange)/total
-			reportProgress("Synthesizing", progress, fmt.Sprintf("OpenAI TTS: %d/%d", current, total))
-		})
+		return p.config.OpenAITTSModel
+	case "fish-audio":
+		return p.config.FishAudioModel
+	}
+	return ""
+}
 
-	case "cosyvoice":
-		reportProgress("Synthesizing", config.ProgressSynthesizeStart+1, "Using CosyVoice (voice cloning)...")
-		err = p.cosyvoice.SynthesizeWithCallback(translatedSubs, dubbedAudioPath, func(current, total int) {
-			progress := config.ProgressSynthesizeStart + (current*synthesizeRange)/total
-			reportProgress("Synthesizing", progress, fmt.Sprintf("CosyVoice: %d/%d", current, total))
-		})
+// recordTranscription records the audio a transcription provider was sent
+func (p *Pipeline) recordTranscription(meter *UsageMeter, provider, audioPath string) {
+	if meter == nil {
+		return
+	}
+	duration, err := p.ffmpeg.GetAudioDuration(audioPath)
+	if err != nil {
+		logger.LogError("Pipeline: usage not recorded, can't measure %s: %v", filepath.Base(audioPath), err)
+		return
+	}
+	meter.Record(UsageRecord{
+		Stage:        UsageStageTranscription,
+		Provider:     provider,
+		Model:        p.transcriptionModel(provider),
+		AudioSeconds: duration,
+	})
+}
 
-	case "edge-tts":
-		reportProgress("Synthesizing", config.ProgressSynthesizeStart+1, "Using Edge TTS (FREE neural)...")
-		if p.edgeTTS != nil {
-			p.edgeTTS.SetVoice(job.Voice)
-		}
-		err = p.edgeTTS.SynthesizeWithCallback(translatedSubs, dubbedAudioPath, func(current, total int) {
-			progress := config.ProgressSynthesizeStart + (current*synthesizeRange)/total
-			reportProgress("Synthesizing", progress, fmt.Sprintf("Edge TTS: %d/%d", current, total))
-		})
+// UsageLedger returns the ledger provider calls are recorded in
+func (p *Pipeline) UsageLedger() *UsageLedger {
+	return p.usage
+}
 
-	case "fish-audio":
-		reportProgress("Synthesizing", config.ProgressSynthesizeStart+1, "Using Fish Audio (Fish Speech quality)...")
-		if p.fishAudioTTS != nil {
-			// Use voice ID from job (selected from voice dropdown)
-			p.fishAudioTTS.SetVoice(job.Voice)
-		}
-		err = p.fishAudioTTS.SynthesizeWithCallback(translatedSubs, dubbedAudioPath, func(current, total int) {
-			progress := config.ProgressSynthesizeStart + (current*synthesizeRange)/total
-			reportProgress("Synthesizing", progress, fmt.Sprintf("Fish Audio: %d/%d", current, total))
-		})
+// transcriptionCacheKey builds the cache key for a transcription request.
+// Returns a zero key (never cached) if caching is off or hashing fails.
+func (p *Pipeline) transcriptionCacheKey(audioPath, provider, language, prompt string) TranscriptionCacheKey {
+	if p.transcriptCache == nil {
+		return TranscriptionCacheKey{}
+	}
 
-	default: // "piper"
-		reportProgress("Synthesizing", config.ProgressSynthesizeStart+1, "Using Piper TTS...")
-		p.tts.SetVoice(job.Voice)
-		err = p.tts.SynthesizeWithCallback(translatedSubs, dubbedAudioPath, func(current, total int) {
-			progress := config.ProgressSynthesizeStart + (current*synthesizeRange)/total
-			reportProgress("Synthesizing", progress, fmt.Sprintf("Piper: %d/%d", current, total))
-		})
+	hash, err := HashAudioFile(audioPath)
+	if err != nil {
+		logger.LogError("Pipeline: %v", err)
+		return TranscriptionCacheKey{}
 	}
 
+	return TranscriptionCacheKey{
+		AudioHash: hash,
+		Provider:  provider,
+		Model:     p.transcriptionModel(provider),
+		Language:  language,
+		Prompt:    prompt,
+	}
+}
+
+// transcriptionModel returns the model name used by a transcription provider
+func (p *Pipeline) transcriptionModel(provider string) string {
+	switch provider {
+	case "faster-whisper":
+		return p.config.FasterWhisperModel
+	case "whisperkit":
+		return p.config.WhisperKitModel
+	case "openai":
+		return "whisper-1"
+	case "groq":
+		return groqWhisperModel
+	default: // whisper-cpp
+		return filepath.Base(p.whisper.GetModelPath())
+	}
+}
+
+// vocabularyFor returns the job's vocabulary, falling back to the configured default
+func (p *Pipeline) vocabularyFor(job *models.TranslationJob) []string {
+	if len(job.Vocabulary) > 0 {
+		return job.Vocabulary
+	}
+	return p.config.TranscriptionVocabulary
+}
+
+// filterHallucinations runs the hallucination filter over a fresh transcript.
+// Silence detection acts as VAD; if it fails the filter runs without it.
+// The report is written next to the output video when anything was touched.
+func (p *Pipeline) filterHallucinations(job *models.TranslationJob, subtitles models.SubtitleList, audioPath string) models.SubtitleList {
+	silences, err := p.ffmpeg.DetectSilence(audioPath, -35, 1.0)
 	if err != nil {
-		job.Fail(err)
-		return fmt.Errorf("speech synthesis failed: %w", err)
+		logger.LogError("Pipeline: silence detection failed, filtering without VAD: %v", err)
+		silences = nil
 	}
-	job.DubbedAudioPath = dubbedAudioPath
-	reportProgress("Synthesizing", config.ProgressSynthesizeEnd, "Speech synthesis complete")
 
-	// Stage 5: Mux Video
-	logger.LogInfo("Pipeline: Stage 5/5 - Muxing final video")
-	reportProgress("Muxing", config.ProgressMuxStart, "Creating final video...")
-	job.SetStatus(models.StatusMuxing, "Creating final video", config.ProgressMuxStart)
+	filtered, report := NewHallucinationFilter().Filter(subtitles, job.SourceLang, silences)
+	logger.LogInfo("Pipeline: hallucination filter %s", report.Summary())
 
-	// Generate output path
-	outputPath := p.generateOutputPath(job.InputPath)
+	if report.Removed == 0 && report.Flagged == 0 {
+		return filtered
+	}
 
-	// Mux video with audio - optionally keep background audio
-	var muxErr error
-	if p.config.KeepBackgroundAudio && p.config.BackgroundAudioVolume > 0 {
-		reportProgress("Muxing", config.ProgressMuxStart+5, "Mixing dubbed audio with original background...")
-		muxErr = p.ffmpeg.MuxVideoAudioWithOriginal(job.InputPath, dubbedAudioPath, outputPath, p.config.BackgroundAudioVolume)
-	} else {
-		muxErr = p.ffmpeg.MuxVideoAudio(job.InputPath, dubbedAudioPath, outputPath)
+	for _, seg := range report.Segments {
+		logger.LogDebug("Filter: %s #%d [%s] %q: %s", seg.Action, seg.Index, seg.Start, seg.Text, strings.Join(seg.Reasons, "; "))
 	}
-	if muxErr != nil {
-		job.Fail(muxErr)
-		return fmt.Errorf("video muxing failed: %w", muxErr)
+
+	outputPath := p.generateOutputPath(job.InputPath)
+	reportPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "_filter_report.json"
+	if err := report.WriteJSON(reportPath); err != nil {
+		logger.LogError("Pipeline: %v", err)
+	} else {
+		job.FilterReportPath = reportPath
 	}
 
-	job.Complete(outputPath)
-	logger.LogInfo("Pipeline: Complete! Output: %s", outputPath)
-	reportProgress("Complete", config.ProgressMuxEnd, "Translation complete!")
+	return filtered
+}
 
-	return nil
+// reviewSegments writes the job's review list of low-confidence and
+// QA-flagged segments next to the output video
+func (p *Pipeline) reviewSegments(job *models.TranslationJob, source, translated models.SubtitleList, glossary *Glossary) {
+	reviewer := NewSegmentReviewer()
+	reviewer.Glossary = glossary
+	list := reviewer.Review(source, translated, job.SourceLang, job.TargetLang)
+	list.InputFile = job.InputPath
+	logger.LogInfo("Pipeline: %s", list.Summary())
+
+	job.ReviewCount = len(list.Items)
+	if job.ReviewCount == 0 {
+		return
+	}
+
+	outputPath := p.generateOutputPath(job.InputPath)
+	reviewPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "_review.json"
+	if err := list.WriteJSON(reviewPath); err != nil {
+		logger.LogError("Pipeline: %v", err)
+	} else {
+		job.ReviewListPath = reviewPath
+	}
 }
 
 // getTranscriptionProvider returns the effective transcription provider
@@ -604,6 +1395,10 @@ func (p *Pipeline) ValidateJob(job *models.TranslationJob) error {
 		return err
 	}
 
+	if err := p.checkLanguages(job); err != nil {
+		return err
+	}
+
 	// Validate transcription provider
 	switch p.getTranscriptionProvider() {
 	case "faster-whisper":
@@ -640,22 +1435,49 @@ func (p *Pipeline) ValidateJob(job *models.TranslationJob) error {
 		}
 	}
 
+	if _, err := p.glossaryFor(job); err != nil {
+		return err
+	}
+	if IsLLMTranslationProvider(p.getTranslationProvider()) {
+		if _, err := p.translationProfileFor(job); err != nil {
+			return err
+		}
+		if _, err := LoadPromptTemplates(DefaultPromptDir()); err != nil {
+			return err
+		}
+	}
+
 	// Validate translation provider
 	switch p.getTranslationProvider() {
-	case "deepseek":
-		if p.deepseek == nil || p.config.DeepSeekKey == "" {
-			return fmt.Errorf("DeepSeek API key required")
+	case LLMProviderOpenAI, LLMProviderDeepSeek, LLMProviderGrok,
+		LLMProviderOllama, LLMProviderLMStudio, LLMProviderLlamaCpp, LLMProviderCustom:
+		if p.llm == nil {
+			return fmt.Errorf("%s translation is not configured", p.getTranslationProvider())
 		}
-	case "openai":
-		if p.config.OpenAIKey == "" {
-			return fmt.Errorf("OpenAI API key required for translation")
+		if err := p.llm.CheckAPIKey(); err != nil {
+			return err
+		}
+	case TranslationProviderLibreTranslate:
+		if p.libre == nil {
+			return fmt.Errorf("LibreTranslate is not configured")
+		}
+		if err := p.libre.CheckInstalled(); err != nil {
+			return err
+		}
+		if job.SourceLang != AutoSourceLang && job.SourceLang != job.TargetLang {
+			if err := p.libre.CheckLanguagePair(job.SourceLang, job.TargetLang); err != nil {
+				return err
+			}
 		}
 	default: // argos
 		if err := p.translator.CheckInstalled(); err != nil {
 			return err
 		}
-		if err := p.translator.CheckLanguagePackage(job.SourceLang, job.TargetLang); err != nil {
-			return err
+		// With "auto" the pair isn't known until detection runs
+		if job.SourceLang != AutoSourceLang && job.SourceLang != job.TargetLang {
+			if err := p.translator.CheckLanguagePackage(job.SourceLang, job.TargetLang); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -672,7 +1494,7 @@ func (p *Pipeline) ValidateJob(job *models.TranslationJob) error {
 		if err := p.cosyvoice.CheckInstalled(); err != nil {
 			return err
 		}
-		if !p.cosyvoice.HasVoiceSample() {
+		if !p.cosyvoice.HasVoiceSample() && !p.config.AutoVoiceReference {
 			return fmt.Errorf("voice sample required for CosyVoice")
 		}
 	case "edge-tts":
@@ -697,14 +1519,66 @@ func (p *Pipeline) ValidateJob(job *models.TranslationJob) error {
 		if err := p.tts.CheckInstalled(); err != nil {
 			return err
 		}
-		if err := p.tts.CheckVoiceModel(); err != nil {
-			return err
+		// The job's own voice, not the default the service was created with
+		if job.Voice == "" {
+			if err := p.tts.CheckVoiceModel(); err != nil {
+				return err
+			}
+		} else if !VoiceModelExists(job.Voice) {
+			return fmt.Errorf("Piper voice %s is not downloaded. Preview it to download it, or pick a downloaded voice", job.Voice)
 		}
 	}
 
 	return nil
 }
 
+// checkLanguages verifies the selected providers handle the job's
+// languages
+func (p *Pipeline) checkLanguages(job *models.TranslationJob) error {
+	sourceLang, targetLang := job.SourceLang, job.TargetLang
+	if sourceLang == "" {
+		sourceLang = p.config.DefaultSourceLang
+	}
+	if targetLang == "" {
+		targetLang = p.config.DefaultTargetLang
+	}
+
+	if sourceLang != AutoSourceLang {
+		if err := checkLanguageSupport(text.StageTranscription, p.getTranscriptionProvider(), sourceLang); err != nil {
+			return err
+		}
+	}
+	if sourceLang != targetLang {
+		if sourceLang != AutoSourceLang {
+			if err := checkLanguageSupport(text.StageTranslation, p.getTranslationProvider(), sourceLang); err != nil {
+				return err
+			}
+		}
+		if err := checkLanguageSupport(text.StageTranslation, p.getTranslationProvider(), targetLang); err != nil {
+			return err
+		}
+	}
+	return checkLanguageSupport(text.StageTTS, p.getTTSProvider(), targetLang)
+}
+
+// checkLanguageSupport returns an error naming the providers that do
+// handle lang when provider doesn't
+func checkLanguageSupport(stage, provider, lang string) error {
+	l, ok := text.LookupLanguage(lang)
+	if !ok {
+		return fmt.Errorf("unknown language %q", lang)
+	}
+	if text.SupportsLanguage(stage, provider, l.Code) {
+		return nil
+	}
+	others := text.ProvidersFor(stage, l.Code)
+	if len(others) == 0 {
+		return fmt.Errorf("no %s provider supports %s", stage, l.Name)
+	}
+	return fmt.Errorf("%s does not support %s for %s; supported by: %s",
+		provider, l.Name, stage, strings.Join(others, ", "))
+}
+
 // CheckDependencies verifies all required tools are installed
 func (p *Pipeline) CheckDependencies() map[string]error {
 	results := make(map[string]error)
@@ -730,8 +1604,11 @@ func (p *Pipeline) CheckDependencies() map[string]error {
 	// Check translation providers
 	results["argos-translate"] = p.translator.CheckInstalled()
 	results["argos-ru-en"] = p.translator.CheckLanguagePackage("ru", "en")
-	if p.deepseek != nil {
-		results["deepseek"] = p.deepseek.CheckAPIKey()
+	if p.llm != nil {
+		results[p.getTranslationProvider()] = p.llm.CheckAPIKey()
+	}
+	if p.libre != nil {
+		results["libretranslate"] = p.libre.CheckInstalled()
 	}
 
 	// Check TTS providers
@@ -750,6 +1627,11 @@ func (p *Pipeline) CheckDependencies() map[string]error {
 		results["fish-audio"] = p.fishAudioTTS.CheckInstalled()
 	}
 
+	// Check speaker diarization
+	if p.diarizer != nil {
+		results["pyannote"] = p.diarizer.CheckInstalled()
+	}
+
 	return results
 }
 
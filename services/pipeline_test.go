@@ -3,6 +3,7 @@ package services
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 	"video-translator/models"
@@ -282,3 +283,39 @@ func TestPipeline_TempDirCreation(t *testing.T) {
 	// Cleanup
 	p.Cleanup()
 }
+
+func TestPipeline_CheckLanguages(t *testing.T) {
+	tests := []struct {
+		transcription, translation, tts string
+		source, target                  string
+		wantErr                         string // Substring; "" = supported
+	}{
+		{"whisper-cpp", "argos", "edge-tts", "ru", "en", ""},
+		{"whisper-cpp", "argos", "edge-tts", "auto", "de", ""},
+		{"groq", "openai", "openai", "jv", "haw", ""},
+		{"whisper-cpp", "argos", "openai", "en", "haw", "argos does not support Hawaiian for translation; supported by: deepseek"},
+		{"whisper-cpp", "openai", "cosyvoice", "en", "fr", "cosyvoice does not support French for tts; supported by:"},
+		{"whisper-cpp", "openai", "edge-tts", "en", "en", ""}, // No translation needed
+		{"whisper-cpp", "openai", "openai", "xx", "en", `unknown language "xx"`},
+	}
+	for _, tt := range tests {
+		config := models.DefaultConfig()
+		config.TranscriptionProvider = tt.transcription
+		config.TranslationProvider = tt.translation
+		config.TTSProvider = tt.tts
+		p := &Pipeline{config: config}
+
+		job := models.NewTranslationJob("video.mp4")
+		job.SourceLang, job.TargetLang = tt.source, tt.target
+		err := p.checkLanguages(job)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s→%s with %s/%s/%s: %v", tt.source, tt.target, tt.transcription, tt.translation, tt.tts, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s→%s with %s/%s/%s: error = %v, want %q", tt.source, tt.target, tt.transcription, tt.translation, tt.tts, err, tt.wantErr)
+		}
+	}
+}
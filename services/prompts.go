@@ -0,0 +1,274 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// Prompt template names; a file named <name>.tmpl in the prompt directory
+// replaces the built-in template of that name
+const (
+	PromptTranslate         = "translate"
+	PromptTranslateEmotions = "translate_emotions"
+	PromptShorten           = "shorten"
+	PromptDocumentBrief     = "document_brief"
+)
+
+// PromptData is what prompt templates are rendered with
+type PromptData struct {
+	SourceLanguage string // Language names ("Russian"), not codes
+	TargetLanguage string // Includes the locale variant ("Brazilian Portuguese")
+
+	Profile models.TranslationProfile // Raw profile fields, for custom templates
+	Style   string                    // Profile rendered as a prompt section ("" when empty)
+	Context string                    // Glossary, memory, brief and neighbouring lines
+	Input   string                    // JSON segments, or the transcript for the brief
+}
+
+// PromptTemplates holds the prompt templates used by LLM translation
+type PromptTemplates struct {
+	templates map[string]*template.Template
+}
+
+// DefaultPromptDir returns where prompt overrides are read from
+func DefaultPromptDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "video-translator", "prompts")
+}
+
+var builtinPromptSources = map[string]string{
+	PromptTranslate:         standardTranslationPrompt,
+	PromptTranslateEmotions: emotionTranslationPrompt,
+	PromptShorten:           shortenTranslationPrompt,
+	PromptDocumentBrief:     documentBriefPrompt,
+}
+
+// defaultPrompts are the built-in templates
+var defaultPrompts = mustBuiltinPrompts()
+
+func mustBuiltinPrompts() *PromptTemplates {
+	t := &PromptTemplates{templates: make(map[string]*template.Template)}
+	for name, src := range builtinPromptSources {
+		t.templates[name] = template.Must(template.New(name).Option("missingkey=error").Parse(src))
+	}
+	return t
+}
+
+// LoadPromptTemplates returns the built-in templates with any <name>.tmpl
+// files in dir taking their place. A missing dir means no overrides; a
+// template that doesn't parse is an error.
+func LoadPromptTemplates(dir string) (*PromptTemplates, error) {
+	t := &PromptTemplates{templates: make(map[string]*template.Template)}
+	for name, tmpl := range defaultPrompts.templates {
+		t.templates[name] = tmpl
+	}
+
+	for name := range builtinPromptSources {
+		path := filepath.Join(dir, name+".tmpl")
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt template: %w", err)
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+		}
+		logger.LogInfo("Prompts: using %s", path)
+		t.templates[name] = tmpl
+	}
+	return t, nil
+}
+
+// Render executes the named template
+func (t *PromptTemplates) Render(name string, data PromptData) (string, error) {
+	if t == nil {
+		t = defaultPrompts
+	}
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt template %q", name)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// localeNames spells out regional variants so the model can't miss them
+var localeNames = map[string]string{
+	"pt-BR":  "Brazilian Portuguese",
+	"pt-PT":  "European Portuguese",
+	"es-ES":  "European Spanish (Spain)",
+	"es-MX":  "Mexican Spanish",
+	"es-419": "Latin American Spanish",
+	"en-US":  "American English",
+	"en-GB":  "British English",
+	"fr-FR":  "French (France)",
+	"fr-CA":  "Canadian French",
+	"de-DE":  "German (Germany)",
+	"de-AT":  "Austrian German",
+	"de-CH":  "Swiss Standard German",
+	"zh-CN":  "Simplified Chinese (Mainland China)",
+	"zh-TW":  "Traditional Chinese (Taiwan)",
+}
+
+// TranslationLocales returns the known regional variants of lang (all of
+// them when lang is empty), sorted
+func TranslationLocales(lang string) []string {
+	var locales []string
+	for locale := range localeNames {
+		if lang == "" || primaryLangTag(locale) == lang {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// targetLanguageName names lang, or the profile's regional variant of it.
+// A locale for a different language (a profile reused across targets) is
+// ignored.
+func targetLanguageName(lang string, profile models.TranslationProfile) string {
+	if profile.Locale == "" || primaryLangTag(profile.Locale) != lang {
+		return text.GetLanguageName(lang)
+	}
+	if name, ok := localeNames[profile.Locale]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", text.GetLanguageName(lang), profile.Locale)
+}
+
+// renderStyle turns a profile into prompt instructions for translating
+// into lang, empty when the profile sets nothing
+func renderStyle(profile models.TranslationProfile, lang string) string {
+	var lines []string
+	switch profile.Formality {
+	case models.FormalityFormal:
+		lines = append(lines, `Address people formally: use the polite form of "you" where the language has one (Sie, vous, usted, вы) and formal verb forms.`)
+	case models.FormalityInformal:
+		lines = append(lines, `Address people informally: use the familiar form of "you" where the language has one (du, tu, tú, ты) and casual verb forms.`)
+	}
+	if r := strings.TrimSpace(profile.Register); r != "" {
+		lines = append(lines, fmt.Sprintf("Register: %s.", r))
+	}
+	if a := strings.TrimSpace(profile.Audience); a != "" {
+		lines = append(lines, fmt.Sprintf("Target audience: %s. Choose vocabulary they will understand.", a))
+	}
+	if profile.Locale != "" && primaryLangTag(profile.Locale) == lang {
+		lines = append(lines, fmt.Sprintf("Write %s (%s): use its spelling, vocabulary and forms of address.",
+			targetLanguageName(lang, profile), profile.Locale))
+	}
+
+	var b strings.Builder
+	if len(lines) > 0 {
+		b.WriteString("STYLE:\n")
+		for _, l := range lines {
+			b.WriteString("- " + l + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if instr := strings.TrimSpace(profile.Instructions); instr != "" {
+		b.WriteString("ADDITIONAL INSTRUCTIONS:\n" + instr + "\n\n")
+	}
+	return b.String()
+}
+
+// Built-in prompts, in text/template syntax with PromptData
+
+const standardTranslationPrompt = `Translate the following video subtitles from {{.SourceLanguage}} to {{.TargetLanguage}}.
+
+The input is a JSON array of segments, each with an "id" and "text".
+Translate every segment on its own and keep its id unchanged.
+Keep the translations natural and conversational for video dubbing.
+A segment with "max_chars" is spoken in a fixed time: keep its translation within
+that many characters, paraphrasing concisely rather than dropping meaning.
+A segment with "fix" was translated before and rejected; follow the correction in "fix".
+
+Reply with a JSON object and nothing else, in exactly this shape:
+{"translations": [{"id": 1, "text": "translated text"}, ...]}
+
+Return exactly one entry per input id. Do not merge, split, skip or reorder segments.
+
+{{.Style}}{{.Context}}INPUT:
+{{.Input}}`
+
+const emotionTranslationPrompt = `You are translating video subtitles from {{.SourceLanguage}} to {{.TargetLanguage}} for text-to-speech dubbing.
+
+TASK: For each segment, provide BOTH an emotion tag AND the translation.
+
+WHY EMOTIONS MATTER:
+- This translation will be spoken by an AI voice
+- The voice uses the emotion tag to adjust its tone, pitch and pace so speech sounds natural
+- Without emotions, the dubbed audio sounds flat and robotic
+- Matching emotions to content makes the video feel professionally dubbed
+
+AVAILABLE EMOTIONS (pick the most fitting one):
+- happy - cheerful, positive content
+- sad - melancholic, disappointing news
+- excited - energetic, enthusiastic announcements
+- calm - neutral explanations, instructions
+- angry - frustrated, complaints
+- surprised - unexpected information
+- nervous - uncertain, worried
+- confident - assertive statements
+- curious - questions, wondering
+- empathetic - understanding, supportive
+
+The input is a JSON array of segments, each with an "id" and "text".
+
+Reply with a JSON object and nothing else, in exactly this shape:
+{"translations": [{"id": 1, "emotion": "happy", "text": "This is amazing news!"}, ...]}
+
+RULES:
+1. Return exactly one entry per input id, with the id unchanged
+2. Do not merge, split, skip or reorder segments
+3. Use ONLY emotions from the list above (lowercase, no parentheses)
+4. Use "calm" for neutral/informational content (most common)
+5. Match emotion to the MEANING of the text, not just keywords
+6. Keep translations natural and conversational
+7. A segment with "max_chars" is spoken in a fixed time: keep its translation
+   within that many characters, paraphrasing concisely rather than dropping meaning
+8. A segment with "fix" was translated before and rejected; follow the correction in "fix"
+
+{{.Style}}{{.Context}}INPUT:
+{{.Input}}`
+
+const shortenTranslationPrompt = `The following {{.TargetLanguage}} dubbing lines are too long to be spoken in the time available.
+
+The input is a JSON array of segments with an "id", the original "source" line, the current
+translation "text" and "max_chars", the most characters that can be spoken in time.
+Rewrite each "text" as a shorter paraphrase of at most "max_chars" characters that keeps
+the meaning of "source". Drop filler words first; keep names and key facts.
+
+Reply with a JSON object and nothing else, in exactly this shape:
+{"translations": [{"id": 1, "text": "shorter text"}, ...]}
+
+Return exactly one entry per input id, with the id unchanged.
+
+{{.Style}}{{.Context}}INPUT:
+{{.Input}}`
+
+const documentBriefPrompt = `Below is the {{.SourceLanguage}} transcript of a video that is about to be translated for dubbing.
+
+Write a brief for the translator in English, at most 120 words, covering:
+- the topic and genre
+- the speakers: names, gender, and how they address each other (formal or informal)
+- the tone and register
+- recurring names and terms that must be translated consistently
+
+Reply with the brief only.
+
+TRANSCRIPT:
+{{.Input}}`
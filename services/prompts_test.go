@@ -0,0 +1,85 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"video-translator/models"
+)
+
+func TestLoadPromptTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	// No overrides: built-in prompts
+	prompts, err := LoadPromptTemplates(filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates: %v", err)
+	}
+	got, err := prompts.Render(PromptTranslate, PromptData{SourceLanguage: "Russian", TargetLanguage: "English", Input: "[]"})
+	if err != nil || !strings.Contains(got, "from Russian to English") || !strings.HasSuffix(got, "INPUT:\n[]") {
+		t.Errorf("built-in prompt = %q, %v", got, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "shorten.tmpl"), []byte("Shorten for {{.Profile.Audience}} in {{.TargetLanguage}}:\n{{.Input}}"), 0644)
+	prompts, err = LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates: %v", err)
+	}
+	got, _ = prompts.Render(PromptShorten, PromptData{TargetLanguage: "German", Profile: models.TranslationProfile{Audience: "kids"}, Input: "x"})
+	if got != "Shorten for kids in German:\nx" {
+		t.Errorf("override = %q", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "translate.tmpl"), []byte("{{.Nope"), 0644)
+	if _, err := LoadPromptTemplates(dir); err == nil {
+		t.Error("unparseable template should fail to load")
+	}
+
+	os.WriteFile(filepath.Join(dir, "translate.tmpl"), []byte("{{.Unknown}}"), 0644)
+	prompts, _ = LoadPromptTemplates(dir)
+	if _, err := prompts.Render(PromptTranslate, PromptData{}); err == nil {
+		t.Error("unknown field should fail to render")
+	}
+}
+
+func TestRenderStyle(t *testing.T) {
+	if renderStyle(models.TranslationProfile{Name: "default"}, "pt") != "" {
+		t.Error("empty profile should add nothing")
+	}
+
+	profile := models.TranslationProfile{
+		Formality:    models.FormalityInformal,
+		Register:     "casual",
+		Audience:     "teenagers",
+		Locale:       "pt-BR",
+		Instructions: "Keep English loanwords.",
+	}
+	style := renderStyle(profile, "pt")
+	for _, want := range []string{"familiar form", "Register: casual.", "Target audience: teenagers", "Brazilian Portuguese (pt-BR)", "ADDITIONAL INSTRUCTIONS:\nKeep English loanwords."} {
+		if !strings.Contains(style, want) {
+			t.Errorf("style lacks %q:\n%s", want, style)
+		}
+	}
+
+	// A locale for another language is ignored
+	if strings.Contains(renderStyle(profile, "es"), "pt-BR") {
+		t.Error("pt-BR locale applied to Spanish")
+	}
+	if got := targetLanguageName("pt", profile); got != "Brazilian Portuguese" {
+		t.Errorf("targetLanguageName = %q", got)
+	}
+	if got := targetLanguageName("es", models.TranslationProfile{Locale: "es-CL"}); got != "Spanish (es-CL)" {
+		t.Errorf("unknown locale name = %q", got)
+	}
+}
+
+func TestTranslationLocales(t *testing.T) {
+	if got := TranslationLocales("pt"); strings.Join(got, ",") != "pt-BR,pt-PT" {
+		t.Errorf("TranslationLocales(pt) = %v", got)
+	}
+	if len(TranslationLocales("")) != len(localeNames) {
+		t.Error("empty language should list every locale")
+	}
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"video-translator/internal/logger"
+)
+
+// PythonWorkerConfig describes a long-lived Python worker process
+type PythonWorkerConfig struct {
+	Name       string // For logs and errors ("Argos", "CosyVoice")
+	PythonPath string
+	Dir        string // Working directory (empty = current)
+
+	// Script defines handle_<method>(params) functions and any one-time
+	// setup (imports, model loading) at module level. It is run inside
+	// pythonWorkerHarness, which answers requests by calling the handlers.
+	Script string
+
+	StartTimeout   time.Duration // Until the script's setup finishes
+	RequestTimeout time.Duration // Per request; the process is killed on expiry
+
+	// HealthCheckAfter pings a worker that has been idle this long before
+	// reusing it (0 = never)
+	HealthCheckAfter time.Duration
+}
+
+// PythonWorker keeps a Python process running so imports and models load
+// once instead of once per call. Requests and responses are single JSON
+// lines over stdin/stdout:
+//
+//	→ {"id": 1, "method": "translate", "params": {...}}
+//	← {"id": 1, "result": ...}  or  {"id": 1, "error": "..."}
+//
+// The process starts on first use and is restarted if it dies; a request
+// that finds it dead is retried once on a fresh process. Requests are
+// serialized; use several workers for parallelism.
+type PythonWorker struct {
+	cfg PythonWorkerConfig
+
+	mu       sync.Mutex
+	proc     *workerProcess
+	nextID   int64
+	lastUsed time.Time
+	closed   bool
+}
+
+// workerProcess is one run of the worker script
+type workerProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan workerResponse
+	exited    chan struct{} // Closed when the process exits
+	stopped   chan struct{} // Closed on kill, so a blocked reader gives up
+	stopOnce  sync.Once
+	stderr    *stderrTail
+}
+
+type workerRequest struct {
+	ID     int64       `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+type workerResponse struct {
+	ID     int64           `json:"id"`
+	Ready  bool            `json:"ready,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// errWorkerDied marks failures caused by the process exiting, which are
+// worth one retry on a new process
+type errWorkerDied struct{ msg string }
+
+func (e *errWorkerDied) Error() string { return e.msg }
+
+// pythonWorkerHarness wraps a worker script with the request loop. stdout
+// is reserved for protocol lines: anything the libraries print goes to
+// stderr instead.
+const pythonWorkerHarness = `import sys, json, traceback
+_proto_out = sys.stdout
+sys.stdout = sys.stderr
+
+def _send(obj):
+    _proto_out.write(json.dumps(obj, ensure_ascii=False) + "\n")
+    _proto_out.flush()
+
+def handle_ping(params):
+    return "pong"
+
+%s
+
+_send({"ready": True})
+for _line in sys.stdin:
+    _line = _line.strip()
+    if not _line:
+        continue
+    _id = None
+    try:
+        _req = json.loads(_line)
+        _id = _req.get("id")
+        _handler = globals().get("handle_" + _req.get("method", ""))
+        if _handler is None:
+            raise ValueError("unknown method: %%s" %% _req.get("method"))
+        _send({"id": _id, "result": _handler(_req.get("params") or {})})
+    except Exception as _e:
+        traceback.print_exc()
+        _send({"id": _id, "error": "%%s: %%s" %% (type(_e).__name__, _e)})
+`
+
+// NewPythonWorker creates a worker; the process starts on first Call
+func NewPythonWorker(cfg PythonWorkerConfig) *PythonWorker {
+	if cfg.Name == "" {
+		cfg.Name = "Python worker"
+	}
+	if cfg.PythonPath == "" {
+		cfg.PythonPath = "python3"
+	}
+	if cfg.StartTimeout <= 0 {
+		cfg.StartTimeout = 2 * time.Minute
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 5 * time.Minute
+	}
+	return &PythonWorker{cfg: cfg}
+}
+
+// Call runs method with params and decodes its result into result (which
+// may be nil to discard it)
+func (w *PythonWorker) Call(method string, params, result interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("%s: worker is closed", w.cfg.Name)
+	}
+
+	if w.proc != nil && w.cfg.HealthCheckAfter > 0 && time.Since(w.lastUsed) > w.cfg.HealthCheckAfter {
+		if err := w.call("ping", nil, nil, 30*time.Second); err != nil {
+			logger.LogError("%s: health check failed, restarting worker: %v", w.cfg.Name, err)
+			w.stopLocked()
+		}
+	}
+
+	err := w.call(method, params, result, w.cfg.RequestTimeout)
+	if _, died := err.(*errWorkerDied); died {
+		logger.LogError("%s: %v; restarting worker and retrying", w.cfg.Name, err)
+		err = w.call(method, params, result, w.cfg.RequestTimeout)
+	}
+	w.lastUsed = time.Now()
+	return err
+}
+
+// Ping checks that the worker is up, starting it if needed
+func (w *PythonWorker) Ping() error {
+	return w.Call("ping", nil, nil)
+}
+
+// call sends one request, starting the process if needed; callers hold mu
+func (w *PythonWorker) call(method string, params, result interface{}, timeout time.Duration) error {
+	if w.proc == nil {
+		if err := w.startLocked(); err != nil {
+			return err
+		}
+	}
+	proc := w.proc
+
+	w.nextID++
+	line, err := json.Marshal(workerRequest{ID: w.nextID, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode request: %w", w.cfg.Name, err)
+	}
+	if _, err := proc.stdin.Write(append(line, '\n')); err != nil {
+		w.stopLocked()
+		return &errWorkerDied{fmt.Sprintf("%s: worker stopped accepting requests: %v", w.cfg.Name, err)}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case resp, ok := <-proc.responses:
+			if !ok {
+				w.stopLocked()
+				return &errWorkerDied{fmt.Sprintf("%s: worker exited during %s: %s", w.cfg.Name, method, proc.stderr.String())}
+			}
+			if resp.ID != w.nextID {
+				// Reply to an earlier request that timed out
+				continue
+			}
+			if resp.Error != "" {
+				return fmt.Errorf("%s: %s failed: %s", w.cfg.Name, method, resp.Error)
+			}
+			if result != nil {
+				if err := json.Unmarshal(resp.Result, result); err != nil {
+					return fmt.Errorf("%s: bad %s result: %w", w.cfg.Name, method, err)
+				}
+			}
+			return nil
+		case <-timer.C:
+			// The process is in an unknown state; the next call gets a new one
+			w.stopLocked()
+			return fmt.Errorf("%s: %s timed out after %v", w.cfg.Name, method, timeout)
+		}
+	}
+}
+
+// startLocked launches the process and waits for its ready line
+func (w *PythonWorker) startLocked() error {
+	cmd := exec.Command(w.cfg.PythonPath, "-u", "-c", fmt.Sprintf(pythonWorkerHarness, w.cfg.Script))
+	cmd.Dir = w.cfg.Dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.cfg.Name, err)
+	}
+	// An io.Pipe rather than StdoutPipe: Wait then waits for all output to
+	// be read instead of racing the reader
+	stdout, stdoutWriter := io.Pipe()
+	proc := &workerProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan workerResponse, 1),
+		exited:    make(chan struct{}),
+		stopped:   make(chan struct{}),
+		stderr:    &stderrTail{name: w.cfg.Name},
+	}
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = proc.stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: failed to start %s: %w", w.cfg.Name, w.cfg.PythonPath, err)
+	}
+	started := time.Now()
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var resp workerResponse
+			if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+				logger.LogDebug("%s: ignoring non-protocol output: %s", w.cfg.Name, scanner.Text())
+				continue
+			}
+			select {
+			case proc.responses <- resp:
+			case <-proc.stopped:
+			}
+		}
+		// Drain so the process never blocks writing to a dead reader
+		io.Copy(io.Discard, stdout)
+		close(proc.responses)
+	}()
+	go func() {
+		cmd.Wait()
+		stdoutWriter.Close()
+		close(proc.exited)
+	}()
+
+	timer := time.NewTimer(w.cfg.StartTimeout)
+	defer timer.Stop()
+	select {
+	case resp, ok := <-proc.responses:
+		if !ok || !resp.Ready {
+			proc.kill()
+			return fmt.Errorf("%s: worker failed to start: %s", w.cfg.Name, proc.stderr.String())
+		}
+	case <-timer.C:
+		proc.kill()
+		return fmt.Errorf("%s: worker not ready after %v: %s", w.cfg.Name, w.cfg.StartTimeout, proc.stderr.String())
+	}
+
+	logger.LogInfo("%s: worker started (pid %d, ready in %v)", w.cfg.Name, cmd.Process.Pid, time.Since(started).Round(time.Millisecond))
+	w.proc = proc
+	w.lastUsed = time.Now()
+	return nil
+}
+
+// stopLocked kills the current process, if any
+func (w *PythonWorker) stopLocked() {
+	if w.proc != nil {
+		w.proc.kill()
+		w.proc = nil
+	}
+}
+
+// Close shuts the worker down: stdin is closed so the script finishes its
+// current request and exits, and it is killed if it hasn't within a few
+// seconds. Calls after Close fail.
+func (w *PythonWorker) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.proc == nil {
+		return nil
+	}
+	proc := w.proc
+	w.proc = nil
+
+	proc.stdin.Close()
+	select {
+	case <-proc.exited:
+	case <-time.After(5 * time.Second):
+		logger.LogError("%s: worker did not exit, killing it", w.cfg.Name)
+		proc.kill()
+	}
+	return nil
+}
+
+// kill stops the process and waits for it to be reaped
+func (p *workerProcess) kill() {
+	p.stopOnce.Do(func() { close(p.stopped) })
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	<-p.exited
+}
+
+// stderrTail logs a worker's stderr and keeps its end for error messages
+type stderrTail struct {
+	name string
+	mu   sync.Mutex
+	buf  []byte
+}
+
+// stderrTailBytes is how much stderr is kept, enough for a traceback
+const stderrTailBytes = 4096
+
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line != "" {
+			logger.LogDebug("%s stderr: %s", t.name, line)
+		}
+	}
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > stderrTailBytes {
+		t.buf = t.buf[len(t.buf)-stderrTailBytes:]
+	}
+	return len(p), nil
+}
+
+func (t *stderrTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := strings.TrimSpace(string(t.buf))
+	if s == "" {
+		return "no output"
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWorkerScript echoes, fails, prints noise, crashes once (when the
+// marker file doesn't exist yet) and sleeps on request
+const testWorkerScript = `import os, time
+
+def handle_echo(params):
+    print("library noise on stdout")
+    return {"texts": [t.upper() for t in params["texts"]], "pid": os.getpid()}
+
+def handle_fail(params):
+    raise ValueError("bad input")
+
+def handle_crash_once(params):
+    if not os.path.exists(params["marker"]):
+        open(params["marker"], "w").close()
+        os._exit(3)
+    return os.getpid()
+
+def handle_sleep(params):
+    time.sleep(params["seconds"])
+    return "woke"
+`
+
+func newTestWorker(t *testing.T) *PythonWorker {
+	t.Helper()
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+	w := NewPythonWorker(PythonWorkerConfig{
+		Name:           "Test worker",
+		PythonPath:     python,
+		Script:         testWorkerScript,
+		StartTimeout:   30 * time.Second,
+		RequestTimeout: 10 * time.Second,
+	})
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestPythonWorker_Call(t *testing.T) {
+	w := newTestWorker(t)
+
+	type echo struct {
+		Texts []string `json:"texts"`
+		PID   int      `json:"pid"`
+	}
+	var first, second echo
+	if err := w.Call("echo", map[string]interface{}{"texts": []string{"héllo", "a\nb"}}, &first); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if strings.Join(first.Texts, "|") != "HÉLLO|A\nB" {
+		t.Errorf("texts = %q", first.Texts)
+	}
+	if err := w.Call("echo", map[string]interface{}{"texts": []string{}}, &second); err != nil {
+		t.Fatal(err)
+	}
+	if first.PID != second.PID {
+		t.Error("calls should reuse the same process")
+	}
+
+	err := w.Call("fail", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "ValueError: bad input") {
+		t.Errorf("handler error = %v", err)
+	}
+	if err := w.Call("nope", nil, nil); err == nil || !strings.Contains(err.Error(), "unknown method") {
+		t.Errorf("unknown method error = %v", err)
+	}
+	if err := w.Ping(); err != nil {
+		t.Errorf("Ping after handler errors: %v", err)
+	}
+}
+
+func TestPythonWorker_RestartsAfterCrash(t *testing.T) {
+	w := newTestWorker(t)
+	if err := w.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first attempt kills the process; Call retries on a new one
+	var pid int
+	marker := filepath.Join(t.TempDir(), "crashed")
+	if err := w.Call("crash_once", map[string]string{"marker": marker}, &pid); err != nil {
+		t.Fatalf("Call after crash: %v", err)
+	}
+	if pid == 0 {
+		t.Error("no result from the restarted worker")
+	}
+}
+
+func TestPythonWorker_Timeout(t *testing.T) {
+	w := newTestWorker(t)
+	w.cfg.RequestTimeout = 300 * time.Millisecond
+
+	err := w.Call("sleep", map[string]float64{"seconds": 5}, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+
+	// The stuck process was killed; the next call gets a fresh one
+	var out string
+	if err := w.Call("sleep", map[string]float64{"seconds": 0}, &out); err != nil || out != "woke" {
+		t.Errorf("call after timeout = %q, %v", out, err)
+	}
+}
+
+func TestPythonWorker_StartFailureAndClose(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+	broken := NewPythonWorker(PythonWorkerConfig{Name: "Broken", PythonPath: python, Script: "import no_such_module_xyz"})
+	err = broken.Ping()
+	if err == nil || !strings.Contains(err.Error(), "no_such_module_xyz") {
+		t.Errorf("start error = %v, want the import error", err)
+	}
+
+	w := newTestWorker(t)
+	if err := w.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if err := w.Ping(); err == nil {
+		t.Error("Call after Close should fail")
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	internalhttp "video-translator/internal/http"
+	"video-translator/internal/logger"
+	"video-translator/models"
+)
+
+const (
+	rateLimitWindow = time.Minute
+
+	// Pause after a 429 that doesn't say how long to wait
+	rateLimitDefaultBackoff = 2 * time.Second
+
+	// Concurrency is halved at most once per interval, so a burst of 429s
+	// from requests that were already in flight counts as one signal
+	rateLimitDecreaseInterval = 2 * time.Second
+)
+
+// RateCost is what one request is expected to use
+type RateCost struct {
+	Tokens     int
+	Characters int
+}
+
+// rateEvent is usage counted against the last minute
+type rateEvent struct {
+	at         time.Time
+	requests   int
+	tokens     int
+	characters int
+}
+
+// RateLimiter keeps one provider within its per-minute limits across all
+// jobs. Requests are counted when they start; tokens and characters when
+// the usage ledger records them, with in-flight requests holding their
+// estimate until then. Concurrency adapts AIMD-style: it halves when the
+// provider answers 429 and grows back by about one per round of successes.
+type RateLimiter struct {
+	name string
+
+	mu           sync.Mutex
+	limit        models.RateLimit
+	window       []rateEvent
+	inFlight     int
+	reserved     RateCost
+	concurrency  float64 // Current cap, 0 = unlimited
+	pausedUntil  time.Time
+	lastDecrease time.Time
+	changed      chan struct{} // Closed when a request finishes
+}
+
+// NewRateLimiter creates a limiter named for logs
+func NewRateLimiter(name string, limit models.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		name:        name,
+		limit:       limit,
+		concurrency: float64(limit.MaxConcurrency),
+		changed:     make(chan struct{}),
+	}
+}
+
+// SetLimit changes the limits, keeping what has been used this minute
+func (l *RateLimiter) SetLimit(limit models.RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit.MaxConcurrency > 0 && (l.concurrency == 0 || l.concurrency > float64(limit.MaxConcurrency)) {
+		l.concurrency = float64(limit.MaxConcurrency)
+	}
+	l.limit = limit
+	l.notifyLocked()
+}
+
+// Acquire blocks until a request costing cost may start. Every Acquire
+// must be followed by a Release.
+func (l *RateLimiter) Acquire(cost RateCost) {
+	if l == nil {
+		return
+	}
+	for {
+		l.mu.Lock()
+		wait := l.waitLocked(cost, time.Now())
+		if wait == 0 {
+			l.inFlight++
+			l.reserved.Tokens += cost.Tokens
+			l.reserved.Characters += cost.Characters
+			l.window = append(l.window, rateEvent{at: time.Now(), requests: 1})
+			l.mu.Unlock()
+			return
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-changed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// waitLocked returns how long until a request costing cost could start, 0
+// if it can start now
+func (l *RateLimiter) waitLocked(cost RateCost, now time.Time) time.Duration {
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.concurrency > 0 && l.inFlight >= max(1, int(l.concurrency)) {
+		return rateLimitWindow // Until a request finishes
+	}
+
+	l.pruneLocked(now)
+	var used rateEvent
+	for _, e := range l.window {
+		used.requests += e.requests
+		used.tokens += e.tokens
+		used.characters += e.characters
+	}
+	over := func(used, reserved, want, limit int) bool {
+		// A request larger than the whole allowance goes alone
+		return limit > 0 && used+reserved+want > limit && used+reserved > 0
+	}
+	if over(used.requests, 0, 1, l.limit.RequestsPerMinute) ||
+		over(used.tokens, l.reserved.Tokens, cost.Tokens, l.limit.TokensPerMinute) ||
+		over(used.characters, l.reserved.Characters, cost.Characters, l.limit.CharactersPerMinute) {
+		if len(l.window) > 0 {
+			// Until the oldest usage leaves the window
+			return max(l.window[0].at.Add(rateLimitWindow).Sub(now), time.Millisecond)
+		}
+		return rateLimitWindow // Only in-flight reservations; until one finishes
+	}
+	return 0
+}
+
+func (l *RateLimiter) pruneLocked(now time.Time) {
+	cutoff := now.Add(-rateLimitWindow)
+	i := 0
+	for i < len(l.window) && !l.window[i].at.After(cutoff) {
+		i++
+	}
+	l.window = l.window[i:]
+}
+
+// Release ends a request started with Acquire(cost). throttled reports a
+// 429, with the wait the provider asked for (0 if it didn't say).
+func (l *RateLimiter) Release(cost RateCost, throttled bool, retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.inFlight--
+	l.reserved.Tokens -= cost.Tokens
+	l.reserved.Characters -= cost.Characters
+
+	if throttled {
+		if retryAfter <= 0 {
+			retryAfter = rateLimitDefaultBackoff
+		}
+		if until := now.Add(retryAfter); until.After(l.pausedUntil) {
+			l.pausedUntil = until
+		}
+		if now.Sub(l.lastDecrease) >= rateLimitDecreaseInterval {
+			before := l.concurrency
+			if before == 0 {
+				before = float64(l.inFlight + 1)
+			}
+			l.concurrency = max(1, before/2)
+			l.lastDecrease = now
+			logger.LogInfo("Rate limit: %s throttled, pausing %v and lowering concurrency to %d",
+				l.name, retryAfter.Round(time.Millisecond), int(l.concurrency))
+		}
+	} else if l.concurrency > 0 {
+		l.concurrency += 1 / l.concurrency
+		if l.limit.MaxConcurrency > 0 {
+			l.concurrency = min(l.concurrency, float64(l.limit.MaxConcurrency))
+		}
+	}
+	l.notifyLocked()
+}
+
+// Observe counts usage the ledger recorded against the last minute
+func (l *RateLimiter) Observe(r UsageRecord) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.window = append(l.window, rateEvent{
+		at:         time.Now(),
+		tokens:     r.InputTokens + r.OutputTokens,
+		characters: r.Characters,
+	})
+	l.notifyLocked()
+}
+
+// Concurrency returns the current cap on requests in flight (0 = none)
+func (l *RateLimiter) Concurrency() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.concurrency)
+}
+
+func (l *RateLimiter) notifyLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// rateLimiters are the process-wide limiters by "stage/provider", shared
+// by every pipeline so parallel jobs draw from one allowance
+var rateLimiters = struct {
+	sync.Mutex
+	byKey map[string]*RateLimiter
+}{byKey: make(map[string]*RateLimiter)}
+
+// ConfigureRateLimits applies limits to the shared limiters. Limiters that
+// already exist keep their state, so running jobs pick up new limits.
+func ConfigureRateLimits(limits map[string]models.RateLimit) {
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	for key := range rateLimiters.byKey {
+		if _, ok := limits[key]; !ok {
+			delete(rateLimiters.byKey, key)
+		}
+	}
+	for key, limit := range limits {
+		if l, ok := rateLimiters.byKey[key]; ok {
+			l.SetLimit(limit)
+		} else {
+			rateLimiters.byKey[key] = NewRateLimiter(key, limit)
+		}
+	}
+}
+
+// rateLimiterFor returns the shared limiter of a provider at a stage, nil
+// (no limiting) when it has no configured limits
+func rateLimiterFor(stage, provider string) *RateLimiter {
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	return rateLimiters.byKey[stage+"/"+provider]
+}
+
+// doRateLimited sends req once l admits it, and tells l whether the
+// provider throttled it
+func doRateLimited(l *RateLimiter, client *http.Client, req *http.Request, cost RateCost) (*http.Response, error) {
+	l.Acquire(cost)
+	resp, err := client.Do(req)
+	throttled := err == nil && resp.StatusCode == http.StatusTooManyRequests
+	var retryAfter time.Duration
+	if throttled {
+		retryAfter = internalhttp.ParseRetryAfter(resp.Header)
+	}
+	l.Release(cost, throttled, retryAfter)
+	return resp, err
+}
+
+// promptRateCost estimates the tokens of a chat request: the prompt, and
+// a reply about as long
+func promptRateCost(prompt string) RateCost {
+	return RateCost{Tokens: int(2 * float64(utf8.RuneCountInString(prompt)) / budgetCharsPerToken)}
+}
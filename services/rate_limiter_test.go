@@ -0,0 +1,196 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func TestRateLimiter_RequestsPerMinute(t *testing.T) {
+	l := NewRateLimiter("test", models.RateLimit{RequestsPerMinute: 2})
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if wait := l.waitLocked(RateCost{}, now); wait != 0 {
+			t.Fatalf("request %d waits %v", i+1, wait)
+		}
+		l.Acquire(RateCost{})
+		l.Release(RateCost{}, false, 0)
+	}
+	now = time.Now()
+	wait := l.waitLocked(RateCost{}, now)
+	if wait <= 0 || wait > time.Minute {
+		t.Errorf("third request waits %v, want until the first leaves the window", wait)
+	}
+	if wait := l.waitLocked(RateCost{}, now.Add(time.Minute+time.Second)); wait != 0 {
+		t.Errorf("a minute later waits %v", wait)
+	}
+}
+
+func TestRateLimiter_TokensFromLedger(t *testing.T) {
+	l := NewRateLimiter("test", models.RateLimit{TokensPerMinute: 1000})
+	now := time.Now()
+
+	// A request bigger than the allowance still goes when nothing else is
+	if wait := l.waitLocked(RateCost{Tokens: 5000}, now); wait != 0 {
+		t.Errorf("oversized lone request waits %v", wait)
+	}
+
+	l.Observe(UsageRecord{InputTokens: 700, OutputTokens: 200})
+	if wait := l.waitLocked(RateCost{Tokens: 50}, now); wait != 0 {
+		t.Errorf("950 of 1000 tokens waits %v", wait)
+	}
+	if wait := l.waitLocked(RateCost{Tokens: 200}, now); wait == 0 {
+		t.Error("1100 of 1000 tokens should wait")
+	}
+
+	// In-flight estimates count until released
+	l2 := NewRateLimiter("test", models.RateLimit{CharactersPerMinute: 100})
+	l2.Acquire(RateCost{Characters: 80})
+	if wait := l2.waitLocked(RateCost{Characters: 30}, now); wait == 0 {
+		t.Error("reserved characters should count")
+	}
+	l2.Release(RateCost{Characters: 80}, false, 0)
+	if wait := l2.waitLocked(RateCost{Characters: 30}, now); wait != 0 {
+		t.Errorf("after release waits %v", wait)
+	}
+}
+
+func TestRateLimiter_AIMD(t *testing.T) {
+	l := NewRateLimiter("test", models.RateLimit{MaxConcurrency: 8})
+	if got := l.Concurrency(); got != 8 {
+		t.Fatalf("initial concurrency = %d", got)
+	}
+
+	// A burst of 429s from requests already in flight halves once
+	for i := 0; i < 4; i++ {
+		l.Acquire(RateCost{})
+	}
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		l.Release(RateCost{}, true, 50*time.Millisecond)
+	}
+	if got := l.Concurrency(); got != 4 {
+		t.Errorf("after throttling concurrency = %d, want 4", got)
+	}
+
+	// Retry-After pauses new requests
+	l.Acquire(RateCost{})
+	if waited := time.Since(start); waited < 40*time.Millisecond {
+		t.Errorf("acquired after %v, want the 50ms pause honored", waited)
+	}
+	l.Release(RateCost{}, false, 0)
+
+	// Successes grow it back, but not past the configured maximum
+	for i := 0; i < 100; i++ {
+		l.Acquire(RateCost{})
+		l.Release(RateCost{}, false, 0)
+	}
+	if got := l.Concurrency(); got != 8 {
+		t.Errorf("after successes concurrency = %d, want 8", got)
+	}
+}
+
+func TestRateLimiter_ConcurrencyCap(t *testing.T) {
+	l := NewRateLimiter("test", models.RateLimit{MaxConcurrency: 2})
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Acquire(RateCost{})
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			l.Release(RateCost{}, false, 0)
+		}()
+	}
+	wg.Wait()
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", p)
+	}
+}
+
+func TestDoRateLimited_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	l := NewRateLimiter("test", models.RateLimit{MaxConcurrency: 10})
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := doRateLimited(l, server.Client(), req, RateCost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := l.Concurrency(); got != 5 {
+		t.Errorf("concurrency = %d, want 5", got)
+	}
+	if wait := l.waitLocked(RateCost{}, time.Now()); wait < 2*time.Second || wait > 3*time.Second {
+		t.Errorf("paused for %v, want the 3s Retry-After", wait)
+	}
+}
+
+func TestRateLimiter_SharedAcrossServices(t *testing.T) {
+	defer ConfigureRateLimits(models.DefaultRateLimits())
+	ConfigureRateLimits(map[string]models.RateLimit{"translation/test": {MaxConcurrency: 1}})
+
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		if n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprint(w, `{"choices": [{"message": {"content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	// Two jobs, each with its own service and workers
+	var wg sync.WaitGroup
+	for job := 0; job < 2; job++ {
+		cfg := testLLMConfig(server.URL)
+		cfg.Provider = "test"
+		s := NewLLMTranslationService(cfg)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := s.chat("hello", false, nil); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if p := peak.Load(); p != 1 {
+		t.Errorf("peak concurrent requests = %d, want 1 across both services", p)
+	}
+
+	// Reconfiguring keeps the limiter and its state
+	l := rateLimiterFor(UsageStageTranslation, "test")
+	ConfigureRateLimits(map[string]models.RateLimit{"translation/test": {MaxConcurrency: 3}})
+	if rateLimiterFor(UsageStageTranslation, "test") != l {
+		t.Error("reconfiguring replaced the limiter")
+	}
+	ConfigureRateLimits(nil)
+	if rateLimiterFor(UsageStageTranslation, "test") != nil {
+		t.Error("limiter without limits should be removed")
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// SegmentReviewer picks out segments a human should check before trusting
+// the dub: lines Whisper was unsure about and translations that look wrong.
+// Thresholds are stricter than HallucinationFilter's since nothing is dropped.
+type SegmentReviewer struct {
+	// Transcription confidence: flag when AvgLogprob is below this or
+	// NoSpeechProb is above NoSpeechThreshold (scored segments only)
+	LogprobThreshold  float64
+	NoSpeechThreshold float64
+
+	// Translation length relative to the source, in characters. Sources
+	// shorter than MinRatioSourceLength are too short for a useful ratio.
+	MinLengthRatio       float64
+	MaxLengthRatio       float64
+	MinRatioSourceLength int
+
+	// Translations faster than this (characters per second of segment
+	// time) can't be spoken in the slot without heavy speed-up
+	MaxCharsPerSecond float64
+
+	// Glossary, when set, flags translations that don't honour its terms
+	Glossary *Glossary
+}
+
+// ReviewItem is one segment on the review list
+type ReviewItem struct {
+	Index       int    `json:"index"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Speaker     string `json:"speaker,omitempty"`
+	Source      string `json:"source"`
+	Translation string `json:"translation"`
+
+	// Confidence is exp(avg_logprob), the mean token probability (0-1).
+	// Omitted when the transcription provider reported no scores.
+	Confidence   *float64 `json:"confidence,omitempty"`
+	AvgLogprob   *float64 `json:"avg_logprob,omitempty"`
+	NoSpeechProb *float64 `json:"no_speech_prob,omitempty"`
+
+	Reasons []string `json:"reasons"`
+}
+
+// ReviewList is the per-job list of suspect segments
+type ReviewList struct {
+	InputFile     string       `json:"input_file"`
+	SourceLang    string       `json:"source_lang"`
+	TargetLang    string       `json:"target_lang"`
+	TotalSegments int          `json:"total_segments"`
+	Items         []ReviewItem `json:"items"`
+}
+
+// NewSegmentReviewer creates a reviewer with thresholds tuned to flag
+// roughly the worst few percent of segments on clean speech
+func NewSegmentReviewer() *SegmentReviewer {
+	return &SegmentReviewer{
+		LogprobThreshold:     -0.8,
+		NoSpeechThreshold:    0.4,
+		MinLengthRatio:       0.4,
+		MaxLengthRatio:       2.5,
+		MinRatioSourceLength: 15,
+		MaxCharsPerSecond:    20,
+	}
+}
+
+// Review pairs each source segment with its translation (by start time) and
+// returns the segments that need a human look, in source order.
+func (r *SegmentReviewer) Review(source, translated models.SubtitleList, sourceLang, targetLang string) *ReviewList {
+	list := &ReviewList{
+		SourceLang:    sourceLang,
+		TargetLang:    targetLang,
+		TotalSegments: len(source),
+		Items:         []ReviewItem{},
+	}
+
+	byStart := make(map[time.Duration]models.Subtitle, len(translated))
+	for _, sub := range translated {
+		byStart[sub.StartTime] = sub
+	}
+
+	for i, src := range source {
+		var reasons []string
+		reasons = append(reasons, r.transcriptionIssues(src)...)
+
+		trans, ok := byStart[src.StartTime]
+		if ok {
+			reasons = append(reasons, r.translationIssues(src, trans, sourceLang, targetLang)...)
+		} else {
+			reasons = append(reasons, "no translation for this segment")
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		item := ReviewItem{
+			Index:       i + 1,
+			Start:       formatReportTime(src.StartTime),
+			End:         formatReportTime(src.EndTime),
+			Speaker:     src.Speaker,
+			Source:      src.Text,
+			Translation: trans.Text,
+			Reasons:     reasons,
+		}
+		if src.HasScores {
+			confidence := math.Exp(src.AvgLogprob)
+			avgLogprob, noSpeech := src.AvgLogprob, src.NoSpeechProb
+			item.Confidence = &confidence
+			item.AvgLogprob = &avgLogprob
+			item.NoSpeechProb = &noSpeech
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	return list
+}
+
+// transcriptionIssues checks the decoder scores of a source segment
+func (r *SegmentReviewer) transcriptionIssues(src models.Subtitle) []string {
+	if !src.HasScores {
+		return nil
+	}
+
+	var reasons []string
+	if src.AvgLogprob < r.LogprobThreshold {
+		reasons = append(reasons, fmt.Sprintf("low transcription confidence (%.0f%%)", math.Exp(src.AvgLogprob)*100))
+	}
+	if src.NoSpeechProb > r.NoSpeechThreshold {
+		reasons = append(reasons, fmt.Sprintf("may not be speech (no_speech_prob %.2f)", src.NoSpeechProb))
+	}
+	return reasons
+}
+
+// translationIssues runs the translation QA checks on one segment pair
+func (r *SegmentReviewer) translationIssues(src, trans models.Subtitle, sourceLang, targetLang string) []string {
+	srcNorm := text.NormalizeForMatch(src.Text)
+	transNorm := text.NormalizeForMatch(trans.Text)
+
+	if transNorm == "" {
+		if srcNorm != "" {
+			return []string{"empty translation"}
+		}
+		return nil
+	}
+
+	var reasons []string
+	for _, v := range r.Glossary.Verify(src.Text, trans.Text) {
+		reasons = append(reasons, "glossary: "+v.String())
+	}
+	if sourceLang != targetLang && srcNorm == transNorm {
+		reasons = append(reasons, "translation is identical to the source (untranslated?)")
+	}
+
+	srcLen := utf8.RuneCountInString(src.Text)
+	transLen := utf8.RuneCountInString(trans.Text)
+	if srcLen >= r.MinRatioSourceLength {
+		ratio := float64(transLen) / float64(srcLen)
+		if ratio < r.MinLengthRatio {
+			reasons = append(reasons, fmt.Sprintf("translation much shorter than source (%.1fx)", ratio))
+		} else if ratio > r.MaxLengthRatio {
+			reasons = append(reasons, fmt.Sprintf("translation much longer than source (%.1fx)", ratio))
+		}
+	}
+
+	// A length budget from the voice's measured rate beats the generic limit
+	if trans.CharBudget > 0 {
+		if !trans.FitsBudget {
+			reasons = append(reasons, fmt.Sprintf("too long to speak in time (%d chars, budget %d)", transLen, trans.CharBudget))
+		}
+	} else if duration := (trans.EndTime - trans.StartTime).Seconds(); duration > 0 {
+		if cps := float64(transLen) / duration; cps > r.MaxCharsPerSecond {
+			reasons = append(reasons, fmt.Sprintf("too long to speak in %.1fs (%.0f chars/sec)", duration, cps))
+		}
+	}
+
+	return reasons
+}
+
+// Summary returns a one-line description for logs and progress messages
+func (l *ReviewList) Summary() string {
+	return fmt.Sprintf("%d of %d segments need review", len(l.Items), l.TotalSegments)
+}
+
+// WriteJSON saves the review list as indented JSON
+func (l *ReviewList) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review list: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write review list: %w", err)
+	}
+	logger.LogInfo("Review: list written to %s", path)
+	return nil
+}
+
+// LoadReviewList reads a review list written by WriteJSON
+func LoadReviewList(path string) (*ReviewList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review list: %w", err)
+	}
+	var list ReviewList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse review list: %w", err)
+	}
+	return &list, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func reviewSub(startSec, endSec float64, s string) models.Subtitle {
+	return models.Subtitle{
+		StartTime: time.Duration(startSec * float64(time.Second)),
+		EndTime:   time.Duration(endSec * float64(time.Second)),
+		Text:      s,
+	}
+}
+
+func TestSegmentReviewer_Review(t *testing.T) {
+	confident := reviewSub(0, 3, "Привет, как у тебя дела?")
+	confident.AvgLogprob, confident.NoSpeechProb, confident.HasScores = -0.2, 0.01, true
+
+	unsure := reviewSub(3, 6, "Кажется, он сказал что-то")
+	unsure.AvgLogprob, unsure.NoSpeechProb, unsure.HasScores = -1.2, 0.05, true
+
+	untranslated := reviewSub(6, 9, "Москва")
+	tooLong := reviewSub(9, 10, "Да")
+	missing := reviewSub(10, 12, "Пока")
+
+	source := models.SubtitleList{confident, unsure, untranslated, tooLong, missing}
+	translated := models.SubtitleList{
+		reviewSub(0, 3, "Hi, how are you doing?"),
+		reviewSub(3, 6, "I think he said something"),
+		reviewSub(6, 9, "Москва"),
+		reviewSub(9, 10, "Yes, absolutely, I completely agree with you"),
+	}
+
+	list := NewSegmentReviewer().Review(source, translated, "ru", "en")
+
+	if list.TotalSegments != 5 {
+		t.Errorf("TotalSegments = %d, want 5", list.TotalSegments)
+	}
+
+	want := map[int]string{
+		2: "low transcription confidence",
+		3: "identical to the source",
+		4: "too long to speak",
+		5: "no translation",
+	}
+	if len(list.Items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(list.Items), len(want), list.Items)
+	}
+	for _, item := range list.Items {
+		reason, ok := want[item.Index]
+		if !ok {
+			t.Errorf("unexpected item #%d: %v", item.Index, item.Reasons)
+			continue
+		}
+		if !strings.Contains(strings.Join(item.Reasons, "; "), reason) {
+			t.Errorf("item #%d reasons = %v, want one containing %q", item.Index, item.Reasons, reason)
+		}
+	}
+
+	first := list.Items[0]
+	if first.Confidence == nil || math.Abs(*first.Confidence-math.Exp(-1.2)) > 1e-9 {
+		t.Errorf("item #2 confidence = %v, want exp(-1.2)", first.Confidence)
+	}
+	if list.Items[1].Confidence != nil {
+		t.Error("unscored segment should have no confidence")
+	}
+}
+
+func TestSegmentReviewer_LengthBudget(t *testing.T) {
+	source := models.SubtitleList{reviewSub(0, 2, "Добрый день"), reviewSub(2, 4, "Спасибо")}
+	fits := reviewSub(0, 2, "Good afternoon")
+	fits.CharBudget, fits.FitsBudget = 30, true
+	over := reviewSub(2, 4, "Thank you so very much")
+	over.CharBudget, over.FitsBudget = 12, false
+
+	list := NewSegmentReviewer().Review(source, models.SubtitleList{fits, over}, "ru", "en")
+	if len(list.Items) != 1 || list.Items[0].Index != 2 {
+		t.Fatalf("items = %+v, want only #2", list.Items)
+	}
+	if !strings.Contains(list.Items[0].Reasons[0], "budget 12") {
+		t.Errorf("reasons = %v", list.Items[0].Reasons)
+	}
+}
+
+func TestSegmentReviewer_Glossary(t *testing.T) {
+	source := models.SubtitleList{reviewSub(0, 3, "Добро пожаловать в Облако Акме")}
+	translated := models.SubtitleList{reviewSub(0, 3, "Welcome to the Akme Cloud")}
+
+	reviewer := NewSegmentReviewer()
+	reviewer.Glossary = NewGlossary("test", []GlossaryTerm{{"Облако Акме", "Acme Cloud"}}, nil)
+	list := reviewer.Review(source, translated, "ru", "en")
+	if len(list.Items) != 1 || !strings.HasPrefix(list.Items[0].Reasons[0], "glossary: ") {
+		t.Errorf("items = %+v, want a glossary flag", list.Items)
+	}
+}
+
+func TestSegmentReviewer_SameLanguage(t *testing.T) {
+	source := models.SubtitleList{reviewSub(0, 2, "Hello there")}
+	list := NewSegmentReviewer().Review(source, source, "en", "en")
+	if len(list.Items) != 0 {
+		t.Errorf("same-language copy should not be flagged as untranslated: %+v", list.Items)
+	}
+}
+
+func TestReviewList_WriteAndLoad(t *testing.T) {
+	source := models.SubtitleList{reviewSub(0, 2, "Привет")}
+	list := NewSegmentReviewer().Review(source, nil, "ru", "en")
+	list.InputFile = "video.mp4"
+
+	path := filepath.Join(t.TempDir(), "review.json")
+	if err := list.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	loaded, err := LoadReviewList(path)
+	if err != nil {
+		t.Fatalf("LoadReviewList: %v", err)
+	}
+	if loaded.InputFile != "video.mp4" || len(loaded.Items) != 1 || loaded.Items[0].Source != "Привет" {
+		t.Errorf("round trip mismatch: %+v", loaded)
+	}
+	if loaded.Summary() != "1 of 1 segments need review" {
+		t.Errorf("Summary() = %q", loaded.Summary())
+	}
+}
+
+func TestAttachWhisperCppScores(t *testing.T) {
+	data := `{"transcription":[
+		{"offsets":{"from":0,"to":2000},"text":" Hello","tokens":[
+			{"text":"[_BEG_]","p":0.9},{"text":" Hello","p":0.5},{"text":"[_TT_100]","p":0.1}]},
+		{"offsets":{"from":2000,"to":4000},"text":" world","tokens":[
+			{"text":" world","p":1.0}]}
+	]}`
+	path := filepath.Join(t.TempDir(), "audio.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	subs := models.SubtitleList{reviewSub(0, 2, "Hello"), reviewSub(2, 4, "world"), reviewSub(4, 5, "unmatched")}
+	attachWhisperCppScores(subs, path)
+
+	if !subs[0].HasScores || math.Abs(subs[0].AvgLogprob-math.Log(0.5)) > 1e-9 {
+		t.Errorf("segment 1 = %+v, want avg_logprob log(0.5)", subs[0])
+	}
+	if !subs[1].HasScores || subs[1].AvgLogprob != 0 {
+		t.Errorf("segment 2 = %+v, want avg_logprob 0", subs[1])
+	}
+	if subs[2].HasScores {
+		t.Error("segment without JSON entry should stay unscored")
+	}
+
+	// Missing JSON leaves subtitles untouched
+	attachWhisperCppScores(models.SubtitleList{reviewSub(0, 1, "x")}, filepath.Join(t.TempDir(), "missing.json"))
+}
+
+func TestParseVerboseJSON(t *testing.T) {
+	data := `{"language":"russian","segments":[
+		{"start":0.0,"end":1.5,"text":" Привет","avg_logprob":-0.3,"no_speech_prob":0.02},
+		{"start":1.5,"end":3.0,"text":" Пока","avg_logprob":-0.9,"no_speech_prob":0.1}
+	]}`
+
+	subs, err := parseVerboseJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("parseVerboseJSON: %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("got %d segments, want 2", len(subs))
+	}
+	if subs[0].Index != 1 || subs[0].Text != "Привет" || !subs[0].HasScores || subs[0].AvgLogprob != -0.3 {
+		t.Errorf("segment 1 = %+v", subs[0])
+	}
+	if subs[1].EndTime != 3*time.Second || subs[1].NoSpeechProb != 0.1 {
+		t.Errorf("segment 2 = %+v", subs[1])
+	}
+}
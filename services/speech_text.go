@@ -0,0 +1,133 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// ttsPhonemeMarkup writes an IPA transcription inline for TTS providers
+// that read phonemes; the others speak the lexicon's respelling
+var ttsPhonemeMarkup = map[string]func(ipa string) string{
+	// Piper hands [[ ]] straight to its phonemizer
+	"piper": func(ipa string) string { return "[[ " + ipa + " ]]" },
+}
+
+// PrepareSpeech returns a copy of subs with the text the provider's voice
+// should read in lang: normalized (numbers, dates, money, units,
+// abbreviations and URLs spelled out) when normalize is set, then with the
+// lexicon's pronunciations. subs keep the text as written for subtitles.
+func PrepareSpeech(subs models.SubtitleList, lang, provider string, normalize bool, lexicon []models.LexiconEntry) models.SubtitleList {
+	say := newPronunciations(lexicon, lang, provider)
+	spoken := make(models.SubtitleList, len(subs))
+	changed := 0
+	for i, sub := range subs {
+		spoken[i] = sub
+		if normalize {
+			spoken[i].Text = text.NormalizeForSpeech(spoken[i].Text, lang)
+		}
+		spoken[i].Text = say.apply(spoken[i].Text)
+		if spoken[i].Text != sub.Text {
+			changed++
+			logger.LogDebug("Speech text: #%d %q -> %q", sub.Index, sub.Text, spoken[i].Text)
+		}
+	}
+	if changed > 0 {
+		logger.LogInfo("Speech text: rewrote %d/%d lines for %s", changed, len(subs), provider)
+	}
+	return spoken
+}
+
+// pronunciations replaces lexicon words with what the voice should say
+type pronunciations struct {
+	re     *regexp.Regexp
+	spoken map[string]string // Lowercase word -> replacement
+}
+
+// newPronunciations picks the lexicon entries for lang, preferring ones
+// written for that language over general ones. Entries with only an IPA
+// transcription are skipped for providers that can't read phonemes.
+func newPronunciations(lexicon []models.LexiconEntry, lang, provider string) *pronunciations {
+	markup := ttsPhonemeMarkup[provider]
+	spoken := make(map[string]string)
+	for _, e := range lexicon {
+		if e.Lang != "" && primaryLangTag(e.Lang) != primaryLangTag(lang) {
+			continue
+		}
+		say := e.Respelling
+		if markup != nil && e.Phoneme != "" {
+			say = markup(e.Phoneme)
+		}
+		key := strings.ToLower(strings.TrimSpace(e.Word))
+		if say == "" || key == "" {
+			continue
+		}
+		if _, ok := spoken[key]; ok && e.Lang == "" {
+			continue
+		}
+		spoken[key] = say
+	}
+	if len(spoken) == 0 {
+		return nil
+	}
+
+	words := make([]string, 0, len(spoken))
+	for w := range spoken {
+		words = append(words, w)
+	}
+	// Longest first so "Visual Studio Code" wins over "Visual Studio"
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) > len(words[j])
+		}
+		return words[i] < words[j]
+	})
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	return &pronunciations{
+		re:     regexp.MustCompile(`(?i)` + strings.Join(words, "|")),
+		spoken: spoken,
+	}
+}
+
+// apply replaces whole-word matches in one pass, so a respelling that
+// contains its own word isn't replaced again
+func (p *pronunciations) apply(s string) string {
+	if p == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range p.re.FindAllStringIndex(s, -1) {
+		if !wordBoundary(s, loc[0], loc[1]) {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(p.spoken[strings.ToLower(s[loc[0]:loc[1]])])
+		last = loc[1]
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// wordBoundary reports whether s[start:end] isn't part of a longer word
+func wordBoundary(s string, start, end int) bool {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	if r, _ := utf8.DecodeLastRuneInString(s[:start]); start > 0 && isWord(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && isWord(r) {
+		return false
+	}
+	return true
+}
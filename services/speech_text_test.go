@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+func TestNormalizeForSpeech(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		// English
+		{"en", "In 2024 we raised $5M.", "In twenty twenty-four we raised five million dollars."},
+		{"en", "It costs $3.50 or €12", "It costs three dollars and fifty cents or twelve euros"},
+		{"en", "Dr. Smith ran 5 km at 12 km/h", "Doctor Smith ran five kilometers at twelve kilometers per hour"},
+		{"en", "Up 12.5% since 1999", "Up twelve point five percent since nineteen ninety-nine"},
+		{"en", "We had 1,234,567 users", "We had one million two hundred thirty-four thousand five hundred sixty-seven users"},
+		{"en", "Released on 2024-03-15", "Released on March fifteenth, twenty twenty-four"},
+		{"en", "The 21st time, 1 kg", "The twenty-first time, one kilogram"},
+		{"en", "Visit https://www.example.com/docs/ now", "Visit example dot com slash docs now"},
+		{"en", "Mail support@acme.io", "Mail support at acme dot io"},
+		{"en", "Founded in 2005 and 1900", "Founded in two thousand five and nineteen hundred"},
+		{"en", "Pay USD 20 for 3 items", "Pay twenty dollars for three items"},
+		{"en", "Web3, MP3 and v1.2.3 stay; 10:30 too", "Web3, MP3 and v1.2.3 stay; 10:30 too"},
+		// German
+		{"de", "Das kostet 1.250 € und 3,5 %", "Das kostet eintausendzweihundertfünfzig Euro und drei Komma fünf Prozent"},
+		{"de", "z.B. am 15.03.2024 um 21 Uhr", "zum Beispiel am fünfzehnten März zweitausendvierundzwanzig um einundzwanzig Uhr"},
+		{"de", "Im Jahr 1984 fuhr er 101 km", "Im Jahr neunzehnhundertvierundachtzig fuhr er einhunderteins Kilometer"},
+		// Spanish
+		{"es", "Cuesta 21 $ y pesa 1 kg", "Cuesta veintiún dólares y pesa un kilogramo"},
+		{"es", "Ganó $2M el 1/5/2023", "Ganó dos millones de dólares el primero de mayo de dos mil veintitrés"},
+		// French
+		{"fr", "Il a 71 ans et 80 000 €", "Il a soixante et onze ans et quatre-vingt mille euros"},
+		{"fr", "Le 1/12/1998, M. Dupont", "Le premier décembre mille neuf cent quatre-vingt-dix-huit, Monsieur Dupont"},
+		// Russian
+		{"ru", "Это стоит 21 ₽ и 2 мин", "Это стоит двадцать один рубль и две минуты"},
+		{"ru", "Дата: 15.03.2024, т.е. 5 км", "Дата: пятнадцатое марта две тысячи двадцать четвёртого года, то есть пять километров"},
+		{"ru", "Рост 3 %, 1000 и 2000000", "Рост три процента, одна тысяча и два миллиона"},
+		{"ru", "Бюджет 1 500 000 RUB", "Бюджет один миллион пятьсот тысяч рублей"},
+		// Years only where a date is set up, signs, fractions, phone numbers
+		{"en", "Call 555-1234 or (212) 555-0187", "Call five five five, one two three four or two one two, five five five, zero one eight seven"},
+		{"en", "Dial +1 800-555-0100", "Dial plus one, eight zero zero, five five five, zero one zero zero"},
+		{"en", "We sold 1985 units in 1985", "We sold one thousand nine hundred eighty-five units in nineteen eighty-five"},
+		{"en", "Born March 1950, (1984) novel", "Born March nineteen fifty, (one thousand nine hundred eighty-four) novel"},
+		{"en", "Add 1/2 cup and 3/4 of 2/3, open 24/7", "Add one half cup and three quarters of two thirds, open 24/7"},
+		{"en", "It was -5 degrees, (−12) at night", "It was minus five degrees, (minus twelve) at night"},
+		{"en", "Down to -3 °C", "Down to minus three degrees Celsius"},
+		{"de", "Seit 1990 nur 1/4 und -2 Grad", "Seit neunzehnhundertneunzig nur ein Viertel und minus zwei Grad"},
+		{"es", "Llegaron 1/2 y -3", "Llegaron un medio y menos tres"},
+		{"fr", "Les 2/3 et 1/4", "Les deux tiers et un quart"},
+		{"ru", "в 1985 году", "в тысяча девятьсот восемьдесят пятом году"},
+		{"ru", "Весной 2003 года, с 1999 г. и 1/2", "Весной две тысячи третьего года, с 1999 г. и 1/2"},
+		{"ru", "Добавьте 1500 г муки", "Добавьте одна тысяча пятьсот граммов муки"},
+		{"ru", "Было 1985 человек и -5 градусов", "Было одна тысяча девятьсот восемьдесят пять человек и минус пять градусов"},
+		// No rules: left for the voice
+		{"ja", "2024年に$5M", "2024年に$5M"},
+	}
+	for _, tt := range tests {
+		if got := text.NormalizeForSpeech(tt.in, tt.lang); got != tt.want {
+			t.Errorf("NormalizeForSpeech(%q, %s)\n got %q\nwant %q", tt.in, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestParseLexicon(t *testing.T) {
+	entries, err := models.ParseLexicon(`
+# Product names
+Kubernetes = koo-ber-NET-eez
+de: Nginx = Engine X
+Huawei = WAH-way /ˈwɑːweɪ/
+Xi = /ɕi/
+`)
+	if err != nil {
+		t.Fatalf("ParseLexicon: %v", err)
+	}
+	want := []models.LexiconEntry{
+		{Word: "Kubernetes", Respelling: "koo-ber-NET-eez"},
+		{Word: "Nginx", Respelling: "Engine X", Lang: "de"},
+		{Word: "Huawei", Respelling: "WAH-way", Phoneme: "ˈwɑːweɪ"},
+		{Word: "Xi", Phoneme: "ɕi"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	again, err := models.ParseLexicon(models.FormatLexicon(entries))
+	if err != nil || len(again) != len(entries) || again[2] != entries[2] {
+		t.Errorf("FormatLexicon round trip = %+v, %v", again, err)
+	}
+
+	for _, bad := range []string{"Kubernetes", "= respelling", "Kubernetes ="} {
+		if _, err := models.ParseLexicon(bad); err == nil {
+			t.Errorf("ParseLexicon(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPrepareSpeech(t *testing.T) {
+	lexicon := []models.LexiconEntry{
+		{Word: "Kubernetes", Respelling: "koo-ber-NET-eez"},
+		{Word: "Nginx", Respelling: "engine ex"},
+		{Word: "Nginx", Respelling: "Engine X", Lang: "de"},
+		{Word: "Huawei", Respelling: "WAH-way", Phoneme: "ˈwɑːweɪ"},
+		{Word: "Xi", Phoneme: "ɕi"},
+		{Word: "Go", Respelling: "Go lang Go"}, // Contains its own word
+	}
+	subs := models.SubtitleList{
+		{Index: 1, Text: "Kubernetes costs $5M, says Dr. Xi", Speaker: "SPEAKER_00"},
+		{Index: 2, Text: "Nginx vs. Huawei; Go, Golang"},
+	}
+
+	spoken := PrepareSpeech(subs, "en", "edge-tts", true, lexicon)
+	if got, want := spoken[0].Text, "koo-ber-NET-eez costs five million dollars, says Doctor Xi"; got != want {
+		t.Errorf("edge-tts line 1 = %q, want %q", got, want)
+	}
+	if got, want := spoken[1].Text, "engine ex versus WAH-way; Go lang Go, Golang"; got != want {
+		t.Errorf("edge-tts line 2 = %q, want %q", got, want)
+	}
+	if spoken[0].Speaker != "SPEAKER_00" || spoken[0].Index != 1 {
+		t.Errorf("PrepareSpeech lost subtitle fields: %+v", spoken[0])
+	}
+	if subs[0].Text != "Kubernetes costs $5M, says Dr. Xi" {
+		t.Errorf("PrepareSpeech changed the subtitles: %q", subs[0].Text)
+	}
+
+	// Piper reads phonemes; German picks its own Nginx entry
+	spoken = PrepareSpeech(subs, "de", "piper", false, lexicon)
+	if got, want := spoken[0].Text, "koo-ber-NET-eez costs $5M, says Dr. [[ ɕi ]]"; got != want {
+		t.Errorf("piper line 1 = %q, want %q", got, want)
+	}
+	if got, want := spoken[1].Text, "Engine X vs. [[ ˈwɑːweɪ ]]; Go lang Go, Golang"; got != want {
+		t.Errorf("piper line 2 = %q, want %q", got, want)
+	}
+
+	if got := PrepareSpeech(subs, "en", "openai", false, nil); got[0].Text != subs[0].Text {
+		t.Errorf("with nothing to do PrepareSpeech changed %q to %q", subs[0].Text, got[0].Text)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"video-translator/internal/diskcache"
+	"video-translator/internal/logger"
+	"video-translator/models"
+)
+
+// TranscriptionCache stores subtitle lists on disk keyed by the content of
+// the extracted audio plus everything that changes the transcript (provider,
+// model, language, prompt). Entries are evicted least-recently-used once the
+// cache grows past maxBytes.
+//
+// All methods are safe on a nil *TranscriptionCache, which acts as a
+// disabled cache.
+type TranscriptionCache struct {
+	files *diskcache.Cache
+}
+
+// DefaultTranscriptionCacheDir returns ~/.cache/video-translator/transcripts
+func DefaultTranscriptionCacheDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cache", "video-translator", "transcripts")
+}
+
+// NewTranscriptionCache creates a cache in dir limited to maxBytes (0 = unlimited)
+func NewTranscriptionCache(dir string, maxBytes int64) *TranscriptionCache {
+	return &TranscriptionCache{
+		files: diskcache.New("Transcription cache", dir, ".json", maxBytes),
+	}
+}
+
+// TranscriptionCacheKey identifies one transcription of one audio file
+type TranscriptionCacheKey struct {
+	AudioHash string
+	Provider  string
+	Model     string
+	Language  string
+	Prompt    string
+}
+
+// String returns the file-safe digest used as the cache entry name
+func (k TranscriptionCacheKey) String() string {
+	h := sha256.Sum256([]byte(strings.Join([]string{k.AudioHash, k.Provider, k.Model, k.Language, k.Prompt}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+// HashAudioFile returns the SHA-256 of a file's contents
+func HashAudioFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open audio for hashing: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash audio: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheEntry is the on-disk format; the key fields make entries inspectable
+type cacheEntry struct {
+	Provider  string              `json:"provider"`
+	Model     string              `json:"model"`
+	Language  string              `json:"language"`
+	CreatedAt time.Time           `json:"created_at"`
+	Subtitles models.SubtitleList `json:"subtitles"`
+}
+
+// Get returns the cached subtitles for key, refreshing its LRU timestamp
+func (c *TranscriptionCache) Get(key TranscriptionCacheKey) (models.SubtitleList, bool) {
+	if c == nil || key.AudioHash == "" {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	found := c.files.Get(key.String(), func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			logger.LogError("Transcription cache: dropping corrupt entry %s: %v", filepath.Base(path), err)
+			os.Remove(path)
+			return err
+		}
+		return nil
+	})
+	return entry.Subtitles, found
+}
+
+// Put stores subtitles under key and evicts old entries if over the size limit
+func (c *TranscriptionCache) Put(key TranscriptionCacheKey, subs models.SubtitleList) error {
+	if c == nil || key.AudioHash == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		Provider:  key.Provider,
+		Model:     key.Model,
+		Language:  key.Language,
+		CreatedAt: time.Now(),
+		Subtitles: subs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	return c.files.Put(key.String(), func(path string) error {
+		return os.WriteFile(path, data, 0644)
+	})
+}
+
+// Size returns the total size in bytes and number of cached entries
+func (c *TranscriptionCache) Size() (int64, int) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.files.Size()
+}
+
+// Clear removes every cached transcription
+func (c *TranscriptionCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+	return c.files.Clear()
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func testCacheKey(hash string) TranscriptionCacheKey {
+	return TranscriptionCacheKey{AudioHash: hash, Provider: "whisper-cpp", Model: "ggml-base.bin", Language: "ru"}
+}
+
+func TestTranscriptionCache_PutGet(t *testing.T) {
+	cache := NewTranscriptionCache(t.TempDir(), 0)
+	subs := models.SubtitleList{
+		{Index: 1, StartTime: time.Second, EndTime: 2 * time.Second, Text: "Привет", AvgLogprob: -0.3, HasScores: true},
+	}
+
+	if _, ok := cache.Get(testCacheKey("abc")); ok {
+		t.Fatal("Get() on empty cache should miss")
+	}
+	if err := cache.Put(testCacheKey("abc"), subs); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := cache.Get(testCacheKey("abc"))
+	if !ok {
+		t.Fatal("Get() should hit after Put()")
+	}
+	if len(got) != 1 || got[0] != subs[0] {
+		t.Errorf("Get() = %+v, want %+v", got, subs)
+	}
+
+	// Any key component change is a different entry
+	other := testCacheKey("abc")
+	other.Language = "en"
+	if _, ok := cache.Get(other); ok {
+		t.Error("Get() with different language should miss")
+	}
+}
+
+func TestTranscriptionCache_Evicts(t *testing.T) {
+	dir := t.TempDir()
+	subs := models.SubtitleList{{Index: 1, Text: "some text that takes up a bit of space"}}
+
+	// Measure one entry to size the limit to two entries
+	probe := NewTranscriptionCache(t.TempDir(), 0)
+	probe.Put(testCacheKey("x"), subs)
+	entrySize, _ := probe.Size()
+
+	cache := NewTranscriptionCache(dir, entrySize*2+entrySize/2)
+	cache.Put(testCacheKey("a"), subs)
+	cache.Put(testCacheKey("b"), subs)
+
+	// Make "a" the oldest, then touch "b" so it's most recently used
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, testCacheKey("a").String()+".json"), old, old)
+	cache.Get(testCacheKey("b"))
+
+	cache.Put(testCacheKey("c"), subs)
+
+	if _, ok := cache.Get(testCacheKey("a")); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	if _, ok := cache.Get(testCacheKey("b")); !ok {
+		t.Error("recently used entry should survive eviction")
+	}
+	if _, count := cache.Size(); count != 2 {
+		t.Errorf("Size() count = %d, want 2", count)
+	}
+}
+
+func TestTranscriptionCache_Clear(t *testing.T) {
+	cache := NewTranscriptionCache(t.TempDir(), 0)
+	cache.Put(testCacheKey("a"), models.SubtitleList{{Text: "a"}})
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if size, count := cache.Size(); size != 0 || count != 0 {
+		t.Errorf("Size() after Clear() = %d, %d; want 0, 0", size, count)
+	}
+}
+
+func TestTranscriptionCache_ClearedByAnotherInstance(t *testing.T) {
+	dir := t.TempDir()
+	subs := models.SubtitleList{{Index: 1, Text: "text"}}
+	cache := NewTranscriptionCache(dir, 0)
+	cache.Put(testCacheKey("a"), subs)
+	cache.Put(testCacheKey("b"), subs)
+
+	// The settings panel clears the directory through its own instance
+	if err := NewTranscriptionCache(dir, 0).Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, ok := cache.Get(testCacheKey("a")); ok {
+		t.Error("entry cleared by another instance should miss")
+	}
+	if err := cache.Put(testCacheKey("a"), subs); err != nil {
+		t.Fatalf("Put() after clear error = %v", err)
+	}
+	if _, ok := cache.Get(testCacheKey("a")); !ok {
+		t.Error("entry written again should hit")
+	}
+}
+
+func TestTranscriptionCache_Nil(t *testing.T) {
+	var cache *TranscriptionCache
+	if _, ok := cache.Get(testCacheKey("a")); ok {
+		t.Error("nil cache should always miss")
+	}
+	if err := cache.Put(testCacheKey("a"), nil); err != nil {
+		t.Errorf("nil cache Put() error = %v", err)
+	}
+}
+
+func TestHashAudioFile(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.wav")
+	b := filepath.Join(dir, "b.wav")
+	os.WriteFile(a, []byte("same audio"), 0644)
+	os.WriteFile(b, []byte("same audio"), 0644)
+
+	ha, err := HashAudioFile(a)
+	if err != nil {
+		t.Fatalf("HashAudioFile() error = %v", err)
+	}
+	hb, _ := HashAudioFile(b)
+	if ha != hb {
+		t.Error("identical content should hash identically regardless of path")
+	}
+
+	if _, err := HashAudioFile(filepath.Join(dir, "missing.wav")); err == nil {
+		t.Error("HashAudioFile() should fail for a missing file")
+	}
+}
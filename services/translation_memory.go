@@ -0,0 +1,542 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// TMEntry is one remembered translation
+type TMEntry struct {
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Scope      string    `json:"scope,omitempty"`   // Provider/style it was made with; empty matches any
+	Emotion    string    `json:"emotion,omitempty"` // Set when translated with emotion tags
+	Uses       int       `json:"uses"`
+	Updated    time.Time `json:"updated"`
+}
+
+// TMMatch is a fuzzy translation memory hit
+type TMMatch struct {
+	TMEntry
+	Similarity float64 // 0-1, 1 = identical after normalization
+}
+
+// TranslationMemory remembers translations across jobs, so recurring lines
+// (intros, catchphrases) are translated once per language pair and scope.
+// Entries are kept in memory and saved as a single JSON file.
+//
+// All methods are safe on a nil *TranslationMemory, which acts as a
+// disabled memory.
+type TranslationMemory struct {
+	path    string
+	mu      sync.Mutex
+	buckets map[string]map[string]*TMEntry // pair|scope -> normalized source -> entry
+	dirty   bool
+}
+
+// Fuzzy matching limits: below this similarity a suggestion misleads more
+// than it helps, and lines differing this much in length can't reach it
+const (
+	MinFuzzySimilarity = 0.75
+	fuzzyLengthRatio   = 0.75
+)
+
+// DefaultTranslationMemoryPath returns ~/.config/video-translator/translation_memory.json
+func DefaultTranslationMemoryPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "video-translator", "translation_memory.json")
+}
+
+// OpenTranslationMemory loads the memory at path; a missing file gives an
+// empty memory that will be created on Save
+func OpenTranslationMemory(path string) (*TranslationMemory, error) {
+	tm := &TranslationMemory{path: path, buckets: make(map[string]map[string]*TMEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return tm, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation memory: %w", err)
+	}
+
+	var entries []TMEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse translation memory: %w", err)
+	}
+	for _, e := range entries {
+		tm.put(e)
+	}
+	tm.dirty = false
+	return tm, nil
+}
+
+// translationMemories are the open memories by path, shared by every
+// pipeline and Settings so one job's save never drops another's entries
+var translationMemories = struct {
+	sync.Mutex
+	byPath map[string]*TranslationMemory
+}{byPath: make(map[string]*TranslationMemory)}
+
+// SharedTranslationMemory returns the process-wide memory at path, loading
+// it on first use
+func SharedTranslationMemory(path string) (*TranslationMemory, error) {
+	translationMemories.Lock()
+	defer translationMemories.Unlock()
+	if tm, ok := translationMemories.byPath[path]; ok {
+		return tm, nil
+	}
+	tm, err := OpenTranslationMemory(path)
+	if err != nil {
+		return nil, err
+	}
+	translationMemories.byPath[path] = tm
+	return tm, nil
+}
+
+// normalizeTMSource is the lookup key for a source line: case and spacing
+// don't change a translation, punctuation ("Yes." vs "Yes?") can
+func normalizeTMSource(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func tmBucket(sourceLang, targetLang, scope string) string {
+	return sourceLang + "|" + targetLang + "|" + scope
+}
+
+// put stores e, replacing any entry for the same source; callers hold mu
+func (tm *TranslationMemory) put(e TMEntry) {
+	e.Source = strings.TrimSpace(e.Source)
+	e.Target = strings.TrimSpace(e.Target)
+	key := normalizeTMSource(e.Source)
+	if key == "" || e.Target == "" {
+		return
+	}
+	bucket := tmBucket(e.SourceLang, e.TargetLang, e.Scope)
+	if tm.buckets[bucket] == nil {
+		tm.buckets[bucket] = make(map[string]*TMEntry)
+	}
+	if old, ok := tm.buckets[bucket][key]; ok && e.Uses < old.Uses {
+		e.Uses = old.Uses
+	}
+	if e.Updated.IsZero() {
+		e.Updated = time.Now()
+	}
+	tm.buckets[bucket][key] = &e
+	tm.dirty = true
+}
+
+// Add remembers a translation
+func (tm *TranslationMemory) Add(source, target, sourceLang, targetLang, scope, emotion string) {
+	if tm == nil {
+		return
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.put(TMEntry{
+		Source:     source,
+		Target:     target,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Scope:      scope,
+		Emotion:    emotion,
+	})
+}
+
+// Lookup returns the exact match for source, preferring an entry made in
+// scope over a scope-less (e.g. imported) one
+func (tm *TranslationMemory) Lookup(source, sourceLang, targetLang, scope string) (TMEntry, bool) {
+	if tm == nil {
+		return TMEntry{}, false
+	}
+	key := normalizeTMSource(source)
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	for _, s := range []string{scope, ""} {
+		if e, ok := tm.buckets[tmBucket(sourceLang, targetLang, s)][key]; ok {
+			e.Uses++
+			tm.dirty = true
+			return *e, true
+		}
+		if scope == "" {
+			break
+		}
+	}
+	return TMEntry{}, false
+}
+
+// Fuzzy returns up to limit entries at least minSimilarity similar to
+// source (edit distance over length), best first. Exact matches are
+// excluded; use Lookup for those.
+func (tm *TranslationMemory) Fuzzy(source, sourceLang, targetLang, scope string, minSimilarity float64, limit int) []TMMatch {
+	if tm == nil || limit <= 0 {
+		return nil
+	}
+	key := normalizeTMSource(source)
+	keyLen := utf8.RuneCountInString(key)
+	if keyLen == 0 {
+		return nil
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	var matches []TMMatch
+	scopes := []string{scope}
+	if scope != "" {
+		scopes = append(scopes, "")
+	}
+	for _, s := range scopes {
+		for candidate, e := range tm.buckets[tmBucket(sourceLang, targetLang, s)] {
+			if candidate == key {
+				continue
+			}
+			candLen := utf8.RuneCountInString(candidate)
+			longer := max(keyLen, candLen)
+			if float64(min(keyLen, candLen))/float64(longer) < fuzzyLengthRatio {
+				continue
+			}
+			similarity := 1 - float64(text.Levenshtein(key, candidate))/float64(longer)
+			if similarity >= minSimilarity {
+				matches = append(matches, TMMatch{TMEntry: *e, Similarity: similarity})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].Source < matches[j].Source
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Suggestions returns the best fuzzy match of each of texts, at most limit
+// in total, for showing an LLM how similar lines were translated before
+func (tm *TranslationMemory) Suggestions(texts []string, sourceLang, targetLang, scope string, limit int) []TMMatch {
+	if tm == nil {
+		return nil
+	}
+	var suggestions []TMMatch
+	seen := make(map[string]bool)
+	for _, t := range texts {
+		if len(suggestions) >= limit {
+			break
+		}
+		for _, m := range tm.Fuzzy(t, sourceLang, targetLang, scope, MinFuzzySimilarity, 1) {
+			if key := normalizeTMSource(m.Source); !seen[key] {
+				seen[key] = true
+				suggestions = append(suggestions, m)
+			}
+		}
+	}
+	return suggestions
+}
+
+// Entries returns all entries sorted by language pair, scope and source
+func (tm *TranslationMemory) Entries() []TMEntry {
+	if tm == nil {
+		return nil
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.entries()
+}
+
+// entries returns all entries sorted; callers hold mu
+func (tm *TranslationMemory) entries() []TMEntry {
+	var entries []TMEntry
+	for _, bucket := range tm.buckets {
+		for _, e := range bucket {
+			entries = append(entries, *e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.SourceLang+a.TargetLang+a.Scope != b.SourceLang+b.TargetLang+b.Scope {
+			return a.SourceLang+a.TargetLang+a.Scope < b.SourceLang+b.TargetLang+b.Scope
+		}
+		return a.Source < b.Source
+	})
+	return entries
+}
+
+// Len returns the number of entries
+func (tm *TranslationMemory) Len() int {
+	if tm == nil {
+		return 0
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	n := 0
+	for _, bucket := range tm.buckets {
+		n += len(bucket)
+	}
+	return n
+}
+
+// Clear removes all entries (saved on the next Save)
+func (tm *TranslationMemory) Clear() {
+	if tm == nil {
+		return
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.buckets = make(map[string]map[string]*TMEntry)
+	tm.dirty = true
+}
+
+// Save writes the memory to disk if it changed
+func (tm *TranslationMemory) Save() error {
+	if tm == nil {
+		return nil
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if !tm.dirty {
+		return nil
+	}
+
+	entries := tm.entries()
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation memory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(tm.path), 0755); err != nil {
+		return fmt.Errorf("failed to create translation memory directory: %w", err)
+	}
+	// Write then rename so a crash never leaves a truncated memory
+	tmp, err := os.CreateTemp(filepath.Dir(tm.path), filepath.Base(tm.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write translation memory: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), tm.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write translation memory: %w", err)
+	}
+	tm.dirty = false
+	logger.LogDebug("Translation memory: saved %d entries to %s", len(entries), tm.path)
+	return nil
+}
+
+// ApplyTranslationMemory fills translations of subs from exact memory hits.
+// It returns the translated list (hits filled in, everything else a copy of
+// the source) and the indices that still need translating. Hits that break
+// the glossary are not served.
+func ApplyTranslationMemory(tm *TranslationMemory, subs models.SubtitleList, sourceLang, targetLang, scope string, glossary *Glossary) (models.SubtitleList, []int) {
+	translated := make(models.SubtitleList, len(subs))
+	copy(translated, subs)
+
+	var pending []int
+	for i, sub := range subs {
+		if strings.TrimSpace(sub.Text) == "" {
+			continue
+		}
+		e, ok := tm.Lookup(sub.Text, sourceLang, targetLang, scope)
+		if !ok || len(glossary.Verify(sub.Text, e.Target)) > 0 {
+			pending = append(pending, i)
+			continue
+		}
+		translated[i].Text = e.Target
+	}
+	return translated, pending
+}
+
+// TMX 1.4 document, enough of it to round-trip with CAT tools
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	SrcLang      string       `xml:"srclang,attr,omitempty"`
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	UsageCount   int          `xml:"usagecount,attr,omitempty"`
+	Props        []tmxProp    `xml:"prop"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	OldLang string `xml:"lang,attr,omitempty"` // TMX 1.1 used lang= instead of xml:lang=
+	Seg     string `xml:"seg"`
+}
+
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.OldLang
+}
+
+// tmxDateFormat is TMX's basic ISO 8601 UTC format
+const tmxDateFormat = "20060102T150405Z"
+
+// ExportTMX writes all entries as a TMX 1.4 file
+func (tm *TranslationMemory) ExportTMX(path string) error {
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "video-translator",
+			CreationToolVersion: "1",
+			SegType:             "sentence",
+			OTMF:                "video-translator",
+			AdminLang:           "en",
+			SrcLang:             "*all*",
+			DataType:            "plaintext",
+		},
+	}
+	for _, e := range tm.Entries() {
+		unit := tmxUnit{
+			SrcLang:      e.SourceLang,
+			CreationDate: e.Updated.UTC().Format(tmxDateFormat),
+			UsageCount:   e.Uses,
+			Variants: []tmxVariant{
+				{Lang: e.SourceLang, Seg: e.Source},
+				{Lang: e.TargetLang, Seg: e.Target},
+			},
+		}
+		if e.Scope != "" {
+			unit.Props = append(unit.Props, tmxProp{Type: "x-scope", Value: e.Scope})
+		}
+		if e.Emotion != "" {
+			unit.Props = append(unit.Props, tmxProp{Type: "x-emotion", Value: e.Emotion})
+		}
+		doc.Units = append(doc.Units, unit)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode TMX: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write TMX: %w", err)
+	}
+	logger.LogInfo("Translation memory: exported %d entries to %s", len(doc.Units), path)
+	return nil
+}
+
+// ImportTMX adds the units of a TMX file and returns how many entries were
+// added. Each unit yields one entry per non-source variant; the source is
+// the unit's srclang, else the header's, else the first variant. Language
+// codes are reduced to their primary subtag ("en-US" -> "en").
+func (tm *TranslationMemory) ImportTMX(path string) (int, error) {
+	if tm == nil {
+		return 0, fmt.Errorf("translation memory is disabled")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open TMX: %w", err)
+	}
+	defer f.Close()
+
+	var doc tmxDocument
+	if err := xml.NewDecoder(f).Decode(&doc); err != nil && err != io.EOF {
+		return 0, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	added := 0
+	for _, unit := range doc.Units {
+		if len(unit.Variants) < 2 {
+			continue
+		}
+		srcLang := unit.SrcLang
+		if srcLang == "" || srcLang == "*all*" {
+			srcLang = doc.Header.SrcLang
+		}
+		source := unit.Variants[0]
+		for _, v := range unit.Variants {
+			if srcLang != "*all*" && strings.EqualFold(v.lang(), srcLang) {
+				source = v
+				break
+			}
+		}
+
+		entry := TMEntry{Uses: unit.UsageCount}
+		if t, err := time.Parse(tmxDateFormat, unit.CreationDate); err == nil {
+			entry.Updated = t
+		}
+		for _, p := range unit.Props {
+			switch p.Type {
+			case "x-scope":
+				entry.Scope = p.Value
+			case "x-emotion":
+				entry.Emotion = p.Value
+			}
+		}
+
+		for _, v := range unit.Variants {
+			if v == source {
+				continue
+			}
+			e := entry
+			e.Source, e.SourceLang = source.Seg, primaryLangTag(source.lang())
+			e.Target, e.TargetLang = v.Seg, primaryLangTag(v.lang())
+			if e.SourceLang == "" || e.TargetLang == "" {
+				continue
+			}
+			tm.put(e)
+			added++
+		}
+	}
+	logger.LogInfo("Translation memory: imported %d entries from %s", added, path)
+	return added, nil
+}
+
+// primaryLangTag reduces a BCP 47 tag to the lowercase primary language
+func primaryLangTag(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(tag))
+}
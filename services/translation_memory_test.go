@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"video-translator/models"
+)
+
+func TestTranslationMemory_Lookup(t *testing.T) {
+	tm, err := OpenTranslationMemory(filepath.Join(t.TempDir(), "tm.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.Add("Hello there.", "Hallo.", "en", "de", "deepseek", "")
+	tm.Add("Good night", "Gute Nacht", "en", "de", "", "")
+
+	if e, ok := tm.Lookup("  hello   THERE. ", "en", "de", "deepseek"); !ok || e.Target != "Hallo." {
+		t.Errorf("normalized lookup = %+v, %v", e, ok)
+	}
+	if _, ok := tm.Lookup("Hello there?", "en", "de", "deepseek"); ok {
+		t.Error("punctuation should be part of the key")
+	}
+	if _, ok := tm.Lookup("Hello there.", "en", "fr", "deepseek"); ok {
+		t.Error("other language pair should miss")
+	}
+	if _, ok := tm.Lookup("Hello there.", "en", "de", "argos"); ok {
+		t.Error("other scope should miss")
+	}
+	if _, ok := tm.Lookup("Good night", "en", "de", "argos"); !ok {
+		t.Error("scope-less entries should match any scope")
+	}
+
+	var disabled *TranslationMemory
+	disabled.Add("a", "b", "en", "de", "", "")
+	if _, ok := disabled.Lookup("a", "en", "de", ""); ok || disabled.Len() != 0 || disabled.Save() != nil {
+		t.Error("nil memory should be a no-op")
+	}
+}
+
+func TestTranslationMemory_Fuzzy(t *testing.T) {
+	tm, _ := OpenTranslationMemory(filepath.Join(t.TempDir(), "tm.json"))
+	tm.Add("Thanks for watching, see you next week", "Danke fürs Zuschauen, bis nächste Woche", "en", "de", "", "")
+	tm.Add("Thanks for watching", "Danke fürs Zuschauen", "en", "de", "", "")
+	tm.Add("Something else entirely", "Etwas ganz anderes", "en", "de", "", "")
+
+	matches := tm.Fuzzy("Thanks for watching, see you next time", "en", "de", "", MinFuzzySimilarity, 5)
+	if len(matches) != 1 || !strings.HasPrefix(matches[0].Target, "Danke fürs Zuschauen, bis") {
+		t.Fatalf("Fuzzy = %+v", matches)
+	}
+	if matches[0].Similarity >= 1 || matches[0].Similarity < MinFuzzySimilarity {
+		t.Errorf("similarity = %v", matches[0].Similarity)
+	}
+
+	if got := tm.Fuzzy("Thanks for watching", "en", "de", "", MinFuzzySimilarity, 5); len(got) != 0 {
+		t.Errorf("exact match should be left to Lookup, got %+v", got)
+	}
+}
+
+func TestTranslationMemory_SaveAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "tm.json")
+	tm, _ := OpenTranslationMemory(path)
+	tm.Add("Yes.", "Ja.", "en", "de", "openai:emotions", "happy")
+	if err := tm.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reopened, err := OpenTranslationMemory(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	e, ok := reopened.Lookup("yes.", "en", "de", "openai:emotions")
+	if !ok || e.Target != "Ja." || e.Emotion != "happy" {
+		t.Errorf("reopened entry = %+v, %v", e, ok)
+	}
+
+	reopened.Clear()
+	if err := reopened.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := OpenTranslationMemory(path); again.Len() != 0 {
+		t.Errorf("Len after Clear = %d", again.Len())
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenTranslationMemory(path); err == nil {
+		t.Error("corrupt memory should fail to open")
+	}
+}
+
+func TestSharedTranslationMemory_ConcurrentJobs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tm.json")
+	first, err := SharedTranslationMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := SharedTranslationMemory(path); second != first {
+		t.Fatal("each caller got its own copy of the memory")
+	}
+
+	// Two jobs adding and saving at once keep both's entries
+	var wg sync.WaitGroup
+	for job := 0; job < 2; job++ {
+		wg.Add(1)
+		go func(job int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				first.Add(fmt.Sprintf("line %d-%d", job, i), "x", "en", "de", "", "")
+				if err := first.Save(); err != nil {
+					t.Error(err)
+				}
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	reopened, err := OpenTranslationMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != 40 {
+		t.Errorf("saved %d entries, want 40", reopened.Len())
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestTranslationMemory_TMXRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	tm, _ := OpenTranslationMemory(filepath.Join(dir, "tm.json"))
+	tm.Add("Fish & chips <3", "Fisch & Pommes <3", "en", "de", "deepseek", "")
+	tm.Add("Good morning", "Bonjour", "en", "fr", "", "calm")
+
+	tmxPath := filepath.Join(dir, "export.tmx")
+	if err := tm.ExportTMX(tmxPath); err != nil {
+		t.Fatalf("ExportTMX: %v", err)
+	}
+	data, _ := os.ReadFile(tmxPath)
+	if !strings.Contains(string(data), `xml:lang="de"`) || !strings.Contains(string(data), "&amp;") {
+		t.Errorf("unexpected TMX:\n%s", data)
+	}
+
+	imported, _ := OpenTranslationMemory(filepath.Join(dir, "other.json"))
+	added, err := imported.ImportTMX(tmxPath)
+	if err != nil || added != 2 {
+		t.Fatalf("ImportTMX = %d, %v", added, err)
+	}
+	if e, ok := imported.Lookup("Fish & chips <3", "en", "de", "deepseek"); !ok || e.Target != "Fisch & Pommes <3" {
+		t.Errorf("round-tripped entry = %+v, %v", e, ok)
+	}
+	if e, ok := imported.Lookup("Good morning", "en", "fr", ""); !ok || e.Emotion != "calm" {
+		t.Errorf("round-tripped emotion = %+v, %v", e, ok)
+	}
+}
+
+func TestTranslationMemory_ImportForeignTMX(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cat.tmx")
+	os.WriteFile(path, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header srclang="en-US" creationtool="SomeCAT" segtype="sentence" o-tmf="x" adminlang="en" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="de-DE"><seg>Auf Wiedersehen</seg></tuv>
+      <tuv xml:lang="en-US"><seg>Goodbye</seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Au revoir</seg></tuv>
+    </tu>
+    <tu><tuv xml:lang="en-US"><seg>Lonely</seg></tuv></tu>
+  </body>
+</tmx>`), 0644)
+
+	tm, _ := OpenTranslationMemory(filepath.Join(dir, "tm.json"))
+	added, err := tm.ImportTMX(path)
+	if err != nil || added != 2 {
+		t.Fatalf("ImportTMX = %d, %v", added, err)
+	}
+	for lang, want := range map[string]string{"de": "Auf Wiedersehen", "fr": "Au revoir"} {
+		if e, ok := tm.Lookup("goodbye", "en", lang, "argos"); !ok || e.Target != want {
+			t.Errorf("en→%s = %+v, %v", lang, e, ok)
+		}
+	}
+}
+
+func TestApplyTranslationMemory(t *testing.T) {
+	tm, _ := OpenTranslationMemory(filepath.Join(t.TempDir(), "tm.json"))
+	tm.Add("Acme rocks", "Acme rockt", "en", "de", "", "")
+	tm.Add("Hello", "Hallo", "en", "de", "", "")
+	glossary := NewGlossary("test", []GlossaryTerm{{"hello", "Servus"}}, nil)
+
+	subs := models.SubtitleList{{Text: "Acme rocks"}, {Text: "Hello"}, {Text: " "}, {Text: "New line"}}
+	translated, pending := ApplyTranslationMemory(tm, subs, "en", "de", "argos", glossary)
+	if translated[0].Text != "Acme rockt" {
+		t.Errorf("hit not applied: %q", translated[0].Text)
+	}
+	// "Hallo" breaks the glossary, so it is translated again
+	if len(pending) != 2 || pending[0] != 1 || pending[1] != 3 {
+		t.Errorf("pending = %v, want [1 3]", pending)
+	}
+	if subs[0].Text != "Acme rocks" {
+		t.Error("ApplyTranslationMemory must not modify its input")
+	}
+}
@@ -49,7 +49,7 @@ func TestTranslateSubtitlesWithProgress_Empty(t *testing.T) {
 	subs := models.SubtitleList{}
 	progressCalled := false
 
-	result, err := s.TranslateSubtitlesWithProgress(subs, "ru", "en", func(current, total int) {
+	result, err := s.TranslateSubtitlesWithProgress(subs, "ru", "en", nil, func(current, total int) {
 		progressCalled = true
 	})
 
@@ -174,7 +174,7 @@ func TestTranslateSubtitlesWithProgress_NoPython(t *testing.T) {
 		{Index: 2, Text: "World"},
 	}
 
-	_, err := s.TranslateSubtitlesWithProgress(subs, "en", "de", func(current, total int) {
+	_, err := s.TranslateSubtitlesWithProgress(subs, "en", "de", nil, func(current, total int) {
 		// Progress callback
 	})
 
@@ -187,7 +187,7 @@ func TestTranslateSubtitlesWithProgress_EmptyList(t *testing.T) {
 	s := &TranslatorService{pythonPath: "python3"}
 	subs := models.SubtitleList{}
 
-	result, err := s.TranslateSubtitlesWithProgress(subs, "en", "de", nil)
+	result, err := s.TranslateSubtitlesWithProgress(subs, "en", "de", nil, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -218,3 +218,18 @@ func TestCheckLanguagePackage_NoPython(t *testing.T) {
 		t.Error("CheckLanguagePackage() should return error for nonexistent python")
 	}
 }
+
+func TestArgosCode(t *testing.T) {
+	tests := map[string]string{"no": "nb", "en": "en", "zh": "zh", "pt-BR": "pt"}
+	for lang, want := range tests {
+		if got := argosCode(lang); got != want {
+			t.Errorf("argosCode(%q) = %q, want %q", lang, got, want)
+		}
+	}
+	if _, ok := GetSupportedTargetLanguages()["no"]; !ok {
+		t.Error("Norwegian should be offered for Argos")
+	}
+	if _, ok := GetSupportedTargetLanguages()["yue"]; ok {
+		t.Error("Argos has no Cantonese package")
+	}
+}
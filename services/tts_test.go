@@ -1,10 +1,18 @@
 package services
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
+	"video-translator/internal/config"
+	"video-translator/internal/media"
+	"video-translator/internal/subtitle"
+	"video-translator/internal/text"
+	"video-translator/internal/tts"
 	"video-translator/models"
 )
 
@@ -50,7 +58,7 @@ func TestTTSService_GetVoice(t *testing.T) {
 
 func TestTTSService_getModelPath(t *testing.T) {
 	s := NewTTSService("en_US-amy-medium")
-	modelPath := s.getModelPath()
+	modelPath := s.getModelPath(s.voiceModel)
 	if !filepath.IsAbs(modelPath) {
 		t.Errorf("getModelPath() should return absolute path, got %q", modelPath)
 	}
@@ -90,7 +98,7 @@ func TestTTSService_Synthesize_EmptyText(t *testing.T) {
 
 func TestTTSService_SynthesizeSubtitles_Empty(t *testing.T) {
 	s := NewTTSService("")
-	subs := models.SubtitleList{}
+	subs := subtitle.List{}
 	err := s.SynthesizeSubtitles(subs, "/tmp/output.wav")
 	if err == nil {
 		t.Error("SynthesizeSubtitles() should return error for empty subtitles")
@@ -99,7 +107,7 @@ func TestTTSService_SynthesizeSubtitles_Empty(t *testing.T) {
 
 func TestTTSService_SynthesizeWithCallback_Empty(t *testing.T) {
 	s := NewTTSService("")
-	subs := models.SubtitleList{}
+	subs := subtitle.List{}
 	err := s.SynthesizeWithCallback(subs, "/tmp/output.wav", nil)
 	if err == nil {
 		t.Error("SynthesizeWithCallback() should return error for empty subtitles")
@@ -109,7 +117,7 @@ func TestTTSService_SynthesizeWithCallback_Empty(t *testing.T) {
 func TestTTSService_Cleanup(t *testing.T) {
 	s := NewTTSService("")
 	// Create temp dir if it doesn't exist
-	os.MkdirAll(s.tempDir, 0755)
+	os.MkdirAll(s.TempDir, 0755)
 
 	err := s.Cleanup()
 	if err != nil {
@@ -117,7 +125,7 @@ func TestTTSService_Cleanup(t *testing.T) {
 	}
 
 	// Check that tempDir was removed
-	if _, err := os.Stat(s.tempDir); !os.IsNotExist(err) {
+	if _, err := os.Stat(s.TempDir); !os.IsNotExist(err) {
 		t.Error("Cleanup() should remove tempDir")
 	}
 }
@@ -215,13 +223,9 @@ func TestPiperVoices(t *testing.T) {
 func TestTTSService_SynthesizeSubtitles_WithGaps(t *testing.T) {
 	// This test verifies the logic handles gaps between subtitles
 	// Without actually calling piper (which may not be installed)
-	s := &TTSService{
-		piperPath:  "/nonexistent/piper",
-		voiceModel: "test",
-		voicesDir:  "/tmp",
-		ffmpeg:     NewFFmpegService(),
-		tempDir:    "/tmp/tts-test",
-	}
+	s := NewTTSService("test")
+	s.piperPath = "/nonexistent/piper"
+	s.voicesDir = "/tmp"
 
 	subs := models.SubtitleList{
 		{Index: 1, StartTime: 0, EndTime: time.Second, Text: "First"},
@@ -229,7 +233,7 @@ func TestTTSService_SynthesizeSubtitles_WithGaps(t *testing.T) {
 	}
 
 	// This will fail because piper doesn't exist, but exercises the code path
-	err := s.SynthesizeSubtitles(subs, "/tmp/output.wav")
+	err := s.SynthesizeSubtitles(models.ToInternalSubtitles(subs), "/tmp/output.wav")
 	if err == nil {
 		t.Error("SynthesizeSubtitles() should fail with nonexistent piper")
 	}
@@ -248,3 +252,239 @@ func containsStringHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestVoiceCatalogLanguages(t *testing.T) {
+	tests := []struct {
+		provider string
+		lang     string
+		want     bool
+	}{
+		{"edge-tts", "en", true},
+		{"edge-tts", "de-DE", true},
+		{"piper", "en", true},
+		{"piper", "de", true},
+		{"piper", "haw", false},
+		{"cosyvoice", "yue", true},
+		{"cosyvoice", "fr", false},
+		{"openai", "haw", true},
+	}
+	for _, tt := range tests {
+		if got := text.SupportsLanguage(text.StageTTS, tt.provider, tt.lang); got != tt.want {
+			t.Errorf("SupportsLanguage(tts, %s, %s) = %v, want %v", tt.provider, tt.lang, got, tt.want)
+		}
+	}
+}
+
+// fakeSynthesizer fails a segment's first failures[index] attempts
+type fakeSynthesizer struct {
+	mu        sync.Mutex
+	calls     map[int]int
+	failures  map[int]int
+	voices    []string
+	permanent bool
+}
+
+func (f *fakeSynthesizer) CheckInstalled() error  { return nil }
+func (f *fakeSynthesizer) CacheKey(string) string { return "fake" }
+
+func (f *fakeSynthesizer) SynthesizeSegment(seg tts.Segment, outputPath string) error {
+	f.mu.Lock()
+	f.calls[seg.Index]++
+	f.voices = append(f.voices, seg.Voice)
+	failing := f.calls[seg.Index] <= f.failures[seg.Index]
+	f.mu.Unlock()
+
+	if failing {
+		if f.permanent {
+			return tts.Permanent(fmt.Errorf("bad key"))
+		}
+		return fmt.Errorf("timeout")
+	}
+	seg.RecordUsage(UsageStageTTS, "fake", "", seg.Text)
+	return os.WriteFile(outputPath, []byte(seg.Text), 0644)
+}
+
+func newFakeEngine(t *testing.T, f *fakeSynthesizer) *tts.BaseTTS {
+	b := tts.NewBaseTTS("Fake", "video-translator-fake-tts", 1, f)
+	b.TempDir = t.TempDir()
+	b.RetryDelay = 0
+	return b
+}
+
+func fakeSubtitles(n int) subtitle.List {
+	subs := make(subtitle.List, n)
+	for i := range subs {
+		subs[i] = subtitle.Subtitle{
+			Index:     i + 1,
+			StartTime: time.Duration(i) * time.Second,
+			EndTime:   time.Duration(i+1) * time.Second,
+			Text:      fmt.Sprintf("line %d", i),
+		}
+	}
+	return subs
+}
+
+func TestBaseTTS_RetriesTransientErrors(t *testing.T) {
+	f := &fakeSynthesizer{calls: map[int]int{}, failures: map[int]int{0: config.TTSSegmentAttempts - 1}}
+	b := newFakeEngine(t, f)
+	cache := tts.NewSegmentCache(t.TempDir(), 0)
+	b.SetCache(cache)
+
+	// Without ffmpeg the run can only fail at assembly, after synthesis
+	err := b.SynthesizeSubtitles(fakeSubtitles(1), filepath.Join(t.TempDir(), "out.wav"))
+	if err != nil && !containsString(err.Error(), "failed to assemble audio") {
+		t.Fatalf("run failed before assembly: %v", err)
+	}
+	if f.calls[0] != config.TTSSegmentAttempts {
+		t.Errorf("calls = %d, want %d", f.calls[0], config.TTSSegmentAttempts)
+	}
+	// Only a segment that was synthesized in the end is cached
+	key := tts.SegmentKey(f.CacheKey(""), tts.Segment{Text: "line 0"})
+	if !cache.Get(key, filepath.Join(t.TempDir(), "segment.wav")) {
+		t.Error("segment not kept after succeeding on its last attempt")
+	}
+}
+
+func TestBaseTTS_PassesRunVoice(t *testing.T) {
+	f := &fakeSynthesizer{calls: map[int]int{}}
+	b := newFakeEngine(t, f)
+
+	opts := tts.RunOptions{Voice: "job-voice"}
+	b.SynthesizeWithOptions(fakeSubtitles(3), filepath.Join(t.TempDir(), "out.wav"), opts, nil)
+	if len(f.voices) != 3 {
+		t.Fatalf("synthesized %d segments, want 3", len(f.voices))
+	}
+	for _, v := range f.voices {
+		if v != "job-voice" {
+			t.Errorf("segment voice = %q, want the run's voice", v)
+		}
+	}
+}
+
+func TestBaseTTS_PermanentErrorStopsRun(t *testing.T) {
+	f := &fakeSynthesizer{calls: map[int]int{}, failures: map[int]int{0: 1}, permanent: true}
+	b := newFakeEngine(t, f)
+
+	err := b.SynthesizeSubtitles(fakeSubtitles(5), filepath.Join(t.TempDir(), "out.wav"))
+	if !tts.IsPermanent(err) {
+		t.Fatalf("err = %v, want a permanent error", err)
+	}
+	total := 0
+	for _, n := range f.calls {
+		total += n
+	}
+	if total != 1 {
+		t.Errorf("provider called %d times, want 1 (no retries, no further segments)", total)
+	}
+}
+
+func TestBaseTTS_TooManyFailuresStopsRun(t *testing.T) {
+	failures := map[int]int{}
+	for i := 0; i < 3; i++ {
+		failures[i] = config.TTSSegmentAttempts
+	}
+	f := &fakeSynthesizer{calls: map[int]int{}, failures: failures}
+	b := newFakeEngine(t, f)
+
+	err := b.SynthesizeSubtitles(fakeSubtitles(10), filepath.Join(t.TempDir(), "out.wav"))
+	if err == nil || !containsString(err.Error(), "segments failed") {
+		t.Fatalf("err = %v, want too many failed segments", err)
+	}
+}
+
+func TestBaseTTS_ReusesCachedSegments(t *testing.T) {
+	cache := tts.NewSegmentCache(t.TempDir(), 0)
+	f := &fakeSynthesizer{calls: map[int]int{}}
+	b := newFakeEngine(t, f)
+	b.SetCache(cache)
+
+	subs := fakeSubtitles(3)
+	output := filepath.Join(t.TempDir(), "out.wav")
+	b.SynthesizeSubtitles(subs, output) // Assembly needs ffmpeg; segments are cached before it
+
+	f.calls = map[int]int{}
+	b.SynthesizeSubtitles(subs, output)
+	if len(f.calls) != 0 {
+		t.Errorf("provider called for %d segments on the second run, want 0", len(f.calls))
+	}
+}
+
+func TestPermanentForStatus(t *testing.T) {
+	base := fmt.Errorf("api error")
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{401, true},
+		{402, true},
+		{403, true},
+		{429, false},
+		{500, false},
+	}
+	for _, tt := range tests {
+		if got := tts.IsPermanent(tts.PermanentForStatus(tt.status, base)); got != tt.want {
+			t.Errorf("PermanentForStatus(%d) permanent = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentKey_IncludesRate(t *testing.T) {
+	seg := tts.Segment{Text: "Hello"}
+	normal := tts.SegmentKey("piper|amy", seg)
+
+	seg.Rate = 1
+	if got := tts.SegmentKey("piper|amy", seg); got != normal {
+		t.Error("rate 1 should share the key of an unset rate")
+	}
+	seg.Rate = 1.2
+	if got := tts.SegmentKey("piper|amy", seg); got == normal {
+		t.Error("a faster rate should get its own key")
+	}
+}
+
+func TestTTSProviders_MaxRate(t *testing.T) {
+	openai := NewOpenAITTSService("key", OpenAITTSModelStandard, "nova", 2.0)
+	if got := openai.MaxRate(); got != 2.0 {
+		t.Errorf("OpenAI at speed 2 MaxRate = %v, want 2 (API caps at 4)", got)
+	}
+	fish := NewFishAudioTTSService("key", "", "", 1.0)
+	if got := fish.MaxRate(); got != 2.0 {
+		t.Errorf("Fish Audio MaxRate = %v, want 2", got)
+	}
+	var _ tts.RateSynthesizer = NewEdgeTTSService("")
+	var _ tts.RateSynthesizer = NewTTSService("")
+}
+
+// fakeRateSynthesizer speaks half a second per character, divided by rate
+type fakeRateSynthesizer struct {
+	ffmpeg *media.FFmpegService
+	rates  []float64
+}
+
+func (f *fakeRateSynthesizer) CheckInstalled() error  { return nil }
+func (f *fakeRateSynthesizer) CacheKey(string) string { return "" }
+func (f *fakeRateSynthesizer) MaxRate() float64       { return 2 }
+
+func (f *fakeRateSynthesizer) SynthesizeSegment(seg tts.Segment, outputPath string) error {
+	f.rates = append(f.rates, seg.SpeakingRate())
+	return f.ffmpeg.GenerateSilence(float64(len(seg.Text))*0.5/seg.SpeakingRate(), outputPath)
+}
+
+func TestBaseTTS_RefitsLongSegmentsNatively(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not available")
+	}
+	f := &fakeRateSynthesizer{ffmpeg: media.NewFFmpegService()}
+	b := tts.NewBaseTTS("Fake", "video-translator-fake-tts", 1, f)
+	b.TempDir = t.TempDir()
+
+	// 6 characters = 3s of speech for a 2.5s window
+	subs := subtitle.List{{Index: 1, StartTime: 0, EndTime: 2500 * time.Millisecond, Text: "abcdef"}}
+	opts := tts.RunOptions{RateMin: 1.05, RateMax: 1.5}
+	if err := b.SynthesizeWithOptions(subs, filepath.Join(t.TempDir(), "out.wav"), opts, nil); err != nil {
+		t.Fatalf("SynthesizeWithOptions() error: %v", err)
+	}
+	if len(f.rates) < 2 || f.rates[1] <= 1.2 || f.rates[1] > 1.5 {
+		t.Errorf("rates = %v, want a re-synthesis at about 1.24x", f.rates)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"video-translator/internal/logger"
+	"video-translator/models"
+)
+
+// Usage stages, the first part of a price key
+const (
+	UsageStageTranscription = "transcription"
+	UsageStageTranslation   = "translation"
+	UsageStageTTS           = "tts"
+)
+
+// UsageRecord is one provider call (or one job's worth of a stage, for
+// transcription) and what it cost
+type UsageRecord struct {
+	Time     time.Time `json:"time"`
+	JobID    string    `json:"job_id,omitempty"`
+	Job      string    `json:"job,omitempty"` // Input file name, for reports
+	Stage    string    `json:"stage"`
+	Provider string    `json:"provider"`
+	Model    string    `json:"model,omitempty"`
+
+	InputTokens  int     `json:"input_tokens,omitempty"`
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	Characters   int     `json:"characters,omitempty"`
+	AudioSeconds float64 `json:"audio_seconds,omitempty"`
+	Cost         float64 `json:"cost"` // USD, from the price table at the time
+}
+
+// UsageLedger appends usage records to a JSON-lines file so totals survive
+// restarts. Records are priced when they are written.
+type UsageLedger struct {
+	path   string
+	config *models.Config // Price table
+
+	mu    sync.Mutex
+	daily map[string]float64 // Cost per local date, read from the file once (nil = not read yet)
+}
+
+// DefaultUsageLedgerPath returns where the usage ledger is stored
+func DefaultUsageLedgerPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "video-translator", "usage.jsonl")
+}
+
+// NewUsageLedger returns a ledger at path priced with cfg's price table
+func NewUsageLedger(path string, cfg *models.Config) *UsageLedger {
+	return &UsageLedger{path: path, config: cfg}
+}
+
+// Record prices r and appends it
+func (l *UsageLedger) Record(r UsageRecord) (UsageRecord, error) {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if price, ok := l.config.Price(r.Stage, r.Provider, r.Model); ok {
+		r.Cost = price.Cost(r.InputTokens, r.OutputTokens, r.Characters, r.AudioSeconds)
+	}
+	rateLimiterFor(r.Stage, r.Provider).Observe(r)
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return r, fmt.Errorf("failed to encode usage record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return r, fmt.Errorf("failed to create usage ledger directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return r, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return r, fmt.Errorf("failed to write usage ledger: %w", err)
+	}
+	if l.daily != nil {
+		l.daily[usageDate(r.Time)] += r.Cost
+	}
+	return r, nil
+}
+
+// Records reads the whole ledger, oldest first. A missing file is an empty
+// ledger; unreadable lines (a write cut short) are skipped.
+func (l *UsageLedger) Records() ([]UsageRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.records()
+}
+
+// records reads the ledger file; caller must hold l.mu
+func (l *UsageLedger) records() ([]UsageRecord, error) {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []UsageRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r UsageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			logger.LogDebug("Usage ledger: skipping bad line: %v", err)
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+	return records, nil
+}
+
+// SpentOn returns the cost recorded on day's local date. The file is read
+// once; after that Record keeps the daily totals up to date.
+func (l *UsageLedger) SpentOn(day time.Time) (float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.daily == nil {
+		records, err := l.records()
+		if err != nil {
+			return 0, err
+		}
+		l.daily = make(map[string]float64)
+		for _, r := range records {
+			l.daily[usageDate(r.Time)] += r.Cost
+		}
+	}
+	return l.daily[usageDate(day)], nil
+}
+
+// usageDate is the local date t falls on, the key of the daily totals
+func usageDate(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// Clear deletes the ledger
+func (l *UsageLedger) Clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear usage ledger: %w", err)
+	}
+	l.daily = nil
+	return nil
+}
+
+// UsageMeter records usage against one job. Services hold one while
+// working for a job; a nil meter records nothing.
+type UsageMeter struct {
+	ledger *UsageLedger
+	jobID  string
+	job    string
+
+	mu    sync.Mutex
+	total UsageTotal
+}
+
+// Meter returns a meter that records against job
+func (l *UsageLedger) Meter(job *models.TranslationJob) *UsageMeter {
+	return &UsageMeter{
+		ledger: l,
+		jobID:  job.ID,
+		job:    filepath.Base(job.InputPath),
+		total:  UsageTotal{Key: job.ID},
+	}
+}
+
+// Record adds r to the ledger and the job's running total. Ledger errors
+// are logged: losing a record must not fail a job.
+func (m *UsageMeter) Record(r UsageRecord) {
+	if m == nil {
+		return
+	}
+	r.JobID = m.jobID
+	r.Job = m.job
+	r, err := m.ledger.Record(r)
+	if err != nil {
+		logger.LogError("Usage: %v", err)
+	}
+	m.mu.Lock()
+	m.total.add(r)
+	m.mu.Unlock()
+}
+
+// RecordCharacters records a TTS call for text
+func (m *UsageMeter) RecordCharacters(stage, provider, model, text string) {
+	m.Record(UsageRecord{Stage: stage, Provider: provider, Model: model, Characters: utf8.RuneCountInString(text)})
+}
+
+// Total returns what the job has used so far
+func (m *UsageMeter) Total() UsageTotal {
+	if m == nil {
+		return UsageTotal{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.total
+}
+
+// UsageTotal sums the records sharing a key (a job, day or provider)
+type UsageTotal struct {
+	Key          string
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+	Characters   int
+	AudioSeconds float64
+	Cost         float64
+	Last         time.Time // Most recent record, for sorting jobs
+}
+
+func (t *UsageTotal) add(r UsageRecord) {
+	t.Calls++
+	t.InputTokens += r.InputTokens
+	t.OutputTokens += r.OutputTokens
+	t.Characters += r.Characters
+	t.AudioSeconds += r.AudioSeconds
+	t.Cost += r.Cost
+	if r.Time.After(t.Last) {
+		t.Last = r.Time
+	}
+}
+
+// Usage report groupings
+const (
+	UsageByJob      = "job"
+	UsageByDay      = "day"
+	UsageByProvider = "provider"
+)
+
+// usageKey returns the group of r in a report grouped by by
+func usageKey(r UsageRecord, by string) (string, error) {
+	switch by {
+	case UsageByJob:
+		if r.Job == "" {
+			return r.JobID, nil
+		}
+		return fmt.Sprintf("%s (%s)", r.Job, shortJobID(r.JobID)), nil
+	case UsageByDay:
+		return usageDate(r.Time), nil
+	case UsageByProvider:
+		return r.Stage + "/" + r.Provider, nil
+	}
+	return "", fmt.Errorf("unknown usage grouping %q (use job, day or provider)", by)
+}
+
+func shortJobID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// SummarizeUsage totals records grouped by job, day or provider. Days and
+// jobs come newest first; providers most expensive first.
+func SummarizeUsage(records []UsageRecord, by string) ([]UsageTotal, error) {
+	totals := make(map[string]*UsageTotal)
+	for _, r := range records {
+		key, err := usageKey(r, by)
+		if err != nil {
+			return nil, err
+		}
+		t, ok := totals[key]
+		if !ok {
+			t = &UsageTotal{Key: key}
+			totals[key] = t
+		}
+		t.add(r)
+	}
+
+	result := make([]UsageTotal, 0, len(totals))
+	for _, t := range totals {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		switch by {
+		case UsageByProvider:
+			if result[i].Cost != result[j].Cost {
+				return result[i].Cost > result[j].Cost
+			}
+			return result[i].Key < result[j].Key
+		case UsageByDay:
+			return result[i].Key > result[j].Key
+		default:
+			return result[i].Last.After(result[j].Last)
+		}
+	})
+	return result, nil
+}
+
+// WriteUsageReport prints the ledger's totals grouped by by, with a grand
+// total
+func WriteUsageReport(w io.Writer, records []UsageRecord, by string) error {
+	totals, err := SummarizeUsage(records, by)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\tCALLS\tTOKENS IN\tTOKENS OUT\tCHARS\tAUDIO MIN\tCOST USD\t\n", by)
+	var grand UsageTotal
+	for _, t := range totals {
+		writeUsageRow(tw, t.Key, t)
+		grand.Calls += t.Calls
+		grand.InputTokens += t.InputTokens
+		grand.OutputTokens += t.OutputTokens
+		grand.Characters += t.Characters
+		grand.AudioSeconds += t.AudioSeconds
+		grand.Cost += t.Cost
+	}
+	writeUsageRow(tw, "total", grand)
+	return tw.Flush()
+}
+
+func writeUsageRow(w io.Writer, label string, t UsageTotal) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f\t%s\t\n",
+		label, t.Calls, t.InputTokens, t.OutputTokens, t.Characters, t.AudioSeconds/60, FormatCost(t.Cost))
+}
+
+// FormatCost formats a USD amount, with more precision for small amounts
+func FormatCost(cost float64) string {
+	if cost > 0 && cost < 0.01 {
+		return fmt.Sprintf("$%.4f", cost)
+	}
+	return fmt.Sprintf("$%.2f", cost)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+func newTestUsageLedger(t *testing.T) *UsageLedger {
+	t.Helper()
+	return NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"), models.DefaultConfig())
+}
+
+func TestUsageLedger_RecordAndRead(t *testing.T) {
+	ledger := newTestUsageLedger(t)
+	meter := ledger.Meter(&models.TranslationJob{ID: "0123456789abcdef", InputPath: "/videos/talk.mp4"})
+
+	meter.Record(UsageRecord{Stage: UsageStageTranscription, Provider: "openai", Model: "whisper-1", AudioSeconds: 120})
+	meter.RecordCharacters(UsageStageTTS, "openai", "tts-1", strings.Repeat("ж", 1000))
+	meter.RecordCharacters(UsageStageTTS, "piper", "en_US-amy-medium", "free")
+
+	total := meter.Total()
+	if total.Calls != 3 || total.Characters != 1004 || total.AudioSeconds != 120 {
+		t.Errorf("total = %+v", total)
+	}
+	// 2 min at $0.006 + 1000 chars at $15/M
+	if want := 0.012 + 0.015; !closeTo(total.Cost, want) {
+		t.Errorf("cost = %v, want %v", total.Cost, want)
+	}
+
+	records, err := ledger.Records()
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("%d records, want 3", len(records))
+	}
+	if r := records[1]; r.JobID != "0123456789abcdef" || r.Job != "talk.mp4" || r.Characters != 1000 || !closeTo(r.Cost, 0.015) {
+		t.Errorf("record = %+v", r)
+	}
+	if records[2].Cost != 0 {
+		t.Errorf("piper cost = %v, want 0", records[2].Cost)
+	}
+}
+
+func TestUsageLedger_SkipsBadLines(t *testing.T) {
+	ledger := newTestUsageLedger(t)
+	if records, err := ledger.Records(); err != nil || records != nil {
+		t.Errorf("missing ledger = %v, %v", records, err)
+	}
+
+	ledger.Record(UsageRecord{Stage: UsageStageTTS, Provider: "edge-tts", Characters: 5})
+	f, _ := os.OpenFile(ledger.path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("{\"stage\": \"tts\", \"prov\n")
+	f.Close()
+	ledger.Record(UsageRecord{Stage: UsageStageTTS, Provider: "edge-tts", Characters: 7})
+
+	records, err := ledger.Records()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("records = %v, %v; want the 2 good lines", records, err)
+	}
+
+	if err := ledger.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if records, _ := ledger.Records(); len(records) != 0 {
+		t.Errorf("%d records after Clear", len(records))
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	records := []UsageRecord{
+		{Time: day1, JobID: "job-a", Job: "a.mp4", Stage: UsageStageTranslation, Provider: "deepseek", InputTokens: 100, Cost: 0.5},
+		{Time: day1, JobID: "job-a", Job: "a.mp4", Stage: UsageStageTTS, Provider: "openai", Characters: 50, Cost: 1},
+		{Time: day2, JobID: "job-b", Job: "b.mp4", Stage: UsageStageTTS, Provider: "openai", Characters: 20, Cost: 2},
+	}
+
+	byJob, err := SummarizeUsage(records, UsageByJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byJob) != 2 || byJob[0].Key != "b.mp4 (job-b)" || byJob[1].Calls != 2 || byJob[1].Cost != 1.5 {
+		t.Errorf("by job = %+v", byJob)
+	}
+
+	byDay, _ := SummarizeUsage(records, UsageByDay)
+	if len(byDay) != 2 || byDay[0].Key != "2026-03-02" || byDay[1].InputTokens != 100 {
+		t.Errorf("by day = %+v", byDay)
+	}
+
+	byProvider, _ := SummarizeUsage(records, UsageByProvider)
+	if len(byProvider) != 2 || byProvider[0].Key != "tts/openai" || byProvider[0].Characters != 70 {
+		t.Errorf("by provider = %+v", byProvider)
+	}
+
+	if _, err := SummarizeUsage(records, "week"); err == nil {
+		t.Error("unknown grouping should fail")
+	}
+}
+
+func TestWriteUsageReport(t *testing.T) {
+	records := []UsageRecord{
+		{Time: time.Now(), Stage: UsageStageTranscription, Provider: "groq", AudioSeconds: 90, Cost: 0.0028},
+		{Time: time.Now(), Stage: UsageStageTTS, Provider: "fish-audio", Characters: 1000, Cost: 0.015},
+	}
+	var buf bytes.Buffer
+	if err := WriteUsageReport(&buf, records, UsageByProvider); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("report:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], "tts/fish-audio") || !strings.Contains(lines[2], "$0.0028") {
+		t.Errorf("rows not sorted by cost:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[3]), "total") || !strings.Contains(lines[3], "$0.02") {
+		t.Errorf("total row = %q", lines[3])
+	}
+}
+
+func TestUsageMeter_Nil(t *testing.T) {
+	var meter *UsageMeter
+	meter.Record(UsageRecord{Stage: UsageStageTTS, Provider: "openai", Characters: 10})
+	meter.RecordCharacters(UsageStageTTS, "openai", "tts-1", "text")
+	if total := meter.Total(); total.Calls != 0 {
+		t.Errorf("nil meter total = %+v", total)
+	}
+}
+
+func TestLLMTranslationService_RecordsUsage(t *testing.T) {
+	var requests int32
+	server := fakeChatServer(t, &requests, nil, nil)
+	defer server.Close()
+
+	cfg := testLLMConfig(server.URL)
+	cfg.Provider = "deepseek"
+	cfg.Model = "deepseek-chat"
+	s := NewLLMTranslationService(cfg)
+	ledger := newTestUsageLedger(t)
+	meter := ledger.Meter(&models.TranslationJob{ID: "job", InputPath: "clip.mp4"})
+	other := ledger.Meter(&models.TranslationJob{ID: "other", InputPath: "other.mp4"})
+
+	subs := models.SubtitleList{{Index: 1, Text: "one"}, {Index: 2, Text: "two"}, {Index: 3, Text: "three"}}
+	if _, err := s.TranslateSubtitles(subs, "en", "de", TranslateOptions{Usage: meter}, nil); err != nil {
+		t.Fatalf("TranslateSubtitles: %v", err)
+	}
+	if other.Total().Calls != 0 {
+		t.Errorf("another job's meter recorded %d calls", other.Total().Calls)
+	}
+
+	total := meter.Total()
+	if total.Calls != int(requests) || total.InputTokens != 100*int(requests) || total.OutputTokens != 20*int(requests) {
+		t.Errorf("total = %+v after %d requests", total, requests)
+	}
+	want := float64(requests) * (100*0.28 + 20*0.42) / 1e6
+	if !closeTo(total.Cost, want) {
+		t.Errorf("cost = %v, want %v", total.Cost, want)
+	}
+}
+
+func TestVoiceSynthesizer_RecordsUsage(t *testing.T) {
+	ledger := newTestUsageLedger(t)
+	meter := ledger.Meter(&models.TranslationJob{ID: "job", InputPath: "clip.mp4"})
+	f := &fakeSynthesizer{calls: map[int]int{}}
+
+	synth := voiceSynthesizer{svc: f, voice: "alloy", usage: meter}
+	if err := synth.Synthesize("hello", filepath.Join(t.TempDir(), "rate.wav")); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if total := meter.Total(); total.Calls != 1 || total.Characters != 5 {
+		t.Errorf("total = %+v, want the voice-rate probe recorded", total)
+	}
+	if len(f.voices) != 1 || f.voices[0] != "alloy" {
+		t.Errorf("voices = %v", f.voices)
+	}
+}
+
+func closeTo(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+	"video-translator/models"
+)
+
+// vocabWordRegex matches a word, keeping inner apostrophes, dots and hyphens
+// so "O'Neil", "Node.js" and "Wi-Fi" stay single tokens
+var vocabWordRegex = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’.\-][\p{L}\p{N}]+)*`)
+
+// minFuzzyTermLength skips short terms entirely; below 6 letters too many
+// common words are one edit away ("Go" vs "go", "Sarah" vs "sarah").
+const minFuzzyTermLength = 6
+
+// vocabTerm is a canonical spelling prepared for matching
+type vocabTerm struct {
+	canonical string
+	spaced    string // lowercase, words separated by single spaces
+	joined    string // lowercase, no spaces ("open ai" vs "openai")
+	upper     bool   // canonical starts with an uppercase letter
+	words     int
+	allowed   int // max edit distance, ~20% of the term length
+}
+
+// VocabularyReplacement records one snap to a canonical spelling
+type VocabularyReplacement struct {
+	Index    int
+	Original string
+	Term     string
+}
+
+// ApplyVocabulary snaps near-miss spellings in subtitles to the canonical
+// terms, e.g. "Open AI" -> "OpenAI" or "Kubernets" -> "Kubernetes".
+// Same-letter matches are case-insensitive. Near misses (~20% edits) only
+// snap when the words are capitalized like the term, so an ordinary
+// lowercase word ("parked") is never rewritten to a name ("Parker").
+// Terms shorter than minFuzzyTermLength are left to the ASR prompt alone.
+func ApplyVocabulary(subs models.SubtitleList, terms []string) (models.SubtitleList, []VocabularyReplacement) {
+	prepared := prepareVocabTerms(terms)
+	if len(prepared) == 0 {
+		return subs, nil
+	}
+
+	var replacements []VocabularyReplacement
+	result := make(models.SubtitleList, len(subs))
+	for i, sub := range subs {
+		fixed, reps := snapToVocabulary(sub.Text, prepared)
+		sub.Text = fixed
+		result[i] = sub
+		for _, r := range reps {
+			r.Index = sub.Index
+			replacements = append(replacements, r)
+			logger.LogDebug("Vocabulary: #%d %q -> %q", sub.Index, r.Original, r.Term)
+		}
+	}
+
+	if len(replacements) > 0 {
+		logger.LogInfo("Vocabulary: corrected %d term spellings", len(replacements))
+	}
+	return result, replacements
+}
+
+func prepareVocabTerms(terms []string) []vocabTerm {
+	var prepared []vocabTerm
+	for _, term := range terms {
+		words := strings.Fields(term)
+		if len(words) == 0 {
+			continue
+		}
+		joined := strings.ToLower(strings.Join(words, ""))
+		if len([]rune(joined)) < minFuzzyTermLength {
+			continue
+		}
+		prepared = append(prepared, vocabTerm{
+			canonical: strings.Join(words, " "),
+			spaced:    strings.ToLower(strings.Join(words, " ")),
+			joined:    joined,
+			upper:     unicode.IsUpper([]rune(words[0])[0]),
+			words:     len(words),
+			allowed:   len([]rune(joined)) / 5,
+		})
+	}
+	return prepared
+}
+
+// snapToVocabulary walks the words of s left to right and replaces the best
+// matching window of words with the canonical term.
+func snapToVocabulary(s string, terms []vocabTerm) (string, []VocabularyReplacement) {
+	locs := vocabWordRegex.FindAllStringIndex(s, -1)
+	if len(locs) == 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var replacements []VocabularyReplacement
+	last := 0
+
+	for i := 0; i < len(locs); {
+		bestTerm, bestWords, bestDist := -1, 0, 0
+
+		for ti, term := range terms {
+			// Try the term's own word count plus one either side, so
+			// split ("Open AI") and merged ("JohnSmith") forms both match
+			for n := max(1, term.words-1); n <= term.words+1 && i+n <= len(locs); n++ {
+				words := make([]string, n)
+				for k := 0; k < n; k++ {
+					words[k] = strings.ToLower(s[locs[i+k][0]:locs[i+k][1]])
+				}
+				if []rune(words[0])[0] != []rune(term.joined)[0] {
+					continue
+				}
+				dist := min(
+					text.Levenshtein(strings.Join(words, " "), term.spaced),
+					text.Levenshtein(strings.Join(words, ""), term.joined),
+				)
+				if dist > term.allowed {
+					continue
+				}
+				if dist > 0 && unicode.IsUpper([]rune(s[locs[i][0]:])[0]) != term.upper {
+					continue
+				}
+				if bestTerm < 0 || dist < bestDist || (dist == bestDist && n > bestWords) {
+					bestTerm, bestWords, bestDist = ti, n, dist
+				}
+			}
+		}
+
+		if bestTerm < 0 {
+			i++
+			continue
+		}
+
+		start, end := locs[i][0], locs[i+bestWords-1][1]
+		original := s[start:end]
+		canonical := terms[bestTerm].canonical
+		if original != canonical {
+			b.WriteString(s[last:start])
+			b.WriteString(canonical)
+			last = end
+			replacements = append(replacements, VocabularyReplacement{Original: original, Term: canonical})
+		}
+		i += bestWords
+	}
+
+	if len(replacements) == 0 {
+		return s, nil
+	}
+	b.WriteString(s[last:])
+	return b.String(), replacements
+}
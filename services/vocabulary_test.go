@@ -0,0 +1,75 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"video-translator/models"
+)
+
+func TestApplyVocabulary(t *testing.T) {
+	terms := []string{"OpenAI", "Kubernetes", "Anna Schmidt", "Go", "Parker"}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"We deployed it on Kubernets yesterday.", "We deployed it on Kubernetes yesterday."},
+		{"Open AI released a new model.", "OpenAI released a new model."},
+		{"openai is hiring", "OpenAI is hiring"},
+		{"Ask Ana Schmit about it.", "Ask Anna Schmidt about it."},
+		{"Let's go home.", "Let's go home."},                           // short terms are never snapped
+		{"The car was parked outside.", "The car was parked outside."}, // lowercase words aren't names
+		{"Ask parker about it.", "Ask Parker about it."},               // same letters still snap
+		{"Nothing to change here.", "Nothing to change here."},
+	}
+
+	for _, tt := range tests {
+		subs := models.SubtitleList{{Index: 1, Text: tt.input}}
+		got, _ := ApplyVocabulary(subs, terms)
+		if got[0].Text != tt.want {
+			t.Errorf("ApplyVocabulary(%q) = %q, want %q", tt.input, got[0].Text, tt.want)
+		}
+	}
+}
+
+func TestApplyVocabulary_ReportsReplacements(t *testing.T) {
+	subs := models.SubtitleList{
+		{Index: 1, Text: "Kubernets and Open AI"},
+		{Index: 2, Text: "Kubernetes is fine"},
+	}
+
+	got, reps := ApplyVocabulary(subs, []string{"Kubernetes", "OpenAI"})
+
+	if len(reps) != 2 {
+		t.Fatalf("got %d replacements, want 2: %+v", len(reps), reps)
+	}
+	if reps[0].Index != 1 || reps[0].Original != "Kubernets" || reps[0].Term != "Kubernetes" {
+		t.Errorf("unexpected first replacement: %+v", reps[0])
+	}
+	if subs[0].Text != "Kubernets and Open AI" {
+		t.Error("ApplyVocabulary() should not modify its input")
+	}
+	if got[1].Text != "Kubernetes is fine" {
+		t.Errorf("exact match should be left alone, got %q", got[1].Text)
+	}
+}
+
+func TestApplyVocabulary_NoTerms(t *testing.T) {
+	subs := models.SubtitleList{{Index: 1, Text: "hello"}}
+	got, reps := ApplyVocabulary(subs, nil)
+	if len(reps) != 0 || got[0].Text != "hello" {
+		t.Errorf("ApplyVocabulary(nil) changed subtitles: %+v", got)
+	}
+}
+
+func TestPythonPrompt(t *testing.T) {
+	if got := pythonPrompt(""); got != "None" {
+		t.Errorf("pythonPrompt() with no prompt = %q, want None", got)
+	}
+
+	got := pythonPrompt("Glossary: O'Neil, OpenAI.")
+	if !strings.HasPrefix(got, "'") || !strings.Contains(got, `O\'Neil`) {
+		t.Errorf("pythonPrompt() = %q, want escaped single-quoted literal", got)
+	}
+}
@@ -0,0 +1,573 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"video-translator/internal/config"
+	internalhttp "video-translator/internal/http"
+	"video-translator/internal/logger"
+	"video-translator/internal/text"
+)
+
+const (
+	piperVoicesIndexURL = "https://huggingface.co/rhasspy/piper-voices/resolve/main/voices.json"
+	fishAudioModelsURL  = "https://api.fish.audio/model?self=true&page_size=100"
+)
+
+// voiceCatalogMaxAge is how long a fetched catalog is used before
+// RefreshStale fetches it again
+const voiceCatalogMaxAge = 7 * 24 * time.Hour
+
+// Voice is one entry of a TTS provider's voice catalog
+type Voice struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Provider  string   `json:"provider"`
+	Locale    string   `json:"locale,omitempty"`    // BCP-47 ("en-US"); "" for voices not tied to one
+	Gender    string   `json:"gender,omitempty"`    // female, male; "" when unknown
+	Styles    []string `json:"styles,omitempty"`    // Personalities, tags or quality
+	Languages []string `json:"languages,omitempty"` // Codes spoken besides Locale's ("*" = any)
+}
+
+// Speaks reports whether the voice can read lang. Voices with neither a
+// locale nor languages (OpenAI, cloned samples) speak anything their
+// provider does.
+func (v Voice) Speaks(lang string) bool {
+	want, ok := text.LookupLanguage(lang)
+	if !ok {
+		return false
+	}
+	for _, code := range v.Languages {
+		if code == "*" {
+			return true
+		}
+		if l, ok := text.LookupLanguage(code); ok && l.Code == want.Code {
+			return true
+		}
+	}
+	if v.Locale != "" {
+		l, ok := text.LookupLanguage(v.Locale)
+		return ok && l.Code == want.Code
+	}
+	return len(v.Languages) == 0
+}
+
+// Label is how the voice is shown in pickers: "Aria (en-US, female)"
+func (v Voice) Label() string {
+	var details []string
+	if v.Locale != "" {
+		details = append(details, v.Locale)
+	}
+	if v.Gender != "" {
+		details = append(details, v.Gender)
+	}
+	if len(details) == 0 {
+		return v.Name
+	}
+	return fmt.Sprintf("%s (%s)", v.Name, strings.Join(details, ", "))
+}
+
+// voiceFetcher downloads a provider's current catalog
+type voiceFetcher func() ([]Voice, error)
+
+// VoiceCatalog lists the voices of each TTS provider. Catalogs are fetched
+// from the provider (edge-tts --list-voices, Piper's voices.json, the Fish
+// Audio model listing) and kept on disk, so pickers never wait on the
+// network; until a provider has been fetched its built-in voices are used.
+// CosyVoice's catalog is the voice samples on disk and is read directly.
+type VoiceCatalog struct {
+	dir string
+
+	// Where CosyVoice voice samples are looked for, plus the configured
+	// sample when it's kept elsewhere
+	SampleDir   string
+	CloneSample string
+
+	mu       sync.Mutex
+	voices   map[string][]Voice
+	fetchers map[string]voiceFetcher
+}
+
+// DefaultVoiceCatalogDir returns ~/.cache/video-translator/voices
+func DefaultVoiceCatalogDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cache", "video-translator", "voices")
+}
+
+// DefaultVoiceSampleDir returns where voice samples for cloning are kept
+func DefaultVoiceSampleDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "video-translator", "voice-samples")
+}
+
+// NewVoiceCatalog creates a catalog cached in dir. fishAudioAPIKey lists
+// the account's own Fish Audio voices ("" = featured voices only).
+func NewVoiceCatalog(dir, fishAudioAPIKey string) *VoiceCatalog {
+	c := &VoiceCatalog{
+		dir:       dir,
+		SampleDir: DefaultVoiceSampleDir(),
+		voices:    make(map[string][]Voice),
+	}
+	c.fetchers = map[string]voiceFetcher{
+		"edge-tts": fetchEdgeVoices,
+		"piper":    fetchPiperVoices,
+		"fish-audio": func() ([]Voice, error) {
+			return fetchFishAudioVoices(fishAudioAPIKey)
+		},
+	}
+
+	// Fetched catalogs widen the languages the voice-named providers offer
+	for _, provider := range []string{"edge-tts", "piper"} {
+		if voices, _, ok := c.load(provider); ok {
+			c.voices[provider] = voices
+			registerVoiceLanguages(provider, voices)
+		}
+	}
+	return c
+}
+
+// Voices returns provider's voices sorted by locale and name: the last fetched
+// catalog, else the built-in list
+func (c *VoiceCatalog) Voices(provider string) []Voice {
+	if provider == "cosyvoice" {
+		return c.sampleVoices()
+	}
+
+	c.mu.Lock()
+	voices, ok := c.voices[provider]
+	c.mu.Unlock()
+	if !ok {
+		if loaded, _, found := c.load(provider); found {
+			voices = loaded
+		} else {
+			voices = builtinVoices(provider)
+		}
+		c.mu.Lock()
+		c.voices[provider] = voices
+		c.mu.Unlock()
+	}
+	return voices
+}
+
+// VoicesFor returns provider's voices that can read lang
+func (c *VoiceCatalog) VoicesFor(provider, lang string) []Voice {
+	var result []Voice
+	for _, v := range c.Voices(provider) {
+		if v.Speaks(lang) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Refresh fetches provider's catalog and stores it on disk
+func (c *VoiceCatalog) Refresh(provider string) error {
+	fetch, ok := c.fetchers[provider]
+	if !ok {
+		return nil // Built-in or local catalog, nothing to fetch
+	}
+	voices, err := fetch()
+	if err != nil {
+		return fmt.Errorf("failed to list %s voices: %w", provider, err)
+	}
+	sortVoices(voices)
+
+	c.mu.Lock()
+	c.voices[provider] = voices
+	c.mu.Unlock()
+	registerVoiceLanguages(provider, voices)
+	logger.LogInfo("Voice catalog: %d %s voices", len(voices), provider)
+
+	return c.save(provider, voices)
+}
+
+// RefreshStale fetches every catalog that was never fetched or is older
+// than a week. Errors are logged; the previous catalog stays in use.
+func (c *VoiceCatalog) RefreshStale() {
+	for provider := range c.fetchers {
+		if _, fetchedAt, ok := c.load(provider); ok && time.Since(fetchedAt) < voiceCatalogMaxAge {
+			continue
+		}
+		if err := c.Refresh(provider); err != nil {
+			logger.LogError("Voice catalog: %v", err)
+		}
+	}
+}
+
+// voiceCatalogFile is the on-disk format of one provider's catalog
+type voiceCatalogFile struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Voices    []Voice   `json:"voices"`
+}
+
+func (c *VoiceCatalog) path(provider string) string {
+	return filepath.Join(c.dir, provider+".json")
+}
+
+func (c *VoiceCatalog) load(provider string) ([]Voice, time.Time, bool) {
+	data, err := os.ReadFile(c.path(provider))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var f voiceCatalogFile
+	if err := json.Unmarshal(data, &f); err != nil || len(f.Voices) == 0 {
+		return nil, time.Time{}, false
+	}
+	return f.Voices, f.FetchedAt, true
+}
+
+func (c *VoiceCatalog) save(provider string, voices []Voice) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create voice catalog dir: %w", err)
+	}
+	data, err := json.MarshalIndent(voiceCatalogFile{FetchedAt: time.Now(), Voices: voices}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode voice catalog: %w", err)
+	}
+	if err := os.WriteFile(c.path(provider), data, 0644); err != nil {
+		return fmt.Errorf("failed to write voice catalog: %w", err)
+	}
+	return nil
+}
+
+// sampleVoices lists the voice samples CosyVoice can clone
+func (c *VoiceCatalog) sampleVoices() []Voice {
+	var voices []Voice
+	entries, _ := os.ReadDir(c.SampleDir)
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".wav", ".mp3", ".flac", ".m4a":
+			voices = append(voices, Voice{
+				ID:       filepath.Join(c.SampleDir, e.Name()),
+				Name:     strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())),
+				Provider: "cosyvoice",
+			})
+		}
+	}
+	sortVoices(voices)
+
+	if c.CloneSample != "" && filepath.Dir(c.CloneSample) != c.SampleDir {
+		if _, err := os.Stat(c.CloneSample); err == nil {
+			name := filepath.Base(c.CloneSample)
+			voices = append([]Voice{{
+				ID:       c.CloneSample,
+				Name:     strings.TrimSuffix(name, filepath.Ext(name)),
+				Provider: "cosyvoice",
+			}}, voices...)
+		}
+	}
+	return voices
+}
+
+func sortVoices(voices []Voice) {
+	sort.SliceStable(voices, func(i, j int) bool {
+		if voices[i].Locale != voices[j].Locale {
+			return voices[i].Locale < voices[j].Locale
+		}
+		return voices[i].Name < voices[j].Name
+	})
+}
+
+// registerVoiceLanguages makes the languages of a fetched catalog the
+// provider's TTS languages, for providers whose voices are per locale
+func registerVoiceLanguages(provider string, voices []Voice) {
+	if provider != "edge-tts" && provider != "piper" {
+		return
+	}
+	var codes []string
+	for _, v := range voices {
+		if v.Locale != "" {
+			codes = append(codes, v.Locale)
+		}
+	}
+	text.SetProviderLanguages(text.StageTTS, provider, codes)
+}
+
+// builtinVoices are the voices known without fetching a catalog
+func builtinVoices(provider string) []Voice {
+	var voices []Voice
+	switch provider {
+	case "edge-tts":
+		for id, desc := range EdgeTTSVoices {
+			voices = append(voices, Voice{ID: id, Name: edgeVoiceName(id), Provider: provider,
+				Locale: edgeVoiceLocale(id), Gender: genderFromDescription(desc)})
+		}
+	case "piper":
+		for id, desc := range PiperVoices {
+			voices = append(voices, Voice{ID: id, Name: piperVoiceName(id), Provider: provider,
+				Locale: piperVoiceLocale(id), Gender: genderFromDescription(desc)})
+		}
+	case "openai":
+		for id, desc := range OpenAIVoices {
+			voices = append(voices, Voice{ID: id, Name: strings.SplitN(desc, " (", 2)[0], Provider: provider,
+				Gender: genderFromDescription(desc)})
+		}
+	case "fish-audio":
+		voices = append(voices, fishAudioFeaturedVoices...)
+	}
+	sortVoices(voices)
+	return voices
+}
+
+// genderFromDescription reads "Female"/"Male" out of a voice description
+func genderFromDescription(desc string) string {
+	lower := strings.ToLower(desc)
+	switch {
+	case strings.Contains(lower, "female"):
+		return "female"
+	case strings.Contains(lower, "male"):
+		return "male"
+	}
+	return ""
+}
+
+// --- Edge TTS ---
+
+// fetchEdgeVoices runs edge-tts --list-voices
+func fetchEdgeVoices() ([]Voice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.EdgeTTSTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, findExecutable("edge-tts"), "--list-voices").Output()
+	if err != nil {
+		return nil, fmt.Errorf("edge-tts --list-voices: %w", err)
+	}
+	voices := parseEdgeVoiceList(string(output))
+	if len(voices) == 0 {
+		return nil, fmt.Errorf("edge-tts listed no voices")
+	}
+	return voices, nil
+}
+
+var edgeColumnSeparator = regexp.MustCompile(`\s{2,}`)
+
+// parseEdgeVoiceList reads edge-tts --list-voices output: a table with
+// Name, Gender, ContentCategories and VoicePersonalities columns (edge-tts
+// 6.1.10+), or "Name: ..." / "Gender: ..." blocks (older releases)
+func parseEdgeVoiceList(output string) []Voice {
+	var voices []Voice
+	var current *Voice
+	var header []string
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r ")
+		switch {
+		case strings.HasPrefix(line, "Name: "):
+			voices = append(voices, newEdgeVoice(strings.TrimSpace(strings.TrimPrefix(line, "Name: "))))
+			current = &voices[len(voices)-1]
+		case strings.HasPrefix(line, "Gender: ") && current != nil:
+			current.Gender = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "Gender: ")))
+		case strings.HasPrefix(line, "VoicePersonalities: ") && current != nil:
+			current.Styles = splitList(strings.TrimPrefix(line, "VoicePersonalities: "))
+		case strings.HasPrefix(line, "Name ") && strings.Contains(line, "Gender"):
+			header = edgeColumnSeparator.Split(strings.TrimSpace(line), -1)
+		case header != nil && line != "" && !strings.HasPrefix(line, "---"):
+			voices = append(voices, edgeVoiceFromRow(header, edgeColumnSeparator.Split(strings.TrimSpace(line), -1)))
+		}
+	}
+	return voices
+}
+
+func edgeVoiceFromRow(header, fields []string) Voice {
+	v := newEdgeVoice(fields[0])
+	for i, column := range header {
+		if i >= len(fields) {
+			break
+		}
+		switch column {
+		case "Gender":
+			v.Gender = strings.ToLower(fields[i])
+		case "VoicePersonalities":
+			v.Styles = splitList(fields[i])
+		}
+	}
+	return v
+}
+
+func newEdgeVoice(id string) Voice {
+	v := Voice{ID: id, Name: edgeVoiceName(id), Provider: "edge-tts", Locale: edgeVoiceLocale(id)}
+	if strings.Contains(id, "Multilingual") {
+		v.Languages = []string{"*"}
+	}
+	return v
+}
+
+// edgeVoiceLocale returns "en-US" for "en-US-AriaNeural"
+func edgeVoiceLocale(id string) string {
+	parts := strings.SplitN(id, "-", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[0] + "-" + parts[1]
+}
+
+// edgeVoiceName returns "Aria" for "en-US-AriaNeural" and "Xiaobei" for
+// "zh-CN-liaoning-XiaobeiNeural"
+func edgeVoiceName(id string) string {
+	name := id[strings.LastIndex(id, "-")+1:]
+	name = strings.TrimSuffix(name, "Neural")
+	return strings.TrimSuffix(name, "Multilingual")
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// --- Piper ---
+
+// fetchPiperVoices downloads Piper's voices.json index
+func fetchPiperVoices() ([]Voice, error) {
+	resp, err := internalhttp.NewDefaultClient().Get(piperVoicesIndexURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("voices.json download failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parsePiperVoicesIndex(data)
+}
+
+// parsePiperVoicesIndex reads the voices.json of rhasspy/piper-voices. The
+// index has no gender, so it's taken from the built-in list where known.
+func parsePiperVoicesIndex(data []byte) ([]Voice, error) {
+	var index map[string]struct {
+		Name     string `json:"name"`
+		Quality  string `json:"quality"`
+		Speakers int    `json:"num_speakers"`
+		Language struct {
+			Code string `json:"code"`
+		} `json:"language"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse voices.json: %w", err)
+	}
+
+	voices := make([]Voice, 0, len(index))
+	for id, entry := range index {
+		v := Voice{
+			ID:       id,
+			Name:     piperVoiceName(id),
+			Provider: "piper",
+			Locale:   strings.ReplaceAll(entry.Language.Code, "_", "-"),
+			Gender:   genderFromDescription(PiperVoices[id]),
+		}
+		if entry.Quality != "" {
+			v.Styles = append(v.Styles, entry.Quality)
+		}
+		if entry.Speakers > 1 {
+			v.Styles = append(v.Styles, fmt.Sprintf("%d speakers", entry.Speakers))
+		}
+		voices = append(voices, v)
+	}
+	return voices, nil
+}
+
+// piperVoiceLocale returns "en-US" for "en_US-amy-medium"
+func piperVoiceLocale(id string) string {
+	return strings.ReplaceAll(strings.SplitN(id, "-", 2)[0], "_", "-")
+}
+
+// piperVoiceName returns "Amy" for "en_US-amy-medium"
+func piperVoiceName(id string) string {
+	parts := strings.Split(id, "-")
+	if len(parts) < 2 || parts[1] == "" {
+		return id
+	}
+	return strings.ToUpper(parts[1][:1]) + parts[1][1:]
+}
+
+// --- Fish Audio ---
+
+// fishAudioFeaturedVoices are public Fish Audio voices offered to everyone
+// (browse more at https://fish.audio/discover)
+var fishAudioFeaturedVoices = []Voice{
+	{ID: "933563129e564b19a115bedd57b7406a", Name: "Sarah", Provider: "fish-audio", Gender: "female"},
+	{ID: "bf322df2096a46f18c579d0baa36f41d", Name: "Adrian", Provider: "fish-audio", Gender: "male"},
+	{ID: "b347db033a6549378b48d00acb0d06cd", Name: "Selene", Provider: "fish-audio", Gender: "female"},
+	{ID: "536d3a5e000945adb7038665781a4aca", Name: "Ethan", Provider: "fish-audio", Gender: "male"},
+	{ID: "802e3bc2b27e49c2995d23ef70e6ac89", Name: "Energetic Male", Provider: "fish-audio", Gender: "male"},
+	{ID: "8ef4a238714b45718ce04243307c57a7", Name: "E-girl", Provider: "fish-audio", Gender: "female"},
+}
+
+// fetchFishAudioVoices lists the account's own voice models, followed by
+// the featured voices
+func fetchFishAudioVoices(apiKey string) ([]Voice, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("Fish Audio API key is required")
+	}
+	req, err := http.NewRequest("GET", fishAudioModelsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := fishAudioClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fish Audio API error (status %d): %s", resp.StatusCode, string(data))
+	}
+
+	voices, err := parseFishAudioModels(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(voices, fishAudioFeaturedVoices...), nil
+}
+
+// parseFishAudioModels reads a Fish Audio GET /model listing
+func parseFishAudioModels(data []byte) ([]Voice, error) {
+	var listing struct {
+		Items []struct {
+			ID        string   `json:"_id"`
+			Title     string   `json:"title"`
+			Languages []string `json:"languages"`
+			Tags      []string `json:"tags"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &listing); err != nil {
+		return nil, fmt.Errorf("failed to parse Fish Audio models: %w", err)
+	}
+
+	var voices []Voice
+	for _, item := range listing.Items {
+		v := Voice{ID: item.ID, Name: item.Title, Provider: "fish-audio", Languages: item.Languages}
+		for _, tag := range item.Tags {
+			if g := genderFromDescription(tag); g != "" && v.Gender == "" {
+				v.Gender = g
+			} else {
+				v.Styles = append(v.Styles, tag)
+			}
+		}
+		voices = append(voices, v)
+	}
+	return voices, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const edgeVoiceTable = `Name                               Gender    ContentCategories      VoicePersonalities
+---------------------------------  --------  ---------------------  --------------------------------------
+de-DE-KatjaNeural                  Female    General                Friendly, Positive
+en-US-AvaMultilingualNeural        Female    Conversation, Copilot  Expressive, Caring, Pleasant, Friendly
+zh-CN-liaoning-XiaobeiNeural       Female    Dialect                Humorous, Lively
+`
+
+func TestParseEdgeVoiceList_Table(t *testing.T) {
+	voices := parseEdgeVoiceList(edgeVoiceTable)
+	if len(voices) != 3 {
+		t.Fatalf("got %d voices, want 3: %+v", len(voices), voices)
+	}
+
+	katja := voices[0]
+	if katja.ID != "de-DE-KatjaNeural" || katja.Name != "Katja" || katja.Locale != "de-DE" || katja.Gender != "female" {
+		t.Errorf("katja = %+v", katja)
+	}
+	if len(katja.Styles) != 2 || katja.Styles[0] != "Friendly" {
+		t.Errorf("katja styles = %v", katja.Styles)
+	}
+	if ava := voices[1]; ava.Name != "Ava" || !ava.Speaks("de") {
+		t.Errorf("multilingual voice should speak any language: %+v", ava)
+	}
+	if xiaobei := voices[2]; xiaobei.Name != "Xiaobei" || xiaobei.Locale != "zh-CN" {
+		t.Errorf("dialect voice = %+v", xiaobei)
+	}
+}
+
+func TestParseEdgeVoiceList_Blocks(t *testing.T) {
+	output := "Name: en-GB-RyanNeural\nGender: Male\n\nName: fr-FR-DeniseNeural\nGender: Female\n"
+	voices := parseEdgeVoiceList(output)
+	if len(voices) != 2 {
+		t.Fatalf("got %d voices, want 2", len(voices))
+	}
+	if voices[0].Gender != "male" || voices[1].Locale != "fr-FR" {
+		t.Errorf("voices = %+v", voices)
+	}
+}
+
+func TestParsePiperVoicesIndex(t *testing.T) {
+	data := []byte(`{
+		"en_US-amy-medium": {"key": "en_US-amy-medium", "name": "amy", "quality": "medium", "num_speakers": 1, "language": {"code": "en_US"}},
+		"en_US-libritts-high": {"key": "en_US-libritts-high", "name": "libritts", "quality": "high", "num_speakers": 904, "language": {"code": "en_US"}}
+	}`)
+	voices, err := parsePiperVoicesIndex(data)
+	if err != nil {
+		t.Fatalf("parsePiperVoicesIndex() error: %v", err)
+	}
+	sortVoices(voices)
+	if len(voices) != 2 {
+		t.Fatalf("got %d voices, want 2", len(voices))
+	}
+	amy := voices[0]
+	if amy.Name != "Amy" || amy.Locale != "en-US" || amy.Gender != "female" {
+		t.Errorf("amy = %+v (gender comes from the built-in list)", amy)
+	}
+	if libritts := voices[1]; len(libritts.Styles) != 2 || libritts.Styles[1] != "904 speakers" {
+		t.Errorf("libritts styles = %v", libritts.Styles)
+	}
+}
+
+func TestParseFishAudioModels(t *testing.T) {
+	data := []byte(`{"total": 1, "items": [{"_id": "abc123", "title": "Narrator", "languages": ["en", "de"], "tags": ["male", "calm"]}]}`)
+	voices, err := parseFishAudioModels(data)
+	if err != nil {
+		t.Fatalf("parseFishAudioModels() error: %v", err)
+	}
+	if len(voices) != 1 {
+		t.Fatalf("got %d voices, want 1", len(voices))
+	}
+	v := voices[0]
+	if v.ID != "abc123" || v.Gender != "male" || len(v.Styles) != 1 || v.Styles[0] != "calm" {
+		t.Errorf("voice = %+v", v)
+	}
+	if !v.Speaks("de") || v.Speaks("fr") {
+		t.Errorf("voice should speak only its languages: %+v", v)
+	}
+}
+
+func TestVoice_Speaks(t *testing.T) {
+	tests := []struct {
+		voice Voice
+		lang  string
+		want  bool
+	}{
+		{Voice{Locale: "en-GB"}, "en", true},
+		{Voice{Locale: "en-GB"}, "de", false},
+		{Voice{Locale: "pt-BR"}, "pt", true},
+		{Voice{}, "ja", true}, // OpenAI voices
+		{Voice{Languages: []string{"*"}, Locale: "en-US"}, "ko", true},
+	}
+	for _, tt := range tests {
+		if got := tt.voice.Speaks(tt.lang); got != tt.want {
+			t.Errorf("%+v.Speaks(%q) = %v, want %v", tt.voice, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestVoiceCatalog_BuiltinUntilFetched(t *testing.T) {
+	c := NewVoiceCatalog(t.TempDir(), "")
+	voices := c.VoicesFor("edge-tts", "de")
+	if len(voices) == 0 {
+		t.Fatal("expected built-in German Edge voices")
+	}
+	for _, v := range voices {
+		if v.Locale[:2] != "de" {
+			t.Errorf("non-German voice %s listed for de", v.ID)
+		}
+	}
+	if len(c.Voices("openai")) != len(OpenAIVoices) {
+		t.Errorf("OpenAI voices = %d, want %d", len(c.Voices("openai")), len(OpenAIVoices))
+	}
+}
+
+func TestVoiceCatalog_RefreshCachesOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	c := NewVoiceCatalog(dir, "")
+	t.Cleanup(func() { registerVoiceLanguages("piper", builtinVoices("piper")) }) // Refresh registers Welsh only
+	c.fetchers["piper"] = func() ([]Voice, error) {
+		return []Voice{{ID: "cy_GB-gwryw-medium", Name: "Gwryw", Provider: "piper", Locale: "cy-GB"}}, nil
+	}
+	if err := c.Refresh("piper"); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+
+	// A new catalog reads the fetched voices back instead of the built-in list
+	reloaded := NewVoiceCatalog(dir, "")
+	voices := reloaded.Voices("piper")
+	if len(voices) != 1 || voices[0].ID != "cy_GB-gwryw-medium" {
+		t.Errorf("reloaded voices = %+v", voices)
+	}
+}
+
+func TestVoiceCatalog_CosyVoiceSamples(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "narrator.wav"), []byte("RIFF"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+
+	c := NewVoiceCatalog(t.TempDir(), "")
+	c.SampleDir = dir
+	voices := c.Voices("cosyvoice")
+	if len(voices) != 1 || voices[0].Name != "narrator" || voices[0].ID != filepath.Join(dir, "narrator.wav") {
+		t.Errorf("samples = %+v", voices)
+	}
+}
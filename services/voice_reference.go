@@ -0,0 +1,312 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"video-translator/internal/config"
+	"video-translator/models"
+)
+
+// Loudness bounds used to score voice references (LUFS). ebur128 reports
+// digital silence as -120.7, which would swamp any average.
+const (
+	referenceQuietLUFS = -40.0 // Speech this quiet scores no energy
+	referenceLoudLUFS  = -15.0 // Speech this loud scores full energy
+	referenceFloorLUFS = -70.0 // Quietest value a sample counts as
+	referenceCleanLU   = 30.0  // Speech this far above the floor scores full clarity
+)
+
+// VoiceReference is a stretch of one speaker's speech in the source audio,
+// cut out as the sample a voice-cloning TTS provider imitates
+type VoiceReference struct {
+	Speaker string  // Diarization speaker ID, empty when not diarized
+	Start   float64 // Seconds into the source audio
+	End     float64
+	Score   float64 // 0-1, higher is cleaner
+	Path    string  // Extracted sample, empty until extracted
+}
+
+// Duration returns the reference's length in seconds
+func (r VoiceReference) Duration() float64 {
+	return r.End - r.Start
+}
+
+// TTSClonesVoices reports whether a TTS provider speaks in the voice of a
+// reference sample, so a reference from the source video can be its voice
+func TTSClonesVoices(provider string) bool {
+	return provider == "cosyvoice"
+}
+
+// PickVoiceReferences chooses each speaker's cleanest stretch of speech.
+// Candidates are runs of one speaker's consecutive lines lasting
+// config.VoiceReferenceMin to VoiceReferenceMax, with no other speaker
+// within VoiceReferenceGuard. They are scored on how much of the window is
+// speech (the transcript minus silences), how close it is to
+// VoiceReferenceIdeal, how loud the speech is and how far it stands above
+// the non-speech audio around it, which is where music and noise show up.
+// loudness may be nil, then only timing is scored.
+//
+// The references are ordered by how much each speaker talks, so the first
+// is the main speaker's. Speakers with no long enough run are left out.
+func PickVoiceReferences(subs models.SubtitleList, silences []SilenceInterval, loudness []LoudnessSample) []VoiceReference {
+	if len(subs) == 0 {
+		return nil
+	}
+
+	minSpan := config.VoiceReferenceMin.Seconds()
+	maxSpan := config.VoiceReferenceMax.Seconds()
+	maxGap := config.VoiceReferenceMaxGap.Seconds()
+
+	scorer := newReferenceScorer(subs, silences, loudness)
+	best := make(map[string]VoiceReference)
+	talk := make(map[string]float64)
+
+	for i, first := range subs {
+		talk[first.Speaker] += (first.EndTime - first.StartTime).Seconds()
+
+		start := first.StartTime.Seconds()
+		for j := i; j < len(subs); j++ {
+			sub := subs[j]
+			if sub.Speaker != first.Speaker {
+				break
+			}
+			if j > i && sub.StartTime.Seconds()-subs[j-1].EndTime.Seconds() > maxGap {
+				break
+			}
+
+			end := sub.EndTime.Seconds()
+			if end-start > maxSpan {
+				if j > i {
+					break
+				}
+				end = start + maxSpan // One long line is trimmed
+			}
+			if end-start < minSpan {
+				continue
+			}
+			if overlapsOtherSpeaker(subs, i, j, start, end) {
+				break
+			}
+
+			ref := VoiceReference{
+				Speaker: first.Speaker,
+				Start:   start,
+				End:     end,
+				Score:   scorer.score(subs[i:j+1], start, end),
+			}
+			if current, ok := best[ref.Speaker]; !ok || ref.Score > current.Score {
+				best[ref.Speaker] = ref
+			}
+		}
+	}
+
+	refs := make([]VoiceReference, 0, len(best))
+	for _, ref := range best {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(a, b int) bool {
+		if talk[refs[a].Speaker] != talk[refs[b].Speaker] {
+			return talk[refs[a].Speaker] > talk[refs[b].Speaker]
+		}
+		return refs[a].Speaker < refs[b].Speaker
+	})
+	return refs
+}
+
+// overlapsOtherSpeaker reports whether a line of a speaker other than
+// subs[i]'s falls within VoiceReferenceGuard of [start, end]. Lines
+// i..j all belong to that speaker.
+func overlapsOtherSpeaker(subs models.SubtitleList, i, j int, start, end float64) bool {
+	guard := config.VoiceReferenceGuard.Seconds()
+	speaker := subs[i].Speaker
+	overlaps := func(sub models.Subtitle) bool {
+		return sub.Speaker != speaker && sub.StartTime.Seconds() < end+guard && sub.EndTime.Seconds() > start-guard
+	}
+
+	for k := j + 1; k < len(subs) && subs[k].StartTime.Seconds() < end+guard; k++ {
+		if overlaps(subs[k]) {
+			return true
+		}
+	}
+	// Earlier lines are sorted by start, so a long one can still reach in
+	for k := i - 1; k >= 0; k-- {
+		if overlaps(subs[k]) {
+			return true
+		}
+		if subs[k].StartTime.Seconds() < start-maxLineSeconds-guard {
+			break
+		}
+	}
+	return false
+}
+
+// referenceScorer scores candidate windows against the track's silences
+// and loudness
+type referenceScorer struct {
+	subs     models.SubtitleList
+	silences []SilenceInterval
+	loudness []LoudnessSample
+	floor    float64 // Mean loudness of all non-speech, NaN if there is none
+}
+
+func newReferenceScorer(subs models.SubtitleList, silences []SilenceInterval, loudness []LoudnessSample) *referenceScorer {
+	s := &referenceScorer{subs: subs, silences: silences, loudness: loudness}
+	s.floor = s.nonSpeechLoudness(math.Inf(-1), math.Inf(1))
+	return s
+}
+
+// score rates the window [start, end] covering lines, 0-1
+func (s *referenceScorer) score(lines models.SubtitleList, start, end float64) float64 {
+	span := end - start
+
+	// Speech coverage: the lines' time inside the window, minus silences
+	var speech float64
+	for _, line := range lines {
+		from := math.Max(line.StartTime.Seconds(), start)
+		to := math.Min(line.EndTime.Seconds(), end)
+		if to <= from {
+			continue
+		}
+		speech += to - from
+		for _, silence := range s.silences {
+			speech -= overlap(from, to, silence.Start, silence.End)
+		}
+	}
+	coverage := clamp01(speech / span)
+
+	ideal := config.VoiceReferenceIdeal.Seconds()
+	length := clamp01(1 - math.Abs(span-ideal)/ideal)
+
+	speechLUFS := s.speechLoudness(lines, start, end)
+	if math.IsNaN(speechLUFS) {
+		return 0.6*coverage + 0.4*length
+	}
+
+	energy := clamp01((speechLUFS - referenceQuietLUFS) / (referenceLoudLUFS - referenceQuietLUFS))
+
+	// Music and noise lift the audio between lines; prefer the floor
+	// around the window over the whole track's
+	context := config.VoiceReferenceContext.Seconds()
+	floor := s.nonSpeechLoudness(start-context, end+context)
+	if math.IsNaN(floor) {
+		floor = s.floor
+	}
+	clarity := 0.5 // Unknown without any non-speech to compare against
+	if !math.IsNaN(floor) {
+		clarity = clamp01((speechLUFS - floor) / referenceCleanLU)
+	}
+
+	return 0.3*coverage + 0.2*length + 0.2*energy + 0.3*clarity
+}
+
+// speechLoudness averages the loudness samples inside the window's lines
+// and outside silences, NaN when there are none
+func (s *referenceScorer) speechLoudness(lines models.SubtitleList, start, end float64) float64 {
+	var sum float64
+	var n int
+	for _, sample := range s.samplesIn(start, end) {
+		if s.inSilence(sample.Time) {
+			continue
+		}
+		for _, line := range lines {
+			if sample.Time >= line.StartTime.Seconds() && sample.Time <= line.EndTime.Seconds() {
+				sum += math.Max(sample.LUFS, referenceFloorLUFS)
+				n++
+				break
+			}
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
+
+// nonSpeechLoudness averages the loudness samples in [from, to] that no
+// line covers, NaN when there are none
+func (s *referenceScorer) nonSpeechLoudness(from, to float64) float64 {
+	var sum float64
+	var n int
+	for _, sample := range s.samplesIn(from, to) {
+		if s.inSpeech(sample.Time) {
+			continue
+		}
+		sum += math.Max(sample.LUFS, referenceFloorLUFS)
+		n++
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
+
+// samplesIn returns the loudness samples in [from, to]; ffmpeg reports
+// them in time order
+func (s *referenceScorer) samplesIn(from, to float64) []LoudnessSample {
+	lo := sort.Search(len(s.loudness), func(i int) bool { return s.loudness[i].Time >= from })
+	hi := sort.Search(len(s.loudness), func(i int) bool { return s.loudness[i].Time > to })
+	return s.loudness[lo:hi]
+}
+
+// inSilence reports whether t falls in a detected silence (they are
+// disjoint and in time order)
+func (s *referenceScorer) inSilence(t float64) bool {
+	i := sort.Search(len(s.silences), func(i int) bool { return s.silences[i].End >= t })
+	return i < len(s.silences) && s.silences[i].Start <= t
+}
+
+// maxLineSeconds bounds how far back a line covering a moment can start
+// (Whisper decodes 30 s windows)
+const maxLineSeconds = 30.0
+
+// inSpeech reports whether any line covers t
+func (s *referenceScorer) inSpeech(t float64) bool {
+	i := sort.Search(len(s.subs), func(i int) bool {
+		return s.subs[i].StartTime.Seconds() > t
+	})
+	// Lines starting after t can't cover it; check the ones before
+	for k := i - 1; k >= 0; k-- {
+		if s.subs[k].EndTime.Seconds() >= t {
+			return true
+		}
+		if s.subs[k].StartTime.Seconds() < t-maxLineSeconds {
+			break
+		}
+	}
+	return false
+}
+
+// overlap returns the length of [aStart, aEnd] ∩ [bStart, bEnd]
+func overlap(aStart, aEnd, bStart, bEnd float64) float64 {
+	return math.Max(0, math.Min(aEnd, bEnd)-math.Max(aStart, bStart))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// withVoiceReferences makes extracted references the voices to clone: the
+// main speaker's becomes the primary voice and every other speaker gets
+// their own. Voices mapped explicitly to a speaker are kept.
+func withVoiceReferences(voice string, speakerVoices map[string]string, refs []VoiceReference) (string, map[string]string) {
+	if len(refs) == 0 {
+		return voice, speakerVoices
+	}
+
+	merged := make(map[string]string, len(speakerVoices)+len(refs))
+	for speaker, v := range speakerVoices {
+		merged[speaker] = v
+	}
+	for _, ref := range refs {
+		if ref.Speaker != "" && merged[ref.Speaker] == "" {
+			merged[ref.Speaker] = ref.Path
+		}
+	}
+
+	primary := refs[0].Path
+	if v := speakerVoices[refs[0].Speaker]; v != "" && refs[0].Speaker != "" {
+		primary = v
+	}
+	return primary, merged
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"video-translator/models"
+)
+
+// line is a transcript line from start to end seconds
+func line(start, end float64, speaker string) models.Subtitle {
+	return models.Subtitle{
+		StartTime: time.Duration(start * float64(time.Second)),
+		EndTime:   time.Duration(end * float64(time.Second)),
+		Text:      "...",
+		Speaker:   speaker,
+	}
+}
+
+// loudnessTrack samples every 100 ms up to end seconds, at lufs(t)
+func loudnessTrack(end float64, lufs func(t float64) float64) []LoudnessSample {
+	var samples []LoudnessSample
+	for i := 1; float64(i)*0.1 <= end; i++ {
+		t := float64(i) * 0.1
+		samples = append(samples, LoudnessSample{Time: t, LUFS: lufs(t)})
+	}
+	return samples
+}
+
+func TestPickVoiceReferences_PerSpeaker(t *testing.T) {
+	subs := models.SubtitleList{
+		line(0, 4, "SPEAKER_00"),
+		line(4.5, 9, "SPEAKER_00"),
+		line(9.2, 12, "SPEAKER_01"), // Too short on its own
+		line(13, 16, "SPEAKER_00"),
+		line(16.5, 22, "SPEAKER_00"),
+	}
+
+	refs := PickVoiceReferences(subs, nil, nil)
+	if len(refs) != 1 {
+		t.Fatalf("got %d references, want only SPEAKER_00's: %+v", len(refs), refs)
+	}
+	ref := refs[0]
+	if ref.Speaker != "SPEAKER_00" {
+		t.Errorf("speaker = %s", ref.Speaker)
+	}
+	// 0-9 ends within the guard of SPEAKER_01 at 9.2; 13-22 is clear
+	if ref.Start != 13 || ref.End != 22 {
+		t.Errorf("reference = %.1f-%.1f, want 13.0-22.0", ref.Start, ref.End)
+	}
+}
+
+func TestPickVoiceReferences_OrdersByTalkTime(t *testing.T) {
+	subs := models.SubtitleList{
+		line(0, 6, "SPEAKER_01"),
+		line(10, 20, "SPEAKER_00"),
+		line(21, 30, "SPEAKER_00"),
+	}
+	refs := PickVoiceReferences(subs, nil, nil)
+	if len(refs) != 2 || refs[0].Speaker != "SPEAKER_00" || refs[1].Speaker != "SPEAKER_01" {
+		t.Errorf("references = %+v, want SPEAKER_00 first", refs)
+	}
+}
+
+func TestPickVoiceReferences_TrimsLongLine(t *testing.T) {
+	refs := PickVoiceReferences(models.SubtitleList{line(2, 30, "")}, nil, nil)
+	if len(refs) != 1 || refs[0].Start != 2 || refs[0].Duration() != 15 {
+		t.Errorf("references = %+v, want 15 s from 2 s", refs)
+	}
+}
+
+func TestPickVoiceReferences_SkipsSilenceAndMusic(t *testing.T) {
+	// One speaker, two 10 s runs separated by a gap. The first has music
+	// under the pauses, the second is quiet between lines.
+	subs := models.SubtitleList{
+		line(0, 4.5, ""),
+		line(5.5, 10, ""),
+		line(40, 44.5, ""),
+		line(45.5, 50, ""),
+	}
+	loudness := loudnessTrack(50, func(t float64) float64 {
+		switch {
+		case t < 12:
+			if (t > 4.5 && t < 5.5) || t > 10 {
+				return -22 // Music
+			}
+			return -18
+		case t >= 40 && !(t > 44.5 && t < 45.5):
+			return -18
+		}
+		return -120.7
+	})
+
+	refs := PickVoiceReferences(subs, nil, loudness)
+	if len(refs) != 1 || refs[0].Start != 40 {
+		t.Fatalf("references = %+v, want the quiet run at 40 s", refs)
+	}
+
+	// When VAD finds the quiet run is mostly silence, the music run wins
+	silences := []SilenceInterval{{Start: 40, End: 47}}
+	silent := loudnessTrack(50, func(t float64) float64 {
+		if t > 40 && t < 47 {
+			return -120.7
+		}
+		for _, sample := range loudness {
+			if sample.Time >= t {
+				return sample.LUFS
+			}
+		}
+		return -120.7
+	})
+	refs = PickVoiceReferences(subs, silences, silent)
+	if len(refs) != 1 || refs[0].Start != 0 {
+		t.Errorf("references = %+v, want the run at 0 s once the other is mostly silence", refs)
+	}
+}
+
+func TestWithVoiceReferences(t *testing.T) {
+	refs := []VoiceReference{
+		{Speaker: "SPEAKER_01", Path: "/tmp/ref_01.wav"},
+		{Speaker: "SPEAKER_00", Path: "/tmp/ref_00.wav"},
+	}
+	explicit := map[string]string{"SPEAKER_00": "/samples/narrator.wav"}
+
+	voice, voices := withVoiceReferences("/samples/default.wav", explicit, refs)
+	if voice != "/tmp/ref_01.wav" {
+		t.Errorf("primary voice = %s, want the main speaker's reference", voice)
+	}
+	if voices["SPEAKER_00"] != "/samples/narrator.wav" || voices["SPEAKER_01"] != "/tmp/ref_01.wav" {
+		t.Errorf("speaker voices = %v", voices)
+	}
+	if len(explicit) != 1 {
+		t.Errorf("explicit mapping modified: %v", explicit)
+	}
+
+	if voice, voices := withVoiceReferences("/samples/default.wav", nil, nil); voice != "/samples/default.wav" || voices != nil {
+		t.Errorf("no references changed the voices: %s %v", voice, voices)
+	}
+}
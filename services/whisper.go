@@ -7,12 +7,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -67,6 +69,11 @@ func NewWhisperServiceWithPaths(whisperPath, modelPath string) *WhisperService {
 	}
 }
 
+// GetModelPath returns the ggml model file used by whisper-cpp
+func (s *WhisperService) GetModelPath() string {
+	return s.modelPath
+}
+
 // CheckInstalled verifies whisper-cpp/whisper-cli is available
 func (s *WhisperService) CheckInstalled() error {
 	// Check if path exists or command is in PATH
@@ -86,8 +93,9 @@ func (s *WhisperService) CheckModel() error {
 	return nil
 }
 
-// Transcribe converts audio to text with timestamps
-func (s *WhisperService) Transcribe(audioPath, language string) (models.SubtitleList, error) {
+// Transcribe converts audio to text with timestamps. prompt (e.g. a
+// vocabulary list) biases the spelling of names and terms; empty disables it.
+func (s *WhisperService) Transcribe(audioPath, language, prompt string) (models.SubtitleList, error) {
 	logger.LogInfo("Whisper: model=%s lang=%s file=%s", filepath.Base(s.modelPath), language, filepath.Base(audioPath))
 
 	if err := s.CheckInstalled(); err != nil {
@@ -109,8 +117,12 @@ func (s *WhisperService) Transcribe(audioPath, language string) (models.Subtitle
 		"-l", language,
 		"-f", audioPath,
 		"-osrt",
+		"-ojf", // full JSON with token probabilities, for confidence scores
 		"-of", filepath.Join(outputDir, baseName),
 	}
+	if prompt != "" {
+		args = append(args, "--prompt", prompt)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.ExecTimeoutWhisper)
 	defer cancel()
@@ -126,11 +138,13 @@ func (s *WhisperService) Transcribe(audioPath, language string) (models.Subtitle
 		return nil, fmt.Errorf("failed to parse SRT output: %w", err)
 	}
 
-	return models.FromInternalSubtitles(internalSubs), nil
+	subs := models.FromInternalSubtitles(internalSubs)
+	attachWhisperCppScores(subs, filepath.Join(outputDir, baseName+".json"))
+	return subs, nil
 }
 
 // TranscribeWithProgress transcribes audio while reporting progress via callback
-func (s *WhisperService) TranscribeWithProgress(audioPath, language string, audioDuration float64, onProgress func(currentSec float64, percent int)) (models.SubtitleList, error) {
+func (s *WhisperService) TranscribeWithProgress(audioPath, language, prompt string, audioDuration float64, onProgress func(currentSec float64, percent int)) (models.SubtitleList, error) {
 	if err := s.CheckInstalled(); err != nil {
 		return nil, err
 	}
@@ -149,8 +163,12 @@ func (s *WhisperService) TranscribeWithProgress(audioPath, language string, audi
 		"-l", language,
 		"-f", audioPath,
 		"-osrt",
+		"-ojf", // full JSON with token probabilities, for confidence scores
 		"-of", filepath.Join(outputDir, baseName),
 	}
+	if prompt != "" {
+		args = append(args, "--prompt", prompt)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.ExecTimeoutWhisper)
 	defer cancel()
@@ -203,12 +221,102 @@ func (s *WhisperService) TranscribeWithProgress(audioPath, language string, audi
 		return nil, fmt.Errorf("failed to parse SRT output: %w", err)
 	}
 
-	return models.FromInternalSubtitles(internalSubs), nil
+	subs := models.FromInternalSubtitles(internalSubs)
+	attachWhisperCppScores(subs, filepath.Join(outputDir, baseName+".json"))
+	return subs, nil
+}
+
+// whisperCppJSON is the subset of whisper-cpp's -ojf output we read
+type whisperCppJSON struct {
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"` // milliseconds
+		} `json:"offsets"`
+		Tokens []struct {
+			Text string  `json:"text"`
+			P    float64 `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// attachWhisperCppScores reads the -ojf JSON written next to the SRT and sets
+// AvgLogprob on each subtitle from its token probabilities. whisper-cpp has no
+// per-segment no-speech probability, so NoSpeechProb stays 0. Missing or
+// unreadable JSON leaves the subtitles unscored.
+func attachWhisperCppScores(subs models.SubtitleList, jsonPath string) {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return
+	}
+	var out whisperCppJSON
+	if err := json.Unmarshal(data, &out); err != nil {
+		logger.LogDebug("Whisper: ignoring unreadable JSON scores: %v", err)
+		return
+	}
+
+	scores := make(map[time.Duration]float64, len(out.Transcription))
+	for _, seg := range out.Transcription {
+		sum, n := 0.0, 0
+		for _, tok := range seg.Tokens {
+			// Skip special tokens such as [_BEG_] and [_TT_150]
+			if strings.HasPrefix(tok.Text, "[_") || tok.P <= 0 {
+				continue
+			}
+			sum += math.Log(tok.P)
+			n++
+		}
+		if n > 0 {
+			scores[time.Duration(seg.Offsets.From)*time.Millisecond] = sum / float64(n)
+		}
+	}
+
+	for i := range subs {
+		if logprob, ok := scores[subs[i].StartTime]; ok {
+			subs[i].AvgLogprob = logprob
+			subs[i].HasScores = true
+		}
+	}
+}
+
+// DetectLanguage runs whisper-cpp's language detection (-l auto -dl), which
+// only decodes the first 30 seconds and exits
+func (s *WhisperService) DetectLanguage(audioPath string) (LanguageDetection, error) {
+	if err := s.CheckInstalled(); err != nil {
+		return LanguageDetection{}, err
+	}
+	if err := s.CheckModel(); err != nil {
+		return LanguageDetection{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ExecTimeoutWhisper)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, s.whisperPath, "-m", s.modelPath, "-f", audioPath, "-l", "auto", "-dl")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return LanguageDetection{}, fmt.Errorf("whisper language detection failed: %w\nOutput: %s", err, string(output))
+	}
+
+	matches := text.WhisperDetectedLangRegex.FindStringSubmatch(string(output))
+	if len(matches) < 3 {
+		return LanguageDetection{}, fmt.Errorf("whisper did not report a detected language")
+	}
+	confidence, _ := strconv.ParseFloat(matches[2], 64)
+	return LanguageDetection{Language: matches[1], Confidence: confidence}, nil
+}
+
+// DetectLanguageWithOpenAI asks the OpenAI Whisper API for the language of a
+// (short) clip
+func (s *WhisperService) DetectLanguageWithOpenAI(audioPath, apiKey string) (LanguageDetection, error) {
+	if apiKey == "" {
+		return LanguageDetection{}, fmt.Errorf("OpenAI API key is required")
+	}
+	return detectLanguageViaAPI(rateLimiterFor(UsageStageTranscription, "openai"),
+		"https://api.openai.com/v1/audio/transcriptions", apiKey, "whisper-1", audioPath)
 }
 
 // TranscribeWithOpenAI uses OpenAI's Whisper API for fast transcription
 // Cost: $0.006/minute = ~$1.80 for 5 hours of audio
-func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string, onProgress func(percent int, message string)) (models.SubtitleList, error) {
+func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language, prompt string, onProgress func(percent int, message string)) (models.SubtitleList, error) {
 	logger.LogInfo("OpenAI Whisper API: model=whisper-1 lang=%s file=%s", language, filepath.Base(audioPath))
 
 	if apiKey == "" {
@@ -226,7 +334,7 @@ func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string
 	const maxFileSize = 25 * 1024 * 1024
 	if fileInfo.Size() > maxFileSize {
 		// For large files, we need to split into chunks
-		return s.transcribeWithOpenAIChunked(audioPath, apiKey, language, onProgress)
+		return s.transcribeWithOpenAIChunked(audioPath, apiKey, language, prompt, onProgress)
 	}
 
 	if onProgress != nil {
@@ -256,7 +364,10 @@ func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string
 	// Add other fields
 	writer.WriteField("model", "whisper-1")
 	writer.WriteField("language", language)
-	writer.WriteField("response_format", "srt") // Get SRT directly!
+	writer.WriteField("response_format", "verbose_json") // segments with confidence scores
+	if prompt != "" {
+		writer.WriteField("prompt", prompt)
+	}
 	writer.Close()
 
 	if onProgress != nil {
@@ -273,7 +384,7 @@ func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string
 
 	// Use a client with longer timeout for large files
 	client := &http.Client{Timeout: 10 * time.Minute}
-	resp, err := client.Do(req)
+	resp, err := doRateLimited(rateLimiterFor(UsageStageTranscription, "openai"), client, req, RateCost{})
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -301,12 +412,10 @@ func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string
 		onProgress(38, "Parsing transcription...")
 	}
 
-	// Parse SRT response using internal/subtitle package
-	internalSubs, err := subtitle.ParseSRTString(string(respBody))
+	subtitles, err := parseVerboseJSON(respBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SRT response: %w", err)
+		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
 	}
-	subtitles := models.FromInternalSubtitles(internalSubs)
 
 	if onProgress != nil {
 		onProgress(40, fmt.Sprintf("Transcribed %d segments", len(subtitles)))
@@ -316,7 +425,7 @@ func (s *WhisperService) TranscribeWithOpenAI(audioPath, apiKey, language string
 }
 
 // transcribeWithOpenAIChunked handles files larger than 25MB by splitting them
-func (s *WhisperService) transcribeWithOpenAIChunked(audioPath, apiKey, language string, onProgress func(percent int, message string)) (models.SubtitleList, error) {
+func (s *WhisperService) transcribeWithOpenAIChunked(audioPath, apiKey, language, prompt string, onProgress func(percent int, message string)) (models.SubtitleList, error) {
 	// For now, convert to MP3 with lower bitrate to reduce file size
 	// This is a workaround - ideally we'd split the audio into chunks
 
@@ -356,13 +465,13 @@ func (s *WhisperService) transcribeWithOpenAIChunked(audioPath, apiKey, language
 	}
 
 	// Transcribe the compressed file
-	return s.TranscribeWithOpenAI(compressedPath, apiKey, language, onProgress)
+	return s.TranscribeWithOpenAI(compressedPath, apiKey, language, prompt, onProgress)
 }
 
 
 // TranscribeToText returns just the text without timestamps
 func (s *WhisperService) TranscribeToText(audioPath, language string) (string, error) {
-	subtitles, err := s.Transcribe(audioPath, language)
+	subtitles, err := s.Transcribe(audioPath, language, "")
 	if err != nil {
 		return "", err
 	}
@@ -382,7 +491,7 @@ func (s *WhisperService) TranscribeToText(audioPath, language string) (string, e
 // Each chunk's subtitles are adjusted with the correct offset timestamp.
 func (s *WhisperService) TranscribeChunksParallel(
 	chunks []ChunkInfo,
-	language string,
+	language, prompt string,
 	onProgress func(completed, total int),
 ) (models.SubtitleList, error) {
 	if len(chunks) == 0 {
@@ -391,7 +500,7 @@ func (s *WhisperService) TranscribeChunksParallel(
 
 	// Single chunk - use regular transcription
 	if len(chunks) == 1 {
-		return s.Transcribe(chunks[0].Path, language)
+		return s.Transcribe(chunks[0].Path, language, prompt)
 	}
 
 	logger.LogInfo("Whisper: transcribing %d chunks in parallel", len(chunks))
@@ -410,7 +519,7 @@ func (s *WhisperService) TranscribeChunksParallel(
 		AcquireTranscriptionSlot()
 		defer ReleaseTranscriptionSlot()
 
-		subs, err := s.Transcribe(chunk.Path, language)
+		subs, err := s.Transcribe(chunk.Path, language, prompt)
 		if err != nil {
 			return nil, fmt.Errorf("chunk %d transcription failed: %w", chunk.Index, err)
 		}
@@ -344,7 +344,7 @@ func TestWhisperService_Transcribe_InvalidInput(t *testing.T) {
 	}
 
 	// Should fail because whisper is not installed
-	_, err := s.Transcribe("/nonexistent/audio.wav", "en")
+	_, err := s.Transcribe("/nonexistent/audio.wav", "en", "")
 	if err == nil {
 		t.Error("Transcribe() should return error for nonexistent whisper")
 	}
@@ -357,7 +357,7 @@ func TestWhisperService_TranscribeWithProgress_InvalidInput(t *testing.T) {
 	}
 
 	// Should fail because whisper is not installed
-	_, err := s.TranscribeWithProgress("/nonexistent/audio.wav", "en", 60.0, nil)
+	_, err := s.TranscribeWithProgress("/nonexistent/audio.wav", "en", "", 60.0, nil)
 	if err == nil {
 		t.Error("TranscribeWithProgress() should return error for nonexistent whisper")
 	}
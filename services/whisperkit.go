@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"video-translator/internal/logger"
+	"video-translator/internal/text"
 	"video-translator/models"
 )
 
@@ -29,10 +30,12 @@ type WhisperKitOutput struct {
 
 // WhisperKitSegment represents a transcription segment
 type WhisperKitSegment struct {
-	Start float64          `json:"start"`
-	End   float64          `json:"end"`
-	Text  string           `json:"text"`
-	Words []WhisperKitWord `json:"words"`
+	Start        float64          `json:"start"`
+	End          float64          `json:"end"`
+	Text         string           `json:"text"`
+	Words        []WhisperKitWord `json:"words"`
+	AvgLogprob   *float64         `json:"avgLogprob"`
+	NoSpeechProb *float64         `json:"noSpeechProb"`
 }
 
 // WhisperKitWord represents a word with timing
@@ -221,10 +224,35 @@ func createMinimalWAV(path string) error {
 	return os.WriteFile(path, header, 0644)
 }
 
-// Transcribe converts audio to text with timestamps using WhisperKit
-func (s *WhisperKitService) Transcribe(audioPath, language string) (models.SubtitleList, error) {
+// Transcribe converts audio to text with timestamps using WhisperKit.
+// prompt is passed as --prompt; empty disables it.
+func (s *WhisperKitService) Transcribe(audioPath, language, prompt string) (models.SubtitleList, error) {
 	logger.LogInfo("WhisperKit: transcribing %s (lang=%s, model=%s)", filepath.Base(audioPath), language, s.model)
 
+	report, err := s.runReport(audioPath, language, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return report.toSubtitles(), nil
+}
+
+// DetectLanguage runs whisperkit-cli without --language on a (short) clip and
+// returns the language it settled on. WhisperKit doesn't report a probability,
+// so Confidence is 0.
+func (s *WhisperKitService) DetectLanguage(audioPath string) (LanguageDetection, error) {
+	report, err := s.runReport(audioPath, "auto", "")
+	if err != nil {
+		return LanguageDetection{}, err
+	}
+	code := text.LanguageCodeFromName(report.Language)
+	if code == "" {
+		return LanguageDetection{}, fmt.Errorf("whisperkit did not report a recognizable language (%q)", report.Language)
+	}
+	return LanguageDetection{Language: code}, nil
+}
+
+// runReport runs whisperkit-cli and returns its parsed JSON report
+func (s *WhisperKitService) runReport(audioPath, language, prompt string) (*WhisperKitOutput, error) {
 	if err := s.CheckInstalled(); err != nil {
 		return nil, err
 	}
@@ -245,6 +273,9 @@ func (s *WhisperKitService) Transcribe(audioPath, language string) (models.Subti
 	if language != "" && language != "auto" {
 		args = append(args, "--language", language)
 	}
+	if prompt != "" {
+		args = append(args, "--prompt", prompt)
+	}
 
 	logger.LogInfo("WhisperKit: running whisperkit-cli %s", strings.Join(args, " "))
 	logger.LogInfo("WhisperKit: first run may download model (~1.5GB), please wait...")
@@ -276,7 +307,7 @@ func (s *WhisperKitService) Transcribe(audioPath, language string) (models.Subti
 }
 
 // parseOutput reads and parses the WhisperKit JSON output file
-func (s *WhisperKitService) parseOutput(jsonPath string) (models.SubtitleList, error) {
+func (s *WhisperKitService) parseOutput(jsonPath string) (*WhisperKitOutput, error) {
 	data, err := os.ReadFile(jsonPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read whisperkit output: %w", err)
@@ -290,16 +321,28 @@ func (s *WhisperKitService) parseOutput(jsonPath string) (models.SubtitleList, e
 		return nil, fmt.Errorf("failed to parse whisperkit output: %w", err)
 	}
 
-	logger.LogInfo("WhisperKit: parsed %d segments", len(result.Segments))
+	logger.LogInfo("WhisperKit: parsed %d segments (language=%s)", len(result.Segments), result.Language)
 
+	return &result, nil
+}
+
+// toSubtitles converts the report's segments to subtitles
+func (o *WhisperKitOutput) toSubtitles() models.SubtitleList {
 	var subs models.SubtitleList
-	for _, seg := range result.Segments {
-		subs = append(subs, models.Subtitle{
+	for _, seg := range o.Segments {
+		sub := models.Subtitle{
 			StartTime: time.Duration(seg.Start * float64(time.Second)),
 			EndTime:   time.Duration(seg.End * float64(time.Second)),
 			Text:      strings.TrimSpace(seg.Text),
-		})
+		}
+		// Older whisperkit-cli reports omit decoder scores
+		if seg.AvgLogprob != nil && seg.NoSpeechProb != nil {
+			sub.AvgLogprob = *seg.AvgLogprob
+			sub.NoSpeechProb = *seg.NoSpeechProb
+			sub.HasScores = true
+		}
+		subs = append(subs, sub)
 	}
 
-	return subs, nil
+	return subs
 }
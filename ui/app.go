@@ -19,6 +19,7 @@ import (
 	"video-translator/models"
 	"video-translator/services"
 	uicontainer "video-translator/ui/container"
+	"video-translator/ui/dialogs"
 	"video-translator/ui/layouts"
 	appTheme "video-translator/ui/theme"
 	"video-translator/ui/widgets"
@@ -36,6 +37,11 @@ type MainUI struct {
 	config   *models.Config
 	pipeline *services.Pipeline
 
+	// Jobs running on the current pipeline; a replaced pipeline is closed
+	// once they finish
+	pipelineMu   sync.Mutex
+	pipelineJobs *sync.WaitGroup
+
 	// UI Components
 	sidebar           *widgets.SidebarNav
 	fileListPanel     *uicontainer.FileListPanel
@@ -63,11 +69,12 @@ func NewMainUI(w fyne.Window) *MainUI {
 	}
 
 	ui := &MainUI{
-		window:      w,
-		jobs:        make([]*models.TranslationJob, 0),
-		config:      config,
-		pipeline:    services.NewPipeline(config),
-		currentView: "translate",
+		window:       w,
+		jobs:         make([]*models.TranslationJob, 0),
+		config:       config,
+		pipeline:     services.NewPipeline(config),
+		pipelineJobs: &sync.WaitGroup{},
+		currentView:  "translate",
 	}
 
 	// Set up progress callback
@@ -83,6 +90,34 @@ func NewMainUI(w fyne.Window) *MainUI {
 	return ui
 }
 
+// Close releases the pipeline's background workers
+func (ui *MainUI) Close() {
+	ui.pipeline.Close()
+}
+
+// acquirePipeline returns the current pipeline for a job; call done when
+// the job ends
+func (ui *MainUI) acquirePipeline() (pipeline *services.Pipeline, done func()) {
+	ui.pipelineMu.Lock()
+	defer ui.pipelineMu.Unlock()
+	ui.pipelineJobs.Add(1)
+	return ui.pipeline, ui.pipelineJobs.Done
+}
+
+// replacePipeline makes pipeline the one new jobs run on. The old one
+// keeps its running jobs and is closed after the last of them.
+func (ui *MainUI) replacePipeline(pipeline *services.Pipeline) {
+	ui.pipelineMu.Lock()
+	old, oldJobs := ui.pipeline, ui.pipelineJobs
+	ui.pipeline, ui.pipelineJobs = pipeline, &sync.WaitGroup{}
+	ui.pipelineMu.Unlock()
+
+	go func() {
+		oldJobs.Wait()
+		old.Close()
+	}()
+}
+
 // Build creates the complete UI layout
 func (ui *MainUI) Build() fyne.CanvasObject {
 	// Create sidebar navigation
@@ -101,13 +136,14 @@ func (ui *MainUI) Build() fyne.CanvasObject {
 	// Create progress panel
 	ui.progressPanel = uicontainer.NewProgressPanel()
 	ui.progressPanel.SetOutputDirectory(ui.config.OutputDirectory)
+	ui.progressPanel.OnReview = ui.onReviewJob
 
 	// Create settings panel
 	ui.settingsPanel = uicontainer.NewSettingsPanel(ui.window, ui.config)
 	ui.settingsPanel.OnSave = func(config *models.Config) {
 		ui.config = config
-		ui.pipeline = services.NewPipeline(config)
-		ui.pipeline.SetProgressCallback(func(stage string, percent int, message string) {
+		pipeline := services.NewPipeline(config)
+		pipeline.SetProgressCallback(func(stage string, percent int, message string) {
 			fyne.Do(func() {
 				if ui.progressPanel != nil {
 					ui.progressPanel.SetProgress(stage, percent)
@@ -115,8 +151,10 @@ func (ui *MainUI) Build() fyne.CanvasObject {
 				}
 			})
 		})
-		ui.bottomControls.SetTTSProvider(config.TTSProvider)
+		ui.replacePipeline(pipeline)
+		ui.bottomControls.SetProviders(config.TranscriptionProvider, config.TranslationProvider, config.TTSProvider)
 		ui.progressPanel.SetOutputDirectory(config.OutputDirectory)
+		ui.refreshVoiceCatalog()
 	}
 
 	// Create bottom controls
@@ -124,7 +162,9 @@ func (ui *MainUI) Build() fyne.CanvasObject {
 	ui.bottomControls.OnTranslateSelected = ui.onTranslateSelected
 	ui.bottomControls.OnTranslateAll = ui.onTranslateAll
 	ui.bottomControls.SetOnPreviewVoice(ui.previewSelectedVoice)
-	ui.bottomControls.SetTTSProvider(ui.config.TTSProvider)
+	ui.bottomControls.SetVoiceSource(ui.voiceOptions)
+	ui.bottomControls.SetProviders(ui.config.TranscriptionProvider, ui.config.TranslationProvider, ui.config.TTSProvider)
+	ui.refreshVoiceCatalog()
 
 	// Main content area (split between file list and progress)
 	fileListContent := ui.fileListPanel.Build()
@@ -164,6 +204,33 @@ func (ui *MainUI) Build() fyne.CanvasObject {
 	return ui.mainContent
 }
 
+// voiceOptions lists the catalog's voices for provider that read lang.
+// Piper voices whose model isn't on disk yet are marked, since a job
+// can't use them until a preview downloads them.
+func (ui *MainUI) voiceOptions(provider, lang string) []widgets.VoiceOption {
+	var options []widgets.VoiceOption
+	for _, v := range ui.pipeline.VoiceCatalog().VoicesFor(provider, lang) {
+		name := v.Label()
+		if provider == "piper" && !services.VoiceModelExists(v.ID) {
+			name += " - not downloaded"
+		}
+		options = append(options, widgets.VoiceOption{ID: v.ID, Name: name, Provider: v.Provider})
+	}
+	return options
+}
+
+// refreshVoiceCatalog fetches missing or week-old voice catalogs in the
+// background, then relists languages and voices from them
+func (ui *MainUI) refreshVoiceCatalog() {
+	catalog := ui.pipeline.VoiceCatalog()
+	go func() {
+		catalog.RefreshStale()
+		fyne.Do(func() {
+			ui.bottomControls.SetProviders(ui.config.TranscriptionProvider, ui.config.TranslationProvider, ui.config.TTSProvider)
+		})
+	}()
+}
+
 func (ui *MainUI) buildSidebarWithBackground() fyne.CanvasObject {
 	bg := widgets.NewThemedRectangle(appTheme.ColorNameSidebar)
 	return container.NewStack(bg, ui.sidebar)
@@ -218,6 +285,15 @@ func (ui *MainUI) onFileSelected(index int) {
 	}
 }
 
+func (ui *MainUI) onReviewJob(job *models.TranslationJob) {
+	list, err := services.LoadReviewList(job.ReviewListPath)
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	dialogs.NewReviewDialog(ui.window, list).Show()
+}
+
 func (ui *MainUI) onTranslateSelected() {
 	selected := ui.fileListPanel.GetSelectedIndex()
 	if selected < 0 || selected >= len(ui.jobs) {
@@ -295,7 +371,9 @@ func (ui *MainUI) translateJob(job *models.TranslationJob) {
 	job.TargetLang = ui.bottomControls.GetTargetLang()
 	job.Voice = ui.bottomControls.GetVoice()
 
-	if err := ui.pipeline.ValidateJob(job); err != nil {
+	pipeline, done := ui.acquirePipeline()
+	if err := pipeline.ValidateJob(job); err != nil {
+		done()
 		dialog.ShowCustom("Error", "OK", widget.NewLabel(err.Error()), ui.window)
 		return
 	}
@@ -305,7 +383,8 @@ func (ui *MainUI) translateJob(job *models.TranslationJob) {
 	ui.progressPanel.SetCurrentJob(job)
 
 	go func() {
-		err := ui.pipeline.Process(job)
+		err := pipeline.Process(job)
+		done()
 
 		fyne.Do(func() {
 			ui.fileListPanel.Refresh()
@@ -338,7 +417,10 @@ func (ui *MainUI) translateJobSync(job *models.TranslationJob) {
 		job.Voice = ui.bottomControls.GetVoice()
 	})
 
-	if err := ui.pipeline.ValidateJob(job); err != nil {
+	pipeline, done := ui.acquirePipeline()
+	defer done()
+
+	if err := pipeline.ValidateJob(job); err != nil {
 		fyne.Do(func() {
 			dialog.ShowCustom("Error", "OK", widget.NewLabel(err.Error()), ui.window)
 		})
@@ -352,7 +434,7 @@ func (ui *MainUI) translateJobSync(job *models.TranslationJob) {
 	})
 
 	// Use per-job progress callback for parallel processing
-	err := ui.pipeline.ProcessWithCallback(job, func(stage string, percent int, message string) {
+	err := pipeline.ProcessWithCallback(job, func(stage string, percent int, message string) {
 		fyne.Do(func() {
 			// Update job progress
 			job.Progress = percent
@@ -408,6 +490,9 @@ func (ui *MainUI) previewSelectedVoice() {
 					})
 					return
 				}
+				fyne.Do(func() {
+					ui.bottomControls.SetVoiceOptions(provider) // Drop the "not downloaded" mark
+				})
 			}
 			svc := services.NewTTSService(voice)
 			err = svc.Synthesize(sampleText, tempPath)
@@ -426,7 +511,11 @@ func (ui *MainUI) previewSelectedVoice() {
 			)
 			err = svc.Synthesize(sampleText, tempPath)
 		case "cosyvoice":
-			if ui.config.VoiceCloneSamplePath == "" {
+			sample := voice // A sample from the catalog, else the configured one
+			if _, statErr := os.Stat(sample); sample == "" || statErr != nil {
+				sample = ui.config.VoiceCloneSamplePath
+			}
+			if sample == "" {
 				fyne.Do(func() {
 					dialog.ShowCustom("Error", "OK", widget.NewLabel("CosyVoice requires a voice sample. Configure it in Settings"), ui.window)
 					ui.progressPanel.SetStatus("")
@@ -437,10 +526,11 @@ func (ui *MainUI) previewSelectedVoice() {
 				ui.config.CosyVoicePath,
 				ui.config.CosyVoiceMode,
 				ui.config.CosyVoiceAPIURL,
-				ui.config.VoiceCloneSamplePath,
+				sample,
 				ui.config.PythonPath,
 			)
 			err = svc.Synthesize(sampleText, tempPath)
+			svc.Close() // Stop the model worker the preview started
 		default:
 			err = fmt.Errorf("unknown TTS provider: %s", provider)
 		}
@@ -86,6 +86,7 @@ func (p *DependenciesPanel) runCheck() {
 		"piper-tts",
 		"piper-voice",
 		"edge-tts",
+		"pyannote",
 	}
 
 	// Friendly names for display
@@ -99,6 +100,7 @@ func (p *DependenciesPanel) runCheck() {
 		"piper-tts":       "Piper TTS",
 		"piper-voice":     "Piper Voice",
 		"edge-tts":        "Edge TTS",
+		"pyannote":        "Pyannote (Speaker Diarization)",
 	}
 
 	allGood := true
@@ -25,6 +25,10 @@ type ProgressPanel struct {
 	fileLabel       *canvas.Text
 	statusLabel     *canvas.Text
 	outputLabel     *canvas.Text
+	reviewButton    *widget.Button
+
+	// OnReview opens the review list of a job with flagged segments
+	OnReview func(job *models.TranslationJob)
 }
 
 // NewProgressPanel creates a new progress panel
@@ -101,6 +105,14 @@ func (p *ProgressPanel) Build() fyne.CanvasObject {
 		p.outputLabel,
 	)
 
+	// Review list, shown once a job has segments worth checking
+	p.reviewButton = widget.NewButton("Review", func() {
+		if p.OnReview != nil && p.currentJob != nil {
+			p.OnReview(p.currentJob)
+		}
+	})
+	p.reviewButton.Hide()
+
 	content := container.NewVBox(
 		fileRow,
 		widget.NewSeparator(),
@@ -108,6 +120,7 @@ func (p *ProgressPanel) Build() fyne.CanvasObject {
 		widget.NewSeparator(),
 		statusRow,
 		outputRow,
+		container.NewHBox(p.reviewButton),
 	)
 
 	// Initial color setup
@@ -162,6 +175,15 @@ func (p *ProgressPanel) Refresh() {
 			p.outputLabel.Text = p.currentJob.OutputPath
 			p.outputLabel.Refresh()
 		}
+
+		if p.reviewButton != nil {
+			if p.currentJob.ReviewListPath != "" {
+				p.reviewButton.SetText(fmt.Sprintf("Review %d segments", p.currentJob.ReviewCount))
+				p.reviewButton.Show()
+			} else {
+				p.reviewButton.Hide()
+			}
+		}
 	} else {
 		if p.fileLabel != nil {
 			p.fileLabel.Text = "No file selected"
@@ -178,6 +200,10 @@ func (p *ProgressPanel) Refresh() {
 			p.statusLabel.Text = "Ready"
 			p.statusLabel.Refresh()
 		}
+
+		if p.reviewButton != nil {
+			p.reviewButton.Hide()
+		}
 	}
 
 	if p.outputLabel != nil && p.outputDirectory != "" && (p.currentJob == nil || p.currentJob.OutputPath == "") {
@@ -3,14 +3,19 @@ package container
 import (
 	"fmt"
 	"image/color"
+	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"video-translator/internal/text"
+	"video-translator/internal/tts"
 	"video-translator/models"
 	"video-translator/services"
 )
@@ -31,18 +36,93 @@ type SettingsPanel struct {
 	cosyVoiceModeSelect       *widget.Select
 	cosyVoiceAPIURLEntry      *widget.Entry
 	voiceSamplePathEntry      *widget.Entry
+	autoVoiceReferenceCheck   *widget.Check
 	openAIKeyEntry            *widget.Entry
 	deepSeekKeyEntry          *widget.Entry
 	groqAPIKeyEntry           *widget.Entry
 	grokAPIKeyEntry           *widget.Entry
 	fishAudioAPIKeyEntry      *widget.Entry
 
+	// Transcription cleanup
+	filterHallucinationsCheck *widget.Check
+	vocabularyEntry           *widget.Entry
+	vocabularyFuzzyCheck      *widget.Check
+
+	// Speaker diarization
+	diarizationCheck      *widget.Check
+	huggingFaceTokenEntry *widget.Entry
+	maxSpeakersSelect     *widget.Select
+
+	// Transcription cache
+	transcriptCacheCheck *widget.Check
+	transcriptCacheLabel *widget.Label
+
+	// Speech cache
+	speechCacheCheck *widget.Check
+	speechCacheLabel *widget.Label
+
+	// Translation memory
+	memoryCheck *widget.Check
+	memoryLabel *widget.Label
+
+	// Usage ledger and spending caps
+	usageLabel         *widget.Label
+	jobBudgetEntry     *widget.Entry
+	dailyBudgetEntry   *widget.Entry
+	budgetActionSelect *widget.Select
+
 	// Audio mixing controls
 	keepBackgroundAudioCheck *widget.Check
 	backgroundVolumeSlider   *widget.Slider
 	backgroundVolumeLabel    *widget.Label
 
+	// OpenAI-compatible LLM translation (local servers / custom endpoint)
+	llmBaseURLEntry     *widget.Entry
+	llmModelEntry       *widget.Entry
+	llmAPIKeyEntry      *widget.Entry
+	llmMaxTokensEntry   *widget.Entry
+	llmTemperatureEntry *widget.Entry
+	llmConcurrencyEntry *widget.Entry
+
+	// LibreTranslate server
+	libreURLEntry    *widget.Entry
+	libreAPIKeyEntry *widget.Entry
+
+	// LLM translation context (all LLM providers)
+	contextLinesSelect  *widget.Select
+	runningSummaryCheck *widget.Check
+	documentBriefCheck  *widget.Check
+
+	// Length budgets for isochronous dubbing
+	fitDurationCheck *widget.Check
+
+	// Emotion tags voiced by the TTS provider
+	emotionTaggingCheck *widget.Check
+
+	// Spoken-form text and pronunciations for TTS
+	speechNormalizationCheck *widget.Check
+	lexiconEntry             *widget.Entry
+
+	// Native speed-ups for lines too long for their slot
+	speechRateMaxSelect *widget.Select
+	speechRateMinSelect *widget.Select
+
+	// Glossary (term translations and do-not-translate list)
+	glossarySelect *widget.Select
+
+	// Translation profiles (edited in place; written to config on save)
+	profiles                 []models.TranslationProfile
+	profileIndex             int
+	profileSelect            *widget.Select
+	profileFormalitySelect   *widget.Select
+	profileRegisterEntry     *widget.SelectEntry
+	profileAudienceEntry     *widget.Entry
+	profileLocaleEntry       *widget.SelectEntry
+	profileInstructionsEntry *widget.Entry
+
 	// Conditional containers
+	llmSettings           *fyne.Container
+	libreSettings         *fyne.Container
 	whisperKitSettings    *fyne.Container
 	whisperKitModelSelect *widget.Select
 	whisperKitModelStatus *widget.Label
@@ -116,7 +196,14 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 		"openai",
 		"deepseek",
 		"grok",
-	}, nil)
+		"ollama",    // Local LLM servers (OpenAI-compatible API)
+		"lmstudio",
+		"llama-cpp",
+		"llm", // Any other OpenAI-compatible endpoint
+		"libretranslate", // Self-hosted LibreTranslate server
+	}, func(value string) {
+		p.updateConditionalUI()
+	})
 	p.translationSelect.SetSelected(getOrDefault(p.config.TranslationProvider, "argos"))
 
 	p.ttsSelect = widget.NewSelect([]string{
@@ -197,9 +284,68 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 		),
 	)
 
+	// LLM translation settings (blank fields keep the provider's preset)
+	p.llmBaseURLEntry = widget.NewEntry()
+	p.llmBaseURLEntry.SetPlaceHolder("Preset default (e.g. http://localhost:11434/v1)")
+	p.llmBaseURLEntry.SetText(p.config.LLMBaseURL)
+
+	p.llmModelEntry = widget.NewEntry()
+	p.llmModelEntry.SetPlaceHolder("qwen2.5:7b")
+	p.llmModelEntry.SetText(p.config.LLMModel)
+
+	p.llmAPIKeyEntry = widget.NewPasswordEntry()
+	p.llmAPIKeyEntry.SetPlaceHolder("Optional")
+	p.llmAPIKeyEntry.SetText(p.config.LLMAPIKey)
+
+	p.llmMaxTokensEntry = widget.NewEntry()
+	p.llmMaxTokensEntry.SetPlaceHolder("Preset default")
+	p.llmMaxTokensEntry.SetText(formatOptionalInt(p.config.LLMMaxTokens))
+
+	p.llmTemperatureEntry = widget.NewEntry()
+	p.llmTemperatureEntry.SetPlaceHolder("Preset default (0.3)")
+	if p.config.LLMTemperature > 0 {
+		p.llmTemperatureEntry.SetText(strconv.FormatFloat(p.config.LLMTemperature, 'f', -1, 64))
+	}
+
+	p.llmConcurrencyEntry = widget.NewEntry()
+	p.llmConcurrencyEntry.SetPlaceHolder("Preset default")
+	p.llmConcurrencyEntry.SetText(formatOptionalInt(p.config.LLMConcurrency))
+
+	llmForm := widget.NewForm(
+		widget.NewFormItem("Base URL", p.llmBaseURLEntry),
+		widget.NewFormItem("Model", p.llmModelEntry),
+		widget.NewFormItem("API Key", p.llmAPIKeyEntry),
+		widget.NewFormItem("Max Tokens", p.llmMaxTokensEntry),
+		widget.NewFormItem("Temperature", p.llmTemperatureEntry),
+		widget.NewFormItem("Concurrency", p.llmConcurrencyEntry),
+	)
+	p.llmSettings = container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabel("LLM Translation Settings"),
+		container.NewPadded(llmForm),
+	)
+
+	// LibreTranslate settings
+	p.libreURLEntry = widget.NewEntry()
+	p.libreURLEntry.SetPlaceHolder("http://localhost:5000")
+	p.libreURLEntry.SetText(p.config.LibreTranslateURL)
+
+	p.libreAPIKeyEntry = widget.NewPasswordEntry()
+	p.libreAPIKeyEntry.SetPlaceHolder("Optional")
+	p.libreAPIKeyEntry.SetText(p.config.LibreTranslateAPIKey)
+
+	p.libreSettings = container.NewVBox(
+		widget.NewSeparator(),
+		widget.NewLabel("LibreTranslate Settings"),
+		container.NewPadded(widget.NewForm(
+			widget.NewFormItem("Server URL", p.libreURLEntry),
+			widget.NewFormItem("API Key", p.libreAPIKeyEntry),
+		)),
+	)
+
 	// OpenAI TTS settings
 	p.openaiTTSModelSelect = widget.NewSelect([]string{
-		"tts-1", "tts-1-hd",
+		"tts-1", "tts-1-hd", "gpt-4o-mini-tts",
 	}, nil)
 	p.openaiTTSModelSelect.SetSelected(getOrDefault(p.config.OpenAITTSModel, "tts-1"))
 
@@ -238,6 +384,10 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 
 	voiceSampleRow := container.NewBorder(nil, nil, nil, voiceSampleBrowseBtn, p.voiceSamplePathEntry)
 
+	// The sample above is the fallback for speakers with no clean stretch
+	p.autoVoiceReferenceCheck = widget.NewCheck("Clone each speaker's voice from the video", nil)
+	p.autoVoiceReferenceCheck.SetChecked(p.config.AutoVoiceReference)
+
 	cosyVoiceForm := widget.NewForm(
 		widget.NewFormItem("Mode", withMinHeight(p.cosyVoiceModeSelect, 40)),
 		widget.NewFormItem("API URL", p.cosyVoiceAPIURLEntry),
@@ -246,7 +396,7 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 	p.cosyVoiceSettings = container.NewVBox(
 		widget.NewSeparator(),
 		widget.NewLabel("CosyVoice Settings"),
-		container.NewPadded(cosyVoiceForm),
+		container.NewPadded(container.NewVBox(cosyVoiceForm, p.autoVoiceReferenceCheck)),
 	)
 
 	// Fish Audio settings
@@ -286,6 +436,166 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 	p.fishAudioAPIKeyEntry.SetPlaceHolder("Fish Audio API key")
 	p.fishAudioAPIKeyEntry.SetText(p.config.FishAudioAPIKey)
 
+	// Transcription cleanup
+	p.filterHallucinationsCheck = widget.NewCheck("Filter Whisper hallucinations (\"Thanks for watching!\", repeats, silence)", nil)
+	p.filterHallucinationsCheck.SetChecked(p.config.FilterHallucinations)
+
+	// Custom vocabulary (names/products the recognizer tends to mangle)
+	p.vocabularyEntry = widget.NewMultiLineEntry()
+	p.vocabularyEntry.SetPlaceHolder("One term per line, e.g. OpenAI, Kubernetes, Anna Schmidt")
+	p.vocabularyEntry.SetText(strings.Join(p.config.TranscriptionVocabulary, "\n"))
+	p.vocabularyEntry.SetMinRowsVisible(4)
+
+	p.vocabularyFuzzyCheck = widget.NewCheck("Fix near-miss spellings after transcription", nil)
+	p.vocabularyFuzzyCheck.SetChecked(p.config.VocabularyFuzzyFix)
+
+	// Translation context (LLM providers only)
+	p.contextLinesSelect = widget.NewSelect([]string{"0", "1", "2", "3", "5", "10"}, nil)
+	p.contextLinesSelect.SetSelected(strconv.Itoa(p.config.TranslationContextLines))
+
+	p.runningSummaryCheck = widget.NewCheck("Carry a running summary between batches (slower: batches run in order)", nil)
+	p.runningSummaryCheck.SetChecked(p.config.TranslationRunningSummary)
+
+	p.documentBriefCheck = widget.NewCheck("Describe topic, speakers and tone once before translating", nil)
+	p.documentBriefCheck.SetChecked(p.config.TranslationDocumentBrief)
+
+	p.fitDurationCheck = widget.NewCheck("Fit translations to segment timing (shorten lines that are too long to speak)", nil)
+	p.fitDurationCheck.SetChecked(p.config.FitTranslationToDuration)
+
+	p.emotionTaggingCheck = widget.NewCheck("Tag emotions for expressive speech (Fish Audio, Edge, Piper, OpenAI gpt-4o-mini-tts)", nil)
+	p.emotionTaggingCheck.SetChecked(p.config.EmotionTagging)
+
+	// Pronunciation (numbers spelled out, lexicon for names voices mangle)
+	p.speechNormalizationCheck = widget.NewCheck("Spell out numbers, dates, money and abbreviations before speaking", nil)
+	p.speechNormalizationCheck.SetChecked(p.config.SpeechNormalization)
+
+	p.lexiconEntry = widget.NewMultiLineEntry()
+	p.lexiconEntry.SetPlaceHolder("One per line, e.g. Kubernetes = koo-ber-NET-eez, de: Nginx = Engine X, Huawei = WAH-way /ˈwɑːweɪ/")
+	p.lexiconEntry.SetText(models.FormatLexicon(p.config.PronunciationLexicon))
+	p.lexiconEntry.SetMinRowsVisible(4)
+
+	// Speech timing (provider speaks faster natively before atempo kicks in)
+	rateMax := "off"
+	if p.config.SpeechRateMax > 1 {
+		rateMax = strconv.FormatFloat(p.config.SpeechRateMax, 'f', -1, 64)
+	}
+	p.speechRateMaxSelect = widget.NewSelect(withOption([]string{"off", "1.25", "1.5", "1.75", "2"}, rateMax), nil)
+	p.speechRateMaxSelect.SetSelected(rateMax)
+	rateMin := strconv.FormatFloat(p.config.SpeechRateMin, 'f', -1, 64)
+	p.speechRateMinSelect = widget.NewSelect(withOption([]string{"1.02", "1.05", "1.1", "1.2"}, rateMin), nil)
+	p.speechRateMinSelect.SetSelected(rateMin)
+	speechTimingForm := widget.NewForm(
+		widget.NewFormItem("Max Native Speed-up", p.speechRateMaxSelect),
+		widget.NewFormItem("Re-synthesize From", p.speechRateMinSelect),
+	)
+	speechTimingHint := widget.NewLabel("Lines too long for their slot are re-synthesized faster by the voice itself (Piper, Edge, OpenAI, Fish Audio). Smaller overruns and whatever still doesn't fit are sped up with ffmpeg.")
+	speechTimingHint.Wrapping = fyne.TextWrapWord
+	speechTimingHint.TextStyle = fyne.TextStyle{Italic: true}
+
+	// Glossaries are CSV/JSON files in the glossaries folder
+	p.glossarySelect = widget.NewSelect(append([]string{"none"}, services.ListGlossaries(services.DefaultGlossaryDir())...), nil)
+	p.glossarySelect.SetSelected(getOrDefault(p.config.Glossary, "none"))
+	glossaryHint := widget.NewLabel("CSV (source,target) or JSON files in " + services.DefaultGlossaryDir())
+	glossaryHint.Wrapping = fyne.TextWrapWord
+
+	contextForm := widget.NewForm(
+		widget.NewFormItem("Context Lines", p.contextLinesSelect),
+		widget.NewFormItem("Glossary", p.glossarySelect),
+	)
+
+	// Translation profiles (LLM providers only)
+	profileForm := p.buildProfileEditor()
+	promptHint := widget.NewLabel("Prompt templates can be overridden with .tmpl files in " + services.DefaultPromptDir())
+	promptHint.Wrapping = fyne.TextWrapWord
+	lexiconHint := widget.NewLabel("Respellings are spoken in the word's place; Piper reads /IPA/ transcriptions. Prefix a line with a language code to limit it to that language.")
+	lexiconHint.Wrapping = fyne.TextWrapWord
+
+	// Speaker diarization
+	p.diarizationCheck = widget.NewCheck("Detect speakers and dub each with its own voice", nil)
+	p.diarizationCheck.SetChecked(p.config.DiarizationEnabled)
+
+	p.huggingFaceTokenEntry = widget.NewPasswordEntry()
+	p.huggingFaceTokenEntry.SetPlaceHolder("hf_... (required by pyannote)")
+	p.huggingFaceTokenEntry.SetText(p.config.HuggingFaceToken)
+
+	p.maxSpeakersSelect = widget.NewSelect([]string{"auto", "2", "3", "4", "5", "6"}, nil)
+	if p.config.MaxSpeakers > 0 {
+		p.maxSpeakersSelect.SetSelected(strconv.Itoa(p.config.MaxSpeakers))
+	} else {
+		p.maxSpeakersSelect.SetSelected("auto")
+	}
+
+	diarizationForm := widget.NewForm(
+		widget.NewFormItem("HuggingFace Token", p.huggingFaceTokenEntry),
+		widget.NewFormItem("Max Speakers", p.maxSpeakersSelect),
+	)
+
+	// Transcription cache
+	p.transcriptCacheCheck = widget.NewCheck("Reuse transcripts of previously processed audio", nil)
+	p.transcriptCacheCheck.SetChecked(p.config.TranscriptionCacheEnabled)
+	p.transcriptCacheLabel = widget.NewLabel("")
+	p.updateCacheLabel()
+	clearCacheBtn := widget.NewButtonWithIcon("Clear Cache", theme.DeleteIcon(), func() {
+		p.clearTranscriptionCache()
+	})
+
+	// Speech cache
+	p.speechCacheCheck = widget.NewCheck("Reuse speech already synthesized for unchanged lines", nil)
+	p.speechCacheCheck.SetChecked(p.config.SpeechCacheEnabled)
+	p.speechCacheLabel = widget.NewLabel("")
+	p.updateSpeechCacheLabel()
+	clearSpeechCacheBtn := widget.NewButtonWithIcon("Clear Cache", theme.DeleteIcon(), func() {
+		p.clearSpeechCache()
+	})
+
+	// Translation memory
+	p.memoryCheck = widget.NewCheck("Reuse translations of lines seen in earlier videos", nil)
+	p.memoryCheck.SetChecked(p.config.TranslationMemoryEnabled)
+	p.memoryLabel = widget.NewLabel("")
+	p.updateMemoryLabel()
+	importMemoryBtn := widget.NewButtonWithIcon("Import TMX", theme.FolderOpenIcon(), func() {
+		p.importTranslationMemory()
+	})
+	exportMemoryBtn := widget.NewButtonWithIcon("Export TMX", theme.DocumentSaveIcon(), func() {
+		p.exportTranslationMemory()
+	})
+	clearMemoryBtn := widget.NewButtonWithIcon("Clear", theme.DeleteIcon(), func() {
+		p.clearTranslationMemory()
+	})
+
+	// Usage ledger
+	p.usageLabel = widget.NewLabel("")
+	p.updateUsageLabel()
+	showUsageBtn := widget.NewButtonWithIcon("Show Usage", theme.InfoIcon(), func() {
+		p.showUsage()
+	})
+	clearUsageBtn := widget.NewButtonWithIcon("Clear", theme.DeleteIcon(), func() {
+		p.clearUsage()
+	})
+	p.jobBudgetEntry = widget.NewEntry()
+	p.jobBudgetEntry.SetPlaceHolder("No limit")
+	p.dailyBudgetEntry = widget.NewEntry()
+	p.dailyBudgetEntry.SetPlaceHolder("No limit")
+	if p.config.JobBudget > 0 {
+		p.jobBudgetEntry.SetText(strconv.FormatFloat(p.config.JobBudget, 'f', -1, 64))
+	}
+	if p.config.DailyBudget > 0 {
+		p.dailyBudgetEntry.SetText(strconv.FormatFloat(p.config.DailyBudget, 'f', -1, 64))
+	}
+	p.budgetActionSelect = widget.NewSelect([]string{
+		services.BudgetActionDowngrade,
+		services.BudgetActionAbort,
+	}, nil)
+	p.budgetActionSelect.SetSelected(getOrDefault(p.config.BudgetAction, services.BudgetActionDowngrade))
+	budgetForm := widget.NewForm(
+		widget.NewFormItem("Job Budget (USD)", p.jobBudgetEntry),
+		widget.NewFormItem("Daily Budget (USD)", p.dailyBudgetEntry),
+		widget.NewFormItem("When Over Budget", p.budgetActionSelect),
+	)
+	budgetHint := widget.NewLabel("Before each paid stage the projected cost is checked against the budgets. \"downgrade\" switches the stage to a free provider (Whisper, Argos, Edge TTS); \"abort\" stops the job.")
+	budgetHint.Wrapping = fyne.TextWrapWord
+	budgetHint.TextStyle = fyne.TextStyle{Italic: true}
+
 	// Audio mixing controls
 	p.keepBackgroundAudioCheck = widget.NewCheck("Keep background audio/music", nil)
 	p.keepBackgroundAudioCheck.SetChecked(p.config.KeepBackgroundAudio)
@@ -338,14 +648,60 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 		widget.NewSeparator(),
 		widget.NewLabel("Providers"),
 		container.NewPadded(providersForm),
+		container.NewPadded(p.filterHallucinationsCheck),
+		p.llmSettings,
+		p.libreSettings,
 		p.whisperKitSettings,
 		p.openaiTTSSettings,
 		p.cosyVoiceSettings,
 		p.fishAudioSettings,
 		widget.NewSeparator(),
+		widget.NewLabel("Vocabulary"),
+		container.NewPadded(container.NewVBox(p.vocabularyEntry, p.vocabularyFuzzyCheck)),
+		widget.NewSeparator(),
+		widget.NewLabel("Translation Context & Timing"),
+		container.NewPadded(container.NewVBox(contextForm, glossaryHint, p.runningSummaryCheck, p.documentBriefCheck, p.fitDurationCheck, p.emotionTaggingCheck)),
+		widget.NewSeparator(),
+		widget.NewLabel("Pronunciation"),
+		container.NewPadded(container.NewVBox(p.speechNormalizationCheck, p.lexiconEntry, lexiconHint)),
+		widget.NewSeparator(),
+		widget.NewLabel("Speech Timing"),
+		container.NewPadded(container.NewVBox(speechTimingForm, speechTimingHint)),
+		widget.NewSeparator(),
+		widget.NewLabel("Translation Style"),
+		container.NewPadded(container.NewVBox(profileForm, promptHint)),
+		widget.NewSeparator(),
 		widget.NewLabel("API Keys"),
 		container.NewPadded(apiKeysForm),
 		widget.NewSeparator(),
+		widget.NewLabel("Speakers"),
+		container.NewPadded(container.NewVBox(p.diarizationCheck, diarizationForm)),
+		widget.NewSeparator(),
+		widget.NewLabel("Transcription Cache"),
+		container.NewPadded(container.NewVBox(
+			p.transcriptCacheCheck,
+			container.NewHBox(p.transcriptCacheLabel, clearCacheBtn),
+		)),
+		widget.NewSeparator(),
+		widget.NewLabel("Speech Cache"),
+		container.NewPadded(container.NewVBox(
+			p.speechCacheCheck,
+			container.NewHBox(p.speechCacheLabel, clearSpeechCacheBtn),
+		)),
+		widget.NewSeparator(),
+		widget.NewLabel("Translation Memory"),
+		container.NewPadded(container.NewVBox(
+			p.memoryCheck,
+			container.NewHBox(p.memoryLabel, importMemoryBtn, exportMemoryBtn, clearMemoryBtn),
+		)),
+		widget.NewSeparator(),
+		widget.NewLabel("Usage & Costs"),
+		container.NewPadded(container.NewVBox(
+			container.NewHBox(p.usageLabel, showUsageBtn, clearUsageBtn),
+			budgetForm,
+			budgetHint,
+		)),
+		widget.NewSeparator(),
 		widget.NewLabel("Audio Mixing"),
 		container.NewPadded(audioMixingForm),
 		widget.NewSeparator(),
@@ -365,10 +721,23 @@ func (p *SettingsPanel) Build() fyne.CanvasObject {
 }
 
 func (p *SettingsPanel) updateConditionalUI() {
-	if p.openaiTTSSettings == nil || p.cosyVoiceSettings == nil || p.whisperKitSettings == nil || p.fishAudioSettings == nil {
+	if p.openaiTTSSettings == nil || p.cosyVoiceSettings == nil || p.whisperKitSettings == nil || p.fishAudioSettings == nil || p.llmSettings == nil || p.libreSettings == nil {
 		return
 	}
 
+	// LLM settings for local servers and custom endpoints
+	switch p.translationSelect.Selected {
+	case "ollama", "lmstudio", "llama-cpp", "llm":
+		p.llmSettings.Show()
+	default:
+		p.llmSettings.Hide()
+	}
+	if p.translationSelect.Selected == "libretranslate" {
+		p.libreSettings.Show()
+	} else {
+		p.libreSettings.Hide()
+	}
+
 	// WhisperKit settings
 	if p.transcriptionSelect.Selected == "whisperkit" {
 		p.whisperKitSettings.Show()
@@ -465,7 +834,337 @@ func (p *SettingsPanel) downloadWhisperKitModel() {
 	}()
 }
 
+// updateCacheLabel shows the current size of the transcription cache
+func (p *SettingsPanel) updateCacheLabel() {
+	cache := services.NewTranscriptionCache(services.DefaultTranscriptionCacheDir(), 0)
+	size, count := cache.Size()
+	p.transcriptCacheLabel.SetText(fmt.Sprintf("%d transcripts, %.1f MB (limit %d MB)",
+		count, float64(size)/(1024*1024), p.config.TranscriptionCacheMaxMB))
+}
+
+// clearTranscriptionCache deletes all cached transcripts after confirmation
+func (p *SettingsPanel) clearTranscriptionCache() {
+	dialog.ShowConfirm("Clear Cache", "Delete all cached transcripts? Videos will be transcribed again on the next run.", func(ok bool) {
+		if !ok {
+			return
+		}
+		cache := services.NewTranscriptionCache(services.DefaultTranscriptionCacheDir(), 0)
+		if err := cache.Clear(); err != nil {
+			dialog.ShowCustom("Error", "OK", widget.NewLabel(err.Error()), p.window)
+		}
+		p.updateCacheLabel()
+	}, p.window)
+}
+
+// updateSpeechCacheLabel shows the current size of the speech cache
+func (p *SettingsPanel) updateSpeechCacheLabel() {
+	cache := tts.NewSegmentCache(tts.DefaultCacheDir(), 0)
+	size, count := cache.Size()
+	p.speechCacheLabel.SetText(fmt.Sprintf("%d segments, %.1f MB (limit %d MB)",
+		count, float64(size)/(1024*1024), p.config.SpeechCacheMaxMB))
+}
+
+// clearSpeechCache deletes all cached speech segments after confirmation
+func (p *SettingsPanel) clearSpeechCache() {
+	dialog.ShowConfirm("Clear Cache", "Delete all cached speech? Every line will be synthesized again on the next run.", func(ok bool) {
+		if !ok {
+			return
+		}
+		cache := tts.NewSegmentCache(tts.DefaultCacheDir(), 0)
+		if err := cache.Clear(); err != nil {
+			dialog.ShowCustom("Error", "OK", widget.NewLabel(err.Error()), p.window)
+		}
+		p.updateSpeechCacheLabel()
+	}, p.window)
+}
+
+// buildProfileEditor creates the translation profile controls
+func (p *SettingsPanel) buildProfileEditor() fyne.CanvasObject {
+	p.profiles = append([]models.TranslationProfile(nil), p.config.TranslationProfiles...)
+	if len(p.profiles) == 0 {
+		p.profiles = []models.TranslationProfile{{Name: "default"}}
+	}
+
+	p.profileFormalitySelect = widget.NewSelect([]string{"default", models.FormalityFormal, models.FormalityInformal}, nil)
+	p.profileRegisterEntry = widget.NewSelectEntry([]string{"casual", "neutral", "technical", "literary", "marketing"})
+	p.profileRegisterEntry.SetPlaceHolder("Model decides")
+	p.profileAudienceEntry = widget.NewEntry()
+	p.profileAudienceEntry.SetPlaceHolder("e.g. children, software developers")
+	p.profileLocaleEntry = widget.NewSelectEntry(services.TranslationLocales(""))
+	p.profileLocaleEntry.SetPlaceHolder("e.g. pt-BR (used when it matches the target language)")
+	p.profileInstructionsEntry = widget.NewMultiLineEntry()
+	p.profileInstructionsEntry.SetPlaceHolder("Extra instructions for the translator")
+	p.profileInstructionsEntry.SetMinRowsVisible(3)
+
+	p.profileSelect = widget.NewSelect(p.profileNames(), func(name string) {
+		p.storeProfileFields()
+		for i, profile := range p.profiles {
+			if profile.Name == name {
+				p.profileIndex = i
+				p.loadProfileFields()
+			}
+		}
+	})
+	p.profileIndex = -1
+	selected := p.profiles[0].Name
+	if _, ok := p.config.Profile(p.config.TranslationProfile); ok {
+		selected = p.config.TranslationProfile
+	}
+	p.profileSelect.SetSelected(selected)
+
+	newBtn := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		p.newProfile()
+	})
+	deleteBtn := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		p.deleteProfile()
+	})
+
+	return widget.NewForm(
+		widget.NewFormItem("Profile", container.NewBorder(nil, nil, nil, container.NewHBox(newBtn, deleteBtn), p.profileSelect)),
+		widget.NewFormItem("Formality", p.profileFormalitySelect),
+		widget.NewFormItem("Register", p.profileRegisterEntry),
+		widget.NewFormItem("Audience", p.profileAudienceEntry),
+		widget.NewFormItem("Locale", p.profileLocaleEntry),
+		widget.NewFormItem("Instructions", p.profileInstructionsEntry),
+	)
+}
+
+func (p *SettingsPanel) profileNames() []string {
+	names := make([]string, len(p.profiles))
+	for i, profile := range p.profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// storeProfileFields copies the editor fields into the selected profile
+func (p *SettingsPanel) storeProfileFields() {
+	if p.profileIndex < 0 || p.profileIndex >= len(p.profiles) {
+		return
+	}
+	profile := &p.profiles[p.profileIndex]
+	profile.Formality = p.profileFormalitySelect.Selected
+	if profile.Formality == "default" {
+		profile.Formality = models.FormalityDefault
+	}
+	profile.Register = strings.TrimSpace(p.profileRegisterEntry.Text)
+	profile.Audience = strings.TrimSpace(p.profileAudienceEntry.Text)
+	profile.Locale = strings.TrimSpace(p.profileLocaleEntry.Text)
+	profile.Instructions = strings.TrimSpace(p.profileInstructionsEntry.Text)
+}
+
+// loadProfileFields shows the selected profile in the editor
+func (p *SettingsPanel) loadProfileFields() {
+	profile := p.profiles[p.profileIndex]
+	p.profileFormalitySelect.SetSelected(getOrDefault(profile.Formality, "default"))
+	p.profileRegisterEntry.SetText(profile.Register)
+	p.profileAudienceEntry.SetText(profile.Audience)
+	p.profileLocaleEntry.SetText(profile.Locale)
+	p.profileInstructionsEntry.SetText(profile.Instructions)
+}
+
+// newProfile asks for a name and adds a profile starting from the current one
+func (p *SettingsPanel) newProfile() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. kids-pt-BR")
+	dialog.ShowForm("New Translation Profile", "Create", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Name", nameEntry)},
+		func(ok bool) {
+			name := strings.TrimSpace(nameEntry.Text)
+			if !ok || name == "" {
+				return
+			}
+			for _, existing := range p.profiles {
+				if existing.Name == name {
+					dialog.ShowCustom("Error", "OK", widget.NewLabel(fmt.Sprintf("A profile named %q already exists.", name)), p.window)
+					return
+				}
+			}
+			p.storeProfileFields()
+			profile := p.profiles[p.profileIndex]
+			profile.Name = name
+			p.profiles = append(p.profiles, profile)
+			p.profileSelect.Options = p.profileNames()
+			p.profileSelect.SetSelected(name)
+		}, p.window)
+}
+
+// deleteProfile removes the selected profile, keeping at least one
+func (p *SettingsPanel) deleteProfile() {
+	if len(p.profiles) <= 1 {
+		dialog.ShowCustom("Error", "OK", widget.NewLabel("At least one translation profile is required."), p.window)
+		return
+	}
+	name := p.profiles[p.profileIndex].Name
+	dialog.ShowConfirm("Delete Profile", fmt.Sprintf("Delete translation profile %q?", name), func(ok bool) {
+		if !ok {
+			return
+		}
+		p.profiles = append(p.profiles[:p.profileIndex], p.profiles[p.profileIndex+1:]...)
+		p.profileIndex = -1
+		p.profileSelect.Options = p.profileNames()
+		p.profileSelect.SetSelected(p.profiles[0].Name)
+	}, p.window)
+}
+
+// openTranslationMemory loads the translation memory, showing errors
+func (p *SettingsPanel) openTranslationMemory() (*services.TranslationMemory, bool) {
+	tm, err := services.SharedTranslationMemory(services.DefaultTranslationMemoryPath())
+	if err != nil {
+		dialog.ShowError(err, p.window)
+		return nil, false
+	}
+	return tm, true
+}
+
+// updateMemoryLabel shows how many translations are remembered
+func (p *SettingsPanel) updateMemoryLabel() {
+	tm, err := services.SharedTranslationMemory(services.DefaultTranslationMemoryPath())
+	if err != nil {
+		p.memoryLabel.SetText("Translation memory unreadable")
+		return
+	}
+	p.memoryLabel.SetText(fmt.Sprintf("%d translations", tm.Len()))
+}
+
+// importTranslationMemory merges a user-chosen TMX file into the memory
+func (p *SettingsPanel) importTranslationMemory() {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+
+		tm, ok := p.openTranslationMemory()
+		if !ok {
+			return
+		}
+		added, err := tm.ImportTMX(path)
+		if err == nil {
+			err = tm.Save()
+		}
+		if err != nil {
+			dialog.ShowError(err, p.window)
+			return
+		}
+		p.updateMemoryLabel()
+		dialog.ShowCustom("Import Complete", "OK", widget.NewLabel(fmt.Sprintf("Imported %d translations.", added)), p.window)
+	}, p.window)
+
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".tmx"}))
+	fd.Show()
+}
+
+// exportTranslationMemory saves the memory as a TMX file
+func (p *SettingsPanel) exportTranslationMemory() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		path := writer.URI().Path()
+		writer.Close()
+
+		tm, ok := p.openTranslationMemory()
+		if !ok {
+			return
+		}
+		if err := tm.ExportTMX(path); err != nil {
+			dialog.ShowError(err, p.window)
+		}
+	}, p.window)
+	save.SetFileName("translation_memory.tmx")
+	save.Show()
+}
+
+// clearTranslationMemory deletes all remembered translations after confirmation
+func (p *SettingsPanel) clearTranslationMemory() {
+	dialog.ShowConfirm("Clear Translation Memory", "Delete all remembered translations? Export them to TMX first to keep a copy.", func(ok bool) {
+		if !ok {
+			return
+		}
+		tm, opened := p.openTranslationMemory()
+		if !opened {
+			return
+		}
+		tm.Clear()
+		if err := tm.Save(); err != nil {
+			dialog.ShowError(err, p.window)
+		}
+		p.updateMemoryLabel()
+	}, p.window)
+}
+
+// usageLedger opens the usage ledger priced with the current settings
+func (p *SettingsPanel) usageLedger() *services.UsageLedger {
+	return services.NewUsageLedger(services.DefaultUsageLedgerPath(), p.config)
+}
+
+// updateUsageLabel shows the total recorded provider cost
+func (p *SettingsPanel) updateUsageLabel() {
+	records, err := p.usageLedger().Records()
+	if err != nil {
+		p.usageLabel.SetText("Usage ledger unreadable")
+		return
+	}
+	var cost float64
+	for _, r := range records {
+		cost += r.Cost
+	}
+	p.usageLabel.SetText(fmt.Sprintf("%d provider calls, %s", len(records), services.FormatCost(cost)))
+}
+
+// showUsage shows the ledger's totals by job, day and provider
+func (p *SettingsPanel) showUsage() {
+	records, err := p.usageLedger().Records()
+	if err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+
+	tabs := container.NewAppTabs()
+	for _, by := range []struct{ title, key string }{
+		{"By Job", services.UsageByJob},
+		{"By Day", services.UsageByDay},
+		{"By Provider", services.UsageByProvider},
+	} {
+		var report strings.Builder
+		if err := services.WriteUsageReport(&report, records, by.key); err != nil {
+			dialog.ShowError(err, p.window)
+			return
+		}
+		text := widget.NewLabel(report.String())
+		text.TextStyle = fyne.TextStyle{Monospace: true}
+		tabs.Append(container.NewTabItem(by.title, container.NewScroll(text)))
+	}
+
+	d := dialog.NewCustom("Usage & Costs", "Close", tabs, p.window)
+	d.Resize(fyne.NewSize(800, 450))
+	d.Show()
+}
+
+// clearUsage deletes the usage ledger after confirmation
+func (p *SettingsPanel) clearUsage() {
+	dialog.ShowConfirm("Clear Usage", "Delete all recorded provider usage and costs?", func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := p.usageLedger().Clear(); err != nil {
+			dialog.ShowError(err, p.window)
+		}
+		p.updateUsageLabel()
+	}, p.window)
+}
+
 func (p *SettingsPanel) saveSettings() {
+	// Checked first so a typo doesn't half-save the settings
+	lexicon, err := models.ParseLexicon(p.lexiconEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, p.window)
+		return
+	}
+	p.config.PronunciationLexicon = lexicon
+
 	p.config.OutputDirectory = p.outputDirEntry.Text
 	p.config.TranscriptionProvider = p.transcriptionSelect.Selected
 	p.config.TranslationProvider = p.translationSelect.Selected
@@ -479,6 +1178,7 @@ func (p *SettingsPanel) saveSettings() {
 	p.config.CosyVoiceMode = p.cosyVoiceModeSelect.Selected
 	p.config.CosyVoiceAPIURL = p.cosyVoiceAPIURLEntry.Text
 	p.config.VoiceCloneSamplePath = p.voiceSamplePathEntry.Text
+	p.config.AutoVoiceReference = p.autoVoiceReferenceCheck.Checked
 
 	p.config.OpenAIKey = p.openAIKeyEntry.Text
 	p.config.DeepSeekKey = p.deepSeekKeyEntry.Text
@@ -486,9 +1186,52 @@ func (p *SettingsPanel) saveSettings() {
 	p.config.GrokAPIKey = p.grokAPIKeyEntry.Text
 	p.config.FishAudioAPIKey = p.fishAudioAPIKeyEntry.Text
 
+	// LLM translation overrides (invalid numbers fall back to the preset)
+	p.config.LLMBaseURL = strings.TrimSpace(p.llmBaseURLEntry.Text)
+	p.config.LLMModel = strings.TrimSpace(p.llmModelEntry.Text)
+	p.config.LLMAPIKey = p.llmAPIKeyEntry.Text
+	p.config.LLMMaxTokens, _ = strconv.Atoi(strings.TrimSpace(p.llmMaxTokensEntry.Text))
+	p.config.LLMTemperature, _ = strconv.ParseFloat(strings.TrimSpace(p.llmTemperatureEntry.Text), 64)
+	p.config.LLMConcurrency, _ = strconv.Atoi(strings.TrimSpace(p.llmConcurrencyEntry.Text))
+
+	p.config.LibreTranslateURL = strings.TrimSpace(p.libreURLEntry.Text)
+	p.config.LibreTranslateAPIKey = p.libreAPIKeyEntry.Text
+
+	p.config.TranslationContextLines, _ = strconv.Atoi(p.contextLinesSelect.Selected)
+	p.config.TranslationRunningSummary = p.runningSummaryCheck.Checked
+	p.config.TranslationDocumentBrief = p.documentBriefCheck.Checked
+	p.config.FitTranslationToDuration = p.fitDurationCheck.Checked
+	p.config.EmotionTagging = p.emotionTaggingCheck.Checked
+	p.config.SpeechNormalization = p.speechNormalizationCheck.Checked
+	p.config.SpeechRateMax, _ = strconv.ParseFloat(p.speechRateMaxSelect.Selected, 64) // "off" parses to 0
+	p.config.SpeechRateMin, _ = strconv.ParseFloat(p.speechRateMinSelect.Selected, 64)
+	p.storeProfileFields()
+	p.config.TranslationProfiles = append([]models.TranslationProfile(nil), p.profiles...)
+	p.config.TranslationProfile = p.profileSelect.Selected
+	p.config.Glossary = p.glossarySelect.Selected
+	if p.config.Glossary == "none" {
+		p.config.Glossary = ""
+	}
+
 	// Fish Audio settings
 	p.config.FishAudioModel = p.fishAudioModelSelect.Selected
 
+	p.config.FilterHallucinations = p.filterHallucinationsCheck.Checked
+	p.config.TranscriptionVocabulary = text.ParseVocabulary(p.vocabularyEntry.Text)
+	p.config.VocabularyFuzzyFix = p.vocabularyFuzzyCheck.Checked
+	p.config.TranscriptionCacheEnabled = p.transcriptCacheCheck.Checked
+	p.config.SpeechCacheEnabled = p.speechCacheCheck.Checked
+	p.config.TranslationMemoryEnabled = p.memoryCheck.Checked
+
+	// Spending caps (empty or invalid = no cap)
+	p.config.JobBudget, _ = strconv.ParseFloat(strings.TrimSpace(p.jobBudgetEntry.Text), 64)
+	p.config.DailyBudget, _ = strconv.ParseFloat(strings.TrimSpace(p.dailyBudgetEntry.Text), 64)
+	p.config.BudgetAction = p.budgetActionSelect.Selected
+
+	p.config.DiarizationEnabled = p.diarizationCheck.Checked
+	p.config.HuggingFaceToken = p.huggingFaceTokenEntry.Text
+	p.config.MaxSpeakers, _ = strconv.Atoi(p.maxSpeakersSelect.Selected) // "auto" -> 0
+
 	p.config.KeepBackgroundAudio = p.keepBackgroundAudioCheck.Checked
 	p.config.BackgroundAudioVolume = p.backgroundVolumeSlider.Value / 100.0
 
@@ -542,6 +1285,9 @@ func (p *SettingsPanel) getCostEstimate() string {
 	case "grok":
 		translateCostNum = 0.02
 		translateCost = "~$0.02 (xAI)"
+	case "llm":
+		translateCostNum = 0
+		translateCost = "Depends on endpoint"
 	default:
 		translateCostNum = 0
 		translateCost = "Free (local)"
@@ -579,6 +1325,25 @@ func (p *SettingsPanel) getCostEstimate() string {
 		transcriptCost, translateCost, ttsCost, total)
 }
 
+// formatOptionalInt renders 0 (meaning "use the default") as an empty field
+func formatOptionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+// withOption appends value to options unless it's already one, so a value
+// set in the config file isn't lost when the select is saved
+func withOption(options []string, value string) []string {
+	for _, o := range options {
+		if o == value {
+			return options
+		}
+	}
+	return append(options, value)
+}
+
 func getOrDefault(value, defaultValue string) string {
 	if value == "" {
 		return defaultValue
@@ -0,0 +1,96 @@
+package dialogs
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"video-translator/services"
+)
+
+// ReviewDialog lists the segments of a job that need a human check
+type ReviewDialog struct {
+	window fyne.Window
+	list   *services.ReviewList
+}
+
+// NewReviewDialog creates a review dialog for a loaded review list
+func NewReviewDialog(window fyne.Window, list *services.ReviewList) *ReviewDialog {
+	return &ReviewDialog{
+		window: window,
+		list:   list,
+	}
+}
+
+// Show displays the dialog
+func (d *ReviewDialog) Show() {
+	summary := widget.NewLabel(d.list.Summary())
+	summary.TextStyle = fyne.TextStyle{Bold: true}
+
+	items := widget.NewList(
+		func() int { return len(d.list.Items) },
+		func() fyne.CanvasObject {
+			header := widget.NewLabel("")
+			header.TextStyle = fyne.TextStyle{Bold: true}
+			source := widget.NewLabel("")
+			source.Wrapping = fyne.TextWrapWord
+			translation := widget.NewLabel("")
+			translation.Wrapping = fyne.TextWrapWord
+			reasons := widget.NewLabel("")
+			reasons.TextStyle = fyne.TextStyle{Italic: true}
+			reasons.Wrapping = fyne.TextWrapWord
+			return container.NewVBox(header, source, translation, reasons)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			item := d.list.Items[id]
+			labels := obj.(*fyne.Container).Objects
+
+			header := fmt.Sprintf("#%d  %s → %s", item.Index, item.Start, item.End)
+			if item.Speaker != "" {
+				header += "  " + item.Speaker
+			}
+			if item.Confidence != nil {
+				header += fmt.Sprintf("  (%.0f%% confidence)", *item.Confidence*100)
+			}
+			labels[0].(*widget.Label).SetText(header)
+			labels[1].(*widget.Label).SetText(item.Source)
+			labels[2].(*widget.Label).SetText(item.Translation)
+			labels[3].(*widget.Label).SetText(strings.Join(item.Reasons, "; "))
+		},
+	)
+
+	exportBtn := widget.NewButton("Export JSON...", d.export)
+
+	content := container.NewBorder(
+		summary,
+		container.NewHBox(exportBtn),
+		nil,
+		nil,
+		items,
+	)
+
+	dlg := dialog.NewCustom("Segments to Review", "Close", content, d.window)
+	dlg.Resize(fyne.NewSize(700, 550))
+	dlg.Show()
+}
+
+// export saves the review list to a user-chosen file
+func (d *ReviewDialog) export() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		path := writer.URI().Path()
+		writer.Close()
+
+		if err := d.list.WriteJSON(path); err != nil {
+			dialog.ShowError(err, d.window)
+		}
+	}, d.window)
+	save.SetFileName("review.json")
+	save.Show()
+}
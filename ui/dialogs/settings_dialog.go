@@ -140,7 +140,7 @@ func (d *SettingsDialog) build() fyne.CanvasObject {
 
 	// OpenAI TTS settings
 	d.openaiTTSModelSelect = widget.NewSelect([]string{
-		"tts-1", "tts-1-hd",
+		"tts-1", "tts-1-hd", "gpt-4o-mini-tts",
 	}, nil)
 	d.openaiTTSModelSelect.SetSelected(getOrDefault(d.config.OpenAITTSModel, "tts-1"))
 
@@ -25,8 +25,13 @@ type BottomControls struct {
 	sourceSelector *CompactLanguageSelector
 	targetSelector *CompactLanguageSelector
 	voiceSelector  *VoiceSelector
+	voiceSource    VoiceSource
 	ttsProvider    string
 
+	// Providers the language lists are filtered by
+	transcriptionProvider string
+	translationProvider   string
+
 	translateBtn    *PrimaryButton
 	translateAllBtn *widget.Button
 }
@@ -44,10 +49,41 @@ func NewBottomControls() *BottomControls {
 func (c *BottomControls) SetTTSProvider(provider string) {
 	c.ttsProvider = provider
 	if c.voiceSelector != nil {
+		c.voiceSelector.Language = c.GetTargetLang()
 		c.voiceSelector.SetProvider(provider)
 	}
 }
 
+// SetVoiceSource sets where voice options come from
+func (c *BottomControls) SetVoiceSource(source VoiceSource) {
+	c.voiceSource = source
+	if c.voiceSelector != nil {
+		c.voiceSelector.Source = source
+		c.voiceSelector.Reload()
+	}
+}
+
+// ReloadVoices lists the voices again, e.g. after the catalog was refreshed
+func (c *BottomControls) ReloadVoices() {
+	if c.voiceSelector != nil {
+		c.voiceSelector.Reload()
+	}
+}
+
+// SetProviders updates the language lists for the selected providers and
+// the voice options for the TTS provider
+func (c *BottomControls) SetProviders(transcription, translation, tts string) {
+	c.transcriptionProvider = transcription
+	c.translationProvider = translation
+	if c.sourceSelector != nil {
+		c.sourceSelector.SetLanguages(SourceLanguages(transcription))
+	}
+	if c.targetSelector != nil {
+		c.targetSelector.SetLanguages(TargetLanguages(translation, tts))
+	}
+	c.SetTTSProvider(tts)
+}
+
 // GetSourceLang returns the selected source language code
 func (c *BottomControls) GetSourceLang() string {
 	if c.sourceSelector != nil {
@@ -98,19 +134,23 @@ func createCard(content fyne.CanvasObject) fyne.CanvasObject {
 // Build creates the control bar UI
 func (c *BottomControls) Build() fyne.CanvasObject {
 	// Source language selector
-	c.sourceSelector = NewCompactLanguageSelector(SourceLanguages(), nil)
+	c.sourceSelector = NewCompactLanguageSelector(SourceLanguages(c.transcriptionProvider), nil)
 	c.sourceSelector.SetSelected("ru")
 
 	// Arrow icon
 	arrow := canvas.NewText("→", color.White)
 	arrow.TextSize = 16
 
-	// Target language selector
-	c.targetSelector = NewCompactLanguageSelector(TargetLanguages(), nil)
+	// Target language selector; voices follow the target language
+	c.targetSelector = NewCompactLanguageSelector(TargetLanguages(c.translationProvider, c.ttsProvider), func(code string) {
+		if c.voiceSelector != nil {
+			c.voiceSelector.SetLanguage(code)
+		}
+	})
 	c.targetSelector.SetSelected("en")
 
 	// Voice selector with preview
-	c.voiceSelector = NewVoiceSelector(c.ttsProvider, nil, func() {
+	c.voiceSelector = NewVoiceSelector(c.ttsProvider, c.GetTargetLang(), c.voiceSource, nil, func() {
 		if c.OnPreviewVoice != nil {
 			c.OnPreviewVoice()
 		}
@@ -4,6 +4,8 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+
+	"video-translator/internal/text"
 )
 
 // Language represents a language option
@@ -12,33 +14,26 @@ type Language struct {
 	Name string
 }
 
-// SourceLanguages returns available source languages
-func SourceLanguages() []Language {
-	return []Language{
-		{Code: "ru", Name: "Russian"},
-		{Code: "es", Name: "Spanish"},
-		{Code: "fr", Name: "French"},
-		{Code: "de", Name: "German"},
-		{Code: "zh", Name: "Chinese"},
-		{Code: "ja", Name: "Japanese"},
-		{Code: "ko", Name: "Korean"},
-		{Code: "pt", Name: "Portuguese"},
-		{Code: "it", Name: "Italian"},
-		{Code: "ar", Name: "Arabic"},
+// SourceLanguages returns the languages a transcription provider can
+// listen to, after "Auto-detect"
+func SourceLanguages(transcriptionProvider string) []Language {
+	languages := []Language{{Code: "auto", Name: "Auto-detect"}}
+	for _, l := range text.LanguagesFor(text.StageTranscription, transcriptionProvider) {
+		languages = append(languages, Language{Code: l.Code, Name: l.Name})
 	}
+	return languages
 }
 
-// TargetLanguages returns available target languages
-func TargetLanguages() []Language {
-	return []Language{
-		{Code: "en", Name: "English"},
-		{Code: "es", Name: "Spanish"},
-		{Code: "fr", Name: "French"},
-		{Code: "de", Name: "German"},
-		{Code: "pt", Name: "Portuguese"},
-		{Code: "it", Name: "Italian"},
-		{Code: "ru", Name: "Russian"},
+// TargetLanguages returns the languages both the translation and the TTS
+// provider support
+func TargetLanguages(translationProvider, ttsProvider string) []Language {
+	var languages []Language
+	for _, l := range text.LanguagesFor(text.StageTranslation, translationProvider) {
+		if text.SupportsLanguage(text.StageTTS, ttsProvider, l.Code) {
+			languages = append(languages, Language{Code: l.Code, Name: l.Name})
+		}
 	}
+	return languages
 }
 
 // LanguageSelector is a dropdown for language selection
@@ -174,6 +169,31 @@ func (s *CompactLanguageSelector) GetSelected() string {
 	return s.Selected
 }
 
+// SetLanguages replaces the options, keeping the selection if it is still
+// offered and falling back to the first language otherwise
+func (s *CompactLanguageSelector) SetLanguages(languages []Language) {
+	s.Languages = languages
+	selected := ""
+	for _, lang := range languages {
+		if lang.Code == s.Selected {
+			selected = lang.Code
+			break
+		}
+	}
+	if selected == "" && len(languages) > 0 {
+		selected = languages[0].Code
+	}
+	if s.select_ != nil {
+		options := make([]string, len(languages))
+		for i, lang := range languages {
+			options[i] = lang.Name
+		}
+		s.select_.SetOptions(options)
+		s.select_.ClearSelected()
+	}
+	s.SetSelected(selected)
+}
+
 // Build creates the widget UI
 func (s *CompactLanguageSelector) Build() fyne.CanvasObject {
 	options := make([]string, len(s.Languages))
@@ -10,85 +10,12 @@ import (
 // VoiceOption represents a voice choice
 type VoiceOption struct {
 	ID       string
-	Name     string
+	Name     string // Label shown in the picker ("Aria (en-US, female)")
 	Provider string
 }
 
-// PiperVoices returns available Piper voices
-func PiperVoices() []VoiceOption {
-	return []VoiceOption{
-		{ID: "en_US-amy-medium", Name: "Amy (US)", Provider: "piper"},
-		{ID: "en_US-ryan-medium", Name: "Ryan (US)", Provider: "piper"},
-		{ID: "en_GB-alba-medium", Name: "Alba (UK)", Provider: "piper"},
-		{ID: "de_DE-thorsten-medium", Name: "Thorsten (DE)", Provider: "piper"},
-		{ID: "fr_FR-upmc-medium", Name: "UPMC (FR)", Provider: "piper"},
-	}
-}
-
-// OpenAIVoices returns available OpenAI TTS voices
-func OpenAIVoices() []VoiceOption {
-	return []VoiceOption{
-		{ID: "alloy", Name: "Alloy", Provider: "openai"},
-		{ID: "echo", Name: "Echo", Provider: "openai"},
-		{ID: "fable", Name: "Fable", Provider: "openai"},
-		{ID: "onyx", Name: "Onyx", Provider: "openai"},
-		{ID: "nova", Name: "Nova", Provider: "openai"},
-		{ID: "shimmer", Name: "Shimmer", Provider: "openai"},
-	}
-}
-
-// EdgeTTSVoices returns available Edge TTS voices
-func EdgeTTSVoices() []VoiceOption {
-	return []VoiceOption{
-		{ID: "en-US-AriaNeural", Name: "Aria (US)", Provider: "edge-tts"},
-		{ID: "en-US-GuyNeural", Name: "Guy (US)", Provider: "edge-tts"},
-		{ID: "en-GB-SoniaNeural", Name: "Sonia (UK)", Provider: "edge-tts"},
-		{ID: "en-GB-RyanNeural", Name: "Ryan (UK)", Provider: "edge-tts"},
-		{ID: "de-DE-KatjaNeural", Name: "Katja (DE)", Provider: "edge-tts"},
-		{ID: "fr-FR-DeniseNeural", Name: "Denise (FR)", Provider: "edge-tts"},
-		{ID: "es-ES-ElviraNeural", Name: "Elvira (ES)", Provider: "edge-tts"},
-		{ID: "ru-RU-DariyaNeural", Name: "Dariya (RU)", Provider: "edge-tts"},
-	}
-}
-
-// CosyVoices returns CosyVoice options
-func CosyVoices() []VoiceOption {
-	return []VoiceOption{
-		{ID: "clone", Name: "Voice Clone", Provider: "cosyvoice"},
-	}
-}
-
-// FishAudioVoices returns available Fish Audio voices
-// Voice IDs are reference_ids from fish.audio
-// Browse more voices at https://fish.audio/discover
-func FishAudioVoices() []VoiceOption {
-	return []VoiceOption{
-		{ID: "933563129e564b19a115bedd57b7406a", Name: "Sarah", Provider: "fish-audio"},
-		{ID: "bf322df2096a46f18c579d0baa36f41d", Name: "Adrian", Provider: "fish-audio"},
-		{ID: "b347db033a6549378b48d00acb0d06cd", Name: "Selene", Provider: "fish-audio"},
-		{ID: "536d3a5e000945adb7038665781a4aca", Name: "Ethan", Provider: "fish-audio"},
-		{ID: "802e3bc2b27e49c2995d23ef70e6ac89", Name: "Energetic Male", Provider: "fish-audio"},
-		{ID: "8ef4a238714b45718ce04243307c57a7", Name: "E-girl", Provider: "fish-audio"},
-	}
-}
-
-// GetVoicesForProvider returns voices for a given provider
-func GetVoicesForProvider(provider string) []VoiceOption {
-	switch provider {
-	case "piper":
-		return PiperVoices()
-	case "openai":
-		return OpenAIVoices()
-	case "edge-tts":
-		return EdgeTTSVoices()
-	case "cosyvoice":
-		return CosyVoices()
-	case "fish-audio":
-		return FishAudioVoices()
-	default:
-		return EdgeTTSVoices()
-	}
-}
+// VoiceSource lists a TTS provider's voices that can read lang
+type VoiceSource func(provider, lang string) []VoiceOption
 
 // VoiceSelector allows voice selection with preview
 type VoiceSelector struct {
@@ -97,6 +24,8 @@ type VoiceSelector struct {
 	Voices      []VoiceOption
 	Selected    string
 	Provider    string
+	Language    string // Target language the voices are filtered by
+	Source      VoiceSource
 	OnChanged   func(voiceID string)
 	OnPreview   func()
 
@@ -104,18 +33,17 @@ type VoiceSelector struct {
 	previewBtn  *widget.Button
 }
 
-// NewVoiceSelector creates a new voice selector
-func NewVoiceSelector(provider string, onChanged func(voiceID string), onPreview func()) *VoiceSelector {
-	voices := GetVoicesForProvider(provider)
+// NewVoiceSelector creates a voice selector listing source's voices for
+// provider that read lang
+func NewVoiceSelector(provider, lang string, source VoiceSource, onChanged func(voiceID string), onPreview func()) *VoiceSelector {
 	s := &VoiceSelector{
-		Voices:    voices,
 		Provider:  provider,
+		Language:  lang,
+		Source:    source,
 		OnChanged: onChanged,
 		OnPreview: onPreview,
 	}
-	if len(voices) > 0 {
-		s.Selected = voices[0].ID
-	}
+	s.loadVoices()
 	s.ExtendBaseWidget(s)
 	return s
 }
@@ -123,22 +51,50 @@ func NewVoiceSelector(provider string, onChanged func(voiceID string), onPreview
 // SetProvider updates the provider and available voices
 func (s *VoiceSelector) SetProvider(provider string) {
 	s.Provider = provider
-	s.Voices = GetVoicesForProvider(provider)
+	s.Reload()
+}
 
-	if len(s.Voices) > 0 {
-		s.Selected = s.Voices[0].ID
+// SetLanguage shows only the voices that can read lang
+func (s *VoiceSelector) SetLanguage(lang string) {
+	s.Language = lang
+	s.Reload()
+}
+
+// Reload lists the voices again (after a catalog refresh), keeping the
+// selected voice if it's still offered
+func (s *VoiceSelector) Reload() {
+	s.loadVoices()
+	if s.select_ == nil {
+		return
+	}
+	options := make([]string, len(s.Voices))
+	for i, v := range s.Voices {
+		options[i] = v.Name
 	}
+	s.select_.Options = options
+	if s.Selected == "" {
+		s.select_.ClearSelected()
+	} else {
+		s.SetSelected(s.Selected)
+	}
+	s.select_.Refresh()
+}
 
-	if s.select_ != nil {
-		options := make([]string, len(s.Voices))
-		for i, v := range s.Voices {
-			options[i] = v.Name
-		}
-		s.select_.Options = options
-		if len(options) > 0 {
-			s.select_.SetSelectedIndex(0)
+// loadVoices fetches the voice list from Source and picks the first voice
+// unless the selected one is among them
+func (s *VoiceSelector) loadVoices() {
+	s.Voices = nil
+	if s.Source != nil {
+		s.Voices = s.Source(s.Provider, s.Language)
+	}
+	for _, v := range s.Voices {
+		if v.ID == s.Selected {
+			return
 		}
-		s.select_.Refresh()
+	}
+	s.Selected = ""
+	if len(s.Voices) > 0 {
+		s.Selected = s.Voices[0].ID
 	}
 }
 